@@ -3,10 +3,16 @@ module github.com/your-repo/blockchain-integration-service
 go 1.16
 
 require (
+	github.com/btcsuite/btcd v0.23.4
+	github.com/btcsuite/btcd/btcutil v1.1.3
+	github.com/btcsuite/btcd/btcutil/psbt v1.1.8
+	github.com/btcsuite/btcd/chaincfg/chainhash v1.0.2
 	github.com/gin-gonic/gin v1.7.4
 	github.com/go-redis/redis/v8 v8.11.3
 	github.com/golang-migrate/migrate/v4 v4.15.1
+	github.com/gorilla/websocket v1.4.2
 	github.com/jackc/pgx/v4 v4.13.0
+	github.com/prometheus/client_golang v1.11.0
 	github.com/segmentio/kafka-go v0.4.20
 	github.com/spf13/viper v1.9.0
 	github.com/stretchr/testify v1.7.0
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -10,53 +11,71 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
 	"github.com/your-repo/blockchain-integration-service/internal/api/router"
-	"github.com/your-repo/blockchain-integration-service/internal/config"
 	"github.com/your-repo/blockchain-integration-service/internal/database"
+	"github.com/your-repo/blockchain-integration-service/internal/services"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 )
 
+// configPathEnv names the environment variable holding the path of the configuration file
+const configPathEnv = "CONFIG_PATH"
+
 func main() {
 	// Load configuration
-	cfg, err := config.Load()
+	configPath := os.Getenv(configPathEnv)
+	if configPath == "" {
+		configPath = "config.yaml"
+	}
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Initialize logger
-	l := logger.New(cfg.LogLevel)
+	l, err := logger.NewLogger(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer l.Sync()
 
-	// Connect to database
-	db, err := database.Connect(cfg.DatabaseURL)
+	// Connect to the database and Redis
+	db, err := database.NewPostgresDB(cfg, l)
 	if err != nil {
-		l.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
+	redisClient, err := database.NewRedisClient(cfg, l)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redisClient.Close()
 
-	// Set up Gin engine
-	gin.SetMode(cfg.GinMode)
-	engine := gin.New()
-
-	// Apply middleware
-	engine.Use(gin.Recovery())
-	engine.Use(middleware.Logger(l))
-	// Add more middleware as needed
+	// Construct the services and the routes over them
+	svcs, err := services.NewServices(cfg, db, redisClient.Client, l)
+	if err != nil {
+		log.Fatalf("Failed to initialize services: %v", err)
+	}
+	gin.SetMode(cfg.Server.Mode)
+	engine := router.SetupRouter(svcs, l)
 
-	// Set up routes
-	router.SetupRoutes(engine, db)
+	// Start event publishing and the background loops; they stop once the server has shut down
+	jobs, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	router.StartBackgroundJobs(jobs, svcs, cfg, l)
 
 	// Initialize HTTP server
+	address := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	srv := &http.Server{
-		Addr:    cfg.ServerAddress,
+		Addr:    address,
 		Handler: engine,
 	}
 
 	// Start server in a goroutine
 	go func() {
-		l.Infof("Starting server on %s", cfg.ServerAddress)
+		l.Info("Starting server on " + address)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			l.Fatalf("Failed to start server: %v", err)
+			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
 
@@ -70,8 +89,9 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		l.Fatalf("Server forced to shutdown: %v", err)
+		l.Error("Server forced to shutdown: " + err.Error())
 	}
+	stopJobs()
 
 	l.Info("Server exiting")
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+)
+
+// passphraseEnv names the environment variable holding the passphrase of an encrypted Ethereum keystore
+const passphraseEnv = "OFFLINE_SIGNER_PASSPHRASE"
+
+func main() {
+	// Parse command-line flags
+	in := flag.String("in", "-", "unsigned payload file, as JSON or QR text ('-' for stdin)")
+	out := flag.String("out", "-", "signed payload file ('-' for stdout)")
+	keyFile := flag.String("key", "", "local keystore: an Ethereum keystore JSON, a Bitcoin WIF or an XRP family seed")
+	qr := flag.Bool("qr", false, "write the signed payload as QR text instead of JSON")
+	yes := flag.Bool("yes", false, "sign without asking for confirmation")
+	flag.Parse()
+	if *keyFile == "" {
+		log.Fatalf("A keystore is required: pass -key")
+	}
+
+	// Read and verify the unsigned payload
+	raw, err := readInput(*in)
+	if err != nil {
+		log.Fatalf("Failed to read payload: %v", err)
+	}
+	payload, err := offline.Parse(raw)
+	if err != nil {
+		log.Fatalf("Failed to parse payload: %v", err)
+	}
+	if payload.Signed {
+		log.Fatalf("Payload for transaction %s is already signed", payload.TransactionID)
+	}
+
+	// Show what is about to be signed and ask the operator to confirm
+	fmt.Fprintf(os.Stderr, "Transaction: %s\nBlockchain:  %s\nFormat:      %s\nDigest:      %s\n", payload.TransactionID, payload.BlockchainType, payload.Format, payload.Digest)
+	if !*yes && !confirm() {
+		log.Fatalf("Signing cancelled")
+	}
+
+	// Sign the payload with the local key
+	key, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		log.Fatalf("Failed to read keystore: %v", err)
+	}
+	signed, err := sign(payload, key, os.Getenv(passphraseEnv))
+	if err != nil {
+		log.Fatalf("Failed to sign payload: %v", err)
+	}
+
+	// Write the signed payload, still bound to the originating transaction
+	result := *payload
+	result.Signed = true
+	result.Data = signed
+	result.Seal()
+	if err := writeOutput(*out, &result, *qr); err != nil {
+		log.Fatalf("Failed to write signed payload: %v", err)
+	}
+}
+
+// readInput reads a file, or stdin for "-"
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// writeOutput writes the payload as indented JSON or QR text to a file, or stdout for "-"
+func writeOutput(path string, payload *offline.Payload, qr bool) error {
+	var encoded []byte
+	if qr {
+		text, err := payload.QR()
+		if err != nil {
+			return err
+		}
+		encoded = []byte(text + "\n")
+	} else {
+		var err error
+		if encoded, err = json.MarshalIndent(payload, "", "  "); err != nil {
+			return err
+		}
+		encoded = append(encoded, '\n')
+	}
+
+	if path == "-" {
+		_, err := os.Stdout.Write(encoded)
+		return err
+	}
+	return ioutil.WriteFile(path, encoded, 0600)
+}
+
+// confirm asks the operator on the terminal whether to sign
+func confirm() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	defer tty.Close()
+
+	fmt.Fprint(os.Stderr, "Sign this transaction? [y/N] ")
+	answer, _ := bufio.NewReader(tty).ReadString('\n')
+	return strings.EqualFold(strings.TrimSpace(answer), "y")
+}
+
+// Human tasks:
+// TODO: Decode and display the recipient and amount of every format before confirmation
+// TODO: Read QR payloads directly from a camera
+// TODO: Support hardware wallets as an alternative to the local keystore
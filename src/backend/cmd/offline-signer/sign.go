@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	ripplecrypto "github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// sign signs the payload data with the key for its format and returns the signed data
+func sign(payload *offline.Payload, key []byte, passphrase string) (string, error) {
+	switch payload.Format {
+	case offline.FormatRLP:
+		return signRLP(payload, key, passphrase)
+	case offline.FormatPSBT:
+		return signPSBT(payload, key)
+	case offline.FormatXRPJSON:
+		return signXRP(payload, key)
+	default:
+		return "", fmt.Errorf("unsupported payload format %q", payload.Format)
+	}
+}
+
+// signRLP signs an Ethereum transaction with a key from an encrypted keystore file
+func signRLP(payload *offline.Payload, key []byte, passphrase string) (string, error) {
+	network, ok := evm.Lookup(payload.BlockchainType)
+	if !ok {
+		return "", fmt.Errorf("unknown EVM network %q", payload.BlockchainType)
+	}
+	raw, err := hexutil.Decode(payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid RLP payload: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return "", fmt.Errorf("invalid RLP payload: %w", err)
+	}
+
+	// Refuse to sign anything other than the transaction the digest describes
+	if types.LatestSignerForChainID(network.ChainID).Hash(tx).Hex() != payload.Digest {
+		return "", fmt.Errorf("payload data does not match its digest")
+	}
+
+	stored, err := keystore.DecryptKey(key, passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	signed, err := ethereum.SignTransaction(tx, network, func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, stored.PrivateKey)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := signed.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(encoded), nil
+}
+
+// signPSBT signs the PSBT inputs controlled by a WIF-encoded key
+func signPSBT(payload *offline.Payload, key []byte) (string, error) {
+	wif, err := btcutil.DecodeWIF(strings.TrimSpace(string(key)))
+	if err != nil {
+		return "", fmt.Errorf("invalid WIF key: %w", err)
+	}
+	return utxo.SignPSBTWithKey(payload.Data, wif.PrivKey)
+}
+
+// signXRP signs an XRP payment with the secp256k1 key derived from a family seed and returns the signed blob
+func signXRP(payload *offline.Payload, key []byte) (string, error) {
+	var payment data.Payment
+	if err := json.Unmarshal([]byte(payload.Data), &payment); err != nil {
+		return "", fmt.Errorf("invalid XRP payload: %w", err)
+	}
+
+	seed, err := ripplecrypto.NewRippleHashCheck(strings.TrimSpace(string(key)), ripplecrypto.RIPPLE_FAMILY_SEED)
+	if err != nil {
+		return "", fmt.Errorf("invalid XRP family seed: %w", err)
+	}
+	signingKey, err := ripplecrypto.NewECDSAKey(seed.Payload())
+	if err != nil {
+		return "", err
+	}
+
+	// Sign with the account's first key pair, as derived by standard wallets
+	var sequence uint32
+	if err := data.Sign(&payment, signingKey, &sequence); err != nil {
+		return "", err
+	}
+	_, raw, err := data.Raw(&payment)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(raw)), nil
+}
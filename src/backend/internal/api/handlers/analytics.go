@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
@@ -83,6 +84,32 @@ func (h *AnalyticsHandler) GetPerformanceMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metricsData)
 }
 
+// GetFeeAnalytics handles requests for fee analytics, including gas-station top-ups
+func (h *AnalyticsHandler) GetFeeAnalytics(c *gin.Context) {
+	// Parse the RFC 3339 time range parameters from the request
+	startTime, err := time.Parse(time.RFC3339, c.Query("start_time"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid start_time", err))
+		return
+	}
+	endTime, err := time.Parse(time.RFC3339, c.Query("end_time"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid end_time", err))
+		return
+	}
+
+	// Call the analytics service to get fee analytics data
+	feeData, err := h.analyticsService.GetFeeAnalytics(c.Request.Context(), startTime, endTime)
+	if err != nil {
+		logger.Error("Failed to get fee analytics data", "error", err)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to retrieve fee analytics data", err))
+		return
+	}
+
+	// Return the fee analytics data in the response
+	c.JSON(http.StatusOK, feeData)
+}
+
 // GenerateCustomReport handles requests for generating custom analytics reports
 func (h *AnalyticsHandler) GenerateCustomReport(c *gin.Context) {
 	// Parse and validate the custom report request from the request body
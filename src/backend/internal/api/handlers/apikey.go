@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/apikey"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// APIKeyHandler struct holds dependencies for API key management handlers
+type APIKeyHandler struct {
+	apiKeyService *apikey.Service
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler instance
+func NewAPIKeyHandler(as *apikey.Service) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: as,
+	}
+}
+
+// IssueAPIKey handles issuing a new API key for the caller's organization
+func (ah *APIKeyHandler) IssueAPIKey(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse API key request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the API key service to issue the key in the caller's organization
+	issued, err := ah.apiKeyService.IssueAPIKey(c.Request.Context(), middleware.OrganizationID(c), &req)
+	if err != nil {
+		logger.Error("Failed to issue API key", "error", err)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to issue API key", err))
+		return
+	}
+
+	// Return the plaintext key, which is only shown once
+	c.JSON(http.StatusCreated, issued)
+}
+
+// ListAPIKeys handles listing the caller's organization's API keys
+func (ah *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	// Call the API key service to list the organization's keys
+	keys, err := ah.apiKeyService.ListAPIKeys(c.Request.Context(), middleware.OrganizationID(c))
+	if err != nil {
+		logger.Error("Failed to list API keys", "error", err)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list API keys", err))
+		return
+	}
+
+	// Return the keys without their secrets in the response
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey handles revoking one of the caller's organization's API keys
+func (ah *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	// Extract API key ID from the request parameters
+	keyID := c.Param("id")
+
+	// Call the API key service to revoke the key
+	key, err := ah.apiKeyService.RevokeAPIKey(c.Request.Context(), middleware.OrganizationID(c), keyID)
+	if err != nil {
+		logger.Error("Failed to revoke API key", "error", err, "apiKeyID", keyID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to revoke API key", err))
+		return
+	}
+
+	// Return the revoked key in the response
+	c.JSON(http.StatusOK, key)
+}
+
+// Human tasks:
+// - Add an endpoint to update a key's name and narrow its scopes
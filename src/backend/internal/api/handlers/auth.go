@@ -0,0 +1,225 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/auth"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// AuthHandler struct holds dependencies for login and session handlers
+type AuthHandler struct {
+	authService *auth.Service
+}
+
+// NewAuthHandler creates a new AuthHandler instance
+func NewAuthHandler(as *auth.Service) *AuthHandler {
+	return &AuthHandler{
+		authService: as,
+	}
+}
+
+// Login handles signing a user in with email and password
+func (ah *AuthHandler) Login(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the auth service to check the credentials and start a session
+	tokens, err := ah.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		respondAuthError(c, "Failed to log in", err)
+		return
+	}
+
+	// Return the token pair in the response
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh handles exchanging a refresh token for a new token pair
+func (ah *AuthHandler) Refresh(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the auth service to rotate the refresh token
+	tokens, err := ah.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		respondAuthError(c, "Failed to refresh token", err)
+		return
+	}
+
+	// Return the new token pair in the response
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout handles ending the session a refresh token belongs to
+func (ah *AuthHandler) Logout(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the auth service to revoke the session
+	if err := ah.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		respondAuthError(c, "Failed to log out", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeAllSessions handles ending every session of the authenticated user
+func (ah *AuthHandler) RevokeAllSessions(c *gin.Context) {
+	// Get the authenticated user from the context
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	// Call the auth service to revoke the user's sessions
+	if err := ah.authService.RevokeAllSessions(c.Request.Context(), user.ID.String()); err != nil {
+		logger.Error("Failed to revoke sessions", "error", err, "userID", user.ID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to revoke sessions", err))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// EnrollTOTP handles starting an authenticator app enrollment for the authenticated user
+func (ah *AuthHandler) EnrollTOTP(c *gin.Context) {
+	// Get the authenticated user from the context
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	enrollment, err := ah.authService.EnrollTOTP(c.Request.Context(), user)
+	if err != nil {
+		respondAuthError(c, "Failed to enroll authenticator", err)
+		return
+	}
+
+	// Return the secret and provisioning URI; they are not shown again
+	c.JSON(http.StatusCreated, enrollment)
+}
+
+// ConfirmTOTP handles completing an enrollment with a first authenticator code
+func (ah *AuthHandler) ConfirmTOTP(c *gin.Context) {
+	// Get the authenticated user from the context
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	// Parse and validate the request body
+	var req models.TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Return the recovery codes issued with the enrollment; they are not shown again
+	codes, err := ah.authService.ConfirmTOTP(c.Request.Context(), user, req.Code)
+	if err != nil {
+		respondAuthError(c, "Failed to confirm authenticator", err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// DisableTOTP handles removing the authenticated user's authenticator and recovery codes
+func (ah *AuthHandler) DisableTOTP(c *gin.Context) {
+	// Get the authenticated user from the context
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	if err := ah.authService.DisableTOTP(c.Request.Context(), user); err != nil {
+		respondAuthError(c, "Failed to disable authenticator", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes handles replacing the authenticated user's recovery codes
+func (ah *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	// Get the authenticated user from the context
+	user, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	codes, err := ah.authService.RegenerateRecoveryCodes(c.Request.Context(), user)
+	if err != nil {
+		respondAuthError(c, "Failed to regenerate recovery codes", err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// StepUp handles presenting a second factor and returns an access token that records it
+func (ah *AuthHandler) StepUp(c *gin.Context) {
+	// Get the authenticated user and token claims from the context
+	user, ok := middleware.UserFromContext(c)
+	claims, hasClaims := middleware.ClaimsFromContext(c)
+	if !ok || !hasClaims {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	// Parse and validate the request body
+	var req models.StepUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	token, err := ah.authService.StepUp(c.Request.Context(), claims, user, &req)
+	if err != nil {
+		respondAuthError(c, "Failed to step up", err)
+		return
+	}
+	c.JSON(http.StatusOK, token)
+}
+
+// JWKS handles publishing the public keys that verify access tokens
+func (ah *AuthHandler) JWKS(c *gin.Context) {
+	// Let downstream services cache the key set briefly so rotations propagate quickly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ah.authService.JWKS())
+}
+
+// respondAuthError writes authentication failures and other client errors with their own status and
+// anything else as a 500
+func respondAuthError(c *gin.Context, message string, err error) {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) && appErr.StatusCode >= 400 && appErr.StatusCode < 500 {
+		c.JSON(appErr.StatusCode, appErr)
+		return
+	}
+	logger.Error(message, "error", err)
+	c.JSON(http.StatusInternalServerError, errors.NewAPIError(message, err))
+}
+
+// Human tasks:
+// - Set the refresh token in an HttpOnly cookie for browser clients
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/contract"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// ContractHandler struct holds dependencies for smart-contract handlers
+type ContractHandler struct {
+	contractService *contract.Service
+}
+
+// NewContractHandler creates a new ContractHandler instance
+func NewContractHandler(cs *contract.Service) *ContractHandler {
+	return &ContractHandler{
+		contractService: cs,
+	}
+}
+
+// ReadContract handles read-only contract calls
+func (h *ContractHandler) ReadContract(c *gin.Context) {
+	// Parse and validate the contract call from the request body
+	var req models.ContractCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse contract call request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the contract service to run the call
+	result, err := h.contractService.ReadContract(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to read contract", "error", err, "contract", req.ContractAddress, "method", req.Method)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to read contract", err))
+		return
+	}
+
+	// Return the decoded outputs in the response
+	c.JSON(http.StatusOK, result)
+}
+
+// WriteContract handles state-changing contract calls, which become transactions
+func (h *ContractHandler) WriteContract(c *gin.Context) {
+	// Parse and validate the contract call from the request body
+	var req models.ContractCallRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse contract call request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the contract service to create the transaction
+	tx, err := h.contractService.WriteContract(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to write contract", "error", err, "contract", req.ContractAddress, "method", req.Method)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to write contract", err))
+		return
+	}
+
+	// Return the created transaction in the response
+	c.JSON(http.StatusCreated, tx)
+}
+
+// Human tasks:
+// TODO: Map revert errors to 422 responses
+// TODO: Add unit tests for each handler function
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/escrow"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// EscrowHandler struct holds dependencies for escrow handlers
+type EscrowHandler struct {
+	escrowService *escrow.Service
+}
+
+// NewEscrowHandler creates a new EscrowHandler instance
+func NewEscrowHandler(es *escrow.Service) *EscrowHandler {
+	return &EscrowHandler{
+		escrowService: es,
+	}
+}
+
+// CreateEscrow handles locking vault funds in a new escrow
+func (eh *EscrowHandler) CreateEscrow(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Parse and validate the request body
+	var req models.EscrowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse escrow request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the escrow service to create the escrow
+	created, err := eh.escrowService.CreateEscrow(c.Request.Context(), vaultID, &req)
+	if err != nil {
+		logger.Error("Failed to create escrow", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to create escrow", err))
+		return
+	}
+
+	// Return the created escrow in the response
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListEscrows handles listing the escrows funded by a vault
+func (eh *EscrowHandler) ListEscrows(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Call the escrow service to list the vault's escrows
+	escrows, err := eh.escrowService.ListEscrows(c.Request.Context(), vaultID)
+	if err != nil {
+		logger.Error("Failed to list escrows", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list escrows", err))
+		return
+	}
+
+	// Return the list of escrows in the response
+	c.JSON(http.StatusOK, escrows)
+}
+
+// GetEscrow handles retrieving a specific escrow
+func (eh *EscrowHandler) GetEscrow(c *gin.Context) {
+	// Extract escrow ID from the request parameters
+	escrowID := c.Param("escrowId")
+
+	// Call the escrow service to retrieve the escrow
+	found, err := eh.escrowService.GetEscrow(c.Request.Context(), escrowID)
+	if err != nil {
+		logger.Error("Failed to get escrow", "error", err, "escrowID", escrowID)
+		c.JSON(http.StatusNotFound, errors.NewAPIError("Escrow not found", err))
+		return
+	}
+
+	// Return the escrow details in the response
+	c.JSON(http.StatusOK, found)
+}
+
+// FinishEscrow handles releasing an escrow to its destination
+func (eh *EscrowHandler) FinishEscrow(c *gin.Context) {
+	// Extract escrow ID from the request parameters
+	escrowID := c.Param("escrowId")
+
+	// Call the escrow service to finish the escrow
+	finished, err := eh.escrowService.FinishEscrow(c.Request.Context(), escrowID)
+	if err != nil {
+		logger.Error("Failed to finish escrow", "error", err, "escrowID", escrowID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to finish escrow", err))
+		return
+	}
+
+	// Return the updated escrow in the response
+	c.JSON(http.StatusOK, finished)
+}
+
+// CancelEscrow handles returning an expired escrow to its owner
+func (eh *EscrowHandler) CancelEscrow(c *gin.Context) {
+	// Extract escrow ID from the request parameters
+	escrowID := c.Param("escrowId")
+
+	// Call the escrow service to cancel the escrow
+	cancelled, err := eh.escrowService.CancelEscrow(c.Request.Context(), escrowID)
+	if err != nil {
+		logger.Error("Failed to cancel escrow", "error", err, "escrowID", escrowID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to cancel escrow", err))
+		return
+	}
+
+	// Return the updated escrow in the response
+	c.JSON(http.StatusOK, cancelled)
+}
+
+// Human tasks:
+// - Add authentication and authorization checks
+// - Add unit tests for each handler function
+// - Implement proper HTTP status codes for different scenarios
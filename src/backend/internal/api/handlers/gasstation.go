@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/services/gasstation"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// GasStationHandler struct holds dependencies for gas station handlers
+type GasStationHandler struct {
+	gasStationService *gasstation.Service
+}
+
+// NewGasStationHandler creates a new GasStationHandler instance
+func NewGasStationHandler(gs *gasstation.Service) *GasStationHandler {
+	return &GasStationHandler{
+		gasStationService: gs,
+	}
+}
+
+// gasStationRequest is the payload accepted when configuring an organization's gas station
+type gasStationRequest struct {
+	VaultID string `json:"vault_id" binding:"required"`
+}
+
+// GetGasStation handles retrieving the vault that pays gas for an organization
+func (gh *GasStationHandler) GetGasStation(c *gin.Context) {
+	// Extract organization ID from the request parameters
+	organizationID := c.Param("id")
+
+	// Call the gas station service to look up the configured vault
+	vault, err := gh.gasStationService.GetGasStationVault(c.Request.Context(), organizationID)
+	if err != nil {
+		logger.Error("Failed to get gas station", "error", err, "organizationID", organizationID)
+		c.JSON(http.StatusNotFound, errors.NewAPIError("Gas station not found", err))
+		return
+	}
+
+	// Return the gas station vault in the response
+	c.JSON(http.StatusOK, vault)
+}
+
+// SetGasStation handles configuring the vault that pays gas for an organization
+func (gh *GasStationHandler) SetGasStation(c *gin.Context) {
+	// Extract organization ID from the request parameters
+	organizationID := c.Param("id")
+
+	// Parse and validate the request body
+	var req gasStationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse gas station request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the gas station service to update the organization
+	org, err := gh.gasStationService.SetGasStationVault(c.Request.Context(), organizationID, req.VaultID)
+	if err != nil {
+		logger.Error("Failed to set gas station", "error", err, "organizationID", organizationID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to set gas station", err))
+		return
+	}
+
+	// Return the updated organization in the response
+	c.JSON(http.StatusOK, org)
+}
+
+// Human tasks:
+// - Expose the gas station balance and pending top-ups
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/nft"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// NFTHandler struct holds dependencies for NFT custody handlers
+type NFTHandler struct {
+	nftService *nft.Service
+}
+
+// NewNFTHandler creates a new NFTHandler instance
+func NewNFTHandler(ns *nft.Service) *NFTHandler {
+	return &NFTHandler{
+		nftService: ns,
+	}
+}
+
+// ListHoldings handles listing the NFTs held by a vault
+func (nh *NFTHandler) ListHoldings(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Call the NFT service to list the vault's holdings
+	holdings, err := nh.nftService.ListHoldings(c.Request.Context(), vaultID)
+	if err != nil {
+		logger.Error("Failed to list NFT holdings", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list NFT holdings", err))
+		return
+	}
+
+	// Return the holdings with token IDs and metadata URIs in the response
+	c.JSON(http.StatusOK, holdings)
+}
+
+// TransferNFT handles transferring an NFT out of a vault
+func (nh *NFTHandler) TransferNFT(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Parse and validate the request body
+	var req models.NFTTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse NFT transfer request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the NFT service to create the transfer transaction
+	tx, err := nh.nftService.TransferNFT(c.Request.Context(), vaultID, &req)
+	if err != nil {
+		logger.Error("Failed to transfer NFT", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to transfer NFT", err))
+		return
+	}
+
+	// Return the pending transaction in the response
+	c.JSON(http.StatusCreated, tx)
+}
+
+// Human tasks:
+// - Add filtering of holdings by contract address
+// - Add request validation middleware for NFT routes
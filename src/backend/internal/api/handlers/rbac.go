@@ -0,0 +1,141 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/rbac"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// RBACHandler struct holds dependencies for role, role binding and audit handlers
+type RBACHandler struct {
+	rbacService *rbac.Service
+}
+
+// NewRBACHandler creates a new RBACHandler instance
+func NewRBACHandler(rs *rbac.Service) *RBACHandler {
+	return &RBACHandler{
+		rbacService: rs,
+	}
+}
+
+// CreateRole handles defining a custom role in the caller's organization
+func (rh *RBACHandler) CreateRole(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the RBAC service to create the role
+	role, err := rh.rbacService.CreateRole(c.Request.Context(), middleware.OrganizationID(c), &req)
+	if err != nil {
+		logger.Error("Failed to create role", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to create role", err))
+		return
+	}
+
+	// Return the created role in the response
+	c.JSON(http.StatusCreated, role)
+}
+
+// ListRoles handles listing the built-in and custom roles of the caller's organization
+func (rh *RBACHandler) ListRoles(c *gin.Context) {
+	roles, err := rh.rbacService.ListRoles(c.Request.Context(), middleware.OrganizationID(c))
+	if err != nil {
+		logger.Error("Failed to list roles", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list roles", err))
+		return
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// DeleteRole handles deleting a custom role
+func (rh *RBACHandler) DeleteRole(c *gin.Context) {
+	// Extract role ID from the request parameters
+	roleID := c.Param("id")
+
+	if err := rh.rbacService.DeleteRole(c.Request.Context(), middleware.OrganizationID(c), roleID); err != nil {
+		logger.Error("Failed to delete role", "error", err, "roleID", roleID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to delete role", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// BindRole handles granting a user a role across the organization or on one vault
+func (rh *RBACHandler) BindRole(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.RoleBindingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the RBAC service to create the binding
+	binding, err := rh.rbacService.BindRole(c.Request.Context(), middleware.OrganizationID(c), &req)
+	if err != nil {
+		logger.Error("Failed to bind role", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to bind role", err))
+		return
+	}
+
+	// Return the created binding in the response
+	c.JSON(http.StatusCreated, binding)
+}
+
+// ListRoleBindings handles listing role bindings, optionally filtered by the user_id query parameter
+func (rh *RBACHandler) ListRoleBindings(c *gin.Context) {
+	bindings, err := rh.rbacService.ListRoleBindings(c.Request.Context(), middleware.OrganizationID(c), c.Query("user_id"))
+	if err != nil {
+		logger.Error("Failed to list role bindings", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list role bindings", err))
+		return
+	}
+	c.JSON(http.StatusOK, bindings)
+}
+
+// UnbindRole handles deleting a role binding
+func (rh *RBACHandler) UnbindRole(c *gin.Context) {
+	// Extract binding ID from the request parameters
+	bindingID := c.Param("id")
+
+	if err := rh.rbacService.UnbindRole(c.Request.Context(), middleware.OrganizationID(c), bindingID); err != nil {
+		logger.Error("Failed to unbind role", "error", err, "bindingID", bindingID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to unbind role", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListAuditEvents handles listing the organization's most recent audit events
+func (rh *RBACHandler) ListAuditEvents(c *gin.Context) {
+	// Parse the optional limit query parameter
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+
+	events, err := rh.rbacService.ListAuditEvents(c.Request.Context(), middleware.OrganizationID(c), limit)
+	if err != nil {
+		logger.Error("Failed to list audit events", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list audit events", err))
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// statusOf returns the HTTP status carried by a service error, or 500 for anything else
+func statusOf(err error) int {
+	var appErr *errors.AppError
+	if errors.As(err, &appErr) && appErr.StatusCode != 0 {
+		return appErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
+
+// Human tasks:
+// - Add an endpoint to update a custom role's permissions
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/realtime"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+const (
+	// writeWait bounds how long a write to a client may block
+	writeWait = 10 * time.Second
+
+	// maxClientMessage bounds the size of a client's subscription messages
+	maxClientMessage = 4096
+)
+
+// upgrader accepts WebSocket connections from any origin. Connections authenticate with a bearer token or
+// API key rather than cookies, so other sites cannot open one on a user's behalf
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// RealtimeHandler struct holds dependencies for real-time event stream handlers. Streams outlive the request
+// that opened them, so the caller's credentials are resolved again on every heartbeat
+type RealtimeHandler struct {
+	realtimeService *realtime.Service
+	authorizer      middleware.Authorizer
+	apiKeys         middleware.APIKeyAuthenticator
+	tokens          middleware.AccessTokenAuthenticator
+}
+
+// NewRealtimeHandler creates a new RealtimeHandler instance
+func NewRealtimeHandler(rs *realtime.Service, authorizer middleware.Authorizer, apiKeys middleware.APIKeyAuthenticator, tokens middleware.AccessTokenAuthenticator) *RealtimeHandler {
+	return &RealtimeHandler{
+		realtimeService: rs,
+		authorizer:      authorizer,
+		apiKeys:         apiKeys,
+		tokens:          tokens,
+	}
+}
+
+// WebSocket handles a WebSocket connection streaming the caller's organization's events. Clients pick
+// channels with ?channels= and subscribe or unsubscribe messages, and resume after reconnecting with
+// ?last_event_id= set to the ID of the last event frame they received
+func (rh *RealtimeHandler) WebSocket(c *gin.Context) {
+	// Subscribe before upgrading, so failures get an HTTP error
+	subscriber, ok := rh.subscribe(c, c.Query("last_event_id"))
+	if !ok {
+		return
+	}
+	defer rh.realtimeService.Disconnect(subscriber)
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded
+		logger.Error("Failed to upgrade WebSocket connection", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	// Frames come from this goroutine and the request reader, so writes are serialized
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	var writeMu sync.Mutex
+	send := func(frame *models.RealtimeFrame) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(frame)
+	}
+
+	// Read subscription changes until the client goes away or misses its heartbeats
+	heartbeat := rh.realtimeService.HeartbeatInterval()
+	go func() {
+		defer cancel()
+		rh.readRequests(c, conn, subscriber, send, heartbeat)
+	}()
+
+	// Ping the client so dead connections are noticed and proxies keep live ones open, closing the stream
+	// once the caller may no longer read every channel it is subscribed to
+	go func() {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := rh.reauthorize(c, subscriber); err != nil {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "not authorized"), time.Now().Add(writeWait))
+					cancel()
+					return
+				}
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	// Confirm the subscription, asking the client to reload its state if missed events were lost
+	if err := send(&models.RealtimeFrame{Type: models.RealtimeFrameSubscribed, Channels: subscriber.Channels()}); err != nil {
+		return
+	}
+	if subscriber.Resync() {
+		if err := send(&models.RealtimeFrame{Type: models.RealtimeFrameResync}); err != nil {
+			return
+		}
+	}
+
+	for {
+		message, err := subscriber.Next(ctx)
+		if err != nil {
+			// Slow clients are told to reconnect, resuming from their last event
+			if errors.Is(err, realtime.ErrSlowConsumer) {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "slow consumer"), time.Now().Add(writeWait))
+			}
+			return
+		}
+		if err := send(eventFrame(message)); err != nil {
+			return
+		}
+	}
+}
+
+// EventStream streams the caller's organization's events as Server-Sent Events, for clients behind proxies
+// that break WebSockets. Each SSE event carries the same frame as the WebSocket, named by its type; clients
+// pick channels with ?channels= and resume with the Last-Event-ID header, or ?last_event_id= where they
+// cannot set headers
+func (rh *RealtimeHandler) EventStream(c *gin.Context) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	subscriber, ok := rh.subscribe(c, lastEventID)
+	if !ok {
+		return
+	}
+	defer rh.realtimeService.Disconnect(subscriber)
+
+	// Disable caching and proxy buffering so events reach the client as they are written
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// Confirm the subscription, asking the client to reload its state if missed events were lost
+	if err := writeServerSentEvent(c, &models.RealtimeFrame{Type: models.RealtimeFrameSubscribed, Channels: subscriber.Channels()}); err != nil {
+		return
+	}
+	if subscriber.Resync() {
+		if err := writeServerSentEvent(c, &models.RealtimeFrame{Type: models.RealtimeFrameResync}); err != nil {
+			return
+		}
+	}
+
+	// Wait for events a heartbeat at a time, writing a comment whenever none arrived to keep proxies open
+	ctx := c.Request.Context()
+	heartbeat := rh.realtimeService.HeartbeatInterval()
+	authorizedAt := time.Now()
+	for {
+		// End the stream once the caller may no longer read every channel it is subscribed to
+		if time.Since(authorizedAt) >= heartbeat {
+			if err := rh.reauthorize(c, subscriber); err != nil {
+				writeServerSentEvent(c, &models.RealtimeFrame{Type: models.RealtimeFrameError, Error: err.Error()})
+				return
+			}
+			authorizedAt = time.Now()
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, heartbeat)
+		message, err := subscriber.Next(waitCtx)
+		cancel()
+		switch {
+		case err == nil:
+			err = writeServerSentEvent(c, eventFrame(message))
+		case err == context.DeadlineExceeded && ctx.Err() == nil:
+			_, err = c.Writer.WriteString(": heartbeat\n\n")
+			c.Writer.Flush()
+		case errors.Is(err, realtime.ErrSlowConsumer):
+			// Slow clients are told why before the stream ends; EventSource reconnects with its last event ID
+			writeServerSentEvent(c, &models.RealtimeFrame{Type: models.RealtimeFrameError, Error: err.Error()})
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// subscribe authorizes the requested channels and subscribes to them, replaying events after lastEventID.
+// It responds with the error and returns false on failure
+func (rh *RealtimeHandler) subscribe(c *gin.Context, lastEventID string) (*realtime.Subscriber, bool) {
+	channels := splitChannels(c.Query("channels"))
+	if err := rh.authorizeChannels(c, channels); err != nil {
+		logger.Error("Failed to authorize real-time channels", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to subscribe", err))
+		return nil, false
+	}
+	subscriber, err := rh.realtimeService.Subscribe(c.Request.Context(), middleware.OrganizationID(c), channels, lastEventID)
+	if err != nil {
+		logger.Error("Failed to subscribe to real-time events", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to subscribe", err))
+		return nil, false
+	}
+	return subscriber, true
+}
+
+// readRequests applies a client's subscribe and unsubscribe messages, replying to each, and keeps the
+// connection's read deadline a couple of heartbeats ahead while pongs arrive
+func (rh *RealtimeHandler) readRequests(c *gin.Context, conn *websocket.Conn, subscriber *realtime.Subscriber, send func(*models.RealtimeFrame) error, heartbeat time.Duration) {
+	conn.SetReadLimit(maxClientMessage)
+	conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req models.RealtimeRequest
+		if err := json.Unmarshal(raw, &req); err != nil {
+			if send(&models.RealtimeFrame{Type: models.RealtimeFrameError, Error: "invalid message"}) != nil {
+				return
+			}
+			continue
+		}
+		if send(rh.applyRequest(c, subscriber, &req)) != nil {
+			return
+		}
+	}
+}
+
+// applyRequest changes a subscription and returns the frame answering the request. Subscribed and
+// unsubscribed frames list every channel the client is now subscribed to
+func (rh *RealtimeHandler) applyRequest(c *gin.Context, subscriber *realtime.Subscriber, req *models.RealtimeRequest) *models.RealtimeFrame {
+	switch req.Action {
+	case models.RealtimeActionSubscribe:
+		if err := rh.authorizeChannels(c, req.Channels); err != nil {
+			return &models.RealtimeFrame{Type: models.RealtimeFrameError, Error: err.Error()}
+		}
+		if err := subscriber.Subscribe(req.Channels); err != nil {
+			return &models.RealtimeFrame{Type: models.RealtimeFrameError, Error: err.Error()}
+		}
+		return &models.RealtimeFrame{Type: models.RealtimeFrameSubscribed, Channels: subscriber.Channels()}
+	case models.RealtimeActionUnsubscribe:
+		subscriber.Unsubscribe(req.Channels)
+		return &models.RealtimeFrame{Type: models.RealtimeFrameUnsubscribed, Channels: subscriber.Channels()}
+	}
+	return &models.RealtimeFrame{Type: models.RealtimeFrameError, Error: "unknown action: " + req.Action}
+}
+
+// reauthorize resolves the caller's credentials again and checks they still grant every subscribed channel,
+// so expired tokens and revoked keys, sessions and role bindings end a stream within a heartbeat
+func (rh *RealtimeHandler) reauthorize(c *gin.Context, subscriber *realtime.Subscriber) error {
+	if err := middleware.Reauthenticate(c, rh.apiKeys, rh.tokens); err != nil {
+		return err
+	}
+	return rh.authorizeChannels(c, subscriber.Channels())
+}
+
+// authorizeChannels checks the caller holds the read permission of each channel, on its vault for vault
+// channels
+func (rh *RealtimeHandler) authorizeChannels(c *gin.Context, channels []string) error {
+	for _, channel := range channels {
+		permission, vaultID, err := realtime.ChannelPermission(channel)
+		if err != nil {
+			return err
+		}
+		allowed, err := middleware.Permitted(c, rh.authorizer, permission, vaultID)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.NewForbiddenError("Missing the " + permission + " permission for channel " + channel)
+		}
+	}
+	return nil
+}
+
+// eventFrame wraps an event log message for a client
+func eventFrame(message *realtime.Message) *models.RealtimeFrame {
+	return &models.RealtimeFrame{
+		Type:     models.RealtimeFrameEvent,
+		ID:       message.ID,
+		Channels: message.Channels,
+		Event:    message.Event,
+	}
+}
+
+// writeServerSentEvent writes a frame as an SSE event named by its type, with the frame's event ID if it
+// has one, and flushes it to the client
+func writeServerSentEvent(c *gin.Context, frame *models.RealtimeFrame) error {
+	raw, err := json.Marshal(frame)
+	if err != nil {
+		return err
+	}
+	var event strings.Builder
+	if frame.ID != "" {
+		event.WriteString("id: " + frame.ID + "\n")
+	}
+	event.WriteString("event: " + frame.Type + "\n")
+	event.WriteString("data: " + string(raw) + "\n\n")
+	if _, err := c.Writer.WriteString(event.String()); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// splitChannels parses a comma-separated channel list
+func splitChannels(raw string) []string {
+	var channels []string
+	for _, channel := range strings.Split(raw, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Human tasks:
+// - Accept access tokens through the Sec-WebSocket-Protocol header for browser clients
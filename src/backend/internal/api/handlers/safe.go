@@ -0,0 +1,167 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/safe"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// SafeHandler struct holds dependencies for Safe vault handlers
+type SafeHandler struct {
+	safeService *safe.Service
+}
+
+// NewSafeHandler creates a new SafeHandler instance
+func NewSafeHandler(ss *safe.Service) *SafeHandler {
+	return &SafeHandler{
+		safeService: ss,
+	}
+}
+
+// DeploySafe handles creating a Safe vault by deploying a new Safe contract
+func (sh *SafeHandler) DeploySafe(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.SafeDeployRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse Safe deploy request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the Safe service to create the vault and its deployment transaction
+	vault, err := sh.safeService.DeploySafe(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to deploy Safe", "error", err)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to deploy Safe", err))
+		return
+	}
+
+	// The vault is returned while its deployment is pending
+	c.JSON(http.StatusAccepted, vault)
+}
+
+// AttachSafe handles creating a Safe vault for an already deployed Safe
+func (sh *SafeHandler) AttachSafe(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.SafeAttachRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse Safe attach request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the Safe service to verify and attach the Safe
+	vault, err := sh.safeService.AttachSafe(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to attach Safe", "error", err)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to attach Safe", err))
+		return
+	}
+
+	// Return the created vault in the response
+	c.JSON(http.StatusCreated, vault)
+}
+
+// ConfirmDeployment handles activating a Safe vault once its deployment is mined
+func (sh *SafeHandler) ConfirmDeployment(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Call the Safe service to read the deployment receipt
+	vault, err := sh.safeService.ConfirmDeployment(c.Request.Context(), vaultID)
+	if err != nil {
+		logger.Error("Failed to confirm Safe deployment", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to confirm Safe deployment", err))
+		return
+	}
+
+	// Return the vault in the response
+	c.JSON(http.StatusOK, vault)
+}
+
+// ProposeTransaction handles proposing a Safe transaction and requesting owner signatures
+func (sh *SafeHandler) ProposeTransaction(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Parse and validate the request body
+	var req models.SafeTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse Safe transaction request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the Safe service to propose the transaction
+	safeTx, err := sh.safeService.ProposeTransaction(c.Request.Context(), vaultID, &req)
+	if err != nil {
+		logger.Error("Failed to propose Safe transaction", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to propose Safe transaction", err))
+		return
+	}
+
+	// Return the proposed transaction in the response
+	c.JSON(http.StatusCreated, safeTx)
+}
+
+// ExecuteTransaction handles executing a Safe transaction once the owner threshold is met
+func (sh *SafeHandler) ExecuteTransaction(c *gin.Context) {
+	// Extract vault and Safe transaction IDs from the request parameters
+	vaultID := c.Param("id")
+	safeTxID := c.Param("safeTxId")
+
+	// Call the Safe service to execute the transaction through the relayer
+	safeTx, err := sh.safeService.ExecuteTransaction(c.Request.Context(), vaultID, safeTxID)
+	if err != nil {
+		logger.Error("Failed to execute Safe transaction", "error", err, "vaultID", vaultID, "safeTxID", safeTxID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to execute Safe transaction", err))
+		return
+	}
+
+	// Return the executing transaction in the response
+	c.JSON(http.StatusAccepted, safeTx)
+}
+
+// ConfirmExecution handles recording the outcome of an executing Safe transaction
+func (sh *SafeHandler) ConfirmExecution(c *gin.Context) {
+	// Extract vault and Safe transaction IDs from the request parameters
+	vaultID := c.Param("id")
+	safeTxID := c.Param("safeTxId")
+
+	// Call the Safe service to read the execution receipt
+	safeTx, err := sh.safeService.ConfirmExecution(c.Request.Context(), vaultID, safeTxID)
+	if err != nil {
+		logger.Error("Failed to confirm Safe transaction", "error", err, "vaultID", vaultID, "safeTxID", safeTxID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to confirm Safe transaction", err))
+		return
+	}
+
+	// Return the transaction in the response
+	c.JSON(http.StatusOK, safeTx)
+}
+
+// CancelTransaction handles cancelling a pending Safe transaction
+func (sh *SafeHandler) CancelTransaction(c *gin.Context) {
+	// Extract vault and Safe transaction IDs from the request parameters
+	vaultID := c.Param("id")
+	safeTxID := c.Param("safeTxId")
+
+	// Call the Safe service to cancel the transaction and release or reject its nonce
+	safeTx, err := sh.safeService.CancelTransaction(c.Request.Context(), vaultID, safeTxID)
+	if err != nil {
+		logger.Error("Failed to cancel Safe transaction", "error", err, "vaultID", vaultID, "safeTxID", safeTxID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to cancel Safe transaction", err))
+		return
+	}
+
+	// Return the cancelled transaction, or the rejection that must execute in its place
+	c.JSON(http.StatusOK, safeTx)
+}
+
+// Human tasks:
+// - Add an endpoint listing a Safe's pending transactions
+// - Add request validation middleware for Safe routes
@@ -4,21 +4,25 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/services/signature"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
 // SignatureHandler struct holds dependencies for signature handlers
 type SignatureHandler struct {
 	signatureService *signature.Service
+	authorizer       middleware.Authorizer
 }
 
 // NewSignatureHandler creates a new SignatureHandler instance
-func NewSignatureHandler(ss *signature.Service) *SignatureHandler {
+func NewSignatureHandler(ss *signature.Service, authorizer middleware.Authorizer) *SignatureHandler {
 	return &SignatureHandler{
 		signatureService: ss,
+		authorizer:       authorizer,
 	}
 }
 
@@ -32,6 +36,11 @@ func (sh *SignatureHandler) RequestSignature(c *gin.Context) {
 		return
 	}
 
+	// The vault only becomes known from the body, so vault bindings are checked here rather than on the route
+	if !middleware.AuthorizeVault(c, sh.authorizer, models.PermissionSignRequest, req.VaultID.String()) {
+		return
+	}
+
 	// Call the signature service to initiate a signature request
 	result, err := sh.signatureService.RequestSignature(c.Request.Context(), req)
 	if err != nil {
@@ -65,29 +74,31 @@ func (sh *SignatureHandler) GetSignatureStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, status)
 }
 
-// ListSignatureRequests handles HTTP requests for listing signature requests
+// ListSignatureRequests handles listing a page of signature requests, with the filters, sort and cursor
+// given as query parameters
 func (sh *SignatureHandler) ListSignatureRequests(c *gin.Context) {
-	// Extract pagination and filtering parameters from the request
-	page := c.DefaultQuery("page", "1")
-	limit := c.DefaultQuery("limit", "10")
-	status := c.Query("status")
+	// Parse pagination, sorting and filtering parameters from the request
+	opts, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid list parameters", err))
+		return
+	}
 
 	// Call the signature service to list signature requests
-	requests, err := sh.signatureService.ListSignatureRequests(c.Request.Context(), page, limit, status)
+	page, err := sh.signatureService.ListSignatureRequests(c.Request.Context(), opts)
 	if err != nil {
 		logger.Error("Failed to list signature requests", "error", err)
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list signature requests", err))
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list signature requests", err))
 		return
 	}
 
-	// Return the list of signature requests in the response
-	c.JSON(http.StatusOK, requests)
+	// Return the page of signature requests in the response
+	c.JSON(http.StatusOK, page)
 }
 
 // Human tasks:
 // - Implement input validation for all handler functions
 // - Add proper error handling and logging for each handler
-// - Implement pagination for the ListSignatureRequests handler
 // - Add authentication and authorization checks
 // - Implement rate limiting for API endpoints
 // - Add unit tests for each handler function
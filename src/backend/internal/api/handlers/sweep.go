@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/services/sweep"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// SweepHandler struct holds dependencies for deposit sweeping handlers
+type SweepHandler struct {
+	sweepService *sweep.Service
+}
+
+// NewSweepHandler creates a new SweepHandler instance
+func NewSweepHandler(ss *sweep.Service) *SweepHandler {
+	return &SweepHandler{
+		sweepService: ss,
+	}
+}
+
+// SweepVault handles consolidating a vault's deposit addresses into its main address outside the schedule
+func (sh *SweepHandler) SweepVault(c *gin.Context) {
+	// Extract vault ID from the request parameters
+	vaultID := c.Param("id")
+
+	// Call the sweep service to sweep the vault's deposit addresses
+	result, err := sh.sweepService.SweepVault(c.Request.Context(), vaultID)
+	if err != nil {
+		logger.Error("Failed to sweep vault", "error", err, "vaultID", vaultID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to sweep vault", err))
+		return
+	}
+
+	// Return the sweep transactions and skipped addresses in the response
+	c.JSON(http.StatusOK, result)
+}
+
+// Human tasks:
+// - Add a dry-run mode that reports what would be swept
+// - Restrict manual sweeps to vault administrators
@@ -4,21 +4,26 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/services/transaction"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
 // TransactionHandler struct holds dependencies for transaction handlers
 type TransactionHandler struct {
 	transactionService *transactionService.Service
+	authorizer         middleware.Authorizer
 }
 
 // NewTransactionHandler creates a new TransactionHandler instance
-func NewTransactionHandler(ts *transactionService.Service) *TransactionHandler {
+func NewTransactionHandler(ts *transactionService.Service, authorizer middleware.Authorizer) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: ts,
+		authorizer:         authorizer,
 	}
 }
 
@@ -32,8 +37,25 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 		return
 	}
 
+	// The vault only becomes known from the body, so vault bindings are checked here rather than on the route
+	if !middleware.AuthorizeVault(c, h.authorizer, models.PermissionTxWrite, txRequest.VaultID.String()) {
+		return
+	}
+
+	// In simulate mode, preview the transaction without persisting or submitting it
+	if txRequest.Simulate {
+		result, err := h.transactionService.SimulateTransaction(c.Request.Context(), txRequest.ToTransaction())
+		if err != nil {
+			logger.Error("Failed to simulate transaction", "error", err)
+			c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to simulate transaction", err))
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
 	// Call the transaction service to create a new transaction
-	tx, err := h.transactionService.CreateTransaction(c.Request.Context(), txRequest)
+	tx, err := h.transactionService.CreateTransaction(c.Request.Context(), txRequest.ToTransaction())
 	if err != nil {
 		logger.Error("Failed to create transaction", "error", err)
 		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to create transaction", err))
@@ -61,23 +83,26 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	c.JSON(http.StatusOK, tx)
 }
 
-// ListTransactions handles listing transactions
+// ListTransactions handles listing a page of transactions, with the filters, sort and cursor given as query
+// parameters
 func (h *TransactionHandler) ListTransactions(c *gin.Context) {
-	// Extract pagination and filtering parameters from the request
-	limit := c.DefaultQuery("limit", "10")
-	offset := c.DefaultQuery("offset", "0")
-	status := c.Query("status")
+	// Parse pagination, sorting and filtering parameters from the request
+	opts, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid list parameters", err))
+		return
+	}
 
 	// Call the transaction service to list transactions
-	txs, err := h.transactionService.ListTransactions(c.Request.Context(), limit, offset, status)
+	page, err := h.transactionService.ListTransactions(c.Request.Context(), opts)
 	if err != nil {
 		logger.Error("Failed to list transactions", "error", err)
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list transactions", err))
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list transactions", err))
 		return
 	}
 
-	// Return the list of transactions in the response
-	c.JSON(http.StatusOK, txs)
+	// Return the page of transactions in the response
+	c.JSON(http.StatusOK, page)
 }
 
 // UpdateTransactionStatus handles updating the status of a transaction
@@ -105,10 +130,68 @@ func (h *TransactionHandler) UpdateTransactionStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, tx)
 }
 
+// ExportTransaction handles exporting a cold-storage transaction for an offline signer, as JSON or as QR text
+func (h *TransactionHandler) ExportTransaction(c *gin.Context) {
+	// Extract transaction ID from the request parameters
+	txID := c.Param("id")
+
+	// Call the transaction service to build the unsigned payload
+	payload, err := h.transactionService.ExportTransaction(c.Request.Context(), txID)
+	if err != nil {
+		logger.Error("Failed to export transaction", "error", err, "txID", txID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to export transaction", err))
+		return
+	}
+
+	// Return the payload as compact QR text when requested, otherwise as a JSON file
+	if c.Query("format") == "qr" {
+		text, err := payload.QR()
+		if err != nil {
+			logger.Error("Failed to encode transaction QR payload", "error", err, "txID", txID)
+			c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to export transaction", err))
+			return
+		}
+		c.String(http.StatusOK, text)
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=\""+txID+".unsigned.json\"")
+	c.JSON(http.StatusOK, payload)
+}
+
+// BroadcastTransaction handles importing an offline-signed payload, as JSON or QR text, and broadcasting it
+func (h *TransactionHandler) BroadcastTransaction(c *gin.Context) {
+	// Extract transaction ID from the request parameters
+	txID := c.Param("id")
+
+	// Parse the signed payload and check its integrity
+	raw, err := c.GetRawData()
+	if err != nil {
+		logger.Error("Failed to read signed payload", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+	payload, err := offline.Parse(raw)
+	if err != nil {
+		logger.Error("Failed to parse signed payload", "error", err, "txID", txID)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid signed payload", err))
+		return
+	}
+
+	// Call the transaction service to verify and broadcast the signed transaction
+	tx, err := h.transactionService.BroadcastTransaction(c.Request.Context(), txID, payload)
+	if err != nil {
+		logger.Error("Failed to broadcast transaction", "error", err, "txID", txID)
+		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to broadcast transaction", err))
+		return
+	}
+
+	// Return the submitted transaction in the response
+	c.JSON(http.StatusOK, tx)
+}
+
 // Human tasks:
 // TODO: Implement input validation for all handler functions
 // TODO: Add proper error handling and logging for each handler
-// TODO: Implement pagination for the ListTransactions handler
 // TODO: Add authentication and authorization checks
 // TODO: Implement rate limiting for API endpoints
 // TODO: Add unit tests for each handler function
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/user"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// UserHandler struct holds dependencies for profile, member and invitation handlers
+type UserHandler struct {
+	userService *user.Service
+}
+
+// NewUserHandler creates a new UserHandler instance
+func NewUserHandler(us *user.Service) *UserHandler {
+	return &UserHandler{
+		userService: us,
+	}
+}
+
+// Me handles returning the signed-in user with their memberships and effective permissions
+func (uh *UserHandler) Me(c *gin.Context) {
+	// Get the authenticated user from the context; API keys do not belong to a user
+	u, ok := middleware.UserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, errors.NewUnauthorizedError("User not authenticated"))
+		return
+	}
+
+	profile, err := uh.userService.Profile(c.Request.Context(), u)
+	if err != nil {
+		logger.Error("Failed to get profile", "error", err, "userID", u.ID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to get profile", err))
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// InviteUser handles inviting someone by email to the caller's organization
+func (uh *UserHandler) InviteUser(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.InvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Record the inviting user when the request was not made with an API key
+	var invitedBy *uuid.UUID
+	if u, ok := middleware.UserFromContext(c); ok {
+		invitedBy = &u.ID
+	}
+
+	// Call the user service to create and email the invitation
+	invitation, err := uh.userService.InviteUser(c.Request.Context(), middleware.OrganizationID(c), invitedBy, &req)
+	if err != nil {
+		logger.Error("Failed to invite user", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to invite user", err))
+		return
+	}
+
+	// Return the created invitation in the response; its token is only sent by email
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations handles listing the caller's organization's invitations
+func (uh *UserHandler) ListInvitations(c *gin.Context) {
+	invitations, err := uh.userService.ListInvitations(c.Request.Context(), middleware.OrganizationID(c))
+	if err != nil {
+		logger.Error("Failed to list invitations", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list invitations", err))
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation handles deleting a pending invitation
+func (uh *UserHandler) RevokeInvitation(c *gin.Context) {
+	// Extract invitation ID from the request parameters
+	invitationID := c.Param("id")
+
+	if err := uh.userService.RevokeInvitation(c.Request.Context(), middleware.OrganizationID(c), invitationID); err != nil {
+		logger.Error("Failed to revoke invitation", "error", err, "invitationID", invitationID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to revoke invitation", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation handles redeeming an emailed invitation token; the invitee is not signed in
+func (uh *UserHandler) AcceptInvitation(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	membership, err := uh.userService.AcceptInvitation(c.Request.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to accept invitation", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to accept invitation", err))
+		return
+	}
+	c.JSON(http.StatusCreated, membership)
+}
+
+// ListMembers handles listing the members of the caller's organization
+func (uh *UserHandler) ListMembers(c *gin.Context) {
+	members, err := uh.userService.ListMembers(c.Request.Context(), middleware.OrganizationID(c))
+	if err != nil {
+		logger.Error("Failed to list members", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list members", err))
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// SetMemberRole handles changing a member's role
+func (uh *UserHandler) SetMemberRole(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.MembershipRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Extract user ID from the request parameters
+	userID := c.Param("userId")
+
+	membership, err := uh.userService.SetMemberRole(c.Request.Context(), middleware.OrganizationID(c), userID, &req)
+	if err != nil {
+		logger.Error("Failed to set member role", "error", err, "userID", userID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to set member role", err))
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// DeactivateMember handles removing a member's access to the caller's organization
+func (uh *UserHandler) DeactivateMember(c *gin.Context) {
+	// Extract user ID from the request parameters
+	userID := c.Param("userId")
+
+	membership, err := uh.userService.DeactivateMember(c.Request.Context(), middleware.OrganizationID(c), userID)
+	if err != nil {
+		logger.Error("Failed to deactivate member", "error", err, "userID", userID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to deactivate member", err))
+		return
+	}
+	c.JSON(http.StatusOK, membership)
+}
+
+// Human tasks:
+// - Rate limit invitation acceptance per client IP
+// - Add an endpoint to resend a pending invitation
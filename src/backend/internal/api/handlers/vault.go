@@ -8,6 +8,7 @@ import (
 	"github.com/your-repo/blockchain-integration-service/internal/services/vault"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
 // VaultHandler struct holds dependencies for vault handlers
@@ -61,22 +62,25 @@ func (vh *VaultHandler) GetVault(c *gin.Context) {
 	c.JSON(http.StatusOK, vault)
 }
 
-// ListVaults handles listing all vaults
+// ListVaults handles listing a page of vaults, with the filters, sort and cursor given as query parameters
 func (vh *VaultHandler) ListVaults(c *gin.Context) {
-	// Extract pagination parameters from the request
-	page := c.DefaultQuery("page", "1")
-	pageSize := c.DefaultQuery("pageSize", "10")
+	// Parse pagination, sorting and filtering parameters from the request
+	opts, err := pagination.Parse(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid list parameters", err))
+		return
+	}
 
 	// Call the vault service to list vaults
-	vaults, err := vh.vaultService.ListVaults(c.Request.Context(), page, pageSize)
+	page, err := vh.vaultService.ListVaults(c.Request.Context(), opts)
 	if err != nil {
 		logger.Error("Failed to list vaults", "error", err)
-		c.JSON(http.StatusInternalServerError, errors.NewAPIError("Failed to list vaults", err))
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list vaults", err))
 		return
 	}
 
-	// Return the list of vaults in the response
-	c.JSON(http.StatusOK, vaults)
+	// Return the page of vaults in the response
+	c.JSON(http.StatusOK, page)
 }
 
 // UpdateVault handles updating a vault
@@ -124,7 +128,6 @@ func (vh *VaultHandler) DeleteVault(c *gin.Context) {
 // Human tasks:
 // - Implement input validation for all handler functions
 // - Add proper error handling and logging for each handler
-// - Implement pagination for the ListVaults handler
 // - Add authentication and authorization checks
 // - Implement rate limiting for API endpoints
 // - Add unit tests for each handler function
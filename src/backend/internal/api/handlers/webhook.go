@@ -0,0 +1,133 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/webhook"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// WebhookHandler struct holds dependencies for webhook endpoint handlers
+type WebhookHandler struct {
+	webhookService *webhook.Service
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(ws *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: ws,
+	}
+}
+
+// CreateEndpoint handles registering a webhook endpoint for the caller's organization
+func (wh *WebhookHandler) CreateEndpoint(c *gin.Context) {
+	// Parse and validate the request body
+	var req models.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse webhook endpoint request", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	// Call the webhook service to register the endpoint in the caller's organization
+	created, err := wh.webhookService.CreateEndpoint(c.Request.Context(), middleware.OrganizationID(c), &req)
+	if err != nil {
+		logger.Error("Failed to create webhook endpoint", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to create webhook endpoint", err))
+		return
+	}
+
+	// Return the signing secret, which is only shown once
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListEndpoints handles listing the caller's organization's webhook endpoints
+func (wh *WebhookHandler) ListEndpoints(c *gin.Context) {
+	endpoints, err := wh.webhookService.ListEndpoints(c.Request.Context(), middleware.OrganizationID(c))
+	if err != nil {
+		logger.Error("Failed to list webhook endpoints", "error", err)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list webhook endpoints", err))
+		return
+	}
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// GetEndpoint handles retrieving a webhook endpoint with its health
+func (wh *WebhookHandler) GetEndpoint(c *gin.Context) {
+	endpointID := c.Param("id")
+
+	endpoint, err := wh.webhookService.GetEndpoint(c.Request.Context(), middleware.OrganizationID(c), endpointID)
+	if err != nil {
+		logger.Error("Failed to get webhook endpoint", "error", err, "endpointID", endpointID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to get webhook endpoint", err))
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// UpdateEndpoint handles changing a webhook endpoint, including disabling and re-enabling it
+func (wh *WebhookHandler) UpdateEndpoint(c *gin.Context) {
+	endpointID := c.Param("id")
+
+	// Parse and validate the request body
+	var req models.WebhookEndpointUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to parse webhook endpoint update", "error", err)
+		c.JSON(http.StatusBadRequest, errors.NewAPIError("Invalid request body", err))
+		return
+	}
+
+	endpoint, err := wh.webhookService.UpdateEndpoint(c.Request.Context(), middleware.OrganizationID(c), endpointID, &req)
+	if err != nil {
+		logger.Error("Failed to update webhook endpoint", "error", err, "endpointID", endpointID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to update webhook endpoint", err))
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// DeleteEndpoint handles removing a webhook endpoint and its deliveries
+func (wh *WebhookHandler) DeleteEndpoint(c *gin.Context) {
+	endpointID := c.Param("id")
+
+	if err := wh.webhookService.DeleteEndpoint(c.Request.Context(), middleware.OrganizationID(c), endpointID); err != nil {
+		logger.Error("Failed to delete webhook endpoint", "error", err, "endpointID", endpointID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to delete webhook endpoint", err))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles listing an endpoint's deliveries; ?status=dead lists its dead-letter queue
+func (wh *WebhookHandler) ListDeliveries(c *gin.Context) {
+	endpointID := c.Param("id")
+
+	deliveries, err := wh.webhookService.ListDeliveries(c.Request.Context(), middleware.OrganizationID(c), endpointID, c.Query("status"))
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries", "error", err, "endpointID", endpointID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to list webhook deliveries", err))
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery handles queueing a dead-lettered delivery again
+func (wh *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	endpointID := c.Param("id")
+	deliveryID := c.Param("deliveryId")
+
+	delivery, err := wh.webhookService.ReplayDelivery(c.Request.Context(), middleware.OrganizationID(c), endpointID, deliveryID)
+	if err != nil {
+		logger.Error("Failed to replay webhook delivery", "error", err, "deliveryID", deliveryID)
+		c.JSON(statusOf(err), errors.NewAPIError("Failed to replay webhook delivery", err))
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// Human tasks:
+// - Add an endpoint to rotate an endpoint's signing secret
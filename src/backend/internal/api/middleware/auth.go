@@ -1,15 +1,30 @@
 package middleware
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
-	"github.com/your-repo/blockchain-integration-service/internal/services/auth"
-	"github.com/your-repo/blockchain-integration-service/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/jwt"
 	"strings"
 )
 
-// AuthMiddleware is a middleware function to authenticate incoming requests
-func AuthMiddleware(authSvc *auth.Service) gin.HandlerFunc {
+// Gin context keys set by AuthMiddleware and Authenticate
+const (
+	ContextUser           = "user"
+	ContextClaims         = "claims"
+	ContextOrganizationID = "organization_id"
+)
+
+// AccessTokenAuthenticator resolves an access token to its claims and user
+type AccessTokenAuthenticator interface {
+	AuthenticateAccessToken(ctx context.Context, token string) (*jwt.Claims, *models.User, error)
+}
+
+// AuthMiddleware is a middleware function to authenticate incoming requests with a bearer access token
+func AuthMiddleware(authSvc AccessTokenAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract the Authorization header from the request
 		authHeader := c.GetHeader("Authorization")
@@ -20,67 +35,111 @@ func AuthMiddleware(authSvc *auth.Service) gin.HandlerFunc {
 			return
 		}
 
-		// Extract the token from the Authorization header
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// Validate the token using the JWT package
-		claims, err := jwt.ValidateToken(token)
-		if err != nil {
-			c.AbortWithStatusJSON(401, errors.NewUnauthorizedError("Invalid token"))
+		// Validate the token and check that its session and user are still active
+		if !authenticateBearer(c, authSvc, strings.TrimPrefix(authHeader, "Bearer ")) {
 			return
 		}
 
-		// Extract the user ID from the token claims
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			c.AbortWithStatusJSON(401, errors.NewUnauthorizedError("Invalid token claims"))
-			return
-		}
+		// Call the next handler in the chain
+		c.Next()
+	}
+}
 
-		// Call the auth service to get the user details
-		user, err := authSvc.GetUserByID(userID)
-		if err != nil {
-			c.AbortWithStatusJSON(401, errors.NewUnauthorizedError("User not found"))
+// Authenticate is a middleware function that accepts either a bearer access token or an API key
+func Authenticate(apiKeys APIKeyAuthenticator, tokens AccessTokenAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Prefer a bearer token, then fall back to an API key
+		authenticated := false
+		if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			authenticated = authenticateBearer(c, tokens, strings.TrimPrefix(header, "Bearer "))
+		} else if rawKey := apiKeyFromRequest(c); rawKey != "" {
+			authenticated = authenticateAPIKey(c, apiKeys, rawKey)
+		} else {
+			c.AbortWithStatusJSON(401, errors.NewUnauthorizedError("Missing access token or API key"))
+		}
+		if !authenticated {
 			return
 		}
 
-		// Set the user details in the gin.Context for use in subsequent handlers
-		c.Set("user", user)
-
 		// Call the next handler in the chain
 		c.Next()
 	}
 }
 
-// RoleMiddleware is a middleware function to check if the authenticated user has the required role
-func RoleMiddleware(requiredRole string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get the user details from the gin.Context
-		user, exists := c.Get("user")
-		if !exists {
-			c.AbortWithStatusJSON(401, errors.NewUnauthorizedError("User not authenticated"))
-			return
-		}
+// authenticateBearer validates an access token and stores its user and claims in the Gin context, aborting
+// the request if the token is not valid
+func authenticateBearer(c *gin.Context, authSvc AccessTokenAuthenticator, token string) bool {
+	claims, user, err := authSvc.AuthenticateAccessToken(c.Request.Context(), token)
+	if err != nil {
+		abortAuthentication(c, err)
+		return false
+	}
 
-		// Check if the user's role matches the required role
-		if user.(auth.User).Role != requiredRole {
-			c.AbortWithStatusJSON(403, errors.NewForbiddenError("Insufficient permissions"))
-			return
+	// Set the user details and token claims in the gin.Context for use in subsequent handlers
+	c.Set(ContextUser, user)
+	c.Set(ContextClaims, claims)
+	c.Set(ContextOrganizationID, user.OrganizationID)
+
+	// Scope every service and repository call made for this request to the user's organization
+	c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), user.OrganizationID))
+	return true
+}
+
+// Reauthenticate resolves the request's bearer token or API key again and refreshes the user, claims or key
+// stored in the Gin context. Long-lived streams call it as they run, so expired tokens and revoked sessions,
+// memberships and keys stop working before the stream ends
+func Reauthenticate(c *gin.Context, apiKeys APIKeyAuthenticator, tokens AccessTokenAuthenticator) error {
+	ctx := c.Request.Context()
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		claims, user, err := tokens.AuthenticateAccessToken(ctx, strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			return err
 		}
+		c.Set(ContextUser, user)
+		c.Set(ContextClaims, claims)
+		return nil
+	}
+	if rawKey := apiKeyFromRequest(c); rawKey != "" {
+		key, _, err := apiKeys.Authenticate(ctx, rawKey)
+		if err != nil {
+			return err
+		}
+		c.Set(ContextAPIKey, key)
+		c.Set(ContextScopes, key.Scopes)
+		return nil
+	}
+	return errors.NewUnauthorizedError("Missing access token or API key")
+}
 
-		// If the role matches, call the next handler in the chain
-		c.Next()
+// UserFromContext returns the user resolved from an access token
+func UserFromContext(c *gin.Context) (*models.User, bool) {
+	value, exists := c.Get(ContextUser)
+	if !exists {
+		return nil, false
 	}
+	user, ok := value.(*models.User)
+	return user, ok
+}
+
+// ClaimsFromContext returns the access token claims resolved from an access token
+func ClaimsFromContext(c *gin.Context) (*jwt.Claims, bool) {
+	value, exists := c.Get(ContextClaims)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*jwt.Claims)
+	return claims, ok
+}
+
+// OrganizationID returns the ID of the organization the request was authenticated for, or the zero UUID
+func OrganizationID(c *gin.Context) uuid.UUID {
+	value, _ := c.Get(ContextOrganizationID)
+	id, _ := value.(uuid.UUID)
+	return id
 }
 
 // Human tasks:
-// TODO: Implement proper error responses with clear messages for authentication failures
-// TODO: Add support for multiple authentication methods (e.g., API keys, OAuth)
 // TODO: Implement rate limiting for authentication attempts to prevent brute force attacks
 // TODO: Add logging for authentication and authorization events
-// TODO: Implement token refresh mechanism to extend session duration
-// TODO: Add support for role-based access control (RBAC) with more granular permissions
 // TODO: Implement IP whitelisting for additional security
 // TODO: Add unit tests for the middleware functions
-// TODO: Implement secure token storage and rotation
-// TODO: Add support for multi-factor authentication
\ No newline at end of file
@@ -1,13 +1,32 @@
 package router
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/your-repo/blockchain-integration-service/internal/api/handlers"
 	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/services"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 )
 
+const (
+	// defaultReleaseInterval is how often transactions awaiting gas are released when no interval is configured
+	defaultReleaseInterval = 15 * time.Second
+
+	// defaultPollInterval is how often on-chain state is checked when no interval is configured
+	defaultPollInterval = 30 * time.Second
+)
+
+// eventPublisher receives the events raised by transactions, signature requests and sweeps
+type eventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
+
 // SetupRouter configures and returns the main API router
 func SetupRouter(services *services.Services, log *logger.Logger) *gin.Engine {
 	// Create a new Gin router
@@ -21,63 +40,268 @@ func SetupRouter(services *services.Services, log *logger.Logger) *gin.Engine {
 	// Set up health check route
 	router.GET("/health", handlers.HealthCheck())
 
+	// Expose Prometheus metrics, including per-endpoint RPC health
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	// Create handler instances
 	vaultHandler := handlers.NewVaultHandler(services.VaultService)
-	transactionHandler := handlers.NewTransactionHandler(services.TransactionService)
-	signatureHandler := handlers.NewSignatureHandler(services.SignatureService)
+	transactionHandler := handlers.NewTransactionHandler(services.TransactionService, services.RBACService)
+	signatureHandler := handlers.NewSignatureHandler(services.SignatureService, services.RBACService)
 	analyticsHandler := handlers.NewAnalyticsHandler(services.AnalyticsService)
+	escrowHandler := handlers.NewEscrowHandler(services.EscrowService)
+	contractHandler := handlers.NewContractHandler(services.ContractService)
+	nftHandler := handlers.NewNFTHandler(services.NFTService)
+	safeHandler := handlers.NewSafeHandler(services.SafeService)
+	gasStationHandler := handlers.NewGasStationHandler(services.GasStationService)
+	sweepHandler := handlers.NewSweepHandler(services.SweepService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(services.APIKeyService)
+	authHandler := handlers.NewAuthHandler(services.AuthService)
+	rbacHandler := handlers.NewRBACHandler(services.RBACService)
+	userHandler := handlers.NewUserHandler(services.UserService)
+	webhookHandler := handlers.NewWebhookHandler(services.WebhookService)
+	realtimeHandler := handlers.NewRealtimeHandler(services.RealtimeService, services.RBACService, services.APIKeyService, services.AuthService)
+
+	// Publish the access token verification keys for downstream services
+	router.GET("/.well-known/jwks.json", authHandler.JWKS)
+
+	// Authenticate API requests with user access tokens or organization API keys
+	authenticate := middleware.Authenticate(services.APIKeyService, services.AuthService)
+
+	// Declare the permission each route requires, optionally on the vault named by its :id parameter
+	can := func(permission string) gin.HandlerFunc {
+		return middleware.RequirePermission(services.RBACService, permission)
+	}
+	canOnVault := func(permission string) gin.HandlerFunc {
+		return middleware.RequireVaultPermission(services.RBACService, permission, "id")
+	}
+
+	// Transaction and signature routes are checked on the vault of the record named by their :id parameter
+	canOnTransaction := func(permission string) gin.HandlerFunc {
+		return middleware.RequireResolvedVaultPermission(services.RBACService, permission, func(c *gin.Context) (string, error) {
+			tx, err := services.TransactionService.GetTransaction(c.Request.Context(), c.Param("id"))
+			if err != nil {
+				return "", err
+			}
+			return tx.VaultID.String(), nil
+		})
+	}
+	canOnSignature := func(permission string) gin.HandlerFunc {
+		return middleware.RequireResolvedVaultPermission(services.RBACService, permission, func(c *gin.Context) (string, error) {
+			request, err := services.SignatureService.GetSignatureStatus(c.Request.Context(), c.Param("id"))
+			if err != nil {
+				return "", err
+			}
+			return request.VaultID.String(), nil
+		})
+	}
+
+	// Sensitive routes additionally require a recent second factor from users
+	stepUp := middleware.RequireStepUp(services.AuthService)
+	signedIn := middleware.AuthMiddleware(services.AuthService)
+
+	// Stream the organization's events over a WebSocket; channels are authorized as they are subscribed
+	router.GET("/ws", authenticate, realtimeHandler.WebSocket)
 
 	// Set up API version group
 	v1 := router.Group("/api/v1")
 	{
+		// User login and session routes
+		authRoutes := v1.Group("/auth")
+		{
+			authRoutes.POST("/login", authHandler.Login)
+			authRoutes.POST("/refresh", authHandler.Refresh)
+			authRoutes.POST("/logout", authHandler.Logout)
+			authRoutes.POST("/revoke-all", signedIn, authHandler.RevokeAllSessions)
+
+			// Second factor enrollment and step-up
+			authRoutes.POST("/mfa/totp", signedIn, authHandler.EnrollTOTP)
+			authRoutes.POST("/mfa/totp/confirm", signedIn, authHandler.ConfirmTOTP)
+			authRoutes.DELETE("/mfa/totp", signedIn, stepUp, authHandler.DisableTOTP)
+			authRoutes.POST("/mfa/recovery-codes", signedIn, stepUp, authHandler.RegenerateRecoveryCodes)
+			authRoutes.POST("/step-up", signedIn, authHandler.StepUp)
+		}
+
+		// Signed-in user profile and invitation acceptance routes
+		v1.GET("/me", signedIn, userHandler.Me)
+		v1.POST("/invitations/accept", userHandler.AcceptInvitation)
+
 		// Vault routes
 		vault := v1.Group("/vault")
 		{
-			vault.POST("/create", middleware.Authenticate(), vaultHandler.CreateVault)
-			vault.GET("/list", middleware.Authenticate(), vaultHandler.ListVaults)
-			vault.GET("/:id", middleware.Authenticate(), vaultHandler.GetVault)
-			vault.PUT("/:id", middleware.Authenticate(), vaultHandler.UpdateVault)
-			vault.DELETE("/:id", middleware.Authenticate(), vaultHandler.DeleteVault)
+			vault.POST("/create", authenticate, can(models.PermissionVaultWrite), vaultHandler.CreateVault)
+			vault.GET("/list", authenticate, can(models.PermissionVaultRead), vaultHandler.ListVaults)
+			vault.GET("/:id", authenticate, canOnVault(models.PermissionVaultRead), vaultHandler.GetVault)
+			vault.PUT("/:id", authenticate, canOnVault(models.PermissionVaultWrite), vaultHandler.UpdateVault)
+			vault.DELETE("/:id", authenticate, canOnVault(models.PermissionVaultWrite), vaultHandler.DeleteVault)
+
+			// Escrow operations on a vault
+			vault.POST("/:id/escrows", authenticate, canOnVault(models.PermissionTxWrite), escrowHandler.CreateEscrow)
+			vault.GET("/:id/escrows", authenticate, canOnVault(models.PermissionTxRead), escrowHandler.ListEscrows)
+			vault.GET("/:id/escrows/:escrowId", authenticate, canOnVault(models.PermissionTxRead), escrowHandler.GetEscrow)
+			vault.POST("/:id/escrows/:escrowId/finish", authenticate, canOnVault(models.PermissionTxWrite), escrowHandler.FinishEscrow)
+			vault.POST("/:id/escrows/:escrowId/cancel", authenticate, canOnVault(models.PermissionTxWrite), escrowHandler.CancelEscrow)
+
+			// NFT custody on a vault
+			vault.GET("/:id/nfts", authenticate, canOnVault(models.PermissionVaultRead), nftHandler.ListHoldings)
+			vault.POST("/:id/nfts/transfer", authenticate, canOnVault(models.PermissionTxWrite), nftHandler.TransferNFT)
+
+			// Deposit address sweeping on a vault
+			vault.POST("/:id/sweep", authenticate, canOnVault(models.PermissionTxWrite), sweepHandler.SweepVault)
 		}
 
+		// Safe smart-contract wallet vault routes
+		safes := v1.Group("/safes")
+		{
+			safes.POST("/deploy", authenticate, can(models.PermissionVaultWrite), safeHandler.DeploySafe)
+			safes.POST("/attach", authenticate, can(models.PermissionVaultWrite), safeHandler.AttachSafe)
+			safes.POST("/:id/confirm", authenticate, canOnVault(models.PermissionVaultWrite), safeHandler.ConfirmDeployment)
+			safes.POST("/:id/transactions", authenticate, canOnVault(models.PermissionTxWrite), safeHandler.ProposeTransaction)
+			safes.POST("/:id/transactions/:safeTxId/execute", authenticate, canOnVault(models.PermissionTxApprove), stepUp, safeHandler.ExecuteTransaction)
+			safes.POST("/:id/transactions/:safeTxId/confirm", authenticate, canOnVault(models.PermissionTxWrite), safeHandler.ConfirmExecution)
+			safes.POST("/:id/transactions/:safeTxId/cancel", authenticate, canOnVault(models.PermissionTxApprove), safeHandler.CancelTransaction)
+		}
+
+		// Organization gas station routes
+		orgs := v1.Group("/organizations")
+		{
+			orgs.GET("/:id/gas-station", authenticate, can(models.PermissionOrgAdmin), gasStationHandler.GetGasStation)
+			orgs.PUT("/:id/gas-station", authenticate, can(models.PermissionOrgAdmin), gasStationHandler.SetGasStation)
+		}
+
+		// Organization API key routes, scoped to the caller's organization
+		apiKeys := v1.Group("/api-keys")
+		{
+			apiKeys.POST("", authenticate, can(models.PermissionOrgAdmin), apiKeyHandler.IssueAPIKey)
+			apiKeys.GET("", authenticate, can(models.PermissionOrgAdmin), apiKeyHandler.ListAPIKeys)
+			apiKeys.DELETE("/:id", authenticate, can(models.PermissionOrgAdmin), apiKeyHandler.RevokeAPIKey)
+		}
+
+		// Organization membership and invitation routes, scoped to the caller's organization
+		members := v1.Group("/members")
+		{
+			members.GET("", authenticate, can(models.PermissionOrgAdmin), userHandler.ListMembers)
+			members.PUT("/:userId/role", authenticate, can(models.PermissionOrgAdmin), userHandler.SetMemberRole)
+			members.POST("/:userId/deactivate", authenticate, can(models.PermissionOrgAdmin), userHandler.DeactivateMember)
+		}
+		invitations := v1.Group("/invitations")
+		{
+			invitations.POST("", authenticate, can(models.PermissionOrgAdmin), userHandler.InviteUser)
+			invitations.GET("", authenticate, can(models.PermissionOrgAdmin), userHandler.ListInvitations)
+			invitations.DELETE("/:id", authenticate, can(models.PermissionOrgAdmin), userHandler.RevokeInvitation)
+		}
+
+		// Webhook endpoint and delivery routes, scoped to the caller's organization
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("", authenticate, can(models.PermissionOrgAdmin), webhookHandler.CreateEndpoint)
+			webhooks.GET("", authenticate, can(models.PermissionOrgAdmin), webhookHandler.ListEndpoints)
+			webhooks.GET("/:id", authenticate, can(models.PermissionOrgAdmin), webhookHandler.GetEndpoint)
+			webhooks.PUT("/:id", authenticate, can(models.PermissionOrgAdmin), webhookHandler.UpdateEndpoint)
+			webhooks.DELETE("/:id", authenticate, can(models.PermissionOrgAdmin), webhookHandler.DeleteEndpoint)
+			webhooks.GET("/:id/deliveries", authenticate, can(models.PermissionOrgAdmin), webhookHandler.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:deliveryId/replay", authenticate, can(models.PermissionOrgAdmin), webhookHandler.ReplayDelivery)
+		}
+
+		// Role and role binding routes
+		roles := v1.Group("/roles")
+		{
+			roles.POST("", authenticate, can(models.PermissionOrgAdmin), rbacHandler.CreateRole)
+			roles.GET("", authenticate, can(models.PermissionOrgAdmin), rbacHandler.ListRoles)
+			roles.DELETE("/:id", authenticate, can(models.PermissionOrgAdmin), rbacHandler.DeleteRole)
+		}
+		bindings := v1.Group("/role-bindings")
+		{
+			bindings.POST("", authenticate, can(models.PermissionOrgAdmin), rbacHandler.BindRole)
+			bindings.GET("", authenticate, can(models.PermissionOrgAdmin), rbacHandler.ListRoleBindings)
+			bindings.DELETE("/:id", authenticate, can(models.PermissionOrgAdmin), rbacHandler.UnbindRole)
+		}
+
+		// Stream the organization's events as Server-Sent Events; channels are authorized as on the WebSocket
+		v1.GET("/events/stream", authenticate, realtimeHandler.EventStream)
+
+		// Audit log routes
+		v1.GET("/audit-events", authenticate, can(models.PermissionAuditRead), rbacHandler.ListAuditEvents)
+
 		// Transaction routes
 		tx := v1.Group("/transactions")
 		{
-			tx.POST("/create", middleware.Authenticate(), transactionHandler.CreateTransaction)
-			tx.GET("/list", middleware.Authenticate(), transactionHandler.ListTransactions)
-			tx.GET("/:id", middleware.Authenticate(), transactionHandler.GetTransaction)
-			tx.PUT("/:id/sign", middleware.Authenticate(), transactionHandler.SignTransaction)
-			tx.POST("/:id/broadcast", middleware.Authenticate(), transactionHandler.BroadcastTransaction)
+			// Creation is checked on the vault in the request body by the handler
+			tx.POST("/create", authenticate, transactionHandler.CreateTransaction)
+			tx.GET("/list", authenticate, can(models.PermissionTxRead), transactionHandler.ListTransactions)
+			tx.GET("/:id", authenticate, canOnTransaction(models.PermissionTxRead), transactionHandler.GetTransaction)
+			tx.PUT("/:id/sign", authenticate, canOnTransaction(models.PermissionTxApprove), stepUp, transactionHandler.SignTransaction)
+			tx.GET("/:id/export", authenticate, canOnTransaction(models.PermissionTxWrite), stepUp, transactionHandler.ExportTransaction)
+			tx.POST("/:id/broadcast", authenticate, canOnTransaction(models.PermissionTxWrite), stepUp, transactionHandler.BroadcastTransaction)
+		}
+
+		// Smart-contract routes
+		contracts := v1.Group("/contracts")
+		{
+			contracts.POST("/read", authenticate, can(models.PermissionVaultRead), contractHandler.ReadContract)
+			contracts.POST("/write", authenticate, can(models.PermissionTxWrite), contractHandler.WriteContract)
 		}
 
 		// Signature routes
 		sig := v1.Group("/signatures")
 		{
-			sig.POST("/create", middleware.Authenticate(), signatureHandler.CreateSignature)
-			sig.GET("/list", middleware.Authenticate(), signatureHandler.ListSignatures)
-			sig.GET("/:id", middleware.Authenticate(), signatureHandler.GetSignature)
-			sig.DELETE("/:id", middleware.Authenticate(), signatureHandler.DeleteSignature)
+			// Creation is checked on the vault in the request body by the handler. It only queues a request that
+			// is approved separately, so unlike signing it needs no second factor and API keys may create requests
+			sig.POST("/create", authenticate, signatureHandler.CreateSignature)
+			sig.GET("/list", authenticate, can(models.PermissionSignRead), signatureHandler.ListSignatureRequests)
+			sig.GET("/:id", authenticate, canOnSignature(models.PermissionSignRead), signatureHandler.GetSignature)
+			sig.DELETE("/:id", authenticate, canOnSignature(models.PermissionSignRequest), signatureHandler.DeleteSignature)
 		}
 
 		// Analytics routes
 		analytics := v1.Group("/analytics")
 		{
-			analytics.GET("/transactions", middleware.Authenticate(), analyticsHandler.GetTransactionAnalytics)
-			analytics.GET("/vaults", middleware.Authenticate(), analyticsHandler.GetVaultAnalytics)
-			analytics.GET("/usage", middleware.Authenticate(), analyticsHandler.GetUsageAnalytics)
+			analytics.GET("/transactions", authenticate, can(models.PermissionAnalyticsRead), analyticsHandler.GetTransactionAnalytics)
+			analytics.GET("/vaults", authenticate, can(models.PermissionAnalyticsRead), analyticsHandler.GetVaultAnalytics)
+			analytics.GET("/usage", authenticate, can(models.PermissionAnalyticsRead), analyticsHandler.GetUsageAnalytics)
+			analytics.GET("/fees", authenticate, can(models.PermissionAnalyticsRead), analyticsHandler.GetFeeAnalytics)
 		}
 	}
 
 	return router
 }
 
+// StartBackgroundJobs connects the services that raise events to webhooks and realtime streams, and starts
+// the loops the routes rely on: webhook delivery, realtime fan-out, gas top-up release, sweeps, Safe
+// execution tracking, NFT sync and escrow processing. Every loop stops when the context is cancelled
+func StartBackgroundJobs(ctx context.Context, services *services.Services, cfg *config.Config, log *logger.Logger) {
+	// Publish transaction, signature and deposit events to webhook endpoints and realtime subscribers
+	for _, publisher := range []eventPublisher{services.WebhookService, services.RealtimeService} {
+		services.TransactionService.RegisterEventPublisher(publisher)
+		services.SignatureService.RegisterEventPublisher(publisher)
+		services.SweepService.RegisterEventPublisher(publisher)
+	}
+
+	// Fall back to the default schedules for unset intervals
+	releaseInterval := cfg.Blockchain.GasStation.ReleaseInterval
+	if releaseInterval <= 0 {
+		releaseInterval = defaultReleaseInterval
+	}
+	pollInterval := cfg.Blockchain.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	go services.WebhookService.Run(ctx)
+	go services.RealtimeService.Run(ctx)
+	go services.SweepService.Run(ctx)
+	go services.GasStationService.Run(ctx, releaseInterval)
+	go services.SafeService.Run(ctx, pollInterval)
+	go services.NFTService.Run(ctx, pollInterval)
+	go services.EscrowService.Run(ctx, pollInterval)
+	log.Info("Started background jobs")
+}
+
 // Human tasks:
 // - Implement versioning strategy for API endpoints
 // - Add comprehensive documentation for each route (e.g., using Swagger)
 // - Implement proper error handling and consistent error responses
 // - Add support for CORS configuration
 // - Implement request validation middleware for each route
-// - Add support for API key authentication for external integrations
 // - Implement rate limiting strategies for different API consumers
 // - Add monitoring and metrics collection for API usage
 // - Implement a mechanism for dynamically enabling/disabling routes
@@ -2,36 +2,117 @@ package ethereum
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"strings"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/rpcpool"
 	"github.com/your-repo/blockchain-integration-service/pkg/config"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 )
 
-// EthereumClient represents the Ethereum client
+// EthereumClient represents the Ethereum client for a single EVM network
 type EthereumClient struct {
-	client *ethclient.Client
-	log    *logger.Logger
+	network evm.Network
+	clients []*ethclient.Client
+	pool    *rpcpool.Pool
+	log     *logger.Logger
 }
 
-// NewEthereumClient creates a new Ethereum client
+// NewEthereumClient creates a new Ethereum mainnet client
 func NewEthereumClient(cfg *config.Config, log *logger.Logger) (*EthereumClient, error) {
-	// Create a new Ethereum client using the provided RPC URL from the configuration
-	client, err := ethclient.Dial(cfg.EthereumRPCURL)
+	// Register the configured networks so mainnet overrides are applied
+	if _, err := evm.LoadNetworks(cfg.Blockchain.EVMNetworks); err != nil {
+		log.Error("Failed to load EVM networks", "error", err)
+		return nil, err
+	}
+
+	network, _ := evm.Lookup("ethereum")
+	return NewNetworkClient(mainnetEndpoints(network, cfg), cfg.Blockchain.RPCPool, log)
+}
+
+// NewClients creates a client for every EVM network that has RPC endpoints, keyed by blockchain type
+func NewClients(cfg *config.Config, log *logger.Logger) (map[string]*EthereumClient, error) {
+	networks, err := evm.LoadNetworks(cfg.Blockchain.EVMNetworks)
+	if err != nil {
+		log.Error("Failed to load EVM networks", "error", err)
+		return nil, err
+	}
+
+	clients := make(map[string]*EthereumClient, len(networks))
+	for _, network := range networks {
+		if network.Name == "ethereum" {
+			network = mainnetEndpoints(network, cfg)
+		}
+		// Networks without endpoints are known but not served
+		if len(network.RPCEndpoints) == 0 {
+			continue
+		}
+		client, err := NewNetworkClient(network, cfg.Blockchain.RPCPool, log)
+		if err != nil {
+			return nil, err
+		}
+		clients[network.Name] = client
+	}
+
+	return clients, nil
+}
+
+// NewNetworkClient creates a new client for an EVM network
+func NewNetworkClient(network evm.Network, poolCfg config.RPCPoolConfig, log *logger.Logger) (*EthereumClient, error) {
+	// Dial every configured RPC endpoint, skipping the ones that cannot be reached
+	var urls []string
+	var clients []*ethclient.Client
+	for _, url := range network.RPCEndpoints {
+		client, err := ethclient.Dial(url)
+		if err != nil {
+			log.Error("Failed to create Ethereum client", "error", err, "network", network.Name)
+			continue
+		}
+		urls = append(urls, url)
+		clients = append(clients, client)
+	}
+
+	c := &EthereumClient{
+		network: network,
+		clients: clients,
+		log:     log,
+	}
+
+	// Create the endpoint pool, probing each endpoint for its latest block
+	opts := rpcpool.NewOptions(poolCfg)
+	opts.Retryable = isRetryable
+	pool, err := rpcpool.NewPool(network.Name, urls, func(ctx context.Context, i int) (uint64, error) {
+		return c.clients[i].BlockNumber(ctx)
+	}, opts, log)
 	if err != nil {
-		log.Error("Failed to create Ethereum client", "error", err)
+		log.Error("Failed to create Ethereum endpoint pool", "error", err, "network", network.Name)
 		return nil, err
 	}
+	c.pool = pool
+
+	// If successful, return the new EthereumClient instance
+	return c, nil
+}
+
+// Network returns the EVM network served by the client
+func (c *EthereumClient) Network() evm.Network {
+	return c.network
+}
 
-	// If successful, create and return a new EthereumClient instance
-	return &EthereumClient{
-		client: client,
-		log:    log,
-	}, nil
+// Run probes the health of the RPC endpoints until the context is cancelled
+func (c *EthereumClient) Run(ctx context.Context) {
+	c.pool.Run(ctx)
+}
+
+// EndpointStats returns the health of every RPC endpoint
+func (c *EthereumClient) EndpointStats() []rpcpool.EndpointStats {
+	return c.pool.Stats()
 }
 
 // GetBalance gets the balance of an Ethereum address
@@ -39,8 +120,13 @@ func (c *EthereumClient) GetBalance(ctx context.Context, address string) (*big.I
 	// Convert the address string to an Ethereum address
 	ethAddress := common.HexToAddress(address)
 
-	// Call the client's BalanceAt method to get the balance
-	balance, err := c.client.BalanceAt(ctx, ethAddress, nil)
+	// Call BalanceAt on the best endpoint, failing over on errors
+	var balance *big.Int
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		balance, err = c.clients[i].BalanceAt(ctx, ethAddress, nil)
+		return err
+	})
 	if err != nil {
 		c.log.Error("Failed to get balance", "address", address, "error", err)
 		return nil, err
@@ -52,8 +138,20 @@ func (c *EthereumClient) GetBalance(ctx context.Context, address string) (*big.I
 
 // SendTransaction sends an Ethereum transaction
 func (c *EthereumClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	// Call the client's SendTransaction method to send the transaction
-	err := c.client.SendTransaction(ctx, tx)
+	// Refuse transactions signed for another network or without replay protection
+	if err := CheckReplayProtection(tx, c.network); err != nil {
+		c.log.Error("Refusing transaction without valid replay protection", "error", err, "network", c.network.Name)
+		return err
+	}
+
+	// Broadcast the transaction to several endpoints so a single node cannot drop it
+	err := c.pool.Broadcast(ctx, func(i int) error {
+		err := c.clients[i].SendTransaction(ctx, tx)
+		if err != nil && isAlreadyKnown(err) {
+			return nil
+		}
+		return err
+	})
 	if err != nil {
 		c.log.Error("Failed to send transaction", "error", err)
 		return err
@@ -68,8 +166,13 @@ func (c *EthereumClient) GetTransactionReceipt(ctx context.Context, txHash strin
 	// Convert the transaction hash string to an Ethereum hash
 	hash := common.HexToHash(txHash)
 
-	// Call the client's TransactionReceipt method to get the receipt
-	receipt, err := c.client.TransactionReceipt(ctx, hash)
+	// Call TransactionReceipt on the best endpoint, failing over on errors
+	var receipt *types.Receipt
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		receipt, err = c.clients[i].TransactionReceipt(ctx, hash)
+		return err
+	})
 	if err != nil {
 		c.log.Error("Failed to get transaction receipt", "txHash", txHash, "error", err)
 		return nil, err
@@ -79,14 +182,63 @@ func (c *EthereumClient) GetTransactionReceipt(ctx context.Context, txHash strin
 	return receipt, nil
 }
 
+// GetConfirmations returns how many blocks confirm a transaction and whether it has reached the network's confirmation depth
+func (c *EthereumClient) GetConfirmations(ctx context.Context, txHash string) (uint64, bool, error) {
+	// Find the block that included the transaction
+	receipt, err := c.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return 0, false, err
+	}
+
+	// Compare it against the current head
+	var head uint64
+	err = c.pool.Do(ctx, func(i int) error {
+		var err error
+		head, err = c.clients[i].BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to get block number", "error", err, "network", c.network.Name)
+		return 0, false, err
+	}
+
+	included := receipt.BlockNumber.Uint64()
+	if head < included {
+		return 0, false, nil
+	}
+	confirmations := head - included + 1
+	return confirmations, confirmations >= c.network.Confirmations, nil
+}
+
+// mainnetEndpoints falls back to the legacy Ethereum endpoint settings for mainnet
+func mainnetEndpoints(network evm.Network, cfg *config.Config) evm.Network {
+	if len(network.RPCEndpoints) == 0 {
+		network.RPCEndpoints = rpcpool.Endpoints(cfg.Blockchain.EthereumRPCEndpoints, cfg.Blockchain.EthereumRPC)
+	}
+	return network
+}
+
+// isRetryable reports whether an RPC error should fail over to another endpoint
+func isRetryable(err error) bool {
+	// Reverts are answers from a healthy node and would revert on every endpoint
+	if _, reverted := revertDataFromError(err); reverted {
+		return false
+	}
+	return !errors.Is(err, ethereum.NotFound) && !errors.Is(err, context.Canceled)
+}
+
+// isAlreadyKnown reports whether a node rejected a transaction because it already has it
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
 // Human tasks:
-// TODO: Implement error handling and retries for network failures
 // TODO: Add support for estimating gas prices
 // TODO: Implement a method to deploy smart contracts
 // TODO: Add support for interacting with ERC20 tokens
 // TODO: Implement a method to listen for new blocks and transactions
 // TODO: Add support for signing transactions offline
 // TODO: Implement a method to get historical transaction data
-// TODO: Add support for multiple Ethereum networks (mainnet, testnets)
 // TODO: Implement a method to validate Ethereum addresses
 // TODO: Add support for ENS (Ethereum Name Service) resolution
\ No newline at end of file
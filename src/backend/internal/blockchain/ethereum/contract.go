@@ -0,0 +1,460 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+)
+
+var (
+	// errorSelector is the selector of the standard Error(string) revert
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
+	// panicSelector is the selector of the Panic(uint256) revert raised by failed assertions
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+	// panicReasons describes the Solidity panic codes
+	panicReasons = map[uint64]string{
+		0x01: "assertion failed",
+		0x11: "arithmetic overflow or underflow",
+		0x12: "division or modulo by zero",
+		0x21: "invalid enum value",
+		0x22: "invalid storage byte array",
+		0x31: "pop on empty array",
+		0x32: "array index out of bounds",
+		0x41: "out of memory",
+		0x51: "call to uninitialized function",
+	}
+)
+
+// RevertError is returned when a contract call reverts
+type RevertError struct {
+	Reason string
+	Data   []byte
+}
+
+// Error implements the error interface for RevertError
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// ParseABI parses an ABI given as a full JSON array or as a single fragment object
+func ParseABI(fragment json.RawMessage) (abi.ABI, error) {
+	trimmed := bytes.TrimSpace(fragment)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		trimmed = append(append([]byte{'['}, trimmed...), ']')
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(trimmed))
+	if err != nil {
+		return abi.ABI{}, fmt.Errorf("invalid ABI: %w", err)
+	}
+	return parsed, nil
+}
+
+// EncodeCall ABI-encodes a method call from JSON arguments
+func EncodeCall(contractABI abi.ABI, method string, args []json.RawMessage) ([]byte, error) {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+	if len(args) != len(m.Inputs) {
+		return nil, fmt.Errorf("method %s expects %d arguments, got %d", method, len(m.Inputs), len(args))
+	}
+
+	// Convert every JSON argument into the Go type expected by the ABI packer
+	values := make([]interface{}, len(args))
+	for i, input := range m.Inputs {
+		value, err := convertArg(input.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, input.Name, err)
+		}
+		values[i] = value
+	}
+
+	data, err := contractABI.Pack(method, values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode call: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeOutputs decodes the return data of a method call into JSON-friendly values
+func DecodeOutputs(contractABI abi.ABI, method string, data []byte) ([]models.ContractOutput, error) {
+	m, ok := contractABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found in ABI", method)
+	}
+
+	values, err := m.Outputs.Unpack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode outputs: %w", err)
+	}
+
+	outputs := make([]models.ContractOutput, len(values))
+	for i, value := range values {
+		outputs[i] = models.ContractOutput{
+			Name:  m.Outputs[i].Name,
+			Type:  m.Outputs[i].Type.String(),
+			Value: jsonValue(reflect.ValueOf(value)),
+		}
+	}
+	return outputs, nil
+}
+
+// DecodeRevertReason decodes revert data from Error(string), Panic(uint256) or a custom error declared in the ABI
+func DecodeRevertReason(contractABI *abi.ABI, data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return reason
+		}
+	case bytes.Equal(data[:4], panicSelector) && len(data) >= 36:
+		code := new(big.Int).SetBytes(data[4:36]).Uint64()
+		if reason, ok := panicReasons[code]; ok {
+			return fmt.Sprintf("panic: %s (0x%02x)", reason, code)
+		}
+		return fmt.Sprintf("panic: 0x%02x", code)
+	case contractABI != nil:
+		for _, customErr := range contractABI.Errors {
+			if !bytes.Equal(customErr.ID[:4], data[:4]) {
+				continue
+			}
+			values, err := customErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				break
+			}
+			parts := make([]string, len(values))
+			for i, value := range values {
+				encoded, _ := json.Marshal(jsonValue(reflect.ValueOf(value)))
+				parts[i] = string(encoded)
+			}
+			return fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(parts, ", "))
+		}
+	}
+
+	return "0x" + hex.EncodeToString(data)
+}
+
+// CallContract runs an eth_call against the latest block, returning a RevertError when the call reverts
+func (c *EthereumClient) CallContract(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error) {
+	msg := callMsg(from, to, value, data)
+
+	// Call the contract on the best endpoint, failing over on errors
+	var result []byte
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		result, err = c.clients[i].CallContract(ctx, msg, nil)
+		return err
+	})
+	if err != nil {
+		if revertData, ok := revertDataFromError(err); ok {
+			return nil, &RevertError{Reason: DecodeRevertReason(nil, revertData), Data: revertData}
+		}
+		c.log.Error("Failed to call contract", "error", err, "contract", to, "network", c.network.Name)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RevertReason replays a failed transaction at its block to recover the revert reason
+func (c *EthereumClient) RevertReason(ctx context.Context, txHash string, contractABI *abi.ABI) (string, error) {
+	receipt, err := c.GetTransactionReceipt(ctx, txHash)
+	if err != nil {
+		return "", err
+	}
+	if receipt.Status != 0 {
+		return "", nil
+	}
+
+	// Fetch the original transaction and its sender
+	var tx *types.Transaction
+	err = c.pool.Do(ctx, func(i int) error {
+		var err error
+		tx, _, err = c.clients[i].TransactionByHash(ctx, common.HexToHash(txHash))
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to get transaction", "error", err, "txHash", txHash)
+		return "", err
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(c.network.ChainID), tx)
+	if err != nil {
+		return "", err
+	}
+
+	// Replay the call at the block that included the transaction
+	msg := ethereum.CallMsg{From: from, To: tx.To(), Gas: tx.Gas(), Value: tx.Value(), Data: tx.Data()}
+	err = c.pool.Do(ctx, func(i int) error {
+		_, err := c.clients[i].CallContract(ctx, msg, receipt.BlockNumber)
+		return err
+	})
+	if revertData, ok := revertDataFromError(err); ok {
+		return DecodeRevertReason(contractABI, revertData), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The replay succeeded, so the transaction most likely ran out of gas
+	return "transaction failed without revert data (possibly out of gas)", nil
+}
+
+// callMsg builds an eth_call message
+func callMsg(from, to string, value *big.Int, data []byte) ethereum.CallMsg {
+	toAddress := common.HexToAddress(to)
+	msg := ethereum.CallMsg{To: &toAddress, Value: value, Data: data}
+	if from != "" {
+		msg.From = common.HexToAddress(from)
+	}
+	return msg
+}
+
+// revertDataFromError extracts the revert data carried by a JSON-RPC execution error
+func revertDataFromError(err error) ([]byte, bool) {
+	var dataErr rpc.DataError
+	if err == nil || !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, false
+	}
+	data, decodeErr := hexutil.Decode(encoded)
+	if decodeErr != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// convertArg converts a JSON argument into the Go value the ABI packer expects for a type
+func convertArg(t abi.Type, raw json.RawMessage) (interface{}, error) {
+	switch t.T {
+	case abi.AddressTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("expected an address")
+		}
+		return common.HexToAddress(s), nil
+
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, fmt.Errorf("expected a boolean")
+		}
+		return b, nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, fmt.Errorf("expected a string")
+		}
+		return s, nil
+
+	case abi.IntTy, abi.UintTy:
+		n, err := parseInteger(raw)
+		if err != nil {
+			return nil, err
+		}
+		return integerValue(t, n)
+
+	case abi.BytesTy:
+		return parseHexArg(raw)
+
+	case abi.FixedBytesTy:
+		b, err := parseHexArg(raw)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("expected %d bytes, got %d", t.Size, len(b))
+		}
+		array := reflect.New(t.GetType()).Elem()
+		reflect.Copy(array, reflect.ValueOf(b))
+		return array.Interface(), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("expected an array")
+		}
+		if t.T == abi.ArrayTy && len(items) != t.Size {
+			return nil, fmt.Errorf("expected %d elements, got %d", t.Size, len(items))
+		}
+		var collection reflect.Value
+		if t.T == abi.SliceTy {
+			collection = reflect.MakeSlice(t.GetType(), len(items), len(items))
+		} else {
+			collection = reflect.New(t.GetType()).Elem()
+		}
+		for i, item := range items {
+			value, err := convertArg(*t.Elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			collection.Index(i).Set(reflect.ValueOf(value))
+		}
+		return collection.Interface(), nil
+
+	case abi.TupleTy:
+		// Tuples may be given positionally or keyed by component name
+		items := make([]json.RawMessage, len(t.TupleElems))
+		var named map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil || len(items) != len(t.TupleElems) {
+			if err := json.Unmarshal(raw, &named); err != nil {
+				return nil, fmt.Errorf("expected a tuple as an array or object")
+			}
+			items = make([]json.RawMessage, len(t.TupleElems))
+			for i, name := range t.TupleRawNames {
+				item, ok := named[name]
+				if !ok {
+					return nil, fmt.Errorf("missing tuple component %s", name)
+				}
+				items[i] = item
+			}
+		}
+		tuple := reflect.New(t.GetType()).Elem()
+		for i, elem := range t.TupleElems {
+			value, err := convertArg(*elem, items[i])
+			if err != nil {
+				return nil, fmt.Errorf("component %s: %w", t.TupleRawNames[i], err)
+			}
+			tuple.Field(i).Set(reflect.ValueOf(value))
+		}
+		return tuple.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported ABI type %s", t.String())
+}
+
+// parseInteger parses a JSON number or a decimal or 0x-prefixed string into a big integer
+func parseInteger(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return nil, fmt.Errorf("expected an integer")
+		}
+		s = n.String()
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+// integerValue converts a big integer into the Go type used for an ABI integer type, checking its range
+func integerValue(t abi.Type, n *big.Int) (interface{}, error) {
+	// Check the value fits the signed or unsigned range of the type
+	one := big.NewInt(1)
+	min := new(big.Int)
+	max := new(big.Int).Sub(new(big.Int).Lsh(one, uint(t.Size)), one)
+	if t.T == abi.IntTy {
+		max = new(big.Int).Sub(new(big.Int).Lsh(one, uint(t.Size-1)), one)
+		min = new(big.Int).Neg(new(big.Int).Lsh(one, uint(t.Size-1)))
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return nil, fmt.Errorf("value out of range for %s", t.String())
+	}
+
+	// Sizes up to 64 bits map to native Go integers, larger ones to *big.Int
+	goType := t.GetType()
+	switch goType.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value := reflect.New(goType).Elem()
+		value.SetUint(n.Uint64())
+		return value.Interface(), nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value := reflect.New(goType).Elem()
+		value.SetInt(n.Int64())
+		return value.Interface(), nil
+	}
+	return n, nil
+}
+
+// parseHexArg decodes a 0x-prefixed hex string argument
+func parseHexArg(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("expected a hex string")
+	}
+	b, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string: %w", err)
+	}
+	return b, nil
+}
+
+// jsonValue converts a decoded ABI value into a JSON-friendly value
+func jsonValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch value := v.Interface().(type) {
+	case *big.Int:
+		return value.String()
+	case common.Address:
+		return value.Hex()
+	case common.Hash:
+		return value.Hex()
+	case []byte:
+		return hexutil.Encode(value)
+	}
+
+	switch v.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()).String()
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()).String()
+	case reflect.Array:
+		// Fixed-size byte arrays are rendered as hex
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return hexutil.Encode(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = jsonValue(v.Index(i))
+		}
+		return items
+	case reflect.Struct:
+		fields := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			fields[v.Type().Field(i).Name] = jsonValue(v.Field(i))
+		}
+		return fields
+	}
+	return v.Interface()
+}
+
+// Human tasks:
+// - Decode event logs from receipts using the supplied ABI
+// - Resolve ENS names given as address arguments
+// - Preserve the original tuple component names when decoding struct outputs
@@ -0,0 +1,35 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+)
+
+// EstimateFee estimates the gas a call will use against pending state and prices it at the suggested gas price
+func (c *EthereumClient) EstimateFee(ctx context.Context, from, to string, value *big.Int, data []byte) (*big.Int, error) {
+	msg := callMsg(from, to, value, data)
+
+	// Estimate and price on the same endpoint so both come from one view of the chain
+	var gas uint64
+	var gasPrice *big.Int
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		if gas, err = c.clients[i].EstimateGas(ctx, msg); err != nil {
+			return err
+		}
+		gasPrice, err = c.clients[i].SuggestGasPrice(ctx)
+		return err
+	})
+	if err != nil {
+		if revertData, ok := revertDataFromError(err); ok {
+			return nil, &RevertError{Reason: DecodeRevertReason(nil, revertData), Data: revertData}
+		}
+		c.log.Error("Failed to estimate fee", "error", err, "network", c.network.Name)
+		return nil, err
+	}
+
+	return new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice), nil
+}
+
+// Human tasks:
+// - Price EIP-1559 transactions from the base fee and priority fee
@@ -0,0 +1,276 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+)
+
+const (
+	// erc721ABIJSON covers the ERC-721 events and methods used for custody
+	erc721ABIJSON = `[
+		{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"tokenId","type":"uint256","indexed":true}]},
+		{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]},
+		{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"name":"","type":"string"}]}
+	]`
+
+	// erc1155ABIJSON covers the ERC-1155 events and methods used for custody
+	erc1155ABIJSON = `[
+		{"type":"event","name":"TransferSingle","anonymous":false,"inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"id","type":"uint256","indexed":false},{"name":"value","type":"uint256","indexed":false}]},
+		{"type":"event","name":"TransferBatch","anonymous":false,"inputs":[{"name":"operator","type":"address","indexed":true},{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"ids","type":"uint256[]","indexed":false},{"name":"values","type":"uint256[]","indexed":false}]},
+		{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+		{"type":"function","name":"uri","stateMutability":"view","inputs":[{"name":"id","type":"uint256"}],"outputs":[{"name":"","type":"string"}]}
+	]`
+)
+
+var (
+	erc721ABI  = mustParseABI(erc721ABIJSON)
+	erc1155ABI = mustParseABI(erc1155ABIJSON)
+
+	// Event signatures used to filter transfer logs
+	transferTopic       = erc721ABI.Events["Transfer"].ID
+	transferSingleTopic = erc1155ABI.Events["TransferSingle"].ID
+	transferBatchTopic  = erc1155ABI.Events["TransferBatch"].ID
+)
+
+// mustParseABI parses a built-in ABI, panicking on programmer error
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// NFTABI returns the ABI of a token standard so NFT transfers can be stored as contract calls
+func NFTABI(standard string) (json.RawMessage, error) {
+	switch standard {
+	case models.NFTStandardERC721:
+		return json.RawMessage(erc721ABIJSON), nil
+	case models.NFTStandardERC1155:
+		return json.RawMessage(erc1155ABIJSON), nil
+	}
+	return nil, fmt.Errorf("unsupported NFT standard %q", standard)
+}
+
+// ParseNFTTransfers decodes the token movements in a log. Logs that are not NFT transfers, including
+// ERC-20 Transfer events, which share the ERC-721 signature but not its indexed token ID, return nil
+func ParseNFTTransfers(log types.Log) ([]models.NFTTransfer, error) {
+	if len(log.Topics) == 0 || log.Removed {
+		return nil, nil
+	}
+	base := models.NFTTransfer{
+		ContractAddress: log.Address.Hex(),
+		BlockNumber:     log.BlockNumber,
+		TxHash:          log.TxHash.Hex(),
+		LogIndex:        log.Index,
+	}
+
+	switch log.Topics[0] {
+	case transferTopic:
+		// ERC-721 indexes the token ID, so the log has four topics
+		if len(log.Topics) != 4 {
+			return nil, nil
+		}
+		base.Standard = models.NFTStandardERC721
+		base.From = topicAddress(log.Topics[1])
+		base.To = topicAddress(log.Topics[2])
+		base.TokenID = log.Topics[3].Big().String()
+		base.Amount = "1"
+		return []models.NFTTransfer{base}, nil
+
+	case transferSingleTopic:
+		if len(log.Topics) != 4 {
+			return nil, fmt.Errorf("malformed TransferSingle log in %s", log.TxHash.Hex())
+		}
+		values, err := erc1155ABI.Unpack("TransferSingle", log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("malformed TransferSingle log in %s: %w", log.TxHash.Hex(), err)
+		}
+		base.Standard = models.NFTStandardERC1155
+		base.From = topicAddress(log.Topics[2])
+		base.To = topicAddress(log.Topics[3])
+		base.TokenID = values[0].(*big.Int).String()
+		base.Amount = values[1].(*big.Int).String()
+		return []models.NFTTransfer{base}, nil
+
+	case transferBatchTopic:
+		if len(log.Topics) != 4 {
+			return nil, fmt.Errorf("malformed TransferBatch log in %s", log.TxHash.Hex())
+		}
+		values, err := erc1155ABI.Unpack("TransferBatch", log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("malformed TransferBatch log in %s: %w", log.TxHash.Hex(), err)
+		}
+		ids, amounts := values[0].([]*big.Int), values[1].([]*big.Int)
+		if len(ids) != len(amounts) {
+			return nil, fmt.Errorf("malformed TransferBatch log in %s: %d ids and %d values", log.TxHash.Hex(), len(ids), len(amounts))
+		}
+
+		// Expand the batch into one transfer per token
+		transfers := make([]models.NFTTransfer, len(ids))
+		for i := range ids {
+			transfer := base
+			transfer.Standard = models.NFTStandardERC1155
+			transfer.From = topicAddress(log.Topics[2])
+			transfer.To = topicAddress(log.Topics[3])
+			transfer.TokenID = ids[i].String()
+			transfer.Amount = amounts[i].String()
+			transfer.BatchIndex = i
+			transfers[i] = transfer
+		}
+		return transfers, nil
+	}
+
+	return nil, nil
+}
+
+// EncodeSafeTransferFrom encodes a safeTransferFrom call for a token standard
+func EncodeSafeTransferFrom(standard, from, to string, tokenID, amount *big.Int) ([]byte, error) {
+	if !common.IsHexAddress(from) || !common.IsHexAddress(to) {
+		return nil, fmt.Errorf("invalid address")
+	}
+	if tokenID == nil || tokenID.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token ID")
+	}
+
+	switch standard {
+	case models.NFTStandardERC721:
+		if amount != nil && amount.Cmp(big.NewInt(1)) != 0 {
+			return nil, fmt.Errorf("ERC-721 transfers move exactly one token")
+		}
+		return erc721ABI.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenID)
+	case models.NFTStandardERC1155:
+		if amount == nil || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("ERC-1155 transfers need a positive amount")
+		}
+		return erc1155ABI.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenID, amount, []byte{})
+	}
+	return nil, fmt.Errorf("unsupported NFT standard %q", standard)
+}
+
+// FilterNFTTransfers returns the NFT transfers into or out of the given addresses in a block range
+func (c *EthereumClient) FilterNFTTransfers(ctx context.Context, addresses []string, fromBlock, toBlock uint64) ([]models.NFTTransfer, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	watched := make([]common.Hash, len(addresses))
+	for i, address := range addresses {
+		watched[i] = common.BytesToHash(common.HexToAddress(address).Bytes())
+	}
+
+	// Topics are positional, so senders and recipients need separate queries per standard
+	erc721 := []common.Hash{transferTopic}
+	erc1155 := []common.Hash{transferSingleTopic, transferBatchTopic}
+	queries := [][][]common.Hash{
+		{erc721, watched},
+		{erc721, nil, watched},
+		{erc1155, nil, watched},
+		{erc1155, nil, nil, watched},
+	}
+
+	// Decode each log once, even when it matches several queries
+	seen := make(map[string]bool)
+	var transfers []models.NFTTransfer
+	for _, topics := range queries {
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Topics:    topics,
+		}
+		var logs []types.Log
+		err := c.pool.Do(ctx, func(i int) error {
+			var err error
+			logs, err = c.clients[i].FilterLogs(ctx, query)
+			return err
+		})
+		if err != nil {
+			c.log.Error("Failed to filter NFT transfer logs", "error", err, "network", c.network.Name, "fromBlock", fromBlock, "toBlock", toBlock)
+			return nil, err
+		}
+
+		for _, log := range logs {
+			key := fmt.Sprintf("%s:%d", log.TxHash.Hex(), log.Index)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			parsed, err := ParseNFTTransfers(log)
+			if err != nil {
+				c.log.Error("Skipping malformed NFT transfer log", "error", err, "network", c.network.Name)
+				continue
+			}
+			for _, transfer := range parsed {
+				transfer.BlockchainType = c.network.Name
+				transfers = append(transfers, transfer)
+			}
+		}
+	}
+
+	return transfers, nil
+}
+
+// TokenURI returns the metadata URI of a token, expanding the ERC-1155 {id} placeholder
+func (c *EthereumClient) TokenURI(ctx context.Context, contract, standard string, tokenID *big.Int) (string, error) {
+	contractABI, method := erc721ABI, "tokenURI"
+	if standard == models.NFTStandardERC1155 {
+		contractABI, method = erc1155ABI, "uri"
+	}
+	data, err := contractABI.Pack(method, tokenID)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := c.CallContract(ctx, "", contract, nil, data)
+	if err != nil {
+		return "", err
+	}
+	values, err := contractABI.Unpack(method, raw)
+	if err != nil || len(values) != 1 {
+		return "", fmt.Errorf("contract %s returned an invalid %s response", contract, method)
+	}
+	uri, _ := values[0].(string)
+
+	// ERC-1155 clients substitute the zero-padded lowercase hex token ID
+	if standard == models.NFTStandardERC1155 {
+		uri = strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", tokenID))
+	}
+	return uri, nil
+}
+
+// SafeBlockNumber returns the latest block that has reached the network's confirmation depth
+func (c *EthereumClient) SafeBlockNumber(ctx context.Context) (uint64, error) {
+	var head uint64
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		head, err = c.clients[i].BlockNumber(ctx)
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to get block number", "error", err, "network", c.network.Name)
+		return 0, err
+	}
+
+	if head < c.network.Confirmations {
+		return 0, nil
+	}
+	return head - c.network.Confirmations, nil
+}
+
+// topicAddress converts an indexed address topic to a checksummed address
+func topicAddress(topic common.Hash) string {
+	return common.BytesToAddress(topic.Bytes()).Hex()
+}
+
+// Human tasks:
+// - Subscribe to logs over WebSocket endpoints instead of polling
+// - Detect ERC-721 contracts that do not emit Transfer on mint
@@ -0,0 +1,121 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+)
+
+// ErrDigestMismatch is returned when a signed payload is not the transaction that was exported
+var ErrDigestMismatch = errors.NewBadRequestError("signed transaction does not match the exported transaction")
+
+// ExportUnsigned builds an unsigned transaction from the vault's pending nonce and current gas price and
+// encodes it as RLP for an offline signer. The digest is the EIP-155 signing hash the signer must sign
+func (c *EthereumClient) ExportUnsigned(ctx context.Context, transaction *models.Transaction) (*offline.Payload, error) {
+	value, ok := new(big.Int).SetString(transaction.Amount, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, errors.NewInvalidAmountError("invalid amount: must be an integer amount in wei")
+	}
+	var data []byte
+	if transaction.Data != "" {
+		var err error
+		if data, err = hexutil.Decode(transaction.Data); err != nil {
+			return nil, errors.NewBadRequestError("calldata must be a 0x-prefixed hex string")
+		}
+	}
+	msg := callMsg(transaction.FromAddress, transaction.ToAddress, value, data)
+
+	// Read the nonce, gas and price from one endpoint so they describe the same chain state
+	var nonce, gas uint64
+	var gasPrice *big.Int
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		if nonce, err = c.clients[i].PendingNonceAt(ctx, msg.From); err != nil {
+			return err
+		}
+		if gas, err = c.clients[i].EstimateGas(ctx, msg); err != nil {
+			return err
+		}
+		gasPrice, err = c.clients[i].SuggestGasPrice(ctx)
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to prepare unsigned transaction", "error", err, "transactionID", transaction.ID)
+		return nil, err
+	}
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		GasPrice: gasPrice,
+		Gas:      gas,
+		To:       msg.To,
+		Value:    value,
+		Data:     data,
+	})
+	return EncodeUnsigned(tx, c.network, transaction)
+}
+
+// EncodeUnsigned wraps an unsigned transaction in an offline payload bound to the originating transaction
+func EncodeUnsigned(tx *types.Transaction, network evm.Network, transaction *models.Transaction) (*offline.Payload, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	payload := &offline.Payload{
+		TransactionID:  transaction.ID.String(),
+		BlockchainType: transaction.BlockchainType,
+		Format:         offline.FormatRLP,
+		Data:           hexutil.Encode(raw),
+		Digest:         types.LatestSignerForChainID(network.ChainID).Hash(tx).Hex(),
+	}
+	payload.Seal()
+	return payload, nil
+}
+
+// DecodeSigned decodes a signed RLP payload and checks that it is replay protected and signs the exported digest
+func DecodeSigned(payload *offline.Payload, network evm.Network, digest string) (*types.Transaction, error) {
+	if payload.Format != offline.FormatRLP || !payload.Signed {
+		return nil, errors.NewBadRequestError("payload is not a signed RLP transaction")
+	}
+	raw, err := hexutil.Decode(payload.Data)
+	if err != nil {
+		return nil, errors.NewBadRequestError("signed transaction must be 0x-prefixed hex")
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, errors.NewBadRequestError("invalid signed transaction: " + err.Error())
+	}
+	if err := CheckReplayProtection(tx, network); err != nil {
+		return nil, err
+	}
+
+	// The signing hash excludes the signature, so it must equal the exported digest
+	if types.LatestSignerForChainID(network.ChainID).Hash(tx) != common.HexToHash(digest) {
+		return nil, ErrDigestMismatch
+	}
+	return tx, nil
+}
+
+// BroadcastSigned verifies a signed payload against the exported digest and broadcasts it
+func (c *EthereumClient) BroadcastSigned(ctx context.Context, transaction *models.Transaction, payload *offline.Payload) (string, error) {
+	tx, err := DecodeSigned(payload, c.network, transaction.OfflineDigest)
+	if err != nil {
+		c.log.Error("Rejected offline-signed transaction", "error", err, "transactionID", transaction.ID)
+		return "", err
+	}
+	if err := c.SendTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// Human tasks:
+// - Export EIP-1559 transactions once offline signers support typed transactions
+// - Refresh the nonce and gas price when an export is older than a configurable age
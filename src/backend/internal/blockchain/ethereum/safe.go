@@ -0,0 +1,252 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// safeABIJSON covers the Safe (v1.3+) methods used to set up, inspect and execute through a Safe
+	safeABIJSON = `[
+		{"type":"function","name":"setup","stateMutability":"nonpayable","inputs":[{"name":"_owners","type":"address[]"},{"name":"_threshold","type":"uint256"},{"name":"to","type":"address"},{"name":"data","type":"bytes"},{"name":"fallbackHandler","type":"address"},{"name":"paymentToken","type":"address"},{"name":"payment","type":"uint256"},{"name":"paymentReceiver","type":"address"}],"outputs":[]},
+		{"type":"function","name":"execTransaction","stateMutability":"payable","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"operation","type":"uint8"},{"name":"safeTxGas","type":"uint256"},{"name":"baseGas","type":"uint256"},{"name":"gasPrice","type":"uint256"},{"name":"gasToken","type":"address"},{"name":"refundReceiver","type":"address"},{"name":"signatures","type":"bytes"}],"outputs":[{"name":"success","type":"bool"}]},
+		{"type":"function","name":"nonce","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"getThreshold","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+		{"type":"function","name":"getOwners","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"address[]"}]}
+	]`
+
+	// safeProxyFactoryABIJSON covers the proxy factory method used to deploy a Safe
+	safeProxyFactoryABIJSON = `[
+		{"type":"function","name":"createProxyWithNonce","stateMutability":"nonpayable","inputs":[{"name":"_singleton","type":"address"},{"name":"initializer","type":"bytes"},{"name":"saltNonce","type":"uint256"}],"outputs":[{"name":"proxy","type":"address"}]}
+	]`
+)
+
+var (
+	safeABI             = mustParseABI(safeABIJSON)
+	safeProxyFactoryABI = mustParseABI(safeProxyFactoryABIJSON)
+
+	// domainTypeHash is the EIP-712 domain type used by Safe v1.3 and later
+	domainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+
+	// safeTxTypeHash is the EIP-712 type of a Safe transaction
+	safeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+
+	// proxyCreationTopic is emitted by the proxy factory with the address of a new Safe
+	proxyCreationTopic = crypto.Keccak256Hash([]byte("ProxyCreation(address,address)"))
+
+	// executionSuccessTopic and executionFailureTopic are emitted by a Safe with the hash of each executed transaction
+	executionSuccessTopic = crypto.Keccak256Hash([]byte("ExecutionSuccess(bytes32,uint256)"))
+	executionFailureTopic = crypto.Keccak256Hash([]byte("ExecutionFailure(bytes32,uint256)"))
+)
+
+// SafeTx is a Safe transaction as hashed and executed by the Safe contract. Gas refund fields are
+// left zero because a relayer pays for execution
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// SafeTxHash computes the EIP-712 hash that Safe owners sign for a transaction
+func SafeTxHash(chainID *big.Int, safe common.Address, tx SafeTx) common.Hash {
+	domainSeparator := crypto.Keccak256Hash(
+		domainTypeHash.Bytes(),
+		uintWord(chainID),
+		common.LeftPadBytes(safe.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256Hash(
+		safeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		uintWord(tx.Value),
+		crypto.Keccak256(tx.Data),
+		uintWord(new(big.Int).SetUint64(uint64(tx.Operation))),
+		uintWord(tx.SafeTxGas),
+		uintWord(tx.BaseGas),
+		uintWord(tx.GasPrice),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		uintWord(tx.Nonce),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// RecoverSafeSigner recovers the owner that signed a Safe transaction hash, accepting V as {0, 1} or {27, 28}
+func RecoverSafeSigner(hash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes", crypto.SignatureLength)
+	}
+	sig := append([]byte{}, signature...)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// EncodeSafeSignatures packs owner signatures in the ascending owner order the Safe requires, with V as 27 or 28
+func EncodeSafeSignatures(signatures map[common.Address][]byte) []byte {
+	owners := make([]common.Address, 0, len(signatures))
+	for owner := range signatures {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return bytes.Compare(owners[i].Bytes(), owners[j].Bytes()) < 0
+	})
+
+	packed := make([]byte, 0, len(owners)*crypto.SignatureLength)
+	for _, owner := range owners {
+		sig := append([]byte{}, signatures[owner]...)
+		if sig[64] < 27 {
+			sig[64] += 27
+		}
+		packed = append(packed, sig...)
+	}
+	return packed
+}
+
+// EncodeExecTransaction encodes an execTransaction call carrying the packed owner signatures
+func EncodeExecTransaction(tx SafeTx, signatures []byte) ([]byte, error) {
+	return safeABI.Pack("execTransaction",
+		tx.To, bigOrZero(tx.Value), tx.Data, tx.Operation,
+		bigOrZero(tx.SafeTxGas), bigOrZero(tx.BaseGas), bigOrZero(tx.GasPrice),
+		tx.GasToken, tx.RefundReceiver, signatures,
+	)
+}
+
+// EncodeSafeSetup encodes the initializer that sets a new Safe's owners, threshold and fallback handler
+func EncodeSafeSetup(owners []common.Address, threshold uint64, fallbackHandler common.Address) ([]byte, error) {
+	if threshold == 0 || threshold > uint64(len(owners)) {
+		return nil, fmt.Errorf("threshold must be between 1 and the number of owners")
+	}
+	return safeABI.Pack("setup",
+		owners, new(big.Int).SetUint64(threshold), common.Address{}, []byte{},
+		fallbackHandler, common.Address{}, big.NewInt(0), common.Address{},
+	)
+}
+
+// EncodeCreateProxy encodes the proxy factory call that deploys a Safe with the given initializer
+func EncodeCreateProxy(singleton common.Address, initializer []byte, saltNonce *big.Int) ([]byte, error) {
+	return safeProxyFactoryABI.Pack("createProxyWithNonce", singleton, initializer, bigOrZero(saltNonce))
+}
+
+// SafeAddressFromReceipt finds the address of a Safe deployed by the proxy factory in a receipt.
+// The proxy address is indexed from Safe v1.4 and part of the log data before that
+func SafeAddressFromReceipt(receipt *types.Receipt, factory common.Address) (common.Address, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != factory || len(log.Topics) == 0 || log.Topics[0] != proxyCreationTopic {
+			continue
+		}
+		if len(log.Topics) > 1 {
+			return common.BytesToAddress(log.Topics[1].Bytes()), nil
+		}
+		if len(log.Data) >= 32 {
+			return common.BytesToAddress(log.Data[:32]), nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("no ProxyCreation event from %s in transaction %s", factory.Hex(), receipt.TxHash.Hex())
+}
+
+// SafeExecutionResult reports whether the Safe transaction with the given hash succeeded in a receipt. A Safe
+// emits ExecutionFailure, and still uses up the nonce, when the call it makes reverts
+func SafeExecutionResult(receipt *types.Receipt, safe common.Address, safeTxHash common.Hash) (bool, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != safe || len(log.Topics) == 0 {
+			continue
+		}
+		if log.Topics[0] != executionSuccessTopic && log.Topics[0] != executionFailureTopic {
+			continue
+		}
+
+		// The transaction hash is indexed from Safe v1.4 and part of the log data before that
+		var hash common.Hash
+		if len(log.Topics) > 1 {
+			hash = log.Topics[1]
+		} else if len(log.Data) >= 32 {
+			hash = common.BytesToHash(log.Data[:32])
+		}
+		if hash == safeTxHash {
+			return log.Topics[0] == executionSuccessTopic, nil
+		}
+	}
+	return false, fmt.Errorf("no execution event for Safe transaction %s in transaction %s", safeTxHash.Hex(), receipt.TxHash.Hex())
+}
+
+// SafeNonce returns the nonce the Safe expects for its next transaction
+func (c *EthereumClient) SafeNonce(ctx context.Context, safe string) (uint64, error) {
+	values, err := c.callSafe(ctx, safe, "nonce")
+	if err != nil {
+		return 0, err
+	}
+	return values[0].(*big.Int).Uint64(), nil
+}
+
+// SafeOwners returns the owners and threshold configured on a Safe
+func (c *EthereumClient) SafeOwners(ctx context.Context, safe string) ([]string, uint64, error) {
+	values, err := c.callSafe(ctx, safe, "getOwners")
+	if err != nil {
+		return nil, 0, err
+	}
+	addresses := values[0].([]common.Address)
+	owners := make([]string, len(addresses))
+	for i, address := range addresses {
+		owners[i] = address.Hex()
+	}
+
+	values, err = c.callSafe(ctx, safe, "getThreshold")
+	if err != nil {
+		return nil, 0, err
+	}
+	return owners, values[0].(*big.Int).Uint64(), nil
+}
+
+// callSafe calls a view method without arguments on a Safe and unpacks its outputs
+func (c *EthereumClient) callSafe(ctx context.Context, safe, method string) ([]interface{}, error) {
+	data, err := safeABI.Pack(method)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := c.CallContract(ctx, "", safe, nil, data)
+	if err != nil {
+		return nil, err
+	}
+	values, err := safeABI.Unpack(method, raw)
+	if err != nil || len(values) != 1 {
+		return nil, fmt.Errorf("%s is not a Safe: invalid %s response", safe, method)
+	}
+	return values, nil
+}
+
+// uintWord left-pads an unsigned integer into a 32-byte ABI word
+func uintWord(n *big.Int) []byte {
+	return common.LeftPadBytes(bigOrZero(n).Bytes(), 32)
+}
+
+// bigOrZero substitutes zero for a nil integer
+func bigOrZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
+
+// Human tasks:
+// - Support Safe versions before v1.3, whose domain separator omits the chain ID
+// - Accept contract (EIP-1271) and pre-approved hash signatures from owners
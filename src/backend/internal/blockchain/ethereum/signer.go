@@ -0,0 +1,73 @@
+package ethereum
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+var (
+	// ErrChainIDMismatch is returned when a transaction targets a different chain than the network
+	ErrChainIDMismatch = errors.NewBadRequestError("transaction chain ID does not match the network")
+
+	// ErrReplayUnprotected is returned for transactions signed without EIP-155 replay protection
+	ErrReplayUnprotected = errors.NewBadRequestError("transaction is not replay protected (EIP-155)")
+)
+
+// SignFunc signs a 32-byte digest and returns a 65-byte [R || S || V] signature with V in {0, 1}
+type SignFunc func(hash []byte) ([]byte, error)
+
+// SignTransaction signs a transaction for a network, always binding the signature to the network's chain ID
+func SignTransaction(tx *types.Transaction, network evm.Network, sign SignFunc) (*types.Transaction, error) {
+	if network.ChainID == nil || network.ChainID.Sign() <= 0 {
+		return nil, errors.NewBadRequestError("network " + network.Name + " has no chain ID")
+	}
+
+	// Typed transactions carry their own chain ID, which must match the network
+	if tx.Type() != types.LegacyTxType && tx.ChainId().Cmp(network.ChainID) != 0 {
+		return nil, ErrChainIDMismatch
+	}
+
+	// Hash with a signer that applies EIP-155 to legacy transactions
+	signer := types.LatestSignerForChainID(network.ChainID)
+	signature, err := sign(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := tx.WithSignature(signer, signature)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid transaction signature: " + err.Error())
+	}
+
+	// Check the result so a faulty signer cannot produce a replayable transaction
+	if err := CheckReplayProtection(signed, network); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// CheckReplayProtection verifies that a signed transaction is EIP-155 protected and bound to the network's chain ID
+func CheckReplayProtection(tx *types.Transaction, network evm.Network) error {
+	if !tx.Protected() {
+		return ErrReplayUnprotected
+	}
+	if network.ChainID == nil || tx.ChainId().Cmp(network.ChainID) != 0 {
+		return ErrChainIDMismatch
+	}
+	return nil
+}
+
+// SignTransaction signs a transaction for the client's network
+func (c *EthereumClient) SignTransaction(tx *types.Transaction, sign SignFunc) (*types.Transaction, error) {
+	signed, err := SignTransaction(tx, c.network, sign)
+	if err != nil {
+		c.log.Error("Failed to sign transaction", "error", err, "network", c.network.Name)
+		return nil, err
+	}
+	return signed, nil
+}
+
+// Human tasks:
+// - Sign through the signature service so keys never leave the configured backend
+// - Reject transactions whose nonce has already been used on the network
@@ -0,0 +1,99 @@
+package ethereum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// Simulate runs a transaction against pending state with eth_call and eth_estimateGas without submitting it
+func (c *EthereumClient) Simulate(ctx context.Context, tx *models.Transaction) (*models.SimulationResult, error) {
+	// Parse the native value and calldata
+	value, ok := new(big.Int).SetString(tx.Amount, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, errors.NewInvalidAmountError("invalid amount: must be a non-negative integer amount in wei")
+	}
+	var data []byte
+	if tx.Data != "" {
+		var err error
+		if data, err = hexutil.Decode(tx.Data); err != nil {
+			return nil, errors.NewBadRequestError("calldata must be a 0x-prefixed hex string")
+		}
+	}
+
+	result := &models.SimulationResult{BlockchainType: c.network.Name, Success: true}
+	msg := callMsg(tx.FromAddress, tx.ToAddress, value, data)
+
+	// Execute the call against pending state to catch reverts
+	err := c.pool.Do(ctx, func(i int) error {
+		_, err := c.clients[i].PendingCallContract(ctx, msg)
+		return err
+	})
+	if err != nil {
+		revertData, reverted := revertDataFromError(err)
+		if !reverted {
+			c.log.Error("Failed to simulate call", "error", err, "network", c.network.Name)
+			return nil, err
+		}
+		result.Fail("execution reverted")
+		result.RevertReason = DecodeRevertReason(nil, revertData)
+		return result, nil
+	}
+
+	// Estimate gas and price it at the current suggested gas price
+	var gasPrice *big.Int
+	err = c.pool.Do(ctx, func(i int) error {
+		var err error
+		if result.GasEstimate, err = c.clients[i].EstimateGas(ctx, msg); err != nil {
+			return err
+		}
+		gasPrice, err = c.clients[i].SuggestGasPrice(ctx)
+		return err
+	})
+	if err != nil {
+		if _, reverted := revertDataFromError(err); reverted {
+			result.Fail("gas estimation reverted")
+			return result, nil
+		}
+		c.log.Error("Failed to estimate gas", "error", err, "network", c.network.Name)
+		return nil, err
+	}
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(result.GasEstimate), gasPrice)
+	result.Fee = fee.String()
+
+	// Check the sender can pay for the value and the fee
+	var balance *big.Int
+	err = c.pool.Do(ctx, func(i int) error {
+		var err error
+		balance, err = c.clients[i].PendingBalanceAt(ctx, msg.From)
+		return err
+	})
+	if err != nil {
+		c.log.Error("Failed to get pending balance", "error", err, "network", c.network.Name)
+		return nil, err
+	}
+	cost := new(big.Int).Add(value, fee)
+	if balance.Cmp(cost) < 0 {
+		result.Fail("insufficient " + c.network.NativeSymbol + " balance for value and fee")
+	}
+
+	// Native balance deltas; token movements inside contract calls are not traced
+	result.BalanceDeltas = []models.BalanceDelta{
+		{Address: tx.FromAddress, Asset: c.network.NativeSymbol, Amount: new(big.Int).Neg(cost).String()},
+	}
+	if value.Sign() > 0 {
+		result.BalanceDeltas = append(result.BalanceDeltas, models.BalanceDelta{Address: tx.ToAddress, Asset: c.network.NativeSymbol, Amount: value.String()})
+	}
+	if len(data) > 0 {
+		result.Warnings = append(result.Warnings, "token balance changes made by the contract call are not included")
+	}
+
+	return result, nil
+}
+
+// Human tasks:
+// - Trace the call with debug_traceCall to report token transfers
+// - Price EIP-1559 transactions with the base fee and priority fee instead of the legacy gas price
@@ -0,0 +1,142 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+)
+
+// Network describes an EVM network; its name is the blockchain type used by vaults and transactions
+type Network struct {
+	Name          string   `json:"name"`
+	ChainID       *big.Int `json:"chain_id"`
+	NativeSymbol  string   `json:"native_symbol"`
+	Confirmations uint64   `json:"confirmations"`
+	Testnet       bool     `json:"testnet"`
+	RPCEndpoints  []string `json:"-"`
+}
+
+// defaultNetworks are the built-in EVM networks; RPC endpoints always come from configuration
+var defaultNetworks = []Network{
+	{Name: "ethereum", ChainID: big.NewInt(1), NativeSymbol: "ETH", Confirmations: 12},
+	{Name: "ethereum-sepolia", ChainID: big.NewInt(11155111), NativeSymbol: "ETH", Confirmations: 3, Testnet: true},
+	{Name: "polygon", ChainID: big.NewInt(137), NativeSymbol: "POL", Confirmations: 128},
+	{Name: "arbitrum", ChainID: big.NewInt(42161), NativeSymbol: "ETH", Confirmations: 20},
+	{Name: "base", ChainID: big.NewInt(8453), NativeSymbol: "ETH", Confirmations: 10},
+	{Name: "evm-devnet", ChainID: big.NewInt(31337), NativeSymbol: "ETH", Confirmations: 1, Testnet: true},
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Network{}
+)
+
+func init() {
+	for _, network := range defaultNetworks {
+		registry[network.Name] = network
+	}
+}
+
+// LoadNetworks merges the configured networks into the built-in ones and registers the result
+func LoadNetworks(cfgs []config.EVMNetworkConfig) ([]Network, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	// Start from the currently registered networks
+	merged := make(map[string]Network, len(registry)+len(cfgs))
+	for name, network := range registry {
+		merged[name] = network
+	}
+
+	for _, cfg := range cfgs {
+		name := strings.ToLower(cfg.Name)
+		if name == "" {
+			return nil, fmt.Errorf("EVM network name is required")
+		}
+
+		// Configured values override the defaults of a built-in network
+		network, known := merged[name]
+		if !known {
+			if cfg.ChainID <= 0 {
+				return nil, fmt.Errorf("EVM network %s requires a chain ID", name)
+			}
+			network = Network{Name: name, NativeSymbol: "ETH", Confirmations: 1}
+		}
+		if cfg.ChainID > 0 {
+			network.ChainID = big.NewInt(cfg.ChainID)
+		}
+		if cfg.NativeSymbol != "" {
+			network.NativeSymbol = cfg.NativeSymbol
+		}
+		if cfg.Confirmations > 0 {
+			network.Confirmations = cfg.Confirmations
+		}
+		if cfg.Testnet {
+			network.Testnet = true
+		}
+		if len(cfg.RPCEndpoints) > 0 {
+			network.RPCEndpoints = cfg.RPCEndpoints
+		}
+		merged[name] = network
+	}
+
+	// Two networks sharing a chain ID would defeat replay protection
+	seen := make(map[string]string, len(merged))
+	for name, network := range merged {
+		id := network.ChainID.String()
+		if other, ok := seen[id]; ok {
+			return nil, fmt.Errorf("EVM networks %s and %s share chain ID %s", other, name, id)
+		}
+		seen[id] = name
+	}
+
+	registry = merged
+	return sortedNetworks(merged), nil
+}
+
+// Lookup returns the EVM network for a blockchain type
+func Lookup(blockchainType string) (Network, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	network, ok := registry[strings.ToLower(blockchainType)]
+	return network, ok
+}
+
+// IsNetwork reports whether a blockchain type identifies an EVM network
+func IsNetwork(blockchainType string) bool {
+	_, ok := Lookup(blockchainType)
+	return ok
+}
+
+// Names returns the names of every registered EVM network in alphabetical order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedNetworks returns the networks ordered by name
+func sortedNetworks(networks map[string]Network) []Network {
+	result := make([]Network, 0, len(networks))
+	for _, network := range networks {
+		result = append(result, network)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Human tasks:
+// - Add per-network gas price strategies (EIP-1559 versus legacy)
+// - Use finalized block tags instead of confirmation depth on networks that support them
+// - Load network definitions from the database so they can be changed without a restart
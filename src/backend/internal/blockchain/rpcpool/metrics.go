@@ -0,0 +1,36 @@
+package rpcpool
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// endpointHealthy is 1 when an endpoint is healthy and 0 otherwise
+	endpointHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_endpoint_healthy",
+		Help: "Whether the RPC endpoint is currently healthy",
+	}, []string{"chain", "endpoint"})
+
+	// endpointLatency is the moving average latency of an endpoint
+	endpointLatency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_endpoint_latency_seconds",
+		Help: "Moving average latency of the RPC endpoint",
+	}, []string{"chain", "endpoint"})
+
+	// endpointHeadLag is how many blocks an endpoint trails the best known head
+	endpointHeadLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rpc_endpoint_head_lag_blocks",
+		Help: "Number of blocks the RPC endpoint trails the best known head",
+	}, []string{"chain", "endpoint"})
+
+	// endpointRequests counts requests sent to an endpoint by result
+	endpointRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rpc_endpoint_requests_total",
+		Help: "Total requests sent to the RPC endpoint",
+	}, []string{"chain", "endpoint", "result"})
+)
+
+// Human tasks:
+// - Add a latency histogram once the dashboards need percentiles
+// - Add RPC endpoint panels to the Grafana dashboard
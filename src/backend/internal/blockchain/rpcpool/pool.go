@@ -0,0 +1,367 @@
+package rpcpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+const (
+	// defaultProbeInterval is how often endpoints are probed when no interval is configured
+	defaultProbeInterval = 15 * time.Second
+
+	// defaultProbeTimeout bounds a single health probe
+	defaultProbeTimeout = 5 * time.Second
+
+	// defaultMaxHeadLag is how many blocks an endpoint may trail the best head and stay healthy
+	defaultMaxHeadLag = 3
+
+	// defaultBroadcastFanout is how many endpoints receive each write
+	defaultBroadcastFanout = 2
+
+	// maxConsecutiveFailures marks an endpoint unhealthy until its next successful probe
+	maxConsecutiveFailures = 3
+
+	// latencyWeight is the weight of the newest sample in the latency moving average
+	latencyWeight = 0.3
+)
+
+// ErrNoEndpoints is returned when a pool is created without any endpoints
+var ErrNoEndpoints = errors.New("no RPC endpoints configured")
+
+// ProbeFunc returns the chain head seen by the endpoint at index i
+type ProbeFunc func(ctx context.Context, i int) (uint64, error)
+
+// Options configures health probing and failover
+type Options struct {
+	ProbeInterval   time.Duration
+	ProbeTimeout    time.Duration
+	MaxHeadLag      uint64
+	BroadcastFanout int
+	// Retryable reports whether a request error should fail over to the next endpoint;
+	// errors that are answers from a healthy node (e.g. not found) should return false
+	Retryable func(error) bool
+}
+
+// NewOptions creates pool options from the RPC pool configuration
+func NewOptions(cfg config.RPCPoolConfig) Options {
+	return Options{
+		ProbeInterval:   cfg.ProbeInterval,
+		ProbeTimeout:    cfg.ProbeTimeout,
+		MaxHeadLag:      cfg.MaxHeadLag,
+		BroadcastFanout: cfg.BroadcastFanout,
+	}
+}
+
+// Endpoints returns the configured endpoint list, falling back to a single legacy endpoint
+func Endpoints(endpoints []string, fallback string) []string {
+	if len(endpoints) == 0 && fallback != "" {
+		return []string{fallback}
+	}
+	return endpoints
+}
+
+// EndpointStats is a snapshot of the health of an endpoint
+type EndpointStats struct {
+	Endpoint  string        `json:"endpoint"`
+	Healthy   bool          `json:"healthy"`
+	Latency   time.Duration `json:"latency"`
+	Head      uint64        `json:"head"`
+	HeadLag   uint64        `json:"head_lag"`
+	Requests  uint64        `json:"requests"`
+	Errors    uint64        `json:"errors"`
+	LastError string        `json:"last_error,omitempty"`
+	LastProbe time.Time     `json:"last_probe"`
+}
+
+// endpoint tracks the health of a single RPC endpoint
+type endpoint struct {
+	stats    EndpointStats
+	failures int
+}
+
+// Pool spreads requests for one chain over several RPC endpoints, preferring
+// healthy, low-latency endpoints that are close to the chain head
+type Pool struct {
+	chain     string
+	probe     ProbeFunc
+	opts      Options
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	log       *logger.Logger
+}
+
+// NewPool creates a pool over the given endpoint URLs; the pool refers to endpoints by their index
+func NewPool(chain string, urls []string, probe ProbeFunc, opts Options, log *logger.Logger) (*Pool, error) {
+	if len(urls) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	// Fall back to defaults for any unset options
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = defaultProbeInterval
+	}
+	if opts.ProbeTimeout <= 0 {
+		opts.ProbeTimeout = defaultProbeTimeout
+	}
+	if opts.MaxHeadLag == 0 {
+		opts.MaxHeadLag = defaultMaxHeadLag
+	}
+	if opts.BroadcastFanout <= 0 {
+		opts.BroadcastFanout = defaultBroadcastFanout
+	}
+	if opts.Retryable == nil {
+		opts.Retryable = func(error) bool { return true }
+	}
+
+	// Endpoints start out healthy so requests can be served before the first probe
+	endpoints := make([]*endpoint, len(urls))
+	for i, raw := range urls {
+		endpoints[i] = &endpoint{stats: EndpointStats{Endpoint: endpointName(raw, i), Healthy: true}}
+		endpointHealthy.WithLabelValues(chain, endpoints[i].stats.Endpoint).Set(1)
+	}
+
+	return &Pool{
+		chain:     chain,
+		probe:     probe,
+		opts:      opts,
+		endpoints: endpoints,
+		log:       log,
+	}, nil
+}
+
+// Do calls fn on the best endpoint, failing over to the next one on retryable errors
+func (p *Pool) Do(ctx context.Context, fn func(i int) error) error {
+	var lastErr error
+	for _, i := range p.ranked() {
+		start := time.Now()
+		err := fn(i)
+		if err == nil || !p.opts.Retryable(err) {
+			// A non-retryable error is still a valid answer from the endpoint
+			p.record(i, time.Since(start), nil)
+			return err
+		}
+		p.record(i, time.Since(start), err)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		p.log.Error("RPC request failed, failing over", "chain", p.chain, "endpoint", p.name(i), "error", err)
+	}
+	return lastErr
+}
+
+// Broadcast calls fn concurrently on the best endpoints and succeeds if any of them succeeds
+func (p *Pool) Broadcast(ctx context.Context, fn func(i int) error) error {
+	targets := p.ranked()
+	if len(targets) > p.opts.BroadcastFanout {
+		targets = targets[:p.opts.BroadcastFanout]
+	}
+
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for n, i := range targets {
+		wg.Add(1)
+		go func(n, i int) {
+			defer wg.Done()
+			start := time.Now()
+			errs[n] = fn(i)
+			p.record(i, time.Since(start), errs[n])
+		}(n, i)
+	}
+	wg.Wait()
+
+	// Any endpoint accepting the write is enough, since nodes gossip it to the network
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("broadcast failed on %d endpoints: %w", len(errs), errs[0])
+}
+
+// Probe checks every endpoint once, measuring latency and how far it trails the best head
+func (p *Pool) Probe(ctx context.Context) {
+	type result struct {
+		head    uint64
+		latency time.Duration
+		err     error
+	}
+
+	// Probe all endpoints concurrently
+	results := make([]result, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i := range p.endpoints {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, p.opts.ProbeTimeout)
+			defer cancel()
+			start := time.Now()
+			head, err := p.probe(probeCtx, i)
+			results[i] = result{head: head, latency: time.Since(start), err: err}
+		}(i)
+	}
+	wg.Wait()
+
+	// The best head among the responding endpoints is the reference for lag
+	var bestHead uint64
+	for _, r := range results {
+		if r.err == nil && r.head > bestHead {
+			bestHead = r.head
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i, r := range results {
+		e := p.endpoints[i]
+		e.stats.LastProbe = now
+		if r.err != nil {
+			e.stats.Healthy = false
+			e.stats.LastError = r.err.Error()
+			p.log.Error("RPC endpoint probe failed", "chain", p.chain, "endpoint", e.stats.Endpoint, "error", r.err)
+		} else {
+			e.stats.Head = r.head
+			e.stats.HeadLag = bestHead - r.head
+			e.stats.Healthy = e.stats.HeadLag <= p.opts.MaxHeadLag
+			e.failures = 0
+			e.observeLatency(r.latency)
+		}
+		p.publish(e)
+	}
+}
+
+// Run probes the endpoints on every tick until the context is cancelled
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.ProbeInterval)
+	defer ticker.Stop()
+
+	p.Probe(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Probe(ctx)
+		}
+	}
+}
+
+// Stats returns a snapshot of every endpoint's health
+func (p *Pool) Stats() []EndpointStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := make([]EndpointStats, len(p.endpoints))
+	for i, e := range p.endpoints {
+		stats[i] = e.stats
+	}
+	return stats
+}
+
+// Chain returns the chain served by the pool
+func (p *Pool) Chain() string {
+	return p.chain
+}
+
+// Len returns the number of endpoints in the pool
+func (p *Pool) Len() int {
+	return len(p.endpoints)
+}
+
+// ranked orders the endpoints from best to worst: healthy before unhealthy, then by head lag and latency
+func (p *Pool) ranked() []int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	order := make([]int, len(p.endpoints))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := p.endpoints[order[a]].stats, p.endpoints[order[b]].stats
+		if ea.Healthy != eb.Healthy {
+			return ea.Healthy
+		}
+		if ea.HeadLag != eb.HeadLag {
+			return ea.HeadLag < eb.HeadLag
+		}
+		return ea.Latency < eb.Latency
+	})
+	return order
+}
+
+// record updates an endpoint's statistics after a request
+func (p *Pool) record(i int, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e := p.endpoints[i]
+	e.stats.Requests++
+	if err != nil {
+		e.stats.Errors++
+		e.stats.LastError = err.Error()
+		e.failures++
+		if e.failures >= maxConsecutiveFailures {
+			e.stats.Healthy = false
+		}
+		endpointRequests.WithLabelValues(p.chain, e.stats.Endpoint, "error").Inc()
+	} else {
+		e.failures = 0
+		e.observeLatency(latency)
+		endpointRequests.WithLabelValues(p.chain, e.stats.Endpoint, "success").Inc()
+	}
+	p.publish(e)
+}
+
+// publish exports an endpoint's statistics as metrics; callers must hold the lock
+func (p *Pool) publish(e *endpoint) {
+	healthy := 0.0
+	if e.stats.Healthy {
+		healthy = 1
+	}
+	endpointHealthy.WithLabelValues(p.chain, e.stats.Endpoint).Set(healthy)
+	endpointLatency.WithLabelValues(p.chain, e.stats.Endpoint).Set(e.stats.Latency.Seconds())
+	endpointHeadLag.WithLabelValues(p.chain, e.stats.Endpoint).Set(float64(e.stats.HeadLag))
+}
+
+// name returns the display name of the endpoint at index i
+func (p *Pool) name(i int) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.endpoints[i].stats.Endpoint
+}
+
+// observeLatency folds a latency sample into the moving average
+func (e *endpoint) observeLatency(latency time.Duration) {
+	if e.stats.Latency == 0 {
+		e.stats.Latency = latency
+		return
+	}
+	e.stats.Latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(e.stats.Latency))
+}
+
+// endpointName reduces an endpoint URL to its scheme and host so API keys in paths never reach logs or metrics
+func endpointName(raw string, i int) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Sprintf("endpoint-%d", i)
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// Human tasks:
+// - Weight endpoints by provider rate limits and cost
+// - Expose pool statistics through an admin API endpoint
+// - Deduplicate endpoint names when several endpoints share a host
@@ -2,32 +2,78 @@ package xrp
 
 import (
 	"context"
+	"sync"
+
 	"github.com/rubblelabs/ripple/websockets"
 	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/rpcpool"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 )
 
+// lsfRequireDestTag is the AccountRoot flag set on accounts that require a destination tag
+const lsfRequireDestTag = 0x00020000
+
+// ErrDestinationTagRequired is returned when a payment to a RequireDest account has no destination tag
+var ErrDestinationTagRequired = errors.NewBadRequestError("destination account requires a destination tag")
+
 // XRPClient represents the XRP client
 type XRPClient struct {
-	client *websockets.Remote
-	log    *logger.Logger
+	clients []*websockets.Remote
+	pool    *rpcpool.Pool
+	log     *logger.Logger
 }
 
 // NewXRPClient creates a new XRP client
 func NewXRPClient(cfg *config.Config, log *logger.Logger) (*XRPClient, error) {
-	// Create a new XRP WebSocket client using the provided URL from the configuration
-	client, err := websockets.NewRemote(cfg.XRPWebSocketURL)
+	// Connect to every configured WebSocket endpoint, skipping the ones that cannot be reached
+	var urls []string
+	var clients []*websockets.Remote
+	for _, url := range rpcpool.Endpoints(cfg.Blockchain.XRPEndpoints, cfg.Blockchain.XRPRPC) {
+		client, err := websockets.NewRemote(url)
+		if err != nil {
+			log.Error("Failed to create XRP WebSocket client", "error", err)
+			continue
+		}
+		urls = append(urls, url)
+		clients = append(clients, client)
+	}
+
+	c := &XRPClient{
+		clients: clients,
+		log:     log,
+	}
+
+	// Create the endpoint pool, probing each endpoint for its latest validated ledger
+	opts := rpcpool.NewOptions(cfg.Blockchain.RPCPool)
+	opts.Retryable = isRetryable
+	pool, err := rpcpool.NewPool("xrp", urls, func(ctx context.Context, i int) (uint64, error) {
+		result, err := c.clients[i].Ledger("validated", false)
+		if err != nil {
+			return 0, err
+		}
+		return uint64(result.Ledger.LedgerSequence), nil
+	}, opts, log)
 	if err != nil {
-		log.Error("Failed to create XRP WebSocket client", "error", err)
+		log.Error("Failed to create XRP endpoint pool", "error", err)
 		return nil, err
 	}
+	c.pool = pool
 
-	// If successful, create and return a new XRPClient instance
-	return &XRPClient{
-		client: client,
-		log:    log,
-	}, nil
+	// If successful, return the new XRPClient instance
+	return c, nil
+}
+
+// Run probes the health of the WebSocket endpoints until the context is cancelled
+func (c *XRPClient) Run(ctx context.Context) {
+	c.pool.Run(ctx)
+}
+
+// EndpointStats returns the health of every WebSocket endpoint
+func (c *XRPClient) EndpointStats() []rpcpool.EndpointStats {
+	return c.pool.Stats()
 }
 
 // GetAccountInfo retrieves account information for an XRP address
@@ -35,8 +81,13 @@ func (c *XRPClient) GetAccountInfo(ctx context.Context, address string) (*data.A
 	// Create an account request with the provided address
 	req := &data.AccountInfoRequest{Account: address}
 
-	// Call the client's Account method to get the account information
-	result, err := c.client.Account(ctx, req)
+	// Call Account on the best endpoint, failing over on errors
+	var result *data.AccountInfo
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		result, err = c.clients[i].Account(ctx, req)
+		return err
+	})
 	if err != nil {
 		c.log.Error("Failed to get account information", "address", address, "error", err)
 		return nil, err
@@ -47,9 +98,11 @@ func (c *XRPClient) GetAccountInfo(ctx context.Context, address string) (*data.A
 }
 
 // SubmitTransaction submits an XRP transaction
-func (c *XRPClient) SubmitTransaction(ctx context.Context, tx *data.Transaction) (*data.SubmitResult, error) {
-	// Call the client's Submit method to submit the transaction
-	result, err := c.client.Submit(ctx, tx)
+func (c *XRPClient) SubmitTransaction(ctx context.Context, tx data.Transaction) (*data.SubmitResult, error) {
+	// Broadcast the transaction to several endpoints, keeping the first result
+	result, err := c.broadcast(ctx, func(i int) (*data.SubmitResult, error) {
+		return c.clients[i].Submit(ctx, tx)
+	})
 	if err != nil {
 		c.log.Error("Failed to submit transaction", "error", err)
 		return nil, err
@@ -59,13 +112,33 @@ func (c *XRPClient) SubmitTransaction(ctx context.Context, tx *data.Transaction)
 	return result, nil
 }
 
+// SubmitMultisigned submits a multi-signed transaction blob using submit_multisigned
+func (c *XRPClient) SubmitMultisigned(ctx context.Context, txBlob string) (*data.SubmitResult, error) {
+	// Broadcast the multi-signed blob to several endpoints, keeping the first result
+	result, err := c.broadcast(ctx, func(i int) (*data.SubmitResult, error) {
+		return c.clients[i].SubmitMultisigned(ctx, txBlob)
+	})
+	if err != nil {
+		c.log.Error("Failed to submit multi-signed transaction", "error", err)
+		return nil, err
+	}
+
+	// If successful, return the submission result
+	return result, nil
+}
+
 // GetTransaction retrieves transaction details
 func (c *XRPClient) GetTransaction(ctx context.Context, txHash string) (*data.TransactionWithMetaData, error) {
 	// Create a transaction request with the provided transaction hash
 	req := &data.TxRequest{Transaction: txHash}
 
-	// Call the client's Tx method to get the transaction details
-	result, err := c.client.Tx(ctx, req)
+	// Call Tx on the best endpoint, failing over on errors
+	var result *data.TransactionWithMetaData
+	err := c.pool.Do(ctx, func(i int) error {
+		var err error
+		result, err = c.clients[i].Tx(ctx, req)
+		return err
+	})
 	if err != nil {
 		c.log.Error("Failed to get transaction details", "txHash", txHash, "error", err)
 		return nil, err
@@ -75,8 +148,155 @@ func (c *XRPClient) GetTransaction(ctx context.Context, txHash string) (*data.Tr
 	return result, nil
 }
 
+// isRetryable reports whether a request error should fail over to another endpoint
+func isRetryable(err error) bool {
+	// Missing accounts and transactions are answers from a healthy node and would be missing on every endpoint
+	if isAccountNotFound(err) || isTransactionNotFound(err) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// GetValidatedTransaction returns a transaction once it is in a validated ledger, or nil while it is unknown or
+// only in a ledger that is not yet validated
+func (c *XRPClient) GetValidatedTransaction(ctx context.Context, txHash string) (*data.TransactionWithMetaData, error) {
+	result, err := c.GetTransaction(ctx, txHash)
+	if err != nil {
+		if isTransactionNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Transactions in ledgers past the latest validated one may still change
+	latest, err := c.LatestLedger(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if result.LedgerSequence == 0 || result.LedgerSequence > latest {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// broadcast submits to several endpoints and returns the first successful result
+func (c *XRPClient) broadcast(ctx context.Context, submit func(i int) (*data.SubmitResult, error)) (*data.SubmitResult, error) {
+	var mu sync.Mutex
+	var first *data.SubmitResult
+	err := c.pool.Broadcast(ctx, func(i int) error {
+		result, err := submit(i)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if first == nil {
+			first = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return first, nil
+}
+
+// RequiresDestinationTag reports whether an account has the RequireDest flag set
+func RequiresDestinationTag(info *data.AccountInfo) bool {
+	// Accounts without flags never require a destination tag
+	if info == nil || info.Flags == nil {
+		return false
+	}
+
+	return uint32(*info.Flags)&lsfRequireDestTag != 0
+}
+
+// ValidateTransaction refuses payments without a destination tag to accounts that require one
+func (c *XRPClient) ValidateTransaction(ctx context.Context, tx *models.Transaction) error {
+	// Tagged payments satisfy any destination
+	if tx.DestinationTag != nil {
+		return nil
+	}
+
+	// Look up the destination account; one that does not exist yet cannot require a tag
+	info, err := c.GetAccountInfo(ctx, tx.ToAddress)
+	if err != nil {
+		if isAccountNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if RequiresDestinationTag(info) {
+		c.log.Error("Refusing payment without destination tag", "transactionID", tx.ID, "destination", tx.ToAddress)
+		return ErrDestinationTagRequired
+	}
+
+	return nil
+}
+
+// BuildPayment builds an XRP payment from a transaction model, including tags and memos
+func BuildPayment(tx *models.Transaction) (*data.Payment, error) {
+	// Parse the source and destination accounts
+	account, err := data.NewAccountFromAddress(tx.FromAddress)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid source address")
+	}
+	destination, err := data.NewAccountFromAddress(tx.ToAddress)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid destination address")
+	}
+
+	// Parse the amount to send
+	amount, err := data.NewAmount(tx.Amount)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid amount")
+	}
+
+	// Create the payment with the optional destination tag
+	payment := &data.Payment{
+		Destination:    *destination,
+		Amount:         *amount,
+		DestinationTag: tx.DestinationTag,
+	}
+	payment.TransactionType = data.PAYMENT
+	payment.Account = *account
+	payment.SourceTag = tx.SourceTag
+
+	// Attach the memos, which the ledger stores as raw bytes
+	for _, m := range tx.Memos {
+		var memo data.Memo
+		memo.Memo.MemoType = data.VariableLength(m.MemoType)
+		memo.Memo.MemoData = data.VariableLength(m.MemoData)
+		memo.Memo.MemoFormat = data.VariableLength(m.MemoFormat)
+		payment.Memos = append(payment.Memos, memo)
+	}
+
+	return payment, nil
+}
+
+// SubmitPayment builds and submits a payment, refusing it when a required destination tag is missing
+func (c *XRPClient) SubmitPayment(ctx context.Context, tx *models.Transaction) (string, error) {
+	// Check whether the destination requires a tag before anything is submitted
+	if err := c.ValidateTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+
+	// Build the payment from the transaction model
+	payment, err := BuildPayment(tx)
+	if err != nil {
+		return "", err
+	}
+
+	// Submit the payment to the ledger
+	result, err := c.SubmitTransaction(ctx, payment)
+	if err != nil {
+		return "", err
+	}
+
+	return result.TxJson.GetHash().String(), nil
+}
+
 // Human tasks:
-// TODO: Implement error handling and retries for network failures
 // TODO: Add support for subscribing to ledger and transaction streams
 // TODO: Implement methods for working with XRP payment channels
 // TODO: Add support for multi-signing transactions
@@ -0,0 +1,196 @@
+package xrp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// rippleEpoch is the start of the XRP Ledger time scale (2000-01-01T00:00:00Z)
+const rippleEpoch = 946684800
+
+// fulfillmentFeeDrops is the cost of an EscrowFinish with a fulfillment, plus fulfillmentFeeDropsPer16 for every
+// 16 bytes of fulfillment
+const (
+	fulfillmentFeeDrops      = 330
+	fulfillmentFeeDropsPer16 = 10
+)
+
+// preimageSize is the size of the random preimages generated for PREIMAGE-SHA-256 conditions
+const preimageSize = 32
+
+// ToRippleTime converts a time into seconds since the ripple epoch
+func ToRippleTime(t time.Time) uint32 {
+	return uint32(t.Unix() - rippleEpoch)
+}
+
+// NewPreimageCondition generates a random preimage and returns the hex-encoded PREIMAGE-SHA-256 condition and fulfillment
+func NewPreimageCondition() (string, string, error) {
+	// Generate a random preimage
+	preimage, err := utils.GenerateRandomBytes(preimageSize)
+	if err != nil {
+		return "", "", err
+	}
+
+	condition, fulfillment := PreimageCondition(preimage)
+	return hex.EncodeToString(condition), hex.EncodeToString(fulfillment), nil
+}
+
+// PreimageCondition encodes the PREIMAGE-SHA-256 condition and fulfillment for a preimage
+func PreimageCondition(preimage []byte) ([]byte, []byte) {
+	// The fulfillment is [0] { preimage [0] OCTET STRING }
+	fulfillment := derTag(0xA0, derTag(0x80, preimage))
+
+	// The condition is [0] { fingerprint [0] OCTET STRING, cost [1] INTEGER }
+	fingerprint := sha256.Sum256(preimage)
+	condition := derTag(0xA0, append(derTag(0x80, fingerprint[:]), derTag(0x81, derUint(uint64(len(preimage))))...))
+
+	return condition, fulfillment
+}
+
+// derTag encodes a DER tag-length-value
+func derTag(tag byte, value []byte) []byte {
+	out := []byte{tag}
+	if len(value) < 0x80 {
+		out = append(out, byte(len(value)))
+	} else {
+		length := derUint(uint64(len(value)))
+		out = append(out, 0x80|byte(len(length)))
+		out = append(out, length...)
+	}
+	return append(out, value...)
+}
+
+// derUint encodes an unsigned integer using the minimal number of bytes
+func derUint(v uint64) []byte {
+	if v == 0 {
+		return []byte{0}
+	}
+	var out []byte
+	for v > 0 {
+		out = append([]byte{byte(v)}, out...)
+		v >>= 8
+	}
+	return out
+}
+
+// BuildEscrowCreate builds an EscrowCreate transaction from an escrow model
+func BuildEscrowCreate(escrow *models.Escrow, destinationTag *uint32) (*data.EscrowCreate, error) {
+	// An escrow must be released by time, by condition, or both
+	if escrow.FinishAfter == nil && escrow.Condition == "" {
+		return nil, errors.NewBadRequestError("escrow requires a finish time or a crypto-condition")
+	}
+	if escrow.FinishAfter == nil && escrow.CancelAfter == nil {
+		return nil, errors.NewBadRequestError("escrow requires a finish time or a cancel time")
+	}
+	if escrow.FinishAfter != nil && escrow.CancelAfter != nil && !escrow.CancelAfter.After(*escrow.FinishAfter) {
+		return nil, errors.NewBadRequestError("escrow cancel time must be after its finish time")
+	}
+
+	// Parse the owner, destination and amount
+	owner, err := data.NewAccountFromAddress(escrow.Owner)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid escrow owner")
+	}
+	destination, err := data.NewAccountFromAddress(escrow.Destination)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid escrow destination")
+	}
+	amount, err := data.NewAmount(escrow.Amount)
+	if err != nil || !amount.IsNative() {
+		return nil, errors.NewBadRequestError("escrow amount must be a native XRP amount")
+	}
+
+	tx := &data.EscrowCreate{
+		Destination:    *destination,
+		Amount:         *amount,
+		DestinationTag: destinationTag,
+	}
+	tx.TransactionType = data.ESCROW_CREATE
+	tx.Account = *owner
+
+	// Attach the time conditions
+	if escrow.FinishAfter != nil {
+		finishAfter := ToRippleTime(*escrow.FinishAfter)
+		tx.FinishAfter = &finishAfter
+	}
+	if escrow.CancelAfter != nil {
+		cancelAfter := ToRippleTime(*escrow.CancelAfter)
+		tx.CancelAfter = &cancelAfter
+	}
+
+	// Attach the crypto-condition
+	if escrow.Condition != "" {
+		condition, err := hex.DecodeString(escrow.Condition)
+		if err != nil {
+			return nil, errors.NewBadRequestError("invalid escrow condition")
+		}
+		tx.Condition = (*data.VariableLength)(&condition)
+	}
+
+	return tx, nil
+}
+
+// BuildEscrowFinish builds an EscrowFinish transaction releasing the escrow to its destination. Conditional
+// escrows need their hex-encoded fulfillment
+func BuildEscrowFinish(escrow *models.Escrow, fulfillment string) (*data.EscrowFinish, error) {
+	owner, err := data.NewAccountFromAddress(escrow.Owner)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid escrow owner")
+	}
+
+	tx := &data.EscrowFinish{
+		Owner:         *owner,
+		OfferSequence: escrow.OfferSequence,
+	}
+	tx.TransactionType = data.ESCROW_FINISH
+	tx.Account = *owner
+
+	// Conditional escrows must present both the condition and its fulfillment
+	if escrow.Condition != "" {
+		condition, err := hex.DecodeString(escrow.Condition)
+		if err != nil {
+			return nil, errors.NewBadRequestError("invalid escrow condition")
+		}
+		rawFulfillment, err := hex.DecodeString(fulfillment)
+		if err != nil || len(rawFulfillment) == 0 {
+			return nil, errors.NewBadRequestError("escrow fulfillment is not available")
+		}
+		tx.Condition = (*data.VariableLength)(&condition)
+		tx.Fulfillment = (*data.VariableLength)(&rawFulfillment)
+
+		// Verifying the fulfillment costs more than the reference fee
+		fee, err := data.NewNativeValue(int64(fulfillmentFeeDrops + fulfillmentFeeDropsPer16*((len(rawFulfillment)+15)/16)))
+		if err != nil {
+			return nil, err
+		}
+		tx.Fee = *fee
+	}
+
+	return tx, nil
+}
+
+// BuildEscrowCancel builds an EscrowCancel transaction returning the escrow to its owner
+func BuildEscrowCancel(escrow *models.Escrow) (*data.EscrowCancel, error) {
+	owner, err := data.NewAccountFromAddress(escrow.Owner)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid escrow owner")
+	}
+
+	tx := &data.EscrowCancel{
+		Owner:         *owner,
+		OfferSequence: escrow.OfferSequence,
+	}
+	tx.TransactionType = data.ESCROW_CANCEL
+	tx.Account = *owner
+
+	return tx, nil
+}
+
+// Human tasks:
+// TODO: Add support for the other crypto-condition types (PREFIX, THRESHOLD, ED25519)
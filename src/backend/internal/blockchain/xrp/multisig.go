@@ -0,0 +1,331 @@
+package xrp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// maxSignerEntries is the maximum number of entries the XRP Ledger accepts in a signer list
+const maxSignerEntries = 32
+
+// multiSignPrefix is the hash prefix ("SMT\0") used when signing a transaction for multi-signing
+var multiSignPrefix = []byte{0x53, 0x4D, 0x54, 0x00}
+
+// SignatureRequester is the part of the signature service used to collect partial signatures
+type SignatureRequester interface {
+	RequestSignature(ctx context.Context, request *models.SignatureRequest) (*models.SignatureRequest, error)
+	GetSignatureStatus(ctx context.Context, id string) (*models.SignatureRequest, error)
+}
+
+// MultiSigner coordinates native XRP Ledger multi-signing for a vault
+type MultiSigner struct {
+	client     *XRPClient
+	signatures SignatureRequester
+	vaultKey   *VaultSigner
+	log        *logger.Logger
+}
+
+// NewMultiSigner creates a new MultiSigner instance
+func NewMultiSigner(client *XRPClient, signatures SignatureRequester, log *logger.Logger) *MultiSigner {
+	return &MultiSigner{
+		client:     client,
+		signatures: signatures,
+		vaultKey:   NewVaultSigner(client, signatures, log),
+		log:        log,
+	}
+}
+
+// ValidateMultiSigConfig checks that a signer list can be accepted by the ledger
+func ValidateMultiSigConfig(account string, cfg *models.MultiSigConfig) error {
+	if cfg == nil {
+		return errors.NewBadRequestError("multisig configuration is required")
+	}
+	if cfg.Quorum == 0 {
+		return errors.NewBadRequestError("multisig quorum must be greater than zero")
+	}
+	if len(cfg.Signers) == 0 || len(cfg.Signers) > maxSignerEntries {
+		return errors.NewBadRequestError("multisig signer list must contain between 1 and 32 signers")
+	}
+
+	// Check every signer and sum the available weight. Each signer needs its own account and backend, or one
+	// backend could meet the quorum alone
+	var total uint32
+	seen := make(map[string]bool, len(cfg.Signers))
+	backends := make(map[string]bool, len(cfg.Signers))
+	for _, signer := range cfg.Signers {
+		if signer.Account == account {
+			return errors.NewBadRequestError("vault account cannot be a member of its own signer list")
+		}
+		if seen[signer.Account] {
+			return errors.NewBadRequestError("duplicate signer in multisig signer list: " + signer.Account)
+		}
+		if backends[signer.Backend] {
+			return errors.NewBadRequestError("multisig signers must use different signer backends: " + signer.Account)
+		}
+		if signer.Weight == 0 {
+			return errors.NewBadRequestError("multisig signer weight must be greater than zero")
+		}
+		if _, err := data.NewAccountFromAddress(signer.Account); err != nil {
+			return errors.NewBadRequestError("invalid multisig signer account: " + signer.Account)
+		}
+		seen[signer.Account] = true
+		backends[signer.Backend] = true
+		total += uint32(signer.Weight)
+	}
+
+	// The quorum must be reachable with the configured weights
+	if total < cfg.Quorum {
+		return errors.NewBadRequestError("multisig quorum exceeds the total signer weight")
+	}
+
+	return nil
+}
+
+// BuildSignerListSet builds the SignerListSet transaction that installs a vault's signer list
+func BuildSignerListSet(account string, cfg *models.MultiSigConfig) (*data.SignerListSet, error) {
+	// Validate the configuration before building the transaction
+	if err := ValidateMultiSigConfig(account, cfg); err != nil {
+		return nil, err
+	}
+
+	owner, err := data.NewAccountFromAddress(account)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid vault account")
+	}
+
+	// Convert each configured signer into a ledger signer entry
+	entries := make([]data.SignerEntry, 0, len(cfg.Signers))
+	for _, signer := range cfg.Signers {
+		signerAccount, _ := data.NewAccountFromAddress(signer.Account)
+		weight := signer.Weight
+		var entry data.SignerEntry
+		entry.SignerEntry.Account = signerAccount
+		entry.SignerEntry.SignerWeight = &weight
+		entries = append(entries, entry)
+	}
+
+	quorum := cfg.Quorum
+	tx := &data.SignerListSet{
+		SignerQuorum:  quorum,
+		SignerEntries: entries,
+	}
+	tx.TransactionType = data.SIGNER_LIST_SET
+	tx.Account = *owner
+
+	return tx, nil
+}
+
+// ConfigureSignerList builds the vault's SignerListSet, has the vault's own key sign it and submits it,
+// returning the transaction hash
+func (m *MultiSigner) ConfigureSignerList(ctx context.Context, vault *models.Vault) (string, error) {
+	// Build the SignerListSet from the vault's multisig configuration
+	tx, err := BuildSignerListSet(vault.Address, vault.MultiSig)
+	if err != nil {
+		return "", err
+	}
+
+	// The SignerListSet itself is signed by the vault's regular signer
+	result, err := m.vaultKey.SignAndSubmit(ctx, vault, tx)
+	if err != nil {
+		m.log.Error("Failed to submit SignerListSet", "error", err, "vaultID", vault.ID)
+		return "", err
+	}
+
+	return result.TxJson.GetHash().String(), nil
+}
+
+// MultiSigningData returns the payload a signer must sign to add its signature to a multi-signed transaction
+func MultiSigningData(tx data.Transaction, signer string) ([]byte, error) {
+	signerAccount, err := data.NewAccountFromAddress(signer)
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid signer account: " + signer)
+	}
+
+	// Multi-signed transactions carry an empty SigningPubKey
+	tx.GetBase().SigningPubKey = &data.PublicKey{}
+
+	// Serialize the signing fields and swap the single-signing prefix for the multi-signing one
+	_, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	payload := append(append([]byte{}, multiSignPrefix...), msg[len(multiSignPrefix):]...)
+
+	// Append the signer's account ID as required by the multi-signing scheme
+	return append(payload, signerAccount.Bytes()...), nil
+}
+
+// RequestSignatures creates one signature request per signer in the vault's signer list
+func (m *MultiSigner) RequestSignatures(ctx context.Context, vault *models.Vault, transactionID uuid.UUID, tx data.Transaction) ([]*models.SignatureRequest, error) {
+	if vault.MultiSig == nil {
+		return nil, errors.NewBadRequestError("vault is not configured for multi-signing")
+	}
+
+	requests := make([]*models.SignatureRequest, 0, len(vault.MultiSig.Signers))
+	for _, signer := range vault.MultiSig.Signers {
+		// Compute the signer-specific multi-signing payload
+		payload, err := MultiSigningData(tx, signer.Account)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ask the signer's backend for a partial signature
+		txID := transactionID
+		request, err := m.signatures.RequestSignature(ctx, &models.SignatureRequest{
+			VaultID:       vault.ID,
+			DataToSign:    hex.EncodeToString(payload),
+			SignatureType: models.SignatureTypeXRPMultiSig,
+			TransactionID: &txID,
+			SignerBackend: signer.Backend,
+		})
+		if err != nil {
+			m.log.Error("Failed to request partial signature", "error", err, "vaultID", vault.ID, "signer", signer.Account)
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// CombineSignatures attaches the completed partial signatures to the transaction once the quorum is met. Each
+// request is matched to the signer whose multi-signing payload it signed, so only signatures over this
+// transaction count, and each signer counts once
+func CombineSignatures(tx data.Transaction, transactionID uuid.UUID, cfg *models.MultiSigConfig, requests []*models.SignatureRequest) error {
+	// Index the signer list by account, which the multi-signing payload ends with
+	byAccount := make(map[string]models.MultiSigSigner, len(cfg.Signers))
+	for _, signer := range cfg.Signers {
+		byAccount[signer.Account] = signer
+	}
+
+	var weight uint32
+	signed := make(map[string]bool, len(requests))
+	signers := make([]data.Signer, 0, len(requests))
+	for _, request := range requests {
+		if request.Status != models.SignatureStatusCompleted {
+			continue
+		}
+		if request.TransactionID == nil || *request.TransactionID != transactionID {
+			return errors.NewBadRequestError("partial signature " + request.ID.String() + " belongs to another transaction")
+		}
+
+		// Find the signer the request was made for and check it signed this transaction
+		member, err := signerOf(tx, request, byAccount)
+		if err != nil {
+			return err
+		}
+		if signed[member.Account] {
+			continue
+		}
+
+		// Decode the signer's account, public key and signature
+		account, err := data.NewAccountFromAddress(member.Account)
+		if err != nil {
+			return errors.NewBadRequestError("invalid signer account: " + member.Account)
+		}
+		publicKey, err := hex.DecodeString(member.PublicKey)
+		if err != nil {
+			return errors.NewBadRequestError("invalid signer public key: " + member.Account)
+		}
+		signature, err := hex.DecodeString(request.Signature)
+		if err != nil {
+			return errors.NewBadRequestError("invalid partial signature from signer: " + member.Account)
+		}
+
+		var entry data.Signer
+		entry.Signer.Account = *account
+		entry.Signer.SigningPubKey = new(data.PublicKey)
+		copy(entry.Signer.SigningPubKey[:], publicKey)
+		entry.Signer.TxnSignature = (*data.VariableLength)(&signature)
+		signers = append(signers, entry)
+		signed[member.Account] = true
+		weight += uint32(member.Weight)
+	}
+
+	// Refuse to combine until enough weight has signed
+	if weight < cfg.Quorum {
+		return errors.NewBadRequestError("multisig quorum not yet reached")
+	}
+
+	// The ledger requires signers sorted by account ID
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i].Signer.Account.Bytes(), signers[j].Signer.Account.Bytes()) < 0
+	})
+
+	tx.GetBase().SigningPubKey = &data.PublicKey{}
+	tx.GetBase().Signers = signers
+	return nil
+}
+
+// SubmitMultiSigned collects the transaction's partial signatures, combines them and submits the transaction
+func (m *MultiSigner) SubmitMultiSigned(ctx context.Context, vault *models.Vault, transactionID uuid.UUID, tx data.Transaction, requestIDs []string) (string, error) {
+	if vault.MultiSig == nil {
+		return "", errors.NewBadRequestError("vault is not configured for multi-signing")
+	}
+
+	// Fetch the current state of every partial signature request
+	requests := make([]*models.SignatureRequest, 0, len(requestIDs))
+	for _, id := range requestIDs {
+		request, err := m.signatures.GetSignatureStatus(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		requests = append(requests, request)
+	}
+
+	// Combine the signatures into the transaction
+	if err := CombineSignatures(tx, transactionID, vault.MultiSig, requests); err != nil {
+		return "", err
+	}
+
+	// Serialize the multi-signed blob and submit it
+	_, raw, err := data.Raw(tx)
+	if err != nil {
+		return "", err
+	}
+	result, err := m.client.SubmitMultisigned(ctx, hex.EncodeToString(raw))
+	if err != nil {
+		return "", err
+	}
+
+	return result.TxJson.GetHash().String(), nil
+}
+
+// signerOf returns the signer list member a partial signature request was made for, checking that the
+// request's payload is that member's multi-signing payload for the transaction
+func signerOf(tx data.Transaction, request *models.SignatureRequest, byAccount map[string]models.MultiSigSigner) (models.MultiSigSigner, error) {
+	// The payload ends with the signer's 20-byte account ID
+	payload, err := hex.DecodeString(request.DataToSign)
+	if err != nil || len(payload) < len(data.Account{}) {
+		return models.MultiSigSigner{}, errors.NewBadRequestError("invalid partial signature payload: " + request.ID.String())
+	}
+	var account data.Account
+	copy(account[:], payload[len(payload)-len(account):])
+	member, ok := byAccount[account.String()]
+	if !ok {
+		return models.MultiSigSigner{}, errors.NewBadRequestError("partial signature from an account outside the signer list: " + account.String())
+	}
+
+	// The rest must be this transaction's signing fields
+	expected, err := MultiSigningData(tx, member.Account)
+	if err != nil {
+		return models.MultiSigSigner{}, err
+	}
+	if !bytes.Equal(payload, expected) {
+		return models.MultiSigSigner{}, errors.NewBadRequestError("partial signature from " + member.Account + " was not made over this transaction")
+	}
+	return member, nil
+}
+
+// Human tasks:
+// TODO: Add support for removing a signer list (SignerListSet with quorum 0)
+// TODO: Implement expiry handling for partial signatures that never complete
+// TODO: Add support for disabling the master key once the signer list is installed
@@ -0,0 +1,123 @@
+package xrp
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+)
+
+// ErrDigestMismatch is returned when a signed payload is not the transaction that was exported
+var ErrDigestMismatch = errors.NewBadRequestError("signed transaction does not match the exported transaction")
+
+// ExportUnsigned builds a payment with the account's next sequence and encodes it as JSON for an offline signer.
+// No LastLedgerSequence is set because offline signing can take longer than any ledger window
+func (c *XRPClient) ExportUnsigned(ctx context.Context, tx *models.Transaction) (*offline.Payload, error) {
+	payment, err := BuildPayment(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the sequence and fee the ledger expects
+	info, err := c.GetAccountInfo(ctx, tx.FromAddress)
+	if err != nil {
+		return nil, err
+	}
+	if info.AccountData.Sequence == nil {
+		return nil, errors.NewBadRequestError("source account has no sequence")
+	}
+	payment.Sequence = *info.AccountData.Sequence
+	fee, err := data.NewNativeValue(defaultFeeDrops)
+	if err != nil {
+		return nil, err
+	}
+	payment.Fee = *fee
+
+	return EncodeUnsigned(payment, tx)
+}
+
+// EncodeUnsigned wraps an unsigned payment in an offline payload bound to the originating transaction
+func EncodeUnsigned(payment *data.Payment, tx *models.Transaction) (*offline.Payload, error) {
+	digest, err := unsignedDigest(payment)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &offline.Payload{
+		TransactionID:  tx.ID.String(),
+		BlockchainType: tx.BlockchainType,
+		Format:         offline.FormatXRPJSON,
+		Data:           string(raw),
+		Digest:         digest,
+	}
+	payload.Seal()
+	return payload, nil
+}
+
+// DecodeSigned decodes a signed transaction blob and checks that it is the exported transaction
+func DecodeSigned(payload *offline.Payload, digest string) (data.Transaction, error) {
+	if payload.Format != offline.FormatXRPJSON || !payload.Signed {
+		return nil, errors.NewBadRequestError("payload is not a signed XRP transaction")
+	}
+	raw, err := hex.DecodeString(payload.Data)
+	if err != nil {
+		return nil, errors.NewBadRequestError("signed transaction must be a hex blob")
+	}
+	tx, err := data.ReadTransaction(bytes.NewReader(raw))
+	if err != nil {
+		return nil, errors.NewBadRequestError("invalid signed transaction: " + err.Error())
+	}
+	if tx.GetBase().TxnSignature == nil {
+		return nil, errors.NewBadRequestError("transaction is not signed")
+	}
+
+	signed, err := unsignedDigest(tx)
+	if err != nil {
+		return nil, err
+	}
+	if signed != digest {
+		return nil, ErrDigestMismatch
+	}
+	return tx, nil
+}
+
+// BroadcastSigned verifies a signed payload against the exported digest and submits it
+func (c *XRPClient) BroadcastSigned(ctx context.Context, transaction *models.Transaction, payload *offline.Payload) (string, error) {
+	tx, err := DecodeSigned(payload, transaction.OfflineDigest)
+	if err != nil {
+		c.log.Error("Rejected offline-signed transaction", "error", err, "transactionID", transaction.ID)
+		return "", err
+	}
+	result, err := c.SubmitTransaction(ctx, tx)
+	if err != nil {
+		return "", err
+	}
+	return result.TxJson.GetHash().String(), nil
+}
+
+// unsignedDigest hashes a transaction's signing fields without the signing key, which the offline signer adds
+func unsignedDigest(tx data.Transaction) (string, error) {
+	base := tx.GetBase()
+	publicKey := base.SigningPubKey
+	base.SigningPubKey = nil
+	defer func() { base.SigningPubKey = publicKey }()
+
+	hash, _, err := data.SigningHash(tx)
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// Human tasks:
+// - Support exporting transaction types other than payments
+// - Let operators set a LastLedgerSequence when the signer is expected back quickly
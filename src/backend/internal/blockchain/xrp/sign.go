@@ -0,0 +1,180 @@
+package xrp
+
+import (
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+const (
+	// ledgerWindow is how many ledgers past the latest validated one a signed transaction stays valid for
+	ledgerWindow = 20
+
+	// signatureTimeout bounds how long a vault signature is waited for before the transaction is abandoned
+	signatureTimeout = 30 * time.Second
+
+	// signaturePollInterval is how often a pending signature request is checked
+	signaturePollInterval = 500 * time.Millisecond
+)
+
+// LatestLedger returns the sequence of the latest validated ledger
+func (c *XRPClient) LatestLedger(ctx context.Context) (uint32, error) {
+	var sequence uint32
+	err := c.pool.Do(ctx, func(i int) error {
+		result, err := c.clients[i].Ledger("validated", false)
+		if err != nil {
+			return err
+		}
+		sequence = uint32(result.Ledger.LedgerSequence)
+		return nil
+	})
+	if err != nil {
+		c.log.Error("Failed to get the latest validated ledger", "error", err)
+		return 0, err
+	}
+	return sequence, nil
+}
+
+// Autofill sets the account's next Sequence, the Fee and a LastLedgerSequence on a transaction before it is
+// signed. Multi-signed transactions pay the reference fee once more for each signer
+func (c *XRPClient) Autofill(ctx context.Context, tx data.Transaction, signers int) error {
+	base := tx.GetBase()
+
+	// The sequence is the sending account's next one
+	info, err := c.GetAccountInfo(ctx, base.Account.String())
+	if err != nil {
+		return err
+	}
+	if info.AccountData.Sequence == nil {
+		return errors.NewBadRequestError("source account has no sequence")
+	}
+	base.Sequence = *info.AccountData.Sequence
+
+	// Pay the reference fee for every signature the transaction carries, unless the transaction type already
+	// set the higher fee it needs
+	if base.Fee.IsZero() {
+		fee, err := data.NewNativeValue(int64(defaultFeeDrops * (1 + signers)))
+		if err != nil {
+			return err
+		}
+		base.Fee = *fee
+	}
+
+	// Expire the transaction if it is not validated within a few ledgers, so it can be safely retried
+	latest, err := c.LatestLedger(ctx)
+	if err != nil {
+		return err
+	}
+	lastLedger := latest + ledgerWindow
+	base.LastLedgerSequence = &lastLedger
+
+	return nil
+}
+
+// AwaitSignature polls a signature request until it completes, failing if it fails or the context ends first
+func AwaitSignature(ctx context.Context, signatures SignatureRequester, id string) (*models.SignatureRequest, error) {
+	ticker := time.NewTicker(signaturePollInterval)
+	defer ticker.Stop()
+
+	for {
+		request, err := signatures.GetSignatureStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		switch request.Status {
+		case models.SignatureStatusCompleted:
+			return request, nil
+		case models.SignatureStatusFailed:
+			return nil, errors.NewInternalServerError("signature request "+id+" failed", nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.NewInternalServerError("timed out waiting for signature request "+id, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// VaultSigner signs transactions with a vault's own key through the signature service and submits them
+type VaultSigner struct {
+	client     *XRPClient
+	signatures SignatureRequester
+	log        *logger.Logger
+}
+
+// NewVaultSigner creates a new VaultSigner instance
+func NewVaultSigner(client *XRPClient, signatures SignatureRequester, log *logger.Logger) *VaultSigner {
+	return &VaultSigner{
+		client:     client,
+		signatures: signatures,
+		log:        log,
+	}
+}
+
+// SignAndSubmit autofills a transaction sent from the vault's account, has the vault's key sign it and
+// submits it. The transaction keeps the sequence it was submitted with
+func (v *VaultSigner) SignAndSubmit(ctx context.Context, vault *models.Vault, tx data.Transaction) (*data.SubmitResult, error) {
+	// The ledger checks the signature against the public key carried in the transaction
+	publicKey, err := vaultPublicKey(vault)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the sequence, fee and expiry before anything is signed
+	if err := v.client.Autofill(ctx, tx, 0); err != nil {
+		return nil, err
+	}
+	tx.GetBase().SigningPubKey = publicKey
+	_, payload, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Request the signature as the vault's organization, which background jobs do not carry
+	signCtx, cancel := context.WithTimeout(tenant.WithOrganization(ctx, vault.OrganizationID), signatureTimeout)
+	defer cancel()
+	request, err := v.signatures.RequestSignature(signCtx, &models.SignatureRequest{
+		VaultID:       vault.ID,
+		DataToSign:    hex.EncodeToString(payload),
+		SignatureType: "xrp",
+	})
+	if err != nil {
+		v.log.Error("Failed to request vault signature", "error", err, "vaultID", vault.ID)
+		return nil, err
+	}
+	completed, err := AwaitSignature(signCtx, v.signatures, request.ID.String())
+	if err != nil {
+		v.log.Error("Vault signature did not complete", "error", err, "vaultID", vault.ID, "requestID", request.ID)
+		return nil, err
+	}
+	signature, err := hex.DecodeString(completed.Signature)
+	if err != nil {
+		return nil, errors.NewInternalServerError("invalid vault signature", err)
+	}
+	tx.GetBase().TxnSignature = (*data.VariableLength)(&signature)
+
+	// Submit the signed transaction
+	return v.client.SubmitTransaction(ctx, tx)
+}
+
+// vaultPublicKey decodes the public key of a vault's own signing key
+func vaultPublicKey(vault *models.Vault) (*data.PublicKey, error) {
+	var publicKey data.PublicKey
+	raw, err := hex.DecodeString(vault.PublicKey)
+	if err != nil || len(raw) != len(publicKey) {
+		return nil, errors.NewBadRequestError("vault has no valid signing public key")
+	}
+	copy(publicKey[:], raw)
+	return &publicKey, nil
+}
+
+// Human tasks:
+// TODO: Notify callers when a signature completes instead of polling the signature service
+// TODO: Resubmit transactions that expire past their LastLedgerSequence with a fresh sequence
@@ -0,0 +1,105 @@
+package xrp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+)
+
+const (
+	// baseReserveDrops is the XRP every account must hold to exist on the ledger
+	baseReserveDrops = 1000000
+
+	// ownerReserveDrops is the additional reserve held for each object the account owns
+	ownerReserveDrops = 200000
+
+	// defaultFeeDrops is the reference transaction cost for a payment
+	defaultFeeDrops = 12
+)
+
+// Simulate checks a payment offline and against the sender's balance and reserve without submitting it
+func (c *XRPClient) Simulate(ctx context.Context, tx *models.Transaction) (*models.SimulationResult, error) {
+	result := &models.SimulationResult{BlockchainType: "xrp", Success: true, Fee: strconv.Itoa(defaultFeeDrops)}
+
+	// Build the payment offline to validate its fields
+	payment, err := BuildPayment(tx)
+	if err != nil {
+		result.Fail(err.Error())
+		return result, nil
+	}
+
+	// Look up the destination, which may not exist yet
+	destinationExists := true
+	destination, err := c.GetAccountInfo(ctx, tx.ToAddress)
+	if err != nil {
+		if !isAccountNotFound(err) {
+			return nil, err
+		}
+		destinationExists = false
+	}
+	if destinationExists && tx.DestinationTag == nil && RequiresDestinationTag(destination) {
+		result.Fail(ErrDestinationTagRequired.Error())
+	}
+
+	// Issued currencies depend on trust lines, which are not checked here
+	if !payment.Amount.IsNative() {
+		result.Warnings = append(result.Warnings, "issued currency payment: trust lines and issuer balances are not checked")
+		return result, nil
+	}
+	drops, err := strconv.ParseInt(tx.Amount, 10, 64)
+	if err != nil || drops <= 0 {
+		result.Fail("native XRP amounts must be a positive integer number of drops")
+		return result, nil
+	}
+
+	// A payment to a new account must fund at least the base reserve
+	if !destinationExists && drops < baseReserveDrops {
+		result.Fail("destination account does not exist and the amount is below the base reserve of " + strconv.Itoa(baseReserveDrops) + " drops")
+	}
+
+	// The sender must keep its reserve after paying the amount and the fee
+	sender, err := c.GetAccountInfo(ctx, tx.FromAddress)
+	if err != nil {
+		if isAccountNotFound(err) {
+			result.Fail("source account does not exist")
+			return result, nil
+		}
+		return nil, err
+	}
+	var ownerCount int64
+	if sender.OwnerCount != nil {
+		ownerCount = int64(*sender.OwnerCount)
+	}
+	required, err := data.NewNativeValue(drops + defaultFeeDrops + baseReserveDrops + ownerCount*ownerReserveDrops)
+	if err != nil {
+		return nil, err
+	}
+	if sender.Balance == nil || sender.Balance.Compare(*required) < 0 {
+		result.Fail("insufficient XRP balance for amount, fee and reserve")
+	}
+
+	// Native balance deltas
+	result.BalanceDeltas = []models.BalanceDelta{
+		{Address: tx.FromAddress, Asset: "XRP", Amount: strconv.FormatInt(-(drops + defaultFeeDrops), 10)},
+		{Address: tx.ToAddress, Asset: "XRP", Amount: strconv.FormatInt(drops, 10)},
+	}
+
+	return result, nil
+}
+
+// isAccountNotFound reports whether an account_info error means the account does not exist
+func isAccountNotFound(err error) bool {
+	return strings.Contains(err.Error(), "actNotFound")
+}
+
+// isTransactionNotFound reports whether a tx error means the ledger has no such transaction
+func isTransactionNotFound(err error) bool {
+	return strings.Contains(err.Error(), "txnNotFound")
+}
+
+// Human tasks:
+// - Use the fee and reserve values from server_state instead of the defaults
+// - Check trust lines for issued currency payments
@@ -34,6 +34,9 @@ func NewPostgresDB(cfg *config.Config, log *logger.Logger) (*pgxpool.Pool, error
 	poolConfig.MaxConns = int32(cfg.DB.MaxConnections)
 	poolConfig.MinConns = int32(cfg.DB.MinConnections)
 
+	// Scope every connection to the organization of the context acquiring it, as row-level security requires
+	poolConfig.BeforeAcquire = applyTenant
+
 	// Connect to the database using the configuration
 	pool, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
 	if err != nil {
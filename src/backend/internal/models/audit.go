@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit event actions
+const (
+	AuditActionPermissionDenied = "permission_denied"
+)
+
+// Audit event actor types
+const (
+	AuditActorUser   = "user"
+	AuditActorAPIKey = "api_key"
+)
+
+// AuditEvent records a security-relevant action taken by a user or API key in an organization
+type AuditEvent struct {
+	ID             uuid.UUID         `json:"id"`
+	OrganizationID uuid.UUID         `json:"organization_id"`
+	ActorType      string            `json:"actor_type"`
+	ActorID        string            `json:"actor_id"`
+	Action         string            `json:"action"`
+	Resource       string            `json:"resource"`
+	Details        map[string]string `json:"details,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+}
+
+// Human tasks:
+// TODO: Chain event hashes so tampering with the audit log is detectable
+// TODO: Export audit events to long-term storage
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// ContractCall records the contract method behind a transaction's calldata
+type ContractCall struct {
+	ABI    json.RawMessage   `json:"abi"`
+	Method string            `json:"method"`
+	Args   []json.RawMessage `json:"args"`
+}
+
+// ContractCallRequest represents the payload accepted for contract read and write calls
+type ContractCallRequest struct {
+	VaultID         uuid.UUID         `json:"vault_id"`
+	BlockchainType  string            `json:"blockchain_type" binding:"required"`
+	ContractAddress string            `json:"contract_address" binding:"required"`
+	ABI             json.RawMessage   `json:"abi" binding:"required"`
+	Method          string            `json:"method" binding:"required"`
+	Args            []json.RawMessage `json:"args"`
+	Value           string            `json:"value,omitempty"`
+}
+
+// ContractOutput is a single decoded return value of a contract call
+type ContractOutput struct {
+	Name  string      `json:"name,omitempty"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// ContractCallResult is the decoded result of a contract read call
+type ContractCallResult struct {
+	Outputs []ContractOutput `json:"outputs"`
+	RawData string           `json:"raw_data"`
+}
+
+// Human tasks:
+// TODO: Store verified contract ABIs so callers can refer to them by address
+// TODO: Add validation methods for the ContractCallRequest struct fields
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Escrow statuses
+const (
+	EscrowStatusPending   = "Pending"
+	EscrowStatusSubmitted = "Submitted"
+	EscrowStatusCreated   = "Created"
+	EscrowStatusFinished  = "Finished"
+	EscrowStatusCancelled = "Cancelled"
+	EscrowStatusFailed    = "Failed"
+)
+
+// Escrow represents funds locked on the XRP Ledger until a time or crypto-condition is met. The fulfillment is
+// kept encrypted, and LastLedgerSequence is the ledger after which an unconfirmed EscrowCreate has expired
+type Escrow struct {
+	ID                   uuid.UUID  `json:"id"`
+	VaultID              uuid.UUID  `json:"vault_id"`
+	Owner                string     `json:"owner"`
+	Destination          string     `json:"destination"`
+	Amount               string     `json:"amount"`
+	Condition            string     `json:"condition,omitempty"`
+	EncryptedFulfillment []byte     `json:"-"`
+	FinishAfter          *time.Time `json:"finish_after,omitempty"`
+	CancelAfter          *time.Time `json:"cancel_after,omitempty"`
+	OfferSequence        uint32     `json:"offer_sequence"`
+	LastLedgerSequence   uint32     `json:"-"`
+	CreateTxHash         string     `json:"create_tx_hash,omitempty"`
+	FinishTxHash         string     `json:"finish_tx_hash,omitempty"`
+	CancelTxHash         string     `json:"cancel_tx_hash,omitempty"`
+	Status               string     `json:"status"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// EscrowRequest represents the payload accepted when creating an escrow
+type EscrowRequest struct {
+	Destination    string     `json:"destination" binding:"required"`
+	Amount         string     `json:"amount" binding:"required"`
+	FinishAfter    *time.Time `json:"finish_after,omitempty"`
+	CancelAfter    *time.Time `json:"cancel_after,omitempty"`
+	WithCondition  bool       `json:"with_condition"`
+	DestinationTag *uint32    `json:"destination_tag,omitempty"`
+}
+
+// IsOpen reports whether the escrow still holds funds on the ledger
+func (e *Escrow) IsOpen() bool {
+	return e.Status == EscrowStatusCreated
+}
+
+// CanFinish reports whether the escrow can be finished at the given time
+func (e *Escrow) CanFinish(now time.Time) bool {
+	if !e.IsOpen() {
+		return false
+	}
+	if e.CancelAfter != nil && !now.Before(*e.CancelAfter) {
+		return false
+	}
+	return e.FinishAfter == nil || now.After(*e.FinishAfter)
+}
+
+// CanCancel reports whether the escrow can be cancelled at the given time
+func (e *Escrow) CanCancel(now time.Time) bool {
+	return e.IsOpen() && e.CancelAfter != nil && now.After(*e.CancelAfter)
+}
+
+// Human tasks:
+// TODO: Add support for escrows of issued currencies once the ledger amendment is enabled
+// TODO: Implement audit logging for escrow operations
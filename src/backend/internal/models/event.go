@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types pushed to organizations when their resources change
+const (
+	EventTransactionStatusChanged = "transaction.status_changed"
+	EventDepositReceived          = "deposit.received"
+	EventSignatureCompleted       = "signature.completed"
+	EventSignatureFailed          = "signature.failed"
+)
+
+// EventTypes lists every event type subscribers can filter on
+var EventTypes = []string{
+	EventTransactionStatusChanged,
+	EventDepositReceived,
+	EventSignatureCompleted,
+	EventSignatureFailed,
+}
+
+// Event is a change to one of an organization's resources. Events about the same ResourceID are delivered
+// in the order they happened
+type Event struct {
+	ID             uuid.UUID   `json:"id"`
+	OrganizationID uuid.UUID   `json:"organization_id"`
+	Type           string      `json:"type"`
+	ResourceID     string      `json:"resource_id"`
+	Data           interface{} `json:"data"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+// NewEvent creates an event about a resource of an organization
+func NewEvent(organizationID uuid.UUID, eventType, resourceID string, data interface{}) *Event {
+	return &Event{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		Type:           eventType,
+		ResourceID:     resourceID,
+		Data:           data,
+		CreatedAt:      time.Now().UTC(),
+	}
+}
+
+// DepositEvent is the data of a deposit.received event: funds found on a deposit address and swept into
+// its vault
+type DepositEvent struct {
+	VaultID        uuid.UUID `json:"vault_id"`
+	BlockchainType string    `json:"blockchain_type"`
+	Address        string    `json:"address"`
+	Amount         string    `json:"amount"`
+	TransactionID  uuid.UUID `json:"transaction_id"`
+}
+
+// ValidEventType reports whether an event type exists
+func ValidEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Human tasks:
+// TODO: Add events for vault changes and approvals
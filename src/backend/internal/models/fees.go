@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// NetworkFees is the fee spend on one blockchain, with gas-station top-ups reported separately
+type NetworkFees struct {
+	BlockchainType string `json:"blockchain_type"`
+	NetworkFees    string `json:"network_fees"`
+	GasTopUps      string `json:"gas_top_ups"`
+	GasTopUpCount  int    `json:"gas_top_up_count"`
+}
+
+// FeeAnalyticsData is the fee spend per blockchain over a time range
+type FeeAnalyticsData struct {
+	StartDate time.Time     `json:"start_date"`
+	EndDate   time.Time     `json:"end_date"`
+	Networks  []NetworkFees `json:"networks"`
+}
+
+// Human tasks:
+// TODO: Convert fee totals to a fiat reporting currency
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTPFactor is a user's authenticator app enrollment. The secret is stored encrypted, and the factor only
+// counts once a first code has confirmed the app holds it
+type TOTPFactor struct {
+	UserID          uuid.UUID  `json:"user_id"`
+	EncryptedSecret []byte     `json:"-"`
+	LastUsedStep    int64      `json:"-"`
+	ConfirmedAt     *time.Time `json:"confirmed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// Confirmed reports whether the factor can be used for step-up authentication
+func (f *TOTPFactor) Confirmed() bool {
+	return f.ConfirmedAt != nil
+}
+
+// TOTPEnrollment carries a new TOTP secret and the provisioning URI authenticator apps scan. It is shown once
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// TOTPCodeRequest carries a code from an authenticator app
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// StepUpRequest carries a second factor: an authenticator code or, if the authenticator is lost, an unused
+// recovery code
+type StepUpRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// RecoveryCodes are single-use codes that stand in for an authenticator. They are shown once and only their
+// hashes are kept
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+// Human tasks:
+// TODO: Support WebAuthn security keys as a second factor
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NFT token standards
+const (
+	NFTStandardERC721  = "erc721"
+	NFTStandardERC1155 = "erc1155"
+)
+
+// NFTHolding is a token a vault currently owns. ERC-721 holdings always have a balance of 1
+type NFTHolding struct {
+	ID              uuid.UUID `json:"id"`
+	VaultID         uuid.UUID `json:"vault_id"`
+	BlockchainType  string    `json:"blockchain_type"`
+	ContractAddress string    `json:"contract_address"`
+	TokenID         string    `json:"token_id"`
+	Standard        string    `json:"standard"`
+	Balance         string    `json:"balance"`
+	MetadataURI     string    `json:"metadata_uri,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NFTTransfer is a single token movement decoded from a Transfer, TransferSingle or TransferBatch log
+type NFTTransfer struct {
+	BlockchainType  string `json:"blockchain_type"`
+	ContractAddress string `json:"contract_address"`
+	Standard        string `json:"standard"`
+	From            string `json:"from"`
+	To              string `json:"to"`
+	TokenID         string `json:"token_id"`
+	Amount          string `json:"amount"`
+	BlockNumber     uint64 `json:"block_number"`
+	TxHash          string `json:"tx_hash"`
+	LogIndex        uint   `json:"log_index"`
+	BatchIndex      int    `json:"batch_index"`
+}
+
+// NFTTransferRequest represents the payload accepted when transferring an NFT out of a vault
+type NFTTransferRequest struct {
+	ContractAddress string `json:"contract_address" binding:"required"`
+	TokenID         string `json:"token_id" binding:"required"`
+	ToAddress       string `json:"to_address" binding:"required"`
+	Amount          string `json:"amount,omitempty"`
+}
+
+// Human tasks:
+// TODO: Cache token metadata documents alongside the metadata URI
+// TODO: Add support for CryptoPunks and other pre-ERC-721 collections
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permissions granted to users through roles and to API keys as scopes
+const (
+	PermissionVaultRead     = "vault:read"
+	PermissionVaultWrite    = "vault:write"
+	PermissionTxRead        = "tx:read"
+	PermissionTxWrite       = "tx:write"
+	PermissionTxApprove     = "tx:approve"
+	PermissionSignRead      = "sign:read"
+	PermissionSignRequest   = "sign:request"
+	PermissionAnalyticsRead = "analytics:read"
+	PermissionAuditRead     = "audit:read"
+	PermissionOrgAdmin      = "org:admin"
+)
+
+// Permissions lists every permission a role or API key can be granted
+var Permissions = []string{
+	PermissionVaultRead,
+	PermissionVaultWrite,
+	PermissionTxRead,
+	PermissionTxWrite,
+	PermissionTxApprove,
+	PermissionSignRead,
+	PermissionSignRequest,
+	PermissionAnalyticsRead,
+	PermissionAuditRead,
+	PermissionOrgAdmin,
+}
+
+// Built-in roles available in every organization
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleApprover = "approver"
+	RoleAuditor  = "auditor"
+	RoleAdmin    = "admin"
+)
+
+// readPermissions are shared by every built-in role
+var readPermissions = []string{PermissionVaultRead, PermissionTxRead, PermissionSignRead, PermissionAnalyticsRead}
+
+// BuiltinRoles maps each built-in role to its permissions. Roles are additive, so an admin can do anything
+// a viewer can
+var BuiltinRoles = map[string][]string{
+	RoleViewer:   readPermissions,
+	RoleOperator: append([]string{PermissionVaultWrite, PermissionTxWrite, PermissionSignRequest}, readPermissions...),
+	RoleApprover: append([]string{PermissionTxApprove}, readPermissions...),
+	RoleAuditor:  append([]string{PermissionAuditRead}, readPermissions...),
+	RoleAdmin:    Permissions,
+}
+
+// Role is an organization's custom set of permissions, assignable like a built-in role
+type Role struct {
+	ID             uuid.UUID `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Name           string    `json:"name"`
+	Permissions    []string  `json:"permissions"`
+	Builtin        bool      `json:"builtin"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// RoleBinding grants a user a role in addition to their organization role, either across the organization
+// or only on one vault
+type RoleBinding struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Role           string     `json:"role"`
+	VaultID        *uuid.UUID `json:"vault_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// RoleRequest represents the payload accepted when creating a custom role
+type RoleRequest struct {
+	Name        string   `json:"name" binding:"required"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// RoleBindingRequest represents the payload accepted when binding a role to a user
+type RoleBindingRequest struct {
+	UserID  uuid.UUID  `json:"user_id" binding:"required"`
+	Role    string     `json:"role" binding:"required"`
+	VaultID *uuid.UUID `json:"vault_id,omitempty"`
+}
+
+// Human tasks:
+// TODO: Support time-bound role bindings that expire automatically
+// TODO: Allow custom roles to extend a built-in role
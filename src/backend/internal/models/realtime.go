@@ -0,0 +1,38 @@
+package models
+
+import "encoding/json"
+
+// Actions real-time clients can send
+const (
+	RealtimeActionSubscribe   = "subscribe"
+	RealtimeActionUnsubscribe = "unsubscribe"
+)
+
+// Types of frames sent to real-time clients. A resync frame means events since the client's last event ID
+// are no longer retained, so its state should be reloaded through the REST API
+const (
+	RealtimeFrameEvent        = "event"
+	RealtimeFrameSubscribed   = "subscribed"
+	RealtimeFrameUnsubscribed = "unsubscribed"
+	RealtimeFrameResync       = "resync"
+	RealtimeFrameError        = "error"
+)
+
+// RealtimeRequest represents a message from a real-time client changing its subscriptions
+type RealtimeRequest struct {
+	Action   string   `json:"action"`
+	Channels []string `json:"channels"`
+}
+
+// RealtimeFrame represents a message to a real-time client. Event frames carry the event's ID in the event
+// log, which the client presents to resume after reconnecting
+type RealtimeFrame struct {
+	Type     string          `json:"type"`
+	ID       string          `json:"id,omitempty"`
+	Channels []string        `json:"channels,omitempty"`
+	Event    json.RawMessage `json:"event,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// Human tasks:
+// TODO: Document the real-time protocol for client SDKs
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Safe transaction statuses
+const (
+	SafeTransactionStatusPending   = "Pending"
+	SafeTransactionStatusExecuting = "Executing"
+	SafeTransactionStatusExecuted  = "Executed"
+	SafeTransactionStatusFailed    = "Failed"
+	SafeTransactionStatusCancelled = "Cancelled"
+)
+
+// Safe operations
+const (
+	SafeOperationCall         uint8 = 0
+	SafeOperationDelegateCall uint8 = 1
+)
+
+// SafeConfig describes the Safe contract behind a smart-contract wallet vault
+type SafeConfig struct {
+	Owners         []SafeOwner `json:"owners"`
+	Threshold      uint64      `json:"threshold"`
+	Nonce          uint64      `json:"nonce"`
+	RelayerVaultID uuid.UUID   `json:"relayer_vault_id"`
+	DeploymentTxID *uuid.UUID  `json:"deployment_tx_id,omitempty"`
+	SaltNonce      string      `json:"salt_nonce,omitempty"`
+}
+
+// SafeOwner is an owner of a Safe and the signer backend that holds its key
+type SafeOwner struct {
+	Address string `json:"address"`
+	Backend string `json:"backend"`
+}
+
+// SafeTransaction is a transaction proposed to a Safe, awaiting owner signatures and execution
+type SafeTransaction struct {
+	ID                  uuid.UUID   `json:"id"`
+	VaultID             uuid.UUID   `json:"vault_id"`
+	To                  string      `json:"to"`
+	Value               string      `json:"value"`
+	Data                string      `json:"data,omitempty"`
+	Operation           uint8       `json:"operation"`
+	Nonce               uint64      `json:"nonce"`
+	SafeTxHash          string      `json:"safe_tx_hash"`
+	SignatureRequestIDs []uuid.UUID `json:"signature_request_ids"`
+	ExecutionTxID       *uuid.UUID  `json:"execution_tx_id,omitempty"`
+	Status              string      `json:"status"`
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
+}
+
+// SafeDeployRequest represents the payload accepted when deploying a new Safe vault
+type SafeDeployRequest struct {
+	OrganizationID uuid.UUID   `json:"organization_id"`
+	Name           string      `json:"name" binding:"required"`
+	BlockchainType string      `json:"blockchain_type" binding:"required"`
+	Owners         []SafeOwner `json:"owners" binding:"required"`
+	Threshold      uint64      `json:"threshold" binding:"required"`
+	RelayerVaultID uuid.UUID   `json:"relayer_vault_id" binding:"required"`
+	SaltNonce      string      `json:"salt_nonce,omitempty"`
+}
+
+// SafeAttachRequest represents the payload accepted when attaching an existing Safe as a vault
+type SafeAttachRequest struct {
+	OrganizationID uuid.UUID   `json:"organization_id"`
+	Name           string      `json:"name" binding:"required"`
+	BlockchainType string      `json:"blockchain_type" binding:"required"`
+	Address        string      `json:"address" binding:"required"`
+	Owners         []SafeOwner `json:"owners" binding:"required"`
+	RelayerVaultID uuid.UUID   `json:"relayer_vault_id" binding:"required"`
+}
+
+// SafeTransactionRequest represents the payload accepted when proposing a Safe transaction
+type SafeTransactionRequest struct {
+	To        string `json:"to" binding:"required"`
+	Value     string `json:"value,omitempty"`
+	Data      string `json:"data,omitempty"`
+	Operation uint8  `json:"operation,omitempty"`
+}
+
+// Human tasks:
+// TODO: Add support for Safe modules and guards
+// TODO: Track owner changes made through the Safe itself
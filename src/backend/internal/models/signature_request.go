@@ -8,17 +8,26 @@ import (
 
 // SignatureRequest represents a request for a cryptographic signature in the blockchain integration service.
 type SignatureRequest struct {
-	ID            uuid.UUID `json:"id"`
-	VaultID       uuid.UUID `json:"vault_id"`
-	Status        string    `json:"status"`
-	DataToSign    string    `json:"data_to_sign"`
-	Signature     string    `json:"signature"`
-	SignatureType string    `json:"signature_type"`
-	ExpiresAt     time.Time `json:"expires_at"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	VaultID        uuid.UUID  `json:"vault_id"`
+	Status         string     `json:"status"`
+	DataToSign     string     `json:"data_to_sign"`
+	Signature      string     `json:"signature"`
+	SignatureType  string     `json:"signature_type"`
+	TransactionID  *uuid.UUID `json:"transaction_id,omitempty"`
+	SignerBackend  string     `json:"signer_backend,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
+// SignatureTypeXRPMultiSig identifies a partial signature collected for an XRP multi-signed transaction
+const SignatureTypeXRPMultiSig = "xrp_multisig"
+
+// SignatureTypeSafeOwner identifies an owner signature over a Safe transaction hash
+const SignatureTypeSafeOwner = "safe_owner"
+
 // Human tasks:
 // TODO: Add validation methods for the SignatureRequest struct fields
 // TODO: Implement a method to update the signature request status
@@ -29,4 +38,4 @@ type SignatureRequest struct {
 // TODO: Add a method to extend the expiration time of the signature request
 // TODO: Implement audit logging for signature request operations
 // TODO: Add support for different signature algorithms (e.g., ECDSA, EdDSA)
-// TODO: Implement a method to cancel an ongoing signature request
\ No newline at end of file
+// TODO: Implement a method to cancel an ongoing signature request
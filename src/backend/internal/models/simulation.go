@@ -0,0 +1,30 @@
+package models
+
+// BalanceDelta is the expected change to an account's balance of one asset
+type BalanceDelta struct {
+	Address string `json:"address"`
+	Asset   string `json:"asset"`
+	Amount  string `json:"amount"`
+}
+
+// SimulationResult is the outcome of previewing a transaction without persisting or submitting it
+type SimulationResult struct {
+	BlockchainType string         `json:"blockchain_type"`
+	Success        bool           `json:"success"`
+	Fee            string         `json:"fee"`
+	GasEstimate    uint64         `json:"gas_estimate,omitempty"`
+	RevertReason   string         `json:"revert_reason,omitempty"`
+	BalanceDeltas  []BalanceDelta `json:"balance_deltas"`
+	Errors         []string       `json:"errors,omitempty"`
+	Warnings       []string       `json:"warnings,omitempty"`
+}
+
+// Fail marks the simulation as failed with a reason
+func (r *SimulationResult) Fail(reason string) {
+	r.Success = false
+	r.Errors = append(r.Errors, reason)
+}
+
+// Human tasks:
+// TODO: Include token balance deltas decoded from simulated logs
+// TODO: Add an expiry so simulation results are not reused against stale state
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DepositAddress is an HD-derived receive address whose funds are swept into its vault's main address
+type DepositAddress struct {
+	ID             uuid.UUID `json:"id"`
+	VaultID        uuid.UUID `json:"vault_id"`
+	Address        string    `json:"address"`
+	DerivationPath string    `json:"derivation_path"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SweepResult reports the sweep transactions created for a vault and the deposit addresses left untouched
+type SweepResult struct {
+	VaultID        uuid.UUID      `json:"vault_id"`
+	BlockchainType string         `json:"blockchain_type"`
+	Transactions   []*Transaction `json:"transactions"`
+	Skipped        []SkippedSweep `json:"skipped,omitempty"`
+}
+
+// SkippedSweep explains why a deposit address was not swept
+type SkippedSweep struct {
+	Address string `json:"address,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// Human tasks:
+// TODO: Add an endpoint to derive and register new deposit addresses
+// TODO: Track the balance last seen on each deposit address
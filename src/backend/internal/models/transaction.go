@@ -8,18 +8,75 @@ import (
 
 // Transaction represents a blockchain transaction in the system
 type Transaction struct {
-	ID            uuid.UUID `json:"id"`
-	VaultID       uuid.UUID `json:"vault_id"`
-	BlockchainType string    `json:"blockchain_type"`
-	FromAddress   string    `json:"from_address"`
-	ToAddress     string    `json:"to_address"`
-	Amount        string    `json:"amount"`
-	Fee           string    `json:"fee"`
-	Status        string    `json:"status"`
-	TxHash        string    `json:"tx_hash"`
-	Confirmations int       `json:"confirmations"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID             uuid.UUID         `json:"id"`
+	OrganizationID uuid.UUID         `json:"organization_id"`
+	VaultID        uuid.UUID         `json:"vault_id"`
+	BlockchainType string            `json:"blockchain_type"`
+	FromAddress    string            `json:"from_address"`
+	ToAddress      string            `json:"to_address"`
+	Amount         string            `json:"amount"`
+	Fee            string            `json:"fee"`
+	Status         string            `json:"status"`
+	TxHash         string            `json:"tx_hash"`
+	Confirmations  int               `json:"confirmations"`
+	DestinationTag *uint32           `json:"destination_tag,omitempty"`
+	SourceTag      *uint32           `json:"source_tag,omitempty"`
+	Memos          []TransactionMemo `json:"memos,omitempty"`
+	Data           string            `json:"data,omitempty"`
+	ContractCall   *ContractCall     `json:"contract_call,omitempty"`
+	RevertReason   string            `json:"revert_reason,omitempty"`
+	Purpose        string            `json:"purpose,omitempty"`
+	DependsOnID    *uuid.UUID        `json:"depends_on_id,omitempty"`
+	OfflineDigest  string            `json:"offline_digest,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// TransactionPurposeGasTopUp marks a transaction sent by a gas station to fund another vault's gas
+const TransactionPurposeGasTopUp = "gas_topup"
+
+// TransactionPurposeSweep marks an internal transaction consolidating deposit addresses into the vault's main address.
+// UTXO sweeps spend several deposit addresses at once and leave FromAddress empty
+const TransactionPurposeSweep = "sweep"
+
+// TransactionStatusAwaitingGas is the status of a transaction held until its gas top-up confirms
+const TransactionStatusAwaitingGas = "AwaitingGas"
+
+// TransactionStatusAwaitingOfflineSignature is the status of a cold-storage transaction until its signed payload is imported
+const TransactionStatusAwaitingOfflineSignature = "AwaitingOfflineSignature"
+
+// TransactionMemo represents an arbitrary memo attached to an XRP transaction
+type TransactionMemo struct {
+	MemoType   string `json:"memo_type,omitempty"`
+	MemoData   string `json:"memo_data,omitempty"`
+	MemoFormat string `json:"memo_format,omitempty"`
+}
+
+// TransactionRequest represents the payload accepted when creating a transaction
+type TransactionRequest struct {
+	VaultID        uuid.UUID         `json:"vault_id" binding:"required"`
+	BlockchainType string            `json:"blockchain_type" binding:"required"`
+	ToAddress      string            `json:"to_address" binding:"required"`
+	Amount         string            `json:"amount" binding:"required"`
+	DestinationTag *uint32           `json:"destination_tag,omitempty"`
+	SourceTag      *uint32           `json:"source_tag,omitempty"`
+	Memos          []TransactionMemo `json:"memos,omitempty"`
+	Data           string            `json:"data,omitempty"`
+	Simulate       bool              `json:"simulate,omitempty"`
+}
+
+// ToTransaction converts the request payload into a Transaction model
+func (r *TransactionRequest) ToTransaction() *Transaction {
+	return &Transaction{
+		VaultID:        r.VaultID,
+		BlockchainType: r.BlockchainType,
+		ToAddress:      r.ToAddress,
+		Amount:         r.Amount,
+		DestinationTag: r.DestinationTag,
+		SourceTag:      r.SourceTag,
+		Memos:          r.Memos,
+		Data:           r.Data,
+	}
 }
 
 // Human tasks:
@@ -32,4 +89,4 @@ type Transaction struct {
 // TODO: Add a method to generate a transaction receipt or summary
 // TODO: Implement audit logging for transaction-related operations
 // TODO: Add support for attaching metadata or tags to transactions
-// TODO: Implement a method to estimate transaction fees based on current network conditions
\ No newline at end of file
+// TODO: Implement a method to estimate transaction fees based on current network conditions
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User represents a person who signs in with email and password and belongs to organizations through
+// memberships. OrganizationID and Role are not stored on the user: they are filled in from the membership
+// a request is authenticated for
+type User struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id,omitempty"`
+	Email          string     `json:"email"`
+	PasswordHash   string     `json:"-"`
+	Role           string     `json:"role,omitempty"`
+	DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Membership grants a user a role in one organization. A deactivated membership keeps its history but no
+// longer lets the user act in the organization
+type Membership struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Email          string     `json:"email,omitempty"`
+	Role           string     `json:"role"`
+	DeactivatedAt  *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the membership lets its user act in the organization
+func (m *Membership) Active() bool {
+	return m.DeactivatedAt == nil
+}
+
+// Invitation asks someone by email to join an organization with a role. Only a hash of the emailed token
+// is kept
+type Invitation struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	TokenHash      string     `json:"-"`
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// InvitationRequest represents the email and role an organization admin invites someone with
+type InvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// AcceptInvitationRequest carries an emailed invitation token. A password is required when the invitee
+// has no account yet
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password"`
+}
+
+// MembershipRoleRequest represents a member's new role
+type MembershipRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// Profile is the signed-in user with their memberships and their effective permissions in the organization
+// the request was authenticated for
+type Profile struct {
+	User           *User         `json:"user"`
+	OrganizationID uuid.UUID     `json:"organization_id"`
+	Role           string        `json:"role"`
+	Memberships    []*Membership `json:"memberships"`
+	Permissions    []string      `json:"permissions"`
+}
+
+// Session is a signed-in user's refresh token family. Only a hash of the current refresh token is kept;
+// presenting an earlier one revokes the session. StepUpAt is when the user last presented a second factor
+// in the session
+type Session struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	OrganizationID string     `json:"organization_id"`
+	RefreshHash    string     `json:"refresh_hash"`
+	StepUpAt       *time.Time `json:"step_up_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+}
+
+// LoginRequest represents the credentials a user signs in with. Users who belong to several organizations
+// choose the one to sign in to
+type LoginRequest struct {
+	Email          string     `json:"email" binding:"required"`
+	Password       string     `json:"password" binding:"required"`
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+}
+
+// RefreshRequest carries a refresh token to rotate or revoke
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenPair is an access token and the refresh token that replaces it once it expires
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// AccessToken is an access token issued without a new refresh token, e.g. after step-up authentication
+type AccessToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Human tasks:
+// TODO: Add user profile fields such as display name
+// TODO: Record the client IP and user agent of each session
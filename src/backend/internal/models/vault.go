@@ -8,15 +8,33 @@ import (
 
 // Vault represents a blockchain vault entity in the system
 type Vault struct {
-	ID              uuid.UUID `json:"id"`
-	OrganizationID  uuid.UUID `json:"organization_id"`
-	Name            string    `json:"name"`
-	BlockchainType  string    `json:"blockchain_type"`
-	Address         string    `json:"address"`
-	Balance         string    `json:"balance"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID             uuid.UUID       `json:"id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	Name           string          `json:"name"`
+	BlockchainType string          `json:"blockchain_type"`
+	Address        string          `json:"address"`
+	PublicKey      string          `json:"public_key,omitempty"`
+	Balance        string          `json:"balance"`
+	Status         string          `json:"status"`
+	MultiSig       *MultiSigConfig `json:"multisig,omitempty"`
+	Safe           *SafeConfig     `json:"safe,omitempty"`
+	ColdStorage    bool            `json:"cold_storage"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// MultiSigConfig describes the native ledger signer list of a multi-signed vault
+type MultiSigConfig struct {
+	Quorum  uint32           `json:"quorum"`
+	Signers []MultiSigSigner `json:"signers"`
+}
+
+// MultiSigSigner represents a single weighted signer in a vault's signer list
+type MultiSigSigner struct {
+	Account   string `json:"account"`
+	PublicKey string `json:"public_key"`
+	Weight    uint16 `json:"weight"`
+	Backend   string `json:"backend"`
 }
 
 // TODO: Human tasks
@@ -29,4 +47,4 @@ type Vault struct {
 // - Add a method to calculate the total transaction volume for the vault
 // - Implement audit logging for vault-related operations
 // - Add support for vault-specific settings or configurations
-// - Implement a method to check the vault's transaction history
\ No newline at end of file
+// - Implement a method to check the vault's transaction history
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook delivery statuses. Pending deliveries include those waiting to be retried; dead deliveries gave
+// up and wait in the dead-letter list for a manual replay
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEndpoint is an organization's URL that events are pushed to. Its health is tracked from delivery
+// attempts, and an endpoint that keeps failing is disabled
+type WebhookEndpoint struct {
+	ID                  uuid.UUID  `json:"id"`
+	OrganizationID      uuid.UUID  `json:"organization_id"`
+	URL                 string     `json:"url"`
+	Description         string     `json:"description,omitempty"`
+	EventTypes          []string   `json:"event_types"`
+	Secret              string     `json:"-"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailingSince        *time.Time `json:"failing_since,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Enabled reports whether events are delivered to the endpoint
+func (e *WebhookEndpoint) Enabled() bool {
+	return e.DisabledAt == nil
+}
+
+// Subscribes reports whether the endpoint receives an event type
+func (e *WebhookEndpoint) Subscribes(eventType string) bool {
+	for _, subscribed := range e.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpointRequest represents the payload accepted when registering a webhook endpoint
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1"`
+}
+
+// WebhookEndpointUpdate represents changes to a webhook endpoint. Enabling an endpoint resets its health
+type WebhookEndpointUpdate struct {
+	URL         *string  `json:"url,omitempty" binding:"omitempty,url"`
+	Description *string  `json:"description,omitempty"`
+	EventTypes  []string `json:"event_types,omitempty"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// CreatedWebhookEndpoint is returned once when an endpoint is registered; its signing secret cannot be
+// retrieved again
+type CreatedWebhookEndpoint struct {
+	Endpoint *WebhookEndpoint `json:"endpoint"`
+	Secret   string           `json:"secret"`
+}
+
+// WebhookDelivery is one event queued for one endpoint, with the outcome of its latest attempt. It is retried
+// until RetryUntil, which a replay pushes back
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	OrganizationID uuid.UUID       `json:"organization_id"`
+	EndpointID     uuid.UUID       `json:"endpoint_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	ResourceID     string          `json:"resource_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	RetryUntil     time.Time       `json:"retry_until"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Human tasks:
+// TODO: Let organizations rotate an endpoint's secret with an overlap period
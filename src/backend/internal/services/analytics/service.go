@@ -6,6 +6,7 @@ import (
 
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
 )
@@ -31,6 +32,11 @@ func (s *Service) GetTransactionVolume(ctx context.Context, startDate, endDate t
 		return nil, errors.Wrap(err, "invalid date range")
 	}
 
+	// Only aggregate the caller's organization; the repository scopes its queries to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
 	// Call the repository to fetch transaction volume data
 	data, err := s.repo.FetchTransactionVolume(ctx, startDate, endDate)
 	if err != nil {
@@ -51,6 +57,11 @@ func (s *Service) GetNetworkDistribution(ctx context.Context, startDate, endDate
 		return nil, errors.Wrap(err, "invalid date range")
 	}
 
+	// Only aggregate the caller's organization; the repository scopes its queries to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
 	// Call the repository to fetch network distribution data
 	data, err := s.repo.FetchNetworkDistribution(ctx, startDate, endDate)
 	if err != nil {
@@ -70,6 +81,11 @@ func (s *Service) GetPerformanceMetrics(ctx context.Context, startDate, endDate
 	if err := validateDateRange(startDate, endDate); err != nil {
 		return nil, errors.Wrap(err, "invalid date range")
 	}
+
+	// Only aggregate the caller's organization; the repository scopes its queries to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
 	if err := validateMetricType(metricType); err != nil {
 		return nil, errors.Wrap(err, "invalid metric type")
 	}
@@ -87,6 +103,28 @@ func (s *Service) GetPerformanceMetrics(ctx context.Context, startDate, endDate
 	return processedData, nil
 }
 
+// GetFeeAnalytics retrieves network fees and gas-station top-ups per blockchain for a given time range
+func (s *Service) GetFeeAnalytics(ctx context.Context, startDate, endDate time.Time) (*models.FeeAnalyticsData, error) {
+	// Validate the input date range
+	if err := validateDateRange(startDate, endDate); err != nil {
+		return nil, errors.Wrap(err, "invalid date range")
+	}
+
+	// Only aggregate the caller's organization; the repository scopes its queries to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
+	// Call the repository to fetch fee totals, which count gas top-ups separately from network fees
+	networks, err := s.repo.FetchFeeAnalytics(ctx, startDate, endDate)
+	if err != nil {
+		s.log.Error("Failed to fetch fee analytics data", "error", err)
+		return nil, errors.Wrap(err, "failed to fetch fee analytics data")
+	}
+
+	return &models.FeeAnalyticsData{StartDate: startDate, EndDate: endDate, Networks: networks}, nil
+}
+
 // GenerateCustomReport generates a custom analytics report based on specified criteria
 func (s *Service) GenerateCustomReport(ctx context.Context, request *models.CustomReportRequest) (*models.CustomReportData, error) {
 	// Validate the custom report request
@@ -94,6 +132,11 @@ func (s *Service) GenerateCustomReport(ctx context.Context, request *models.Cust
 		return nil, errors.Wrap(err, "invalid custom report request")
 	}
 
+	// Only report on the caller's organization; the repository scopes its queries to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
 	// Call the repository to fetch necessary data based on the request
 	data, err := s.repo.FetchCustomReportData(ctx, request)
 	if err != nil {
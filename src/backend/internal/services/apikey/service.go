@@ -0,0 +1,234 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// keyPrefix marks the service's API keys so they are recognisable in logs and secret scanners
+	keyPrefix = "bis"
+
+	// prefixBytes and secretBytes are the random lengths of the public lookup prefix and the secret
+	prefixBytes = 6
+	secretBytes = 32
+
+	// lastUsedResolution limits how often a key's last-used time is written
+	lastUsedResolution = time.Minute
+)
+
+var (
+	// ErrInvalidAPIKey is returned for malformed, unknown, revoked or expired keys, without saying which
+	ErrInvalidAPIKey = errors.NewUnauthorizedError("invalid API key")
+
+	// ErrAPIKeyNotFound is returned when a key does not exist in the organization
+	ErrAPIKeyNotFound = errors.NewNotFoundError("API key not found")
+)
+
+// Service struct implements the APIKeyService interface
+type Service struct {
+	repo     repository.APIKeyRepository
+	orgRepo  repository.OrganizationRepository
+	verified sync.Map
+	now      func() time.Time
+	log      *logger.Logger
+}
+
+// NewService creates a new APIKeyService instance
+func NewService(repo repository.APIKeyRepository, orgRepo repository.OrganizationRepository, log *logger.Logger) *Service {
+	return &Service{
+		repo:    repo,
+		orgRepo: orgRepo,
+		now:     time.Now,
+		log:     log,
+	}
+}
+
+// IssueAPIKey creates a key for an organization and returns its plaintext form, which is never stored
+func (s *Service) IssueAPIKey(ctx context.Context, organizationID uuid.UUID, request *models.APIKeyRequest) (*models.IssuedAPIKey, error) {
+	// Validate the requested scopes and expiry
+	if len(request.Scopes) == 0 {
+		return nil, errors.NewBadRequestError("at least one scope is required")
+	}
+	for _, scope := range request.Scopes {
+		if !validScope(scope) {
+			return nil, errors.NewBadRequestError("unknown scope: " + scope)
+		}
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(s.now()) {
+		return nil, errors.NewBadRequestError("expiry must be in the future")
+	}
+
+	// Generate the lookup prefix and the secret
+	prefix, err := randomString(prefixBytes, hex.EncodeToString)
+	if err != nil {
+		return nil, errors.NewInternalServerError("failed to generate API key", err)
+	}
+	secret, err := randomString(secretBytes, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, errors.NewInternalServerError("failed to generate API key", err)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, errors.NewInternalServerError("failed to hash API key", err)
+	}
+
+	key, err := s.repo.CreateAPIKey(ctx, &models.APIKey{
+		OrganizationID: organizationID,
+		Name:           request.Name,
+		Prefix:         prefix,
+		SecretHash:     string(hash),
+		Scopes:         request.Scopes,
+		ExpiresAt:      request.ExpiresAt,
+	})
+	if err != nil {
+		s.log.Error("Failed to create API key", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to create API key")
+	}
+
+	s.log.Info("Issued API key", "organizationID", organizationID, "apiKeyID", key.ID, "prefix", prefix, "scopes", strings.Join(key.Scopes, ","))
+	return &models.IssuedAPIKey{APIKey: key, Key: keyPrefix + "_" + prefix + "_" + secret}, nil
+}
+
+// ListAPIKeys lists an organization's keys, including revoked and expired ones
+func (s *Service) ListAPIKeys(ctx context.Context, organizationID uuid.UUID) ([]*models.APIKey, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, organizationID.String())
+	if err != nil {
+		s.log.Error("Failed to list API keys", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to list API keys")
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes one of an organization's keys; revoking twice keeps the original revocation time
+func (s *Service) RevokeAPIKey(ctx context.Context, organizationID uuid.UUID, keyID string) (*models.APIKey, error) {
+	key, err := s.repo.GetAPIKey(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		s.log.Error("Failed to get API key", "error", err, "apiKeyID", keyID)
+		return nil, errors.Wrap(err, "failed to get API key")
+	}
+	if key.OrganizationID != organizationID {
+		return nil, ErrAPIKeyNotFound
+	}
+	if key.RevokedAt != nil {
+		return key, nil
+	}
+
+	now := s.now()
+	key.RevokedAt = &now
+	updated, err := s.repo.UpdateAPIKey(ctx, key)
+	if err != nil {
+		s.log.Error("Failed to revoke API key", "error", err, "apiKeyID", keyID)
+		return nil, errors.Wrap(err, "failed to revoke API key")
+	}
+	s.verified.Delete(key.Prefix)
+
+	s.log.Info("Revoked API key", "organizationID", organizationID, "apiKeyID", key.ID, "prefix", key.Prefix)
+	return updated, nil
+}
+
+// Authenticate resolves a plaintext key to its record and organization, recording when it was last used
+func (s *Service) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, *models.Organization, error) {
+	prefix, secret, ok := parseKey(rawKey)
+	if !ok {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	// Always load the record so revocation and expiry take effect immediately
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		s.log.Error("Failed to get API key", "error", err, "prefix", prefix)
+		return nil, nil, errors.Wrap(err, "failed to get API key")
+	}
+	now := s.now()
+	if !key.Active(now) || !s.verifySecret(key, secret) {
+		s.log.Info("Rejected API key", "prefix", prefix, "apiKeyID", key.ID)
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	org, err := s.orgRepo.GetOrganization(ctx, key.OrganizationID.String())
+	if err != nil {
+		s.log.Error("Failed to get organization for API key", "error", err, "apiKeyID", key.ID)
+		return nil, nil, errors.Wrap(err, "failed to get organization")
+	}
+
+	// Record usage at a coarse resolution so busy keys do not write on every request
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchAPIKey(ctx, key.ID.String(), now); err != nil {
+			s.log.Error("Failed to record API key usage", "error", err, "apiKeyID", key.ID)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, org, nil
+}
+
+// verifySecret checks a secret against the key's bcrypt hash, remembering successful checks so that
+// repeated requests with the same key skip the deliberately slow comparison
+func (s *Service) verifySecret(key *models.APIKey, secret string) bool {
+	digest := sha256.Sum256([]byte(key.SecretHash + "\x00" + secret))
+	if cached, ok := s.verified.Load(key.Prefix); ok {
+		if subtle.ConstantTimeCompare(cached.([]byte), digest[:]) == 1 {
+			return true
+		}
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(key.SecretHash), []byte(secret)) != nil {
+		return false
+	}
+	s.verified.Store(key.Prefix, digest[:])
+	return true
+}
+
+// parseKey splits a key of the form bis_<prefix>_<secret>
+func parseKey(rawKey string) (string, string, bool) {
+	parts := strings.SplitN(rawKey, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != prefixBytes*2 || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// validScope reports whether a scope is one an API key can be granted
+func validScope(scope string) bool {
+	for _, known := range models.Permissions {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// randomString encodes n random bytes
+func randomString(n int, encode func([]byte) string) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encode(buf), nil
+}
+
+// Human tasks:
+// TODO: Provide a bootstrap command that issues an organization's first admin key
+// TODO: Alert organization admins when a key nears expiry
+// TODO: Add unit tests for each method in the service
@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// rotateScript swaps a session's refresh hash if the presented one is current, and deletes the session if
+// it is not, so a replayed refresh token ends the session for everyone holding it
+var rotateScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+if not raw then
+	return 0
+end
+local session = cjson.decode(raw)
+if session.refresh_hash ~= ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	return -1
+end
+session.refresh_hash = ARGV[2]
+session.expires_at = ARGV[3]
+redis.call('SET', KEYS[1], cjson.encode(session), 'PX', ARGV[4])
+return 1
+`)
+
+// stepUpScript records a step-up on a session without changing its expiry
+var stepUpScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+if not raw then
+	return 0
+end
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl <= 0 then
+	return 0
+end
+local session = cjson.decode(raw)
+session.step_up_at = ARGV[1]
+redis.call('SET', KEYS[1], cjson.encode(session), 'PX', ttl)
+return 1
+`)
+
+// RedisSessionStore keeps sessions in Redis, expiring with their refresh tokens
+type RedisSessionStore struct {
+	client *redis.Client
+}
+
+// NewRedisSessionStore creates a session store on a Redis client
+func NewRedisSessionStore(client *redis.Client) *RedisSessionStore {
+	return &RedisSessionStore{client: client}
+}
+
+// CreateSession stores a session and indexes it under its user
+func (rs *RedisSessionStore) CreateSession(ctx context.Context, session *models.Session) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(session.ExpiresAt)
+
+	pipe := rs.client.TxPipeline()
+	pipe.Set(ctx, sessionKey(session.ID), raw, ttl)
+	pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+	pipe.Expire(ctx, userSessionsKey(session.UserID), ttl)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetSession loads a live session
+func (rs *RedisSessionStore) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	raw, err := rs.client.Get(ctx, sessionKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var session models.Session
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RotateSession replaces a session's refresh hash and extends its expiry if oldHash is the current hash
+func (rs *RedisSessionStore) RotateSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt).Milliseconds()
+	result, err := rotateScript.Run(ctx, rs.client, []string{sessionKey(id)},
+		oldHash, newHash, expiresAt.UTC().Format(time.RFC3339Nano), strconv.FormatInt(ttl, 10)).Int()
+	if err != nil {
+		return err
+	}
+	switch result {
+	case 0:
+		return ErrSessionNotFound
+	case -1:
+		return ErrRefreshTokenReused
+	}
+
+	// Keep the user's session index alive as long as the session
+	session, err := rs.GetSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	return rs.extendUserSessions(ctx, session.UserID, time.Until(expiresAt))
+}
+
+// MarkStepUp records when the session's user last presented a second factor
+func (rs *RedisSessionStore) MarkStepUp(ctx context.Context, id string, at time.Time) error {
+	result, err := stepUpScript.Run(ctx, rs.client, []string{sessionKey(id)}, at.UTC().Format(time.RFC3339Nano)).Int()
+	if err != nil {
+		return err
+	}
+	if result == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSession removes a session
+func (rs *RedisSessionStore) DeleteSession(ctx context.Context, id string) error {
+	session, err := rs.GetSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+	pipe := rs.client.TxPipeline()
+	pipe.Del(ctx, sessionKey(id))
+	pipe.SRem(ctx, userSessionsKey(session.UserID), id)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// DeleteUserSessions removes every session of a user
+func (rs *RedisSessionStore) DeleteUserSessions(ctx context.Context, userID string) error {
+	ids, err := rs.client.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	keys := []string{userSessionsKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, sessionKey(id))
+	}
+	return rs.client.Del(ctx, keys...).Err()
+}
+
+// extendUserSessions pushes back the expiry of a user's session index if ttl outlives it
+func (rs *RedisSessionStore) extendUserSessions(ctx context.Context, userID string, ttl time.Duration) error {
+	key := userSessionsKey(userID)
+	current, err := rs.client.TTL(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if current < ttl {
+		return rs.client.Expire(ctx, key, ttl).Err()
+	}
+	return nil
+}
+
+// sessionKey is the Redis key of a session
+func sessionKey(id string) string {
+	return "auth:session:" + id
+}
+
+// userSessionsKey is the Redis key of the set of a user's session IDs
+func userSessionsKey(userID string) string {
+	return "auth:user_sessions:" + userID
+}
+
+// Human tasks:
+// TODO: Prune expired session IDs from user session sets
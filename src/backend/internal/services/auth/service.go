@@ -0,0 +1,437 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/jwt"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// Defaults for unset token lifetimes and clock skew
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultClockSkew       = 30 * time.Second
+
+	// refreshSecretBytes is the random length of a refresh token's secret
+	refreshSecretBytes = 32
+)
+
+var (
+	// ErrInvalidCredentials is returned for unknown emails, wrong passwords and deactivated users alike
+	ErrInvalidCredentials = errors.NewUnauthorizedError("invalid email or password")
+
+	// ErrNotMember is returned when a user signs in to an organization they are not an active member of
+	ErrNotMember = errors.NewForbiddenError("not a member of the organization")
+
+	// ErrOrganizationRequired is returned when a user of several organizations signs in without choosing one
+	ErrOrganizationRequired = errors.NewBadRequestError("organization_id is required for members of several organizations")
+
+	// ErrInvalidToken is returned for access tokens that fail validation or belong to a revoked session
+	ErrInvalidToken = errors.NewUnauthorizedError("invalid or expired access token")
+
+	// ErrInvalidRefreshToken is returned for malformed, unknown or expired refresh tokens
+	ErrInvalidRefreshToken = errors.NewUnauthorizedError("invalid or expired refresh token")
+
+	// ErrSessionNotFound is returned by session stores for sessions that do not exist or have expired
+	ErrSessionNotFound = errors.NewNotFoundError("session not found")
+
+	// ErrRefreshTokenReused is returned when a rotated-out refresh token is presented again. The session is
+	// revoked, since either the client or an attacker holds a stolen token
+	ErrRefreshTokenReused = errors.NewUnauthorizedError("refresh token was already used; session revoked")
+
+	// dummyHash is compared against when an email is unknown, so failed logins take the same time either way
+	dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+)
+
+// SessionStore persists refresh token sessions
+type SessionStore interface {
+	CreateSession(ctx context.Context, session *models.Session) error
+	GetSession(ctx context.Context, id string) (*models.Session, error)
+	RotateSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error
+	MarkStepUp(ctx context.Context, id string, at time.Time) error
+	DeleteSession(ctx context.Context, id string) error
+	DeleteUserSessions(ctx context.Context, userID string) error
+}
+
+// Service struct implements the AuthService interface
+type Service struct {
+	users       repository.UserRepository
+	memberships repository.MembershipRepository
+	factors     repository.MFARepository
+	sessions    SessionStore
+	keys        *jwt.KeySet
+	validator   *jwt.Validator
+	totpKey     []byte
+	cfg         config.AuthConfig
+	now         func() time.Time
+	log         *logger.Logger
+}
+
+// NewService creates a new AuthService instance signing with the configured keys
+func NewService(cfg config.AuthConfig, users repository.UserRepository, memberships repository.MembershipRepository, factors repository.MFARepository, sessions SessionStore, log *logger.Logger) (*Service, error) {
+	// Load the signing keys; the first signs and the rest remain valid for verification
+	keys := make([]jwt.Key, 0, len(cfg.SigningKeys))
+	for _, keyCfg := range cfg.SigningKeys {
+		key, err := jwt.ParseKey(keyCfg.ID, keyCfg.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	keySet, err := jwt.NewKeySet(keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the key TOTP secrets are encrypted under
+	totpKey, err := base64.StdEncoding.DecodeString(cfg.TOTPEncryptionKey)
+	if err != nil || len(totpKey) != 32 {
+		return nil, errors.NewInternalServerError("TOTP encryption key must be a base64-encoded 32-byte key", err)
+	}
+
+	// Apply defaults for unset lifetimes
+	if cfg.AccessTokenTTL <= 0 {
+		cfg.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+	if cfg.ClockSkew <= 0 {
+		cfg.ClockSkew = defaultClockSkew
+	}
+	if cfg.StepUpMaxAge <= 0 {
+		cfg.StepUpMaxAge = defaultStepUpMaxAge
+	}
+	if cfg.TOTPIssuer == "" {
+		cfg.TOTPIssuer = defaultTOTPIssuer
+	}
+
+	s := &Service{
+		users:       users,
+		memberships: memberships,
+		factors:     factors,
+		sessions:    sessions,
+		keys:        keySet,
+		totpKey:     totpKey,
+		cfg:         cfg,
+		now:         time.Now,
+		log:         log,
+	}
+	s.validator = &jwt.Validator{
+		Keys:     keySet,
+		Issuer:   cfg.Issuer,
+		Audience: cfg.Audience,
+		Leeway:   cfg.ClockSkew,
+		Now:      func() time.Time { return s.now() },
+	}
+	return s, nil
+}
+
+// Login checks a user's credentials and starts a session in one of the user's organizations
+func (s *Service) Login(ctx context.Context, request *models.LoginRequest) (*models.TokenPair, error) {
+	// Look up the user, comparing against a dummy hash for unknown emails
+	user, err := s.users.GetUserByEmail(ctx, strings.ToLower(strings.TrimSpace(request.Email)))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		s.log.Error("Failed to get user", "error", err)
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(request.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password)) != nil || user.DeactivatedAt != nil {
+		s.log.Info("Rejected login", "userID", user.ID)
+		return nil, ErrInvalidCredentials
+	}
+
+	// Sign in to the requested organization, or the only one the user belongs to
+	membership, err := s.loginMembership(ctx, user, request.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	user.OrganizationID, user.Role = membership.OrganizationID, membership.Role
+
+	// Start a session holding the hash of its first refresh token
+	secret, hash, err := newRefreshSecret()
+	if err != nil {
+		return nil, errors.NewInternalServerError("failed to generate refresh token", err)
+	}
+	now := s.now()
+	session := &models.Session{
+		ID:             uuid.New().String(),
+		UserID:         user.ID.String(),
+		OrganizationID: user.OrganizationID.String(),
+		RefreshHash:    hash,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := s.sessions.CreateSession(ctx, session); err != nil {
+		s.log.Error("Failed to create session", "error", err, "userID", user.ID)
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+
+	s.log.Info("User logged in", "userID", user.ID, "sessionID", session.ID)
+	return s.issueTokens(user, session, secret)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented refresh token stops working, and
+// presenting it again revokes the whole session
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	sessionID, presented, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Rotate the refresh token atomically so concurrent refreshes cannot both succeed
+	secret, hash, err := newRefreshSecret()
+	if err != nil {
+		return nil, errors.NewInternalServerError("failed to generate refresh token", err)
+	}
+	expiresAt := s.now().Add(s.cfg.RefreshTokenTTL)
+	if err := s.sessions.RotateSession(ctx, sessionID, hashSecret(presented), hash, expiresAt); err != nil {
+		switch {
+		case errors.Is(err, ErrSessionNotFound):
+			return nil, ErrInvalidRefreshToken
+		case errors.Is(err, ErrRefreshTokenReused):
+			s.log.Info("Revoked session after refresh token reuse", "sessionID", sessionID)
+			return nil, ErrRefreshTokenReused
+		}
+		s.log.Error("Failed to rotate refresh token", "error", err, "sessionID", sessionID)
+		return nil, errors.Wrap(err, "failed to rotate refresh token")
+	}
+
+	// Reload the session, its user and their membership so deactivation ends the session at the next refresh
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, errors.Wrap(err, "failed to get session")
+	}
+	user, err := s.activeMember(ctx, session.UserID, session.OrganizationID)
+	if err != nil {
+		if err == ErrInvalidToken {
+			s.sessions.DeleteSession(ctx, sessionID)
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return s.issueTokens(user, session, secret)
+}
+
+// Logout revokes the session a refresh token belongs to; unknown sessions are treated as already logged out
+func (s *Service) Logout(ctx context.Context, refreshToken string) error {
+	sessionID, presented, ok := parseRefreshToken(refreshToken)
+	if !ok {
+		return ErrInvalidRefreshToken
+	}
+	session, err := s.sessions.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get session")
+	}
+	if subtle.ConstantTimeCompare([]byte(session.RefreshHash), []byte(hashSecret(presented))) != 1 {
+		return ErrInvalidRefreshToken
+	}
+
+	if err := s.sessions.DeleteSession(ctx, sessionID); err != nil {
+		s.log.Error("Failed to delete session", "error", err, "sessionID", sessionID)
+		return errors.Wrap(err, "failed to delete session")
+	}
+	s.log.Info("User logged out", "userID", session.UserID, "sessionID", sessionID)
+	return nil
+}
+
+// RevokeAllSessions ends every session of a user, invalidating their refresh and access tokens
+func (s *Service) RevokeAllSessions(ctx context.Context, userID string) error {
+	if err := s.sessions.DeleteUserSessions(ctx, userID); err != nil {
+		s.log.Error("Failed to revoke sessions", "error", err, "userID", userID)
+		return errors.Wrap(err, "failed to revoke sessions")
+	}
+	s.log.Info("Revoked all sessions", "userID", userID)
+	return nil
+}
+
+// AuthenticateAccessToken validates an access token and checks that its session is still live and its
+// user still an active member of the token's organization
+func (s *Service) AuthenticateAccessToken(ctx context.Context, token string) (*jwt.Claims, *models.User, error) {
+	claims, err := s.validator.Validate(token)
+	if err != nil {
+		return nil, nil, ErrInvalidToken
+	}
+
+	// Revoked sessions end their access tokens immediately rather than at expiry
+	if _, err := s.sessions.GetSession(ctx, claims.SessionID); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, nil, ErrInvalidToken
+		}
+		s.log.Error("Failed to get session", "error", err, "sessionID", claims.SessionID)
+		return nil, nil, errors.Wrap(err, "failed to get session")
+	}
+
+	user, err := s.activeMember(ctx, claims.Subject, claims.OrganizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return claims, user, nil
+}
+
+// JWKS returns the public keys that verify this service's access tokens
+func (s *Service) JWKS() jwt.JWKS {
+	return s.keys.JWKS()
+}
+
+// issueTokens signs an access token for a session and pairs it with the session's refresh token
+func (s *Service) issueTokens(user *models.User, session *models.Session, secret string) (*models.TokenPair, error) {
+	accessToken, expiresAt, err := s.signAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     session.ID + "." + secret,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// signAccessToken signs an access token for a session. Its amr and auth_time claims record the password
+// sign-in, or the session's last step-up if there was one
+func (s *Service) signAccessToken(user *models.User, session *models.Session) (string, time.Time, error) {
+	methods, authTime := []string{jwt.MethodPassword}, session.CreatedAt
+	if session.StepUpAt != nil {
+		methods, authTime = append(methods, jwt.MethodOTP, jwt.MethodMultiFactor), *session.StepUpAt
+	}
+
+	now := s.now()
+	expiresAt := now.Add(s.cfg.AccessTokenTTL)
+	accessToken, err := s.keys.Sign(&jwt.Claims{
+		Issuer:         s.cfg.Issuer,
+		Subject:        user.ID.String(),
+		Audience:       s.cfg.Audience,
+		ExpiresAt:      expiresAt.Unix(),
+		NotBefore:      now.Unix(),
+		IssuedAt:       now.Unix(),
+		ID:             uuid.New().String(),
+		SessionID:      session.ID,
+		OrganizationID: user.OrganizationID.String(),
+		Role:           user.Role,
+		AuthMethods:    methods,
+		AuthTime:       authTime.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, errors.NewInternalServerError("failed to sign access token", err)
+	}
+	return accessToken, expiresAt, nil
+}
+
+// loginMembership picks the active membership a user signs in with
+func (s *Service) loginMembership(ctx context.Context, user *models.User, organizationID *uuid.UUID) (*models.Membership, error) {
+	// The user is not scoped to an organization yet, so their memberships are listed across all of them
+	memberships, err := s.memberships.ListMemberships(tenant.WithSystem(ctx), user.ID.String())
+	if err != nil {
+		s.log.Error("Failed to list memberships", "error", err, "userID", user.ID)
+		return nil, errors.Wrap(err, "failed to list memberships")
+	}
+	var active []*models.Membership
+	for _, membership := range memberships {
+		if !membership.Active() {
+			continue
+		}
+		if organizationID != nil && membership.OrganizationID == *organizationID {
+			return membership, nil
+		}
+		active = append(active, membership)
+	}
+
+	switch {
+	case organizationID != nil || len(active) == 0:
+		s.log.Info("Rejected login without membership", "userID", user.ID)
+		return nil, ErrNotMember
+	case len(active) > 1:
+		return nil, ErrOrganizationRequired
+	}
+	return active[0], nil
+}
+
+// activeMember loads a user acting in an organization, treating missing and deactivated users and
+// memberships as an invalid token
+func (s *Service) activeMember(ctx context.Context, userID, organizationID string) (*models.User, error) {
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		s.log.Error("Failed to get user", "error", err, "userID", userID)
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user.DeactivatedAt != nil {
+		return nil, ErrInvalidToken
+	}
+
+	// Authentication runs before the request is scoped to an organization
+	membership, err := s.memberships.GetMembership(tenant.WithSystem(ctx), organizationID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
+		s.log.Error("Failed to get membership", "error", err, "userID", userID, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to get membership")
+	}
+	if !membership.Active() {
+		return nil, ErrInvalidToken
+	}
+	user.OrganizationID, user.Role = membership.OrganizationID, membership.Role
+	return user, nil
+}
+
+// newRefreshSecret generates a refresh token secret and the hash stored for it
+func newRefreshSecret() (string, string, error) {
+	buf := make([]byte, refreshSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(buf)
+	return secret, hashSecret(secret), nil
+}
+
+// hashSecret hashes a refresh token secret; the secret's entropy makes a fast hash sufficient
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseRefreshToken splits a refresh token of the form <session ID>.<secret>
+func parseRefreshToken(token string) (string, string, bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", false
+	}
+	if _, err := uuid.Parse(parts[0]); err != nil {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// Human tasks:
+// TODO: Rotate signing keys on a schedule and publish the new key before it signs
+// TODO: Lock accounts after repeated failed logins
+// TODO: List a user's active sessions so they can revoke one from another device
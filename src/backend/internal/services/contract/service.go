@@ -0,0 +1,216 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// Caller runs contract calls on an EVM network and replays failed transactions
+type Caller interface {
+	CallContract(ctx context.Context, from, to string, value *big.Int, data []byte) ([]byte, error)
+	RevertReason(ctx context.Context, txHash string, contractABI *abi.ABI) (string, error)
+}
+
+// TransactionCreator creates transactions through the normal approval and signing pipeline
+type TransactionCreator interface {
+	CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error)
+}
+
+// Service struct implements the ContractService interface
+type Service struct {
+	vaultRepo    repository.VaultRepository
+	transactions TransactionCreator
+	callers      map[string]Caller
+	log          *logger.Logger
+}
+
+// NewService creates a new ContractService instance with a caller per EVM network, keyed by blockchain type
+func NewService(vaultRepo repository.VaultRepository, transactions TransactionCreator, callers map[string]Caller, log *logger.Logger) *Service {
+	return &Service{
+		vaultRepo:    vaultRepo,
+		transactions: transactions,
+		callers:      callers,
+		log:          log,
+	}
+}
+
+// ReadContract runs an eth_call and returns the decoded outputs
+func (s *Service) ReadContract(ctx context.Context, request *models.ContractCallRequest) (*models.ContractCallResult, error) {
+	// Resolve the network and encode the call
+	caller, contractABI, data, value, err := s.prepareCall(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call from the vault address when a vault is given, so msg.sender-dependent views work
+	var from string
+	if request.VaultID != uuid.Nil {
+		vault, err := s.getVault(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		from = vault.Address
+	}
+
+	// Run the call and decode its return data
+	raw, err := caller.CallContract(ctx, from, request.ContractAddress, value, data)
+	if err != nil {
+		return nil, s.callError(err, &contractABI)
+	}
+	outputs, err := ethereum.DecodeOutputs(contractABI, request.Method, raw)
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+
+	return &models.ContractCallResult{Outputs: outputs, RawData: hexutil.Encode(raw)}, nil
+}
+
+// WriteContract encodes a state-changing call and creates a transaction carrying the calldata
+func (s *Service) WriteContract(ctx context.Context, request *models.ContractCallRequest) (*models.Transaction, error) {
+	// Resolve the network and encode the call
+	caller, contractABI, data, value, err := s.prepareCall(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Writes must target state-changing methods and only send value to payable ones
+	method := contractABI.Methods[request.Method]
+	if method.IsConstant() {
+		return nil, errors.NewBadRequestError("method " + request.Method + " does not change state; use a read call")
+	}
+	if value.Sign() > 0 && !method.IsPayable() {
+		return nil, errors.NewBadRequestError("method " + request.Method + " is not payable")
+	}
+
+	// Writes are always sent from a vault
+	if request.VaultID == uuid.Nil {
+		return nil, errors.NewBadRequestError("vault_id is required for contract writes")
+	}
+	vault, err := s.getVault(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run the call first so a certain revert is reported before any gas is spent
+	if _, err := caller.CallContract(ctx, vault.Address, request.ContractAddress, value, data); err != nil {
+		return nil, s.callError(err, &contractABI)
+	}
+
+	// Hand the calldata to the normal transaction pipeline
+	transaction := &models.Transaction{
+		VaultID:        vault.ID,
+		BlockchainType: strings.ToLower(request.BlockchainType),
+		FromAddress:    vault.Address,
+		ToAddress:      request.ContractAddress,
+		Amount:         value.String(),
+		Data:           hexutil.Encode(data),
+		ContractCall: &models.ContractCall{
+			ABI:    request.ABI,
+			Method: request.Method,
+			Args:   request.Args,
+		},
+	}
+
+	return s.transactions.CreateTransaction(ctx, transaction)
+}
+
+// ResolveRevertReason decodes why a mined contract transaction failed and records it on the transaction
+func (s *Service) ResolveRevertReason(ctx context.Context, transaction *models.Transaction) (string, error) {
+	caller, ok := s.callers[strings.ToLower(transaction.BlockchainType)]
+	if !ok || transaction.TxHash == "" {
+		return "", errors.NewBadRequestError("transaction is not a submitted EVM transaction")
+	}
+
+	// Use the ABI stored with the call so custom errors can be decoded
+	var contractABI *abi.ABI
+	if transaction.ContractCall != nil {
+		if parsed, err := ethereum.ParseABI(transaction.ContractCall.ABI); err == nil {
+			contractABI = &parsed
+		}
+	}
+
+	reason, err := caller.RevertReason(ctx, transaction.TxHash, contractABI)
+	if err != nil {
+		s.log.Error("Failed to resolve revert reason", "error", err, "transactionID", transaction.ID)
+		return "", errors.Wrap(err, "failed to resolve revert reason")
+	}
+	transaction.RevertReason = reason
+	return reason, nil
+}
+
+// prepareCall resolves the network caller, parses the ABI and encodes the calldata and value
+func (s *Service) prepareCall(request *models.ContractCallRequest) (Caller, abi.ABI, []byte, *big.Int, error) {
+	caller, ok := s.callers[strings.ToLower(request.BlockchainType)]
+	if !ok {
+		return nil, abi.ABI{}, nil, nil, errors.NewBadRequestError("contract calls are not supported on " + request.BlockchainType)
+	}
+	if _, err := utils.ValidateAddress(request.BlockchainType, request.ContractAddress); err != nil {
+		return nil, abi.ABI{}, nil, nil, err
+	}
+
+	// Parse the ABI and encode the method call
+	contractABI, err := ethereum.ParseABI(request.ABI)
+	if err != nil {
+		return nil, abi.ABI{}, nil, nil, errors.NewBadRequestError(err.Error())
+	}
+	data, err := ethereum.EncodeCall(contractABI, request.Method, request.Args)
+	if err != nil {
+		return nil, abi.ABI{}, nil, nil, errors.NewBadRequestError(err.Error())
+	}
+
+	// Parse the native value sent with the call, in wei
+	value := new(big.Int)
+	if request.Value != "" {
+		if _, ok := value.SetString(request.Value, 10); !ok || value.Sign() < 0 {
+			return nil, abi.ABI{}, nil, nil, errors.NewInvalidAmountError("invalid value: must be a non-negative integer amount in wei")
+		}
+	}
+
+	return caller, contractABI, data, value, nil
+}
+
+// getVault retrieves the request's vault and checks it belongs to the requested network
+func (s *Service) getVault(ctx context.Context, request *models.ContractCallRequest) (*models.Vault, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, request.VaultID.String())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", request.VaultID)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	if !strings.EqualFold(vault.BlockchainType, request.BlockchainType) {
+		return nil, errors.NewBadRequestError("vault is not on " + request.BlockchainType)
+	}
+	return vault, nil
+}
+
+// callError converts a failed call into an API error, decoding custom errors declared in the ABI
+func (s *Service) callError(err error, contractABI *abi.ABI) error {
+	var revertErr *ethereum.RevertError
+	if errors.As(err, &revertErr) {
+		return errors.NewBadRequestError("contract call reverted: " + ethereum.DecodeRevertReason(contractABI, revertErr.Data))
+	}
+	s.log.Error("Failed to call contract", "error", err)
+	return errors.Wrap(err, "failed to call contract")
+}
+
+// Human tasks:
+// TODO: Call ResolveRevertReason from the confirmation monitor once it exists
+// TODO: Add an allow-list of contracts and methods per vault
+// TODO: Add unit tests for each method in the service
@@ -0,0 +1,371 @@
+package escrow
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/xrp"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// Signer signs transactions with a vault's own key and submits them to the XRP Ledger, filling in the
+// sequence, fee and expiry first
+type Signer interface {
+	SignAndSubmit(ctx context.Context, vault *models.Vault, tx data.Transaction) (*data.SubmitResult, error)
+}
+
+// Ledger looks up the outcome of submitted transactions
+type Ledger interface {
+	GetValidatedTransaction(ctx context.Context, txHash string) (*data.TransactionWithMetaData, error)
+	LatestLedger(ctx context.Context) (uint32, error)
+}
+
+// Service struct implements the EscrowService interface
+type Service struct {
+	repo           repository.EscrowRepository
+	vaultRepo      repository.VaultRepository
+	signer         Signer
+	ledger         Ledger
+	fulfillmentKey []byte
+	log            *logger.Logger
+}
+
+// NewService creates a new EscrowService instance
+func NewService(cfg config.BlockchainConfig, repo repository.EscrowRepository, vaultRepo repository.VaultRepository, signer Signer, ledger Ledger, log *logger.Logger) (*Service, error) {
+	// Load the key fulfillments are encrypted under
+	fulfillmentKey, err := base64.StdEncoding.DecodeString(cfg.EscrowEncryptionKey)
+	if err != nil || len(fulfillmentKey) != 32 {
+		return nil, errors.NewInternalServerError("escrow encryption key must be a base64-encoded 32-byte key", err)
+	}
+
+	return &Service{
+		repo:           repo,
+		vaultRepo:      vaultRepo,
+		signer:         signer,
+		ledger:         ledger,
+		fulfillmentKey: fulfillmentKey,
+		log:            log,
+	}, nil
+}
+
+// CreateEscrow locks funds from a vault in a new escrow
+func (s *Service) CreateEscrow(ctx context.Context, vaultID string, request *models.EscrowRequest) (*models.Escrow, error) {
+	// Retrieve the vault funding the escrow
+	vault, err := s.getVault(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToLower(vault.BlockchainType) != "xrp" {
+		return nil, errors.NewBadRequestError("escrows are only supported for XRP vaults")
+	}
+
+	escrow := &models.Escrow{
+		VaultID:     vault.ID,
+		Owner:       vault.Address,
+		Destination: request.Destination,
+		Amount:      request.Amount,
+		FinishAfter: request.FinishAfter,
+		CancelAfter: request.CancelAfter,
+		Status:      models.EscrowStatusPending,
+	}
+
+	// Generate a PREIMAGE-SHA-256 condition when requested, keeping only the encrypted fulfillment
+	if request.WithCondition {
+		condition, fulfillment, err := xrp.NewPreimageCondition()
+		if err != nil {
+			s.log.Error("Failed to generate escrow condition", "error", err)
+			return nil, errors.Wrap(err, "failed to generate escrow condition")
+		}
+		escrow.Condition = condition
+		escrow.EncryptedFulfillment, err = utils.EncryptAES([]byte(fulfillment), s.fulfillmentKey)
+		if err != nil {
+			s.log.Error("Failed to encrypt escrow fulfillment", "error", err)
+			return nil, errors.Wrap(err, "failed to encrypt escrow fulfillment")
+		}
+	}
+
+	// Build the EscrowCreate transaction, which also validates the time conditions
+	tx, err := xrp.BuildEscrowCreate(escrow, request.DestinationTag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Persist the escrow before anything is submitted
+	escrow, err = s.repo.CreateEscrow(ctx, escrow)
+	if err != nil {
+		s.log.Error("Failed to create escrow", "error", err)
+		return nil, errors.Wrap(err, "failed to create escrow")
+	}
+
+	// Sign and submit the EscrowCreate; the escrow is only created once a validated ledger includes it
+	result, err := s.signer.SignAndSubmit(ctx, vault, tx)
+	if err != nil {
+		escrow.Status = models.EscrowStatusFailed
+		s.log.Error("Failed to submit EscrowCreate", "error", err, "escrowID", escrow.ID)
+	} else {
+		escrow.Status = models.EscrowStatusSubmitted
+		escrow.CreateTxHash = result.TxJson.GetHash().String()
+		if tx.LastLedgerSequence != nil {
+			escrow.LastLedgerSequence = *tx.LastLedgerSequence
+		}
+	}
+
+	if _, updateErr := s.repo.UpdateEscrow(ctx, escrow); updateErr != nil {
+		s.log.Error("Failed to update escrow after submission", "error", updateErr, "escrowID", escrow.ID)
+		return nil, errors.Wrap(updateErr, "failed to update escrow after submission")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to submit escrow")
+	}
+
+	return escrow, nil
+}
+
+// GetEscrow retrieves an escrow by its ID
+func (s *Service) GetEscrow(ctx context.Context, id string) (*models.Escrow, error) {
+	escrow, err := s.repo.GetEscrowByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("escrow not found")
+		}
+		s.log.Error("Failed to get escrow", "error", err, "escrowID", id)
+		return nil, errors.Wrap(err, "failed to get escrow")
+	}
+
+	// Escrows belong to the organization of the vault that funded them
+	if _, err := s.getVault(ctx, escrow.VaultID.String()); err != nil {
+		var appErr *errors.AppError
+		if errors.As(err, &appErr) && appErr.StatusCode == 404 {
+			return nil, errors.NewNotFoundError("escrow not found")
+		}
+		return nil, err
+	}
+	return escrow, nil
+}
+
+// ListEscrows lists the escrows funded by a vault
+func (s *Service) ListEscrows(ctx context.Context, vaultID string) ([]*models.Escrow, error) {
+	if _, err := s.getVault(ctx, vaultID); err != nil {
+		return nil, err
+	}
+
+	escrows, err := s.repo.ListEscrowsByVault(ctx, vaultID)
+	if err != nil {
+		s.log.Error("Failed to list escrows", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to list escrows")
+	}
+	return escrows, nil
+}
+
+// FinishEscrow releases an escrow to its destination
+func (s *Service) FinishEscrow(ctx context.Context, id string) (*models.Escrow, error) {
+	escrow, err := s.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !escrow.CanFinish(time.Now()) {
+		return nil, errors.NewBadRequestError("escrow cannot be finished yet")
+	}
+
+	// Conditional escrows are released with their decrypted fulfillment
+	var fulfillment string
+	if escrow.Condition != "" {
+		if len(escrow.EncryptedFulfillment) == 0 {
+			return nil, errors.NewBadRequestError("escrow fulfillment is not available")
+		}
+		plaintext, err := utils.DecryptAES(escrow.EncryptedFulfillment, s.fulfillmentKey)
+		if err != nil {
+			s.log.Error("Failed to decrypt escrow fulfillment", "error", err, "escrowID", escrow.ID)
+			return nil, errors.NewInternalServerError("failed to decrypt escrow fulfillment", err)
+		}
+		fulfillment = string(plaintext)
+	}
+
+	tx, err := xrp.BuildEscrowFinish(escrow, fulfillment)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submitAndRecord(ctx, escrow, tx, models.EscrowStatusFinished)
+}
+
+// CancelEscrow returns an expired escrow to its owner
+func (s *Service) CancelEscrow(ctx context.Context, id string) (*models.Escrow, error) {
+	escrow, err := s.GetEscrow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !escrow.CanCancel(time.Now()) {
+		return nil, errors.NewBadRequestError("escrow cannot be cancelled yet")
+	}
+
+	tx, err := xrp.BuildEscrowCancel(escrow)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.submitAndRecord(ctx, escrow, tx, models.EscrowStatusCancelled)
+}
+
+// ConfirmSubmittedEscrows records the outcome of every submitted EscrowCreate that a validated ledger now
+// includes, taking the offer sequence from the validated transaction, and fails those that expired unincluded
+func (s *Service) ConfirmSubmittedEscrows(ctx context.Context) error {
+	escrows, err := s.repo.ListSubmittedEscrows(ctx)
+	if err != nil {
+		s.log.Error("Failed to list submitted escrows", "error", err)
+		return errors.Wrap(err, "failed to list submitted escrows")
+	}
+
+	for _, escrow := range escrows {
+		// A failure on one escrow must not block the others
+		if err := s.confirmEscrow(ctx, escrow); err != nil {
+			s.log.Error("Failed to confirm escrow", "error", err, "escrowID", escrow.ID)
+		}
+	}
+
+	return nil
+}
+
+// ProcessDueEscrows finishes or cancels every open escrow whose time conditions are met
+func (s *Service) ProcessDueEscrows(ctx context.Context, now time.Time) error {
+	// Retrieve every escrow still holding funds
+	escrows, err := s.repo.ListOpenEscrows(ctx)
+	if err != nil {
+		s.log.Error("Failed to list open escrows", "error", err)
+		return errors.Wrap(err, "failed to list open escrows")
+	}
+
+	for _, escrow := range escrows {
+		var processErr error
+		switch {
+		case escrow.CanCancel(now):
+			_, processErr = s.CancelEscrow(ctx, escrow.ID.String())
+		case escrow.CanFinish(now) && (escrow.Condition == "" || len(escrow.EncryptedFulfillment) > 0):
+			_, processErr = s.FinishEscrow(ctx, escrow.ID.String())
+		}
+
+		// A failure on one escrow must not block the others
+		if processErr != nil {
+			s.log.Error("Failed to process due escrow", "error", processErr, "escrowID", escrow.ID)
+		}
+	}
+
+	return nil
+}
+
+// Run confirms submitted escrows and processes due ones on every tick until the context is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	// Background jobs work across every organization
+	ctx = tenant.WithSystem(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.ConfirmSubmittedEscrows(ctx); err != nil {
+				s.log.Error("Failed to confirm submitted escrows", "error", err)
+			}
+			if err := s.ProcessDueEscrows(ctx, now); err != nil {
+				s.log.Error("Failed to process due escrows", "error", err)
+			}
+		}
+	}
+}
+
+// getVault retrieves a vault of the caller's organization
+func (s *Service) getVault(ctx context.Context, vaultID string) (*models.Vault, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, vaultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	return vault, nil
+}
+
+// confirmEscrow checks whether a submitted EscrowCreate is in a validated ledger and records the outcome
+func (s *Service) confirmEscrow(ctx context.Context, escrow *models.Escrow) error {
+	result, err := s.ledger.GetValidatedTransaction(ctx, escrow.CreateTxHash)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case result == nil:
+		// Still pending unless the ledger has moved past the transaction's expiry
+		latest, err := s.ledger.LatestLedger(ctx)
+		if err != nil {
+			return err
+		}
+		if latest <= escrow.LastLedgerSequence {
+			return nil
+		}
+		escrow.Status = models.EscrowStatusFailed
+		s.log.Error("EscrowCreate expired before it was validated", "escrowID", escrow.ID, "txHash", escrow.CreateTxHash)
+	case result.MetaData.TransactionResult.Success():
+		// Finish and cancel name the escrow by the sequence of the validated EscrowCreate
+		escrow.Status = models.EscrowStatusCreated
+		escrow.OfferSequence = result.GetBase().Sequence
+	default:
+		escrow.Status = models.EscrowStatusFailed
+		s.log.Error("EscrowCreate failed on the ledger", "escrowID", escrow.ID, "result", result.MetaData.TransactionResult.String())
+	}
+
+	if _, err := s.repo.UpdateEscrow(ctx, escrow); err != nil {
+		return errors.Wrap(err, "failed to update escrow")
+	}
+	return nil
+}
+
+// submitAndRecord signs and submits a finish or cancel transaction and records the outcome on the escrow
+func (s *Service) submitAndRecord(ctx context.Context, escrow *models.Escrow, tx data.Transaction, status string) (*models.Escrow, error) {
+	// The transaction is sent from, and signed by, the vault that funded the escrow
+	vault, err := s.getVault(ctx, escrow.VaultID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.signer.SignAndSubmit(ctx, vault, tx)
+	if err != nil {
+		s.log.Error("Failed to submit escrow transaction", "error", err, "escrowID", escrow.ID, "status", status)
+		return nil, errors.Wrap(err, "failed to submit escrow transaction")
+	}
+
+	// Record the transaction hash against the matching operation
+	escrow.Status = status
+	if status == models.EscrowStatusFinished {
+		escrow.FinishTxHash = result.TxJson.GetHash().String()
+	} else {
+		escrow.CancelTxHash = result.TxJson.GetHash().String()
+	}
+
+	updated, err := s.repo.UpdateEscrow(ctx, escrow)
+	if err != nil {
+		s.log.Error("Failed to update escrow", "error", err, "escrowID", escrow.ID)
+		return nil, errors.Wrap(err, "failed to update escrow")
+	}
+
+	return updated, nil
+}
+
+// Human tasks:
+// TODO: Confirm finish and cancel transactions against validated ledgers before updating the status
+// TODO: Add unit tests for each method in the service
+// TODO: Implement audit logging for all escrow operations
@@ -0,0 +1,383 @@
+package gasstation
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// defaultBufferPercent is added on top of the shortfall so gas price movements do not block the transfer again
+const defaultBufferPercent = 20
+
+// ErrTopUpTooLarge is returned when a vault needs more gas than the configured top-up limit
+var ErrTopUpTooLarge = errors.NewBadRequestError("required gas top-up exceeds the configured maximum")
+
+// Chain reads balances, fee estimates and confirmations from an EVM network
+type Chain interface {
+	GetBalance(ctx context.Context, address string) (*big.Int, error)
+	EstimateFee(ctx context.Context, from, to string, value *big.Int, data []byte) (*big.Int, error)
+	GetConfirmations(ctx context.Context, txHash string) (uint64, bool, error)
+}
+
+// Transactions creates top-ups and releases or fails the transfers waiting on them
+type Transactions interface {
+	CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error)
+	ReleaseTransaction(ctx context.Context, id string) error
+	UpdateTransactionStatus(ctx context.Context, id, status string) (*models.Transaction, error)
+}
+
+// Service struct implements the GasStationService interface
+type Service struct {
+	orgRepo       repository.OrganizationRepository
+	vaultRepo     repository.VaultRepository
+	txRepo        repository.TransactionRepository
+	transactions  Transactions
+	chains        map[string]Chain
+	bufferPercent int
+	maxTopUp      *big.Int
+	log           *logger.Logger
+}
+
+// NewService creates a new GasStationService instance with a chain reader per EVM network, keyed by blockchain type
+func NewService(orgRepo repository.OrganizationRepository, vaultRepo repository.VaultRepository, txRepo repository.TransactionRepository, transactions Transactions, chains map[string]Chain, cfg config.GasStationConfig, log *logger.Logger) (*Service, error) {
+	bufferPercent := cfg.BufferPercent
+	if bufferPercent <= 0 {
+		bufferPercent = defaultBufferPercent
+	}
+
+	// Every top-up is capped so a misestimated fee cannot drain the station
+	maxTopUp, ok := new(big.Int).SetString(cfg.MaxTopUpWei, 10)
+	if !ok || maxTopUp.Sign() <= 0 {
+		return nil, errors.NewBadRequestError("gas station MaxTopUpWei must be a positive integer")
+	}
+
+	return &Service{
+		orgRepo:       orgRepo,
+		vaultRepo:     vaultRepo,
+		txRepo:        txRepo,
+		transactions:  transactions,
+		chains:        chains,
+		bufferPercent: bufferPercent,
+		maxTopUp:      maxTopUp,
+		log:           log,
+	}, nil
+}
+
+// SetGasStationVault configures the vault that pays gas for an organization's vaults
+func (s *Service) SetGasStationVault(ctx context.Context, organizationID, vaultID string) (*models.Organization, error) {
+	org, err := s.getOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The station must be a single-key EVM vault owned by the organization
+	vault, err := s.getVault(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if vault.OrganizationID != org.ID {
+		return nil, errors.NewBadRequestError("gas station vault must belong to the organization")
+	}
+	if _, ok := s.chains[strings.ToLower(vault.BlockchainType)]; !ok {
+		return nil, errors.NewBadRequestError("gas stations are not supported on " + vault.BlockchainType)
+	}
+	if vault.Safe != nil || vault.MultiSig != nil {
+		return nil, errors.NewBadRequestError("gas station vault must be a single-key vault")
+	}
+
+	org.GasStationVaultID = &vault.ID
+	updated, err := s.orgRepo.UpdateOrganization(ctx, org)
+	if err != nil {
+		s.log.Error("Failed to update organization", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to update organization")
+	}
+	return updated, nil
+}
+
+// GetGasStationVault returns the vault that pays gas for an organization's vaults
+func (s *Service) GetGasStationVault(ctx context.Context, organizationID string) (*models.Vault, error) {
+	org, err := s.getOrganization(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if org.GasStationVaultID == nil {
+		return nil, errors.NewNotFoundError("organization has no gas station")
+	}
+	return s.getVault(ctx, org.GasStationVaultID.String())
+}
+
+// EnsureGas checks that a vault can pay for a transaction and, if not, sends a top-up from the organization's
+// gas station. It returns the ID of the top-up the transaction must wait for, or nil when it can be submitted
+func (s *Service) EnsureGas(ctx context.Context, transaction *models.Transaction) (*uuid.UUID, error) {
+	// Top-ups are paid by the station and sweeps by their deposit address, so neither waits on a top-up
+	if transaction.Purpose == models.TransactionPurposeGasTopUp || transaction.Purpose == models.TransactionPurposeSweep {
+		return nil, nil
+	}
+	blockchainType := strings.ToLower(transaction.BlockchainType)
+	chain, ok := s.chains[blockchainType]
+	if !ok {
+		return nil, nil
+	}
+
+	// The station only pays gas. A vault sending the native asset pays its own fee, so the station never
+	// funds the transfer itself
+	value, ok := new(big.Int).SetString(transaction.Amount, 10)
+	if !ok {
+		return nil, errors.NewInvalidAmountError("invalid amount: must be an integer amount in wei")
+	}
+	if value.Sign() != 0 {
+		return nil, nil
+	}
+
+	// Only vaults whose organization has a gas station on this network are topped up
+	vault, err := s.getVault(ctx, transaction.VaultID.String())
+	if err != nil {
+		return nil, err
+	}
+	org, err := s.getOrganization(ctx, vault.OrganizationID.String())
+	if err != nil {
+		return nil, err
+	}
+	if org.GasStationVaultID == nil || *org.GasStationVaultID == vault.ID {
+		return nil, nil
+	}
+	station, err := s.getVault(ctx, org.GasStationVaultID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(station.BlockchainType, blockchainType) {
+		return nil, nil
+	}
+
+	// Work out what the transaction's gas will cost
+	var data []byte
+	if transaction.Data != "" {
+		if data, err = hexutil.Decode(transaction.Data); err != nil {
+			return nil, errors.NewBadRequestError("calldata must be a 0x-prefixed hex string")
+		}
+	}
+	fee, err := chain.EstimateFee(ctx, vault.Address, transaction.ToAddress, value, data)
+	if err != nil {
+		return nil, err
+	}
+	transaction.Fee = fee.String()
+
+	// Count funds already on the way and fees already waiting for them
+	balance, err := chain.GetBalance(ctx, vault.Address)
+	if err != nil {
+		return nil, err
+	}
+	inbound, latestTopUp, err := s.pendingTopUps(ctx, vault.ID)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.reservedCost(ctx, vault.ID, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+	available := new(big.Int).Add(balance, inbound)
+	available.Sub(available, reserved)
+
+	// Chain onto a pending top-up when it already covers this transaction
+	if available.Cmp(fee) >= 0 {
+		if latestTopUp != nil {
+			return &latestTopUp.ID, nil
+		}
+		return nil, nil
+	}
+
+	// Otherwise send a new top-up sized for the shortfall plus a buffer
+	amount, err := TopUpAmount(fee, available, s.bufferPercent, s.maxTopUp)
+	if err != nil {
+		s.log.Error("Refusing gas top-up", "error", err, "vaultID", vault.ID, "fee", fee.String(), "available", available.String())
+		return nil, err
+	}
+	topUp, err := s.transactions.CreateTransaction(ctx, &models.Transaction{
+		VaultID:        station.ID,
+		BlockchainType: station.BlockchainType,
+		FromAddress:    station.Address,
+		ToAddress:      vault.Address,
+		Amount:         amount.String(),
+		Purpose:        models.TransactionPurposeGasTopUp,
+	})
+	if err != nil {
+		s.log.Error("Failed to create gas top-up", "error", err, "vaultID", vault.ID, "stationID", station.ID)
+		return nil, err
+	}
+
+	s.log.Info("Created gas top-up", "vaultID", vault.ID, "topUpID", topUp.ID, "amount", amount.String(), "transactionID", transaction.ID)
+	return &topUp.ID, nil
+}
+
+// ReleaseReady submits the transactions whose top-up has confirmed and fails those whose top-up failed
+func (s *Service) ReleaseReady(ctx context.Context) error {
+	waiting, err := s.txRepo.ListAwaitingGas(ctx)
+	if err != nil {
+		s.log.Error("Failed to list transactions awaiting gas", "error", err)
+		return errors.Wrap(err, "failed to list transactions awaiting gas")
+	}
+
+	for _, transaction := range waiting {
+		if err := s.release(ctx, transaction); err != nil {
+			// A failure on one transaction must not block the others
+			s.log.Error("Failed to release transaction awaiting gas", "error", err, "transactionID", transaction.ID)
+		}
+	}
+	return nil
+}
+
+// Run releases transactions awaiting gas on every tick until the context is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	// Background jobs work across every organization
+	ctx = tenant.WithSystem(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ReleaseReady(ctx); err != nil {
+				s.log.Error("Failed to release transactions awaiting gas", "error", err)
+			}
+		}
+	}
+}
+
+// TopUpAmount sizes a top-up to cover the shortfall between cost and available funds plus a percentage buffer
+func TopUpAmount(cost, available *big.Int, bufferPercent int, maxTopUp *big.Int) (*big.Int, error) {
+	shortfall := new(big.Int).Sub(cost, available)
+	if shortfall.Sign() <= 0 {
+		return new(big.Int), nil
+	}
+
+	// shortfall * (100 + buffer) / 100, rounded up
+	amount := new(big.Int).Mul(shortfall, big.NewInt(int64(100+bufferPercent)))
+	amount.Add(amount, big.NewInt(99))
+	amount.Div(amount, big.NewInt(100))
+
+	if maxTopUp != nil && amount.Cmp(maxTopUp) > 0 {
+		return nil, ErrTopUpTooLarge
+	}
+	return amount, nil
+}
+
+// release submits or fails one waiting transaction based on the state of its top-up
+func (s *Service) release(ctx context.Context, transaction *models.Transaction) error {
+	if transaction.DependsOnID == nil {
+		return s.transactions.ReleaseTransaction(ctx, transaction.ID.String())
+	}
+	topUp, err := s.txRepo.GetTransactionByID(ctx, transaction.DependsOnID.String())
+	if err != nil {
+		return err
+	}
+
+	// A failed top-up fails everything chained on it
+	if topUp.Status == "Failed" {
+		_, err := s.transactions.UpdateTransactionStatus(ctx, transaction.ID.String(), "Failed")
+		return err
+	}
+	if topUp.TxHash == "" {
+		return nil
+	}
+
+	chain, ok := s.chains[strings.ToLower(topUp.BlockchainType)]
+	if !ok {
+		return errors.NewBadRequestError("gas stations are not supported on " + topUp.BlockchainType)
+	}
+	_, confirmed, err := chain.GetConfirmations(ctx, topUp.TxHash)
+	if err != nil || !confirmed {
+		return err
+	}
+
+	return s.transactions.ReleaseTransaction(ctx, transaction.ID.String())
+}
+
+// pendingTopUps sums the unconfirmed top-ups sent to a vault and returns the most recent one
+func (s *Service) pendingTopUps(ctx context.Context, vaultID uuid.UUID) (*big.Int, *models.Transaction, error) {
+	topUps, err := s.txRepo.ListPendingTopUps(ctx, vaultID.String())
+	if err != nil {
+		s.log.Error("Failed to list pending gas top-ups", "error", err, "vaultID", vaultID)
+		return nil, nil, errors.Wrap(err, "failed to list pending gas top-ups")
+	}
+
+	total := new(big.Int)
+	var latest *models.Transaction
+	for _, topUp := range topUps {
+		if amount, ok := new(big.Int).SetString(topUp.Amount, 10); ok {
+			total.Add(total, amount)
+		}
+		if latest == nil || topUp.CreatedAt.After(latest.CreatedAt) {
+			latest = topUp
+		}
+	}
+	return total, latest, nil
+}
+
+// reservedCost sums the estimated fees of the vault's other transactions already waiting for gas
+func (s *Service) reservedCost(ctx context.Context, vaultID, excludeID uuid.UUID) (*big.Int, error) {
+	waiting, err := s.txRepo.ListAwaitingGas(ctx)
+	if err != nil {
+		s.log.Error("Failed to list transactions awaiting gas", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to list transactions awaiting gas")
+	}
+
+	total := new(big.Int)
+	for _, transaction := range waiting {
+		if transaction.VaultID != vaultID || transaction.ID == excludeID {
+			continue
+		}
+		if fee, ok := new(big.Int).SetString(transaction.Fee, 10); ok {
+			total.Add(total, fee)
+		}
+	}
+	return total, nil
+}
+
+// getVault retrieves a vault by ID
+func (s *Service) getVault(ctx context.Context, vaultID string) (*models.Vault, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, vaultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	return vault, nil
+}
+
+// getOrganization retrieves an organization by ID
+func (s *Service) getOrganization(ctx context.Context, organizationID string) (*models.Organization, error) {
+	org, err := s.orgRepo.GetOrganization(ctx, organizationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("organization not found")
+		}
+		s.log.Error("Failed to get organization", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to get organization")
+	}
+	if !tenant.Owns(ctx, org.ID) {
+		return nil, errors.NewNotFoundError("organization not found")
+	}
+	return org, nil
+}
+
+// Human tasks:
+// TODO: Serialize EnsureGas per vault so concurrent transfers cannot both skip a needed top-up
+// TODO: Alert when the gas station's own balance falls below a threshold
+// TODO: Add unit tests for each method in the service
@@ -0,0 +1,74 @@
+package realtime
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// acquireScript drops an organization's expired leases and adds one for a stream if fewer than the limit
+// remain. The set expires with its newest lease, so organizations whose streams all ended leave nothing behind
+var acquireScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[4])
+redis.call('PEXPIREAT', KEYS[1], ARGV[2])
+return 1
+`)
+
+// RedisConnections keeps each organization's connection leases in a Redis sorted set scored by expiry, so
+// every replica counts against the same limit
+type RedisConnections struct {
+	client *redis.Client
+}
+
+// NewRedisConnections creates a connection counter on a Redis client
+func NewRedisConnections(client *redis.Client) *RedisConnections {
+	return &RedisConnections{client: client}
+}
+
+// Acquire takes a lease for a stream unless the organization already holds limit unexpired ones
+func (rc *RedisConnections) Acquire(ctx context.Context, organizationID uuid.UUID, streamID string, limit int, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	acquired, err := acquireScript.Run(ctx, rc.client, []string{connectionsKey(organizationID)},
+		now.UnixNano()/int64(time.Millisecond), now.Add(ttl).UnixNano()/int64(time.Millisecond), limit, streamID).Int()
+	if err != nil {
+		return false, err
+	}
+	return acquired == 1, nil
+}
+
+// Refresh pushes back the expiry of open streams' leases. Leases released in the meantime are not recreated
+func (rc *RedisConnections) Refresh(ctx context.Context, streams map[uuid.UUID][]string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl)
+	score := float64(expiresAt.UnixNano() / int64(time.Millisecond))
+
+	pipe := rc.client.Pipeline()
+	for organizationID, streamIDs := range streams {
+		members := make([]*redis.Z, 0, len(streamIDs))
+		for _, streamID := range streamIDs {
+			members = append(members, &redis.Z{Score: score, Member: streamID})
+		}
+		pipe.ZAddXX(ctx, connectionsKey(organizationID), members...)
+		pipe.PExpireAt(ctx, connectionsKey(organizationID), expiresAt)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Release removes a stream's lease
+func (rc *RedisConnections) Release(ctx context.Context, organizationID uuid.UUID, streamID string) error {
+	return rc.client.ZRem(ctx, connectionsKey(organizationID), streamID).Err()
+}
+
+// connectionsKey is the sorted set holding an organization's connection leases
+func connectionsKey(organizationID uuid.UUID) string {
+	return "realtime:connections:" + organizationID.String()
+}
+
+// Human tasks:
+// TODO: Expose the number of open streams per organization as a metric
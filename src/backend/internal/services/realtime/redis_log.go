@@ -0,0 +1,107 @@
+package realtime
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// eventLogKey is the Redis stream holding the event log
+	eventLogKey = "realtime:events"
+
+	// defaultRetention is how many events the log keeps when no retention is configured
+	defaultRetention = 10000
+)
+
+// RedisEventLog keeps the event log in a Redis stream trimmed to roughly the last retention events
+type RedisEventLog struct {
+	client    *redis.Client
+	retention int64
+}
+
+// NewRedisEventLog creates an event log on a Redis client
+func NewRedisEventLog(client *redis.Client, retention int64) *RedisEventLog {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	return &RedisEventLog{client: client, retention: retention}
+}
+
+// Append adds a message to the stream, trimming the oldest entries past the retention
+func (rl *RedisEventLog) Append(ctx context.Context, message *Message) (string, error) {
+	return rl.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventLogKey,
+		MaxLen: rl.retention,
+		Approx: true,
+		Values: map[string]interface{}{
+			"organization_id": message.OrganizationID.String(),
+			"channels":        strings.Join(message.Channels, ","),
+			"event":           string(message.Event),
+		},
+	}).Result()
+}
+
+// Read returns up to count messages after an ID, blocking up to block for one when block is not negative
+func (rl *RedisEventLog) Read(ctx context.Context, after string, count int, block time.Duration) ([]*Message, error) {
+	args := &redis.XReadArgs{
+		Streams: []string{eventLogKey, after},
+		Count:   int64(count),
+		Block:   -1,
+	}
+	if block >= 0 {
+		args.Block = block
+	}
+	streams, err := rl.client.XRead(ctx, args).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*Message
+	for _, stream := range streams {
+		for _, entry := range stream.Messages {
+			messages = append(messages, decodeMessage(entry))
+		}
+	}
+	return messages, nil
+}
+
+// Bounds returns the IDs of the first and last entries of the stream
+func (rl *RedisEventLog) Bounds(ctx context.Context) (string, string, error) {
+	first, err := rl.client.XRangeN(ctx, eventLogKey, "-", "+", 1).Result()
+	if err != nil {
+		return "", "", err
+	}
+	last, err := rl.client.XRevRangeN(ctx, eventLogKey, "+", "-", 1).Result()
+	if err != nil {
+		return "", "", err
+	}
+	if len(first) == 0 || len(last) == 0 {
+		return "", "", nil
+	}
+	return first[0].ID, last[0].ID, nil
+}
+
+// decodeMessage converts a stream entry back into a message
+func decodeMessage(entry redis.XMessage) *Message {
+	message := &Message{ID: entry.ID}
+	if value, ok := entry.Values["organization_id"].(string); ok {
+		message.OrganizationID, _ = uuid.Parse(value)
+	}
+	if value, ok := entry.Values["channels"].(string); ok && value != "" {
+		message.Channels = strings.Split(value, ",")
+	}
+	if value, ok := entry.Values["event"].(string); ok {
+		message.Event = []byte(value)
+	}
+	return message
+}
+
+// Human tasks:
+// TODO: Trim the stream by age as well as length once Redis 6.2 MINID trimming is available everywhere
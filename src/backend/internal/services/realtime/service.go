@@ -0,0 +1,418 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+const (
+	// Defaults for unset streaming settings
+	defaultBufferSize        = 512
+	defaultHeartbeatInterval = 30 * time.Second
+	defaultMaxConnections    = 100
+
+	// readBatch is how many events are read from the log at a time
+	readBatch = 100
+
+	// readBlock is how long Run waits on the log for new events before reading again
+	readBlock = 5 * time.Second
+
+	// retryDelay is how long Run waits after the log fails before reading again
+	retryDelay = time.Second
+
+	// leaseHeartbeats is how many heartbeats a stream's connection lease outlives its last refresh, so a
+	// replica that stops refreshing frees its organizations' slots soon after
+	leaseHeartbeats = 3
+)
+
+// Channels clients subscribe to. A vault's channel carries every event about the vault
+const (
+	ChannelTransactions = "transactions"
+	ChannelSignatures   = "signatures"
+	ChannelDeposits     = "deposits"
+	vaultChannelPrefix  = "vault:"
+)
+
+var (
+	// ErrSlowConsumer is returned to a subscriber that let more events pile up than its buffer holds
+	ErrSlowConsumer = errors.New("subscriber fell too far behind; reconnect with the last event ID", 503, nil)
+
+	// ErrInvalidEventID is returned when a client resumes from an ID that is not an event log ID
+	ErrInvalidEventID = errors.NewBadRequestError("invalid last event ID")
+
+	// ErrTooManyConnections is returned when an organization already has as many streams open as allowed
+	ErrTooManyConnections = errors.New("too many event streams open for the organization", 429, nil)
+)
+
+// Message is an event as carried on the event log. Its ID orders it within the log and is the cursor
+// clients resume from
+type Message struct {
+	ID             string
+	OrganizationID uuid.UUID
+	Channels       []string
+	Event          json.RawMessage
+}
+
+// EventLog is the ordered, retained log events are fanned out through, shared by every replica
+type EventLog interface {
+	// Append adds a message to the log, returning its ID
+	Append(ctx context.Context, message *Message) (string, error)
+
+	// Read returns up to count messages after an ID, waiting up to block for one to arrive; a negative
+	// block returns immediately
+	Read(ctx context.Context, after string, count int, block time.Duration) ([]*Message, error)
+
+	// Bounds returns the IDs of the oldest and newest retained messages, both empty while the log is empty
+	Bounds(ctx context.Context) (oldest, newest string, err error)
+}
+
+// Connections counts the streams each organization has open across every replica. Each stream holds a lease
+// that expires unless refreshed, so the streams of a replica that dies stop counting against the limit
+type Connections interface {
+	// Acquire takes a lease for a stream unless the organization already holds limit unexpired ones,
+	// reporting whether it did
+	Acquire(ctx context.Context, organizationID uuid.UUID, streamID string, limit int, ttl time.Duration) (bool, error)
+
+	// Refresh extends the leases of streams that are still open, keyed by organization
+	Refresh(ctx context.Context, streams map[uuid.UUID][]string, ttl time.Duration) error
+
+	// Release gives up a stream's lease
+	Release(ctx context.Context, organizationID uuid.UUID, streamID string) error
+}
+
+// Service struct implements the RealtimeService interface
+type Service struct {
+	events      EventLog
+	connections Connections
+	cfg         config.RealtimeConfig
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	log         *logger.Logger
+}
+
+// NewService creates a new RealtimeService instance
+func NewService(cfg config.RealtimeConfig, events EventLog, connections Connections, log *logger.Logger) *Service {
+	// Apply defaults for unset settings
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.HeartbeatInterval <= 0 {
+		cfg.HeartbeatInterval = defaultHeartbeatInterval
+	}
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = defaultMaxConnections
+	}
+
+	return &Service{
+		events:      events,
+		connections: connections,
+		cfg:         cfg,
+		subscribers: make(map[*Subscriber]struct{}),
+		log:         log,
+	}
+}
+
+// HeartbeatInterval returns how often idle connections are pinged to keep them and their proxies alive
+func (s *Service) HeartbeatInterval() time.Duration {
+	return s.cfg.HeartbeatInterval
+}
+
+// Publish appends an event to the event log, from which every replica fans it out to its subscribers
+func (s *Service) Publish(ctx context.Context, event *models.Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode event")
+	}
+	if _, err := s.events.Append(ctx, &Message{
+		OrganizationID: event.OrganizationID,
+		Channels:       Channels(event),
+		Event:          raw,
+	}); err != nil {
+		s.log.Error("Failed to append event", "error", err, "eventID", event.ID)
+		return errors.Wrap(err, "failed to append event")
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber for an organization's events on some channels. Given the ID of the last
+// event a client received, the events it missed are replayed first; if they are no longer retained the
+// subscriber is marked for a resync instead. Each subscriber counts against the organization's connection
+// limit, shared by every replica, until it is disconnected
+func (s *Service) Subscribe(ctx context.Context, organizationID uuid.UUID, channels []string, lastEventID string) (*Subscriber, error) {
+	for _, channel := range channels {
+		if _, _, err := ChannelPermission(channel); err != nil {
+			return nil, err
+		}
+	}
+	if lastEventID != "" {
+		if _, _, ok := parseID(lastEventID); !ok {
+			return nil, ErrInvalidEventID
+		}
+	}
+
+	// Take one of the organization's connection slots
+	subscriber := newSubscriber(organizationID, channels, s.cfg.BufferSize)
+	acquired, err := s.connections.Acquire(ctx, organizationID, subscriber.id, s.cfg.MaxConnections, s.leaseTTL())
+	if err != nil {
+		s.log.Error("Failed to count event streams", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to count event streams")
+	}
+	if !acquired {
+		return nil, ErrTooManyConnections
+	}
+
+	// Live events are held back while missed ones are replayed so they arrive in order
+	subscriber.holding = lastEventID != ""
+	s.mu.Lock()
+	s.subscribers[subscriber] = struct{}{}
+	s.mu.Unlock()
+	if lastEventID == "" {
+		return subscriber, nil
+	}
+
+	if err := s.replay(ctx, subscriber, lastEventID); err != nil {
+		s.Disconnect(subscriber)
+		s.log.Error("Failed to replay events", "error", err, "organizationID", organizationID)
+		return nil, errors.Wrap(err, "failed to replay events")
+	}
+	return subscriber, nil
+}
+
+// Disconnect stops delivering events to a subscriber and frees its connection slot
+func (s *Service) Disconnect(subscriber *Subscriber) {
+	s.mu.Lock()
+	_, ok := s.subscribers[subscriber]
+	delete(s.subscribers, subscriber)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	// The request that opened the stream is usually gone, so release the slot on its own context; a failed
+	// release only holds the slot until its lease expires
+	if err := s.connections.Release(context.Background(), subscriber.OrganizationID, subscriber.id); err != nil {
+		s.log.Error("Failed to release event stream", "error", err, "organizationID", subscriber.OrganizationID)
+	}
+}
+
+// Run fans events appended to the log by any replica out to this replica's subscribers, and keeps their
+// connection leases alive, until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	go s.refreshLeases(ctx)
+
+	cursor := ""
+	for ctx.Err() == nil {
+		// Start from the newest event; subscribers resuming from older ones replay them on subscribing
+		if cursor == "" {
+			_, newest, err := s.events.Bounds(ctx)
+			if err != nil {
+				s.log.Error("Failed to read event log bounds", "error", err)
+				sleep(ctx, retryDelay)
+				continue
+			}
+			cursor = newest
+			if cursor == "" {
+				cursor = "0-0"
+			}
+		}
+
+		messages, err := s.events.Read(ctx, cursor, readBatch, readBlock)
+		if err != nil {
+			if ctx.Err() == nil {
+				s.log.Error("Failed to read event log", "error", err)
+				sleep(ctx, retryDelay)
+			}
+			continue
+		}
+		for _, message := range messages {
+			s.dispatch(message)
+			cursor = message.ID
+		}
+	}
+}
+
+// refreshLeases extends the connection leases of this replica's subscribers on every heartbeat
+func (s *Service) refreshLeases(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			streams := make(map[uuid.UUID][]string)
+			s.mu.RLock()
+			for subscriber := range s.subscribers {
+				streams[subscriber.OrganizationID] = append(streams[subscriber.OrganizationID], subscriber.id)
+			}
+			s.mu.RUnlock()
+			if len(streams) == 0 {
+				continue
+			}
+			if err := s.connections.Refresh(ctx, streams, s.leaseTTL()); err != nil {
+				s.log.Error("Failed to refresh event stream leases", "error", err)
+			}
+		}
+	}
+}
+
+// leaseTTL returns how long a connection lease lasts without being refreshed
+func (s *Service) leaseTTL() time.Duration {
+	return leaseHeartbeats * s.cfg.HeartbeatInterval
+}
+
+// dispatch hands a message to the subscribers of its organization
+func (s *Service) dispatch(message *Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for subscriber := range s.subscribers {
+		if subscriber.OrganizationID == message.OrganizationID {
+			subscriber.deliver(message)
+		}
+	}
+}
+
+// replay queues the events a resuming subscriber missed after lastEventID, then releases the live events
+// held back meanwhile
+func (s *Service) replay(ctx context.Context, subscriber *Subscriber, lastEventID string) error {
+	oldest, _, err := s.events.Bounds(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Events after lastEventID may have been trimmed from the log, or be more than the buffer holds
+	resync := oldest != "" && compareIDs(lastEventID, oldest) < 0
+	cursor := lastEventID
+	var missed []*Message
+	for !resync {
+		messages, err := s.events.Read(ctx, cursor, readBatch, -1)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			cursor = message.ID
+			if message.OrganizationID == subscriber.OrganizationID && subscriber.matches(message) {
+				missed = append(missed, message)
+			}
+		}
+		if len(missed) > s.cfg.BufferSize {
+			resync = true
+		}
+		if len(messages) < readBatch {
+			break
+		}
+	}
+
+	if resync {
+		subscriber.release(nil, "", true)
+	} else {
+		subscriber.release(missed, cursor, false)
+	}
+	return nil
+}
+
+// Channels returns the channels an event is published on: its kind's channel and its vault's channel
+func Channels(event *models.Event) []string {
+	var channels []string
+	switch event.Type {
+	case models.EventTransactionStatusChanged:
+		channels = append(channels, ChannelTransactions)
+	case models.EventSignatureCompleted, models.EventSignatureFailed:
+		channels = append(channels, ChannelSignatures)
+	case models.EventDepositReceived:
+		channels = append(channels, ChannelDeposits)
+	}
+
+	var vaultID uuid.UUID
+	switch data := event.Data.(type) {
+	case *models.Transaction:
+		vaultID = data.VaultID
+	case *models.SignatureRequest:
+		vaultID = data.VaultID
+	case *models.DepositEvent:
+		vaultID = data.VaultID
+	}
+	if vaultID != uuid.Nil {
+		channels = append(channels, VaultChannel(vaultID))
+	}
+	return channels
+}
+
+// VaultChannel returns the channel of a vault's events
+func VaultChannel(vaultID uuid.UUID) string {
+	return vaultChannelPrefix + vaultID.String()
+}
+
+// ChannelPermission returns the permission needed to subscribe to a channel, and the vault it is scoped to
+// for vault channels
+func ChannelPermission(channel string) (permission, vaultID string, err error) {
+	switch channel {
+	case ChannelTransactions:
+		return models.PermissionTxRead, "", nil
+	case ChannelSignatures:
+		return models.PermissionSignRead, "", nil
+	case ChannelDeposits:
+		return models.PermissionVaultRead, "", nil
+	}
+	if strings.HasPrefix(channel, vaultChannelPrefix) {
+		id, err := uuid.Parse(strings.TrimPrefix(channel, vaultChannelPrefix))
+		if err == nil {
+			return models.PermissionVaultRead, id.String(), nil
+		}
+	}
+	return "", "", errors.NewBadRequestError("unknown channel: " + channel)
+}
+
+// parseID splits an event log ID of the form <milliseconds>-<sequence>
+func parseID(id string) (ms, seq uint64, ok bool) {
+	parts := strings.SplitN(id, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err = strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+// compareIDs orders two event log IDs, returning -1, 0 or 1
+func compareIDs(a, b string) int {
+	aMs, aSeq, _ := parseID(a)
+	bMs, bSeq, _ := parseID(b)
+	switch {
+	case aMs < bMs || (aMs == bMs && aSeq < bSeq):
+		return -1
+	case aMs == bMs && aSeq == bSeq:
+		return 0
+	}
+	return 1
+}
+
+// sleep waits for a duration or until the context is cancelled
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// Human tasks:
+// TODO: Export fan-out lag and dropped subscriber metrics to Prometheus
@@ -0,0 +1,191 @@
+package realtime
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Subscriber is one client's subscription to an organization's events. Events wait in a bounded queue
+// until the client takes them; a client that falls further behind is dropped with ErrSlowConsumer rather
+// than slowing down delivery to everyone else
+type Subscriber struct {
+	OrganizationID uuid.UUID
+
+	id       string
+	mu       sync.Mutex
+	channels map[string]bool
+	queue    []*Message
+	held     []*Message
+	holding  bool
+	resync   bool
+	cursor   string
+	limit    int
+	err      error
+	ready    chan struct{}
+	done     chan struct{}
+}
+
+// newSubscriber creates a subscriber on some channels holding at most limit waiting events
+func newSubscriber(organizationID uuid.UUID, channels []string, limit int) *Subscriber {
+	subscriber := &Subscriber{
+		OrganizationID: organizationID,
+		id:             uuid.New().String(),
+		channels:       make(map[string]bool, len(channels)),
+		limit:          limit,
+		ready:          make(chan struct{}, 1),
+		done:           make(chan struct{}),
+	}
+	for _, channel := range channels {
+		subscriber.channels[channel] = true
+	}
+	return subscriber
+}
+
+// Next returns the next event, waiting for one to arrive. Once the subscriber has been dropped it returns
+// the events already queued, then the reason it was dropped
+func (sub *Subscriber) Next(ctx context.Context) (*Message, error) {
+	for {
+		sub.mu.Lock()
+		if len(sub.queue) > 0 {
+			message := sub.queue[0]
+			sub.queue[0] = nil
+			sub.queue = sub.queue[1:]
+			sub.mu.Unlock()
+			return message, nil
+		}
+		err := sub.err
+		sub.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-sub.ready:
+		case <-sub.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Subscribe adds channels to the subscription
+func (sub *Subscriber) Subscribe(channels []string) error {
+	for _, channel := range channels {
+		if _, _, err := ChannelPermission(channel); err != nil {
+			return err
+		}
+	}
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	for _, channel := range channels {
+		sub.channels[channel] = true
+	}
+	return nil
+}
+
+// Unsubscribe removes channels from the subscription
+func (sub *Subscriber) Unsubscribe(channels []string) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	for _, channel := range channels {
+		delete(sub.channels, channel)
+	}
+}
+
+// Channels lists the subscribed channels
+func (sub *Subscriber) Channels() []string {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	channels := make([]string, 0, len(sub.channels))
+	for channel := range sub.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Resync reports whether events the client asked to resume from were lost, so it must reload its state
+func (sub *Subscriber) Resync() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.resync
+}
+
+// deliver queues a live event if it is on one of the subscribed channels
+func (sub *Subscriber) deliver(message *Message) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.err != nil || !sub.matchesLocked(message) {
+		return
+	}
+	if sub.holding {
+		if len(sub.held) >= sub.limit {
+			sub.fail(ErrSlowConsumer)
+			return
+		}
+		sub.held = append(sub.held, message)
+		return
+	}
+	if len(sub.queue) >= sub.limit {
+		sub.fail(ErrSlowConsumer)
+		return
+	}
+	sub.enqueue(message)
+}
+
+// release queues replayed events, then the live events held back while they were read. Held events the
+// replay already covered, up to cursor, are skipped
+func (sub *Subscriber) release(replayed []*Message, cursor string, resync bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.resync = resync
+	for _, message := range replayed {
+		sub.enqueue(message)
+	}
+	sub.cursor = cursor
+	for _, message := range sub.held {
+		sub.enqueue(message)
+	}
+	sub.held, sub.holding = nil, false
+}
+
+// matches reports whether an event is on one of the subscribed channels
+func (sub *Subscriber) matches(message *Message) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.matchesLocked(message)
+}
+
+// matchesLocked is matches for callers holding the lock
+func (sub *Subscriber) matchesLocked(message *Message) bool {
+	for _, channel := range message.Channels {
+		if sub.channels[channel] {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue appends an event newer than the last one queued and wakes Next
+func (sub *Subscriber) enqueue(message *Message) {
+	if sub.cursor != "" && compareIDs(message.ID, sub.cursor) <= 0 {
+		return
+	}
+	sub.queue = append(sub.queue, message)
+	sub.cursor = message.ID
+	select {
+	case sub.ready <- struct{}{}:
+	default:
+	}
+}
+
+// fail drops the subscriber, waking Next to report why
+func (sub *Subscriber) fail(err error) {
+	if sub.err == nil {
+		sub.err = err
+		close(sub.done)
+	}
+}
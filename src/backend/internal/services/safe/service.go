@@ -0,0 +1,728 @@
+package safe
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// Chain reads Safe state and receipts from an EVM network
+type Chain interface {
+	Network() evm.Network
+	SafeNonce(ctx context.Context, safe string) (uint64, error)
+	SafeOwners(ctx context.Context, safe string) ([]string, uint64, error)
+	GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error)
+}
+
+// Transactions creates and reads relayer transactions through the normal approval and signing pipeline
+type Transactions interface {
+	CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error)
+	GetTransaction(ctx context.Context, id string) (*models.Transaction, error)
+}
+
+// SignatureRequester is the part of the signature service used to collect owner signatures
+type SignatureRequester interface {
+	RequestSignature(ctx context.Context, request *models.SignatureRequest) (*models.SignatureRequest, error)
+	GetSignatureStatus(ctx context.Context, id string) (*models.SignatureRequest, error)
+}
+
+// Service struct implements the SafeService interface
+type Service struct {
+	repo         repository.SafeTransactionRepository
+	vaultRepo    repository.VaultRepository
+	transactions Transactions
+	signatures   SignatureRequester
+	chains       map[string]Chain
+	contracts    config.SafeContractsConfig
+	delegates    map[common.Address]bool
+	log          *logger.Logger
+}
+
+// NewService creates a new SafeService instance with a chain reader per EVM network, keyed by blockchain type
+func NewService(repo repository.SafeTransactionRepository, vaultRepo repository.VaultRepository, transactions Transactions, signatures SignatureRequester, chains map[string]Chain, contracts config.SafeContractsConfig, log *logger.Logger) *Service {
+	// Index the contracts delegatecalls are allowed into
+	delegates := make(map[common.Address]bool, len(contracts.DelegateCallTargets))
+	for _, target := range contracts.DelegateCallTargets {
+		if common.IsHexAddress(target) {
+			delegates[common.HexToAddress(target)] = true
+		}
+	}
+
+	return &Service{
+		repo:         repo,
+		vaultRepo:    vaultRepo,
+		transactions: transactions,
+		signatures:   signatures,
+		chains:       chains,
+		contracts:    contracts,
+		delegates:    delegates,
+		log:          log,
+	}
+}
+
+// DeploySafe creates a Safe vault and the relayer transaction that deploys its contract through the proxy factory
+func (s *Service) DeploySafe(ctx context.Context, request *models.SafeDeployRequest) (*models.Vault, error) {
+	if _, err := s.chain(request.BlockchainType); err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(s.contracts.SingletonAddress) || !common.IsHexAddress(s.contracts.ProxyFactoryAddress) {
+		return nil, errors.NewInternalServerError("Safe singleton and proxy factory addresses are not configured", nil)
+	}
+	owners, err := validateOwners(request.Owners)
+	if err != nil {
+		return nil, err
+	}
+	relayer, err := s.getRelayer(ctx, request.RelayerVaultID, request.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the requested salt nonce, or a random one so identical owner sets still get distinct Safes
+	saltNonce := new(big.Int)
+	if request.SaltNonce != "" {
+		if _, ok := saltNonce.SetString(request.SaltNonce, 10); !ok || saltNonce.Sign() < 0 {
+			return nil, errors.NewBadRequestError("salt_nonce must be a non-negative integer")
+		}
+	} else {
+		random := uuid.New()
+		saltNonce.SetBytes(random[:])
+	}
+
+	// Encode setup and the proxy factory call
+	initializer, err := ethereum.EncodeSafeSetup(owners, request.Threshold, common.HexToAddress(s.contracts.FallbackHandlerAddress))
+	if err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	data, err := ethereum.EncodeCreateProxy(common.HexToAddress(s.contracts.SingletonAddress), initializer, saltNonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode Safe deployment")
+	}
+
+	// The relayer vault sends the deployment like any other transaction
+	deployment, err := s.transactions.CreateTransaction(ctx, &models.Transaction{
+		VaultID:        relayer.ID,
+		BlockchainType: relayer.BlockchainType,
+		FromAddress:    relayer.Address,
+		ToAddress:      common.HexToAddress(s.contracts.ProxyFactoryAddress).Hex(),
+		Amount:         "0",
+		Data:           hexutil.Encode(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The vault has no address until the deployment is mined
+	vault, err := s.vaultRepo.CreateVault(ctx, &models.Vault{
+		OrganizationID: request.OrganizationID,
+		Name:           request.Name,
+		BlockchainType: strings.ToLower(request.BlockchainType),
+		Status:         "Deploying",
+		Safe: &models.SafeConfig{
+			Owners:         request.Owners,
+			Threshold:      request.Threshold,
+			RelayerVaultID: relayer.ID,
+			DeploymentTxID: &deployment.ID,
+			SaltNonce:      saltNonce.String(),
+		},
+	})
+	if err != nil {
+		s.log.Error("Failed to create Safe vault", "error", err)
+		return nil, errors.Wrap(err, "failed to create Safe vault")
+	}
+	return vault, nil
+}
+
+// ConfirmDeployment reads the deployment receipt and activates the Safe vault with its new address
+func (s *Service) ConfirmDeployment(ctx context.Context, vaultID string) (*models.Vault, error) {
+	vault, err := s.getSafeVault(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if vault.Address != "" || vault.Safe.DeploymentTxID == nil {
+		return vault, nil
+	}
+	chain, err := s.chain(vault.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the mined deployment
+	deployment, err := s.transactions.GetTransaction(ctx, vault.Safe.DeploymentTxID.String())
+	if err != nil {
+		return nil, err
+	}
+	if deployment.TxHash == "" {
+		return nil, errors.NewBadRequestError("Safe deployment has not been submitted yet")
+	}
+	receipt, err := chain.GetTransactionReceipt(ctx, deployment.TxHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Safe deployment receipt")
+	}
+
+	// Take the Safe address from the factory's ProxyCreation event
+	if receipt.Status == types.ReceiptStatusFailed {
+		vault.Status = "Failed"
+	} else {
+		address, err := ethereum.SafeAddressFromReceipt(receipt, common.HexToAddress(s.contracts.ProxyFactoryAddress))
+		if err != nil {
+			s.log.Error("Failed to find deployed Safe", "error", err, "vaultID", vault.ID)
+			return nil, errors.Wrap(err, "failed to find deployed Safe")
+		}
+		vault.Address = address.Hex()
+		vault.Status = "Active"
+	}
+
+	updated, err := s.vaultRepo.UpdateVault(ctx, vault)
+	if err != nil {
+		s.log.Error("Failed to update Safe vault", "error", err, "vaultID", vault.ID)
+		return nil, errors.Wrap(err, "failed to update Safe vault")
+	}
+	return updated, nil
+}
+
+// AttachSafe creates a vault for an existing Safe after checking its owners on chain
+func (s *Service) AttachSafe(ctx context.Context, request *models.SafeAttachRequest) (*models.Vault, error) {
+	chain, err := s.chain(request.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsHexAddress(request.Address) {
+		return nil, errors.NewInvalidAddressError("invalid Safe address")
+	}
+	owners, err := validateOwners(request.Owners)
+	if err != nil {
+		return nil, err
+	}
+	relayer, err := s.getRelayer(ctx, request.RelayerVaultID, request.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every on-chain owner must have a signer backend, and no extra owners may be configured
+	onChainOwners, threshold, err := chain.SafeOwners(ctx, request.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Safe owners")
+	}
+	if len(onChainOwners) != len(owners) {
+		return nil, errors.NewBadRequestError("configured owners do not match the Safe's owners")
+	}
+	configured := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		configured[owner] = true
+	}
+	for _, owner := range onChainOwners {
+		if !configured[common.HexToAddress(owner)] {
+			return nil, errors.NewBadRequestError("Safe owner " + owner + " has no configured signer backend")
+		}
+	}
+
+	// Start tracking from the Safe's current nonce
+	nonce, err := chain.SafeNonce(ctx, request.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Safe nonce")
+	}
+
+	vault, err := s.vaultRepo.CreateVault(ctx, &models.Vault{
+		OrganizationID: request.OrganizationID,
+		Name:           request.Name,
+		BlockchainType: strings.ToLower(request.BlockchainType),
+		Address:        common.HexToAddress(request.Address).Hex(),
+		Status:         "Active",
+		Safe: &models.SafeConfig{
+			Owners:         request.Owners,
+			Threshold:      threshold,
+			Nonce:          nonce,
+			RelayerVaultID: relayer.ID,
+		},
+	})
+	if err != nil {
+		s.log.Error("Failed to create Safe vault", "error", err)
+		return nil, errors.Wrap(err, "failed to create Safe vault")
+	}
+	return vault, nil
+}
+
+// ProposeTransaction assigns the next Safe nonce to a transaction and requests a signature from every owner
+func (s *Service) ProposeTransaction(ctx context.Context, vaultID string, request *models.SafeTransactionRequest) (*models.SafeTransaction, error) {
+	vault, err := s.getSafeVault(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if vault.Address == "" {
+		return nil, errors.NewBadRequestError("Safe has not been deployed yet")
+	}
+	chain, err := s.chain(vault.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the tracked nonce unless the Safe has moved past it
+	onChainNonce, err := chain.SafeNonce(ctx, vault.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Safe nonce")
+	}
+	nonce := vault.Safe.Nonce
+	if onChainNonce > nonce {
+		nonce = onChainNonce
+	}
+
+	created, err := s.propose(ctx, vault, chain, request, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the nonce for this proposal
+	vault.Safe.Nonce = nonce + 1
+	if _, err := s.vaultRepo.UpdateVault(ctx, vault); err != nil {
+		s.log.Error("Failed to update Safe nonce", "error", err, "vaultID", vault.ID)
+		return nil, errors.Wrap(err, "failed to update Safe nonce")
+	}
+	return created, nil
+}
+
+// ExecuteTransaction combines the owner signatures once the threshold is met and sends execTransaction from the relayer vault
+func (s *Service) ExecuteTransaction(ctx context.Context, vaultID, safeTxID string) (*models.SafeTransaction, error) {
+	vault, safeTx, err := s.getSafeTransaction(ctx, vaultID, safeTxID)
+	if err != nil {
+		return nil, err
+	}
+	if safeTx.Status != models.SafeTransactionStatusPending {
+		return nil, errors.NewBadRequestError("Safe transaction is already " + strings.ToLower(safeTx.Status))
+	}
+	chain, err := s.chain(vault.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+
+	// The Safe only accepts the transaction at its current nonce
+	onChainNonce, err := chain.SafeNonce(ctx, vault.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Safe nonce")
+	}
+	if onChainNonce != safeTx.Nonce {
+		return nil, errors.NewBadRequestError("Safe is at nonce " + strconv.FormatUint(onChainNonce, 10) + "; earlier transactions must execute first")
+	}
+
+	// Collect completed signatures that recover to a distinct owner
+	hash := common.HexToHash(safeTx.SafeTxHash)
+	signatures, err := s.collectSignatures(ctx, vault, hash, safeTx.SignatureRequestIDs)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(signatures)) < vault.Safe.Threshold {
+		return nil, errors.NewBadRequestError("Safe threshold not yet reached")
+	}
+
+	// Encode execTransaction and send it from the relayer vault
+	tx, err := toSafeTx(safeTx, s.delegates)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ethereum.EncodeExecTransaction(tx, ethereum.EncodeSafeSignatures(signatures))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode execTransaction")
+	}
+	relayer, err := s.getRelayer(ctx, vault.Safe.RelayerVaultID, vault.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+	execution, err := s.transactions.CreateTransaction(ctx, &models.Transaction{
+		VaultID:        relayer.ID,
+		BlockchainType: relayer.BlockchainType,
+		FromAddress:    relayer.Address,
+		ToAddress:      vault.Address,
+		Amount:         "0",
+		Data:           hexutil.Encode(data),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	safeTx.ExecutionTxID = &execution.ID
+	safeTx.Status = models.SafeTransactionStatusExecuting
+	updated, err := s.repo.UpdateSafeTransaction(ctx, safeTx)
+	if err != nil {
+		s.log.Error("Failed to update Safe transaction", "error", err, "safeTxID", safeTx.ID)
+		return nil, errors.Wrap(err, "failed to update Safe transaction")
+	}
+	return updated, nil
+}
+
+// ConfirmExecution reads the relayer transaction of an executing Safe transaction and records its outcome
+func (s *Service) ConfirmExecution(ctx context.Context, vaultID, safeTxID string) (*models.SafeTransaction, error) {
+	vault, safeTx, err := s.getSafeTransaction(ctx, vaultID, safeTxID)
+	if err != nil {
+		return nil, err
+	}
+	return s.confirmExecution(ctx, vault, safeTx)
+}
+
+// ConfirmExecutions records the outcome of every Safe transaction whose relayer transaction has been sent
+func (s *Service) ConfirmExecutions(ctx context.Context) error {
+	safeTxs, err := s.repo.ListExecutingSafeTransactions(ctx)
+	if err != nil {
+		s.log.Error("Failed to list executing Safe transactions", "error", err)
+		return errors.Wrap(err, "failed to list executing Safe transactions")
+	}
+
+	for _, safeTx := range safeTxs {
+		// A failure on one transaction must not block the others
+		vault, err := s.getSafeVault(ctx, safeTx.VaultID.String())
+		if err == nil {
+			_, err = s.confirmExecution(ctx, vault, safeTx)
+		}
+		if err != nil {
+			s.log.Error("Failed to confirm Safe transaction", "error", err, "safeTxID", safeTx.ID)
+		}
+	}
+	return nil
+}
+
+// CancelTransaction cancels a pending Safe transaction. The newest proposal gives its nonce back; an earlier
+// one would leave a gap that blocks every later proposal, so a rejection (an empty call from the Safe to itself)
+// is proposed at its nonce instead and returned for the owners to sign and execute
+func (s *Service) CancelTransaction(ctx context.Context, vaultID, safeTxID string) (*models.SafeTransaction, error) {
+	vault, safeTx, err := s.getSafeTransaction(ctx, vaultID, safeTxID)
+	if err != nil {
+		return nil, err
+	}
+	if safeTx.Status != models.SafeTransactionStatusPending {
+		return nil, errors.NewBadRequestError("Safe transaction is already " + strings.ToLower(safeTx.Status))
+	}
+	chain, err := s.chain(vault.BlockchainType)
+	if err != nil {
+		return nil, err
+	}
+	onChainNonce, err := chain.SafeNonce(ctx, vault.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read Safe nonce")
+	}
+
+	// Work out whether the nonce can be released or must be used up by a rejection
+	var rejection *models.SafeTransaction
+	switch {
+	case onChainNonce > safeTx.Nonce:
+		// Another transaction already used the nonce
+	case vault.Safe.Nonce == safeTx.Nonce+1:
+		vault.Safe.Nonce = safeTx.Nonce
+		if _, err := s.vaultRepo.UpdateVault(ctx, vault); err != nil {
+			s.log.Error("Failed to release Safe nonce", "error", err, "vaultID", vault.ID)
+			return nil, errors.Wrap(err, "failed to release Safe nonce")
+		}
+	default:
+		rejection, err = s.propose(ctx, vault, chain, &models.SafeTransactionRequest{To: vault.Address, Value: "0"}, safeTx.Nonce)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	safeTx.Status = models.SafeTransactionStatusCancelled
+	cancelled, err := s.repo.UpdateSafeTransaction(ctx, safeTx)
+	if err != nil {
+		s.log.Error("Failed to update Safe transaction", "error", err, "safeTxID", safeTx.ID)
+		return nil, errors.Wrap(err, "failed to update Safe transaction")
+	}
+	if rejection != nil {
+		return rejection, nil
+	}
+	return cancelled, nil
+}
+
+// Run confirms executing Safe transactions on each tick until the context is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	// Background jobs work across every organization
+	ctx = tenant.WithSystem(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.ConfirmExecutions(ctx); err != nil {
+				s.log.Error("Failed to confirm Safe transactions", "error", err)
+			}
+		}
+	}
+}
+
+// propose builds a Safe transaction at the given nonce and requests a signature from every owner
+func (s *Service) propose(ctx context.Context, vault *models.Vault, chain Chain, request *models.SafeTransactionRequest, nonce uint64) (*models.SafeTransaction, error) {
+	safeTx := &models.SafeTransaction{
+		ID:        uuid.New(),
+		VaultID:   vault.ID,
+		To:        request.To,
+		Value:     request.Value,
+		Data:      request.Data,
+		Operation: request.Operation,
+		Nonce:     nonce,
+		Status:    models.SafeTransactionStatusPending,
+	}
+	if safeTx.Value == "" {
+		safeTx.Value = "0"
+	}
+	tx, err := toSafeTx(safeTx, s.delegates)
+	if err != nil {
+		return nil, err
+	}
+	hash := ethereum.SafeTxHash(chain.Network().ChainID, common.HexToAddress(vault.Address), tx)
+	safeTx.SafeTxHash = hash.Hex()
+
+	// Ask every owner's backend to sign the EIP-712 hash; the Safe transaction keeps the request IDs
+	for _, owner := range vault.Safe.Owners {
+		signatureRequest, err := s.signatures.RequestSignature(ctx, &models.SignatureRequest{
+			VaultID:       vault.ID,
+			DataToSign:    hex.EncodeToString(hash.Bytes()),
+			SignatureType: models.SignatureTypeSafeOwner,
+			SignerBackend: owner.Backend,
+		})
+		if err != nil {
+			s.log.Error("Failed to request Safe owner signature", "error", err, "vaultID", vault.ID, "owner", owner.Address)
+			return nil, err
+		}
+		safeTx.SignatureRequestIDs = append(safeTx.SignatureRequestIDs, signatureRequest.ID)
+	}
+
+	created, err := s.repo.CreateSafeTransaction(ctx, safeTx)
+	if err != nil {
+		s.log.Error("Failed to create Safe transaction", "error", err, "vaultID", vault.ID)
+		return nil, errors.Wrap(err, "failed to create Safe transaction")
+	}
+	return created, nil
+}
+
+// confirmExecution moves an executing Safe transaction to executed or failed once its relayer transaction is
+// mined, or back to pending so it can be executed again when the relayer transaction never reached the Safe
+func (s *Service) confirmExecution(ctx context.Context, vault *models.Vault, safeTx *models.SafeTransaction) (*models.SafeTransaction, error) {
+	if safeTx.Status != models.SafeTransactionStatusExecuting || safeTx.ExecutionTxID == nil {
+		return safeTx, nil
+	}
+	execution, err := s.transactions.GetTransaction(ctx, safeTx.ExecutionTxID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case execution.Status == "Failed":
+		// The relayer transaction was never sent, so the Safe nonce is still free
+		safeTx.Status = models.SafeTransactionStatusPending
+		safeTx.ExecutionTxID = nil
+	case execution.TxHash == "":
+		return safeTx, nil
+	default:
+		chain, err := s.chain(vault.BlockchainType)
+		if err != nil {
+			return nil, err
+		}
+		receipt, err := chain.GetTransactionReceipt(ctx, execution.TxHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get Safe execution receipt")
+		}
+
+		// A reverted execTransaction, such as one that ran out of gas, leaves the nonce unused
+		if receipt.Status == types.ReceiptStatusFailed {
+			safeTx.Status = models.SafeTransactionStatusPending
+			safeTx.ExecutionTxID = nil
+			break
+		}
+		success, err := ethereum.SafeExecutionResult(receipt, common.HexToAddress(vault.Address), common.HexToHash(safeTx.SafeTxHash))
+		if err != nil {
+			s.log.Error("Failed to find Safe execution result", "error", err, "safeTxID", safeTx.ID)
+			return nil, errors.Wrap(err, "failed to find Safe execution result")
+		}
+		safeTx.Status = models.SafeTransactionStatusFailed
+		if success {
+			safeTx.Status = models.SafeTransactionStatusExecuted
+		}
+	}
+
+	updated, err := s.repo.UpdateSafeTransaction(ctx, safeTx)
+	if err != nil {
+		s.log.Error("Failed to update Safe transaction", "error", err, "safeTxID", safeTx.ID)
+		return nil, errors.Wrap(err, "failed to update Safe transaction")
+	}
+	return updated, nil
+}
+
+// collectSignatures fetches the owner signatures for a Safe transaction, keeping at most threshold valid ones
+func (s *Service) collectSignatures(ctx context.Context, vault *models.Vault, hash common.Hash, requestIDs []uuid.UUID) (map[common.Address][]byte, error) {
+	owners := make(map[common.Address]bool, len(vault.Safe.Owners))
+	for _, owner := range vault.Safe.Owners {
+		owners[common.HexToAddress(owner.Address)] = true
+	}
+
+	signatures := make(map[common.Address][]byte)
+	for _, id := range requestIDs {
+		if uint64(len(signatures)) >= vault.Safe.Threshold {
+			break
+		}
+		request, err := s.signatures.GetSignatureStatus(ctx, id.String())
+		if err != nil {
+			return nil, err
+		}
+		if request.Status != models.SignatureStatusCompleted {
+			continue
+		}
+
+		// Only count signatures that really come from an owner of this Safe
+		signature, err := hex.DecodeString(strings.TrimPrefix(request.Signature, "0x"))
+		if err != nil {
+			s.log.Error("Ignoring malformed Safe owner signature", "error", err, "requestID", id)
+			continue
+		}
+		signer, err := ethereum.RecoverSafeSigner(hash, signature)
+		if err != nil || !owners[signer] {
+			s.log.Error("Ignoring Safe signature from a non-owner", "requestID", id, "signer", signer.Hex())
+			continue
+		}
+		signatures[signer] = signature
+	}
+	return signatures, nil
+}
+
+// getSafeVault retrieves a vault and checks that it is backed by a Safe
+func (s *Service) getSafeVault(ctx context.Context, vaultID string) (*models.Vault, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, vaultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	if vault.Safe == nil {
+		return nil, errors.NewBadRequestError("vault is not a Safe vault")
+	}
+	return vault, nil
+}
+
+// getSafeTransaction retrieves a Safe vault and one of its transactions
+func (s *Service) getSafeTransaction(ctx context.Context, vaultID, safeTxID string) (*models.Vault, *models.SafeTransaction, error) {
+	vault, err := s.getSafeVault(ctx, vaultID)
+	if err != nil {
+		return nil, nil, err
+	}
+	safeTx, err := s.repo.GetSafeTransaction(ctx, safeTxID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, errors.NewNotFoundError("Safe transaction not found")
+		}
+		s.log.Error("Failed to get Safe transaction", "error", err, "safeTxID", safeTxID)
+		return nil, nil, errors.Wrap(err, "failed to get Safe transaction")
+	}
+	if safeTx.VaultID != vault.ID {
+		return nil, nil, errors.NewNotFoundError("Safe transaction not found")
+	}
+	return vault, safeTx, nil
+}
+
+// getRelayer retrieves the EOA vault that pays for Safe deployments and executions
+func (s *Service) getRelayer(ctx context.Context, relayerID uuid.UUID, blockchainType string) (*models.Vault, error) {
+	relayer, err := s.vaultRepo.GetVault(ctx, relayerID.String())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("relayer vault not found")
+		}
+		s.log.Error("Failed to get relayer vault", "error", err, "vaultID", relayerID)
+		return nil, errors.Wrap(err, "failed to get relayer vault")
+	}
+	if !tenant.Owns(ctx, relayer.OrganizationID) {
+		return nil, errors.NewNotFoundError("relayer vault not found")
+	}
+	if relayer.Safe != nil || relayer.MultiSig != nil {
+		return nil, errors.NewBadRequestError("relayer vault must be a single-key vault")
+	}
+	if !strings.EqualFold(relayer.BlockchainType, blockchainType) {
+		return nil, errors.NewBadRequestError("relayer vault is not on " + blockchainType)
+	}
+	return relayer, nil
+}
+
+// chain returns the chain reader for an EVM network
+func (s *Service) chain(blockchainType string) (Chain, error) {
+	chain, ok := s.chains[strings.ToLower(blockchainType)]
+	if !ok {
+		return nil, errors.NewBadRequestError("Safe vaults are not supported on " + blockchainType)
+	}
+	return chain, nil
+}
+
+// validateOwners checks that owners are distinct addresses with signer backends
+func validateOwners(owners []models.SafeOwner) ([]common.Address, error) {
+	if len(owners) == 0 {
+		return nil, errors.NewBadRequestError("a Safe needs at least one owner")
+	}
+	addresses := make([]common.Address, 0, len(owners))
+	seen := make(map[common.Address]bool, len(owners))
+	for _, owner := range owners {
+		if !common.IsHexAddress(owner.Address) {
+			return nil, errors.NewInvalidAddressError("invalid Safe owner address: " + owner.Address)
+		}
+		address := common.HexToAddress(owner.Address)
+		if seen[address] {
+			return nil, errors.NewBadRequestError("duplicate Safe owner: " + owner.Address)
+		}
+		if owner.Backend == "" {
+			return nil, errors.NewBadRequestError("Safe owner " + owner.Address + " has no signer backend")
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// toSafeTx converts a stored Safe transaction into its contract form, allowing delegatecalls only into the given contracts
+func toSafeTx(safeTx *models.SafeTransaction, delegates map[common.Address]bool) (ethereum.SafeTx, error) {
+	if !common.IsHexAddress(safeTx.To) {
+		return ethereum.SafeTx{}, errors.NewInvalidAddressError("invalid destination address")
+	}
+	value, ok := new(big.Int).SetString(safeTx.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return ethereum.SafeTx{}, errors.NewInvalidAmountError("invalid value: must be a non-negative integer amount in wei")
+	}
+	if safeTx.Operation > models.SafeOperationDelegateCall {
+		return ethereum.SafeTx{}, errors.NewBadRequestError("operation must be 0 (call) or 1 (delegatecall)")
+	}
+	if safeTx.Operation == models.SafeOperationDelegateCall && !delegates[common.HexToAddress(safeTx.To)] {
+		return ethereum.SafeTx{}, errors.NewBadRequestError("delegatecall to " + safeTx.To + " is not allowed")
+	}
+	var data []byte
+	if safeTx.Data != "" {
+		var err error
+		if data, err = hexutil.Decode(safeTx.Data); err != nil {
+			return ethereum.SafeTx{}, errors.NewBadRequestError("data must be a 0x-prefixed hex string")
+		}
+	}
+
+	return ethereum.SafeTx{
+		To:        common.HexToAddress(safeTx.To),
+		Value:     value,
+		Data:      data,
+		Operation: safeTx.Operation,
+		Nonce:     new(big.Int).SetUint64(safeTx.Nonce),
+	}, nil
+}
+
+// Human tasks:
+// TODO: Let organizations extend the delegatecall allowlist per vault
+// TODO: Add unit tests for DeploySafe and AttachSafe
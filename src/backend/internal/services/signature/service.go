@@ -2,30 +2,59 @@ package signature
 
 import (
 	"context"
+	"github.com/google/uuid"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
 	"github.com/your-repo/blockchain-integration-service/pkg/crypto"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
+// listRules are the sort fields and filters signature request listings accept
+var listRules = pagination.Rules{
+	SortFields: []string{pagination.SortCreatedAt, pagination.SortUpdatedAt},
+	Filters:    []string{pagination.FilterStatus, pagination.FilterVaultID, pagination.FilterCreated},
+}
+
+// EventPublisher pushes events about an organization's resources to its subscribers
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
+
 // Service struct implements the SignatureService interface
 type Service struct {
-	repo   repository.SignatureRepository
-	signer crypto.Signer
-	log    *logger.Logger
+	repo      repository.SignatureRepository
+	vaultRepo repository.VaultRepository
+	signer    crypto.Signer
+	backends  map[string]crypto.Signer
+	events    []EventPublisher
+	log       *logger.Logger
 }
 
 // NewService creates a new SignatureService instance
-func NewService(repo repository.SignatureRepository, signer crypto.Signer, log *logger.Logger) *Service {
+func NewService(repo repository.SignatureRepository, vaultRepo repository.VaultRepository, signer crypto.Signer, log *logger.Logger) *Service {
 	// Create a new Service struct
 	return &Service{
-		repo:   repo,
-		signer: signer,
-		log:    log,
+		repo:      repo,
+		vaultRepo: vaultRepo,
+		signer:    signer,
+		backends:  make(map[string]crypto.Signer),
+		log:       log,
 	}
 }
 
+// RegisterBackend registers an additional named signer backend used by requests that set SignerBackend
+func (s *Service) RegisterBackend(name string, signer crypto.Signer) {
+	s.backends[name] = signer
+}
+
+// RegisterEventPublisher registers a publisher notified when a signature request completes or fails
+func (s *Service) RegisterEventPublisher(events EventPublisher) {
+	s.events = append(s.events, events)
+}
+
 // RequestSignature method to request a new signature
 func (s *Service) RequestSignature(ctx context.Context, request *models.SignatureRequest) (*models.SignatureRequest, error) {
 	// Validate the signature request input
@@ -33,6 +62,30 @@ func (s *Service) RequestSignature(ctx context.Context, request *models.Signatur
 		return nil, errors.Wrap(err, "invalid signature request")
 	}
 
+	// Reject requests for signer backends that are not registered
+	if request.SignerBackend != "" {
+		if _, ok := s.backends[request.SignerBackend]; !ok {
+			return nil, errors.BadRequest("unknown signer backend: " + request.SignerBackend)
+		}
+	}
+
+	// Create the request in the caller's organization, on one of its own vaults
+	organizationID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if request.VaultID != uuid.Nil {
+		vault, err := s.vaultRepo.GetVault(ctx, request.VaultID.String())
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			s.log.Error("Failed to get vault", "error", err, "vaultID", request.VaultID)
+			return nil, errors.Wrap(err, "failed to get vault")
+		}
+		if err != nil || vault.OrganizationID != organizationID {
+			return nil, errors.NotFound("vault not found")
+		}
+	}
+	request.OrganizationID = organizationID
+
 	// Set the initial status of the request to 'Pending'
 	request.Status = models.SignatureStatusPending
 
@@ -43,9 +96,10 @@ func (s *Service) RequestSignature(ctx context.Context, request *models.Signatur
 		return nil, errors.Wrap(err, "failed to create signature request")
 	}
 
-	// Initiate an asynchronous signature generation process
+	// Initiate an asynchronous signature generation process, still scoped to the request's organization
 	go func() {
-		if err := s.generateSignature(context.Background(), createdRequest); err != nil {
+		signCtx := tenant.WithOrganization(context.Background(), createdRequest.OrganizationID)
+		if err := s.generateSignature(signCtx, createdRequest); err != nil {
 			s.log.Error("Failed to generate signature", "error", err, "requestID", createdRequest.ID)
 		}
 	}()
@@ -66,32 +120,67 @@ func (s *Service) GetSignatureStatus(ctx context.Context, id string) (*models.Si
 		return nil, errors.Wrap(err, "failed to get signature request")
 	}
 
+	// Treat other organizations' requests as missing so their IDs cannot be probed
+	if !tenant.Owns(ctx, request.OrganizationID) {
+		return nil, errors.NotFound("signature request not found")
+	}
+
 	// If the request is found, return it
 	return request, nil
 }
 
-// ListSignatureRequests method to list signature requests with pagination
-func (s *Service) ListSignatureRequests(ctx context.Context, page, pageSize int) ([]*models.SignatureRequest, int, error) {
-	// Validate pagination parameters
-	if page < 1 || pageSize < 1 {
-		return nil, 0, errors.BadRequest("invalid pagination parameters")
+// ListSignatureRequests method to list a page of signature requests in the requested order, counting every
+// match if asked to
+func (s *Service) ListSignatureRequests(ctx context.Context, opts *pagination.Options) (*pagination.Page, error) {
+	// Validate the sort and filters against those signature request listings support
+	if err := listRules.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	// Refuse to list without an organization; the repository scopes the query to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
 	}
 
-	// Call the repository to list signature requests with pagination
-	requests, total, err := s.repo.ListSignatureRequests(ctx, page, pageSize)
+	// Call the repository for the page, which returns one extra request when another page follows
+	requests, err := s.repo.ListSignatureRequests(ctx, opts)
 	if err != nil {
 		s.log.Error("Failed to list signature requests", "error", err)
-		return nil, 0, errors.Wrap(err, "failed to list signature requests")
+		return nil, errors.Wrap(err, "failed to list signature requests")
 	}
-
-	// If successful, return the list of signature requests and total count
-	return requests, total, nil
+	page := &pagination.Page{}
+	if len(requests) > opts.Limit {
+		requests = requests[:opts.Limit]
+		last := requests[len(requests)-1]
+		page.NextCursor = opts.NextCursor(last.ID, sortValue(last, opts.Sort))
+	}
+	if requests == nil {
+		requests = []*models.SignatureRequest{}
+	}
+	page.Data = requests
+
+	// Count every matching request only when asked, as it is the expensive part
+	if opts.IncludeTotal {
+		total, err := s.repo.CountSignatureRequests(ctx, &opts.Filters)
+		if err != nil {
+			s.log.Error("Failed to count signature requests", "error", err)
+			return nil, errors.Wrap(err, "failed to count signature requests")
+		}
+		page.Total = &total
+	}
+	return page, nil
 }
 
 // generateSignature internal method to generate a signature for a request
 func (s *Service) generateSignature(ctx context.Context, request *models.SignatureRequest) error {
+	// Pick the signer backend requested, falling back to the default signer
+	signer := s.signer
+	if request.SignerBackend != "" {
+		signer = s.backends[request.SignerBackend]
+	}
+
 	// Use the crypto.Signer to generate a signature for the request data
-	signature, err := s.signer.Sign(request.Data)
+	signature, err := signer.Sign(request.Data)
 	if err != nil {
 		s.log.Error("Failed to generate signature", "error", err, "requestID", request.ID)
 		request.Status = models.SignatureStatusFailed
@@ -108,6 +197,18 @@ func (s *Service) generateSignature(ctx context.Context, request *models.Signatu
 		return errors.Wrap(err, "failed to update signature request")
 	}
 
+	// Notify subscribers of the outcome; a failed notification does not undo the signature
+	eventType := models.EventSignatureCompleted
+	if request.Status == models.SignatureStatusFailed {
+		eventType = models.EventSignatureFailed
+	}
+	event := models.NewEvent(request.OrganizationID, eventType, request.ID.String(), request)
+	for _, events := range s.events {
+		if err := events.Publish(ctx, event); err != nil {
+			s.log.Error("Failed to publish signature event", "error", err, "requestID", request.ID)
+		}
+	}
+
 	return nil
 }
 
@@ -123,6 +224,14 @@ func validateSignatureRequest(request *models.SignatureRequest) error {
 	return nil
 }
 
+// sortValue returns a signature request's value of a sort field, as stored in cursors
+func sortValue(request *models.SignatureRequest, field string) string {
+	if field == pagination.SortUpdatedAt {
+		return pagination.TimeValue(request.UpdatedAt)
+	}
+	return pagination.TimeValue(request.CreatedAt)
+}
+
 // Human tasks:
 // TODO: Implement comprehensive input validation for all methods
 // TODO: Add unit tests for each method in the service
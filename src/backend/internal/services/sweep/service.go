@@ -0,0 +1,407 @@
+package sweep
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+const (
+	// defaultInterval is how often a blockchain is swept when no schedule is configured
+	defaultInterval = time.Hour
+
+	// defaultMaxFeePercent is the largest share of a swept balance that may be spent on fees when none is configured
+	defaultMaxFeePercent = 10
+)
+
+// EVMChain reads balances and fee estimates from an EVM network
+type EVMChain interface {
+	GetBalance(ctx context.Context, address string) (*big.Int, error)
+	EstimateFee(ctx context.Context, from, to string, value *big.Int, data []byte) (*big.Int, error)
+}
+
+// Custodian lists and spends UTXOs held by the UTXO custodian
+type Custodian interface {
+	GetUTXOs(ctx context.Context, address string) ([]utxo.UTXO, error)
+	CreateTransaction(ctx context.Context, req *utxo.TransactionRequest) (*utxo.Transaction, error)
+}
+
+// TransactionCreator creates transactions through the normal approval and signing pipeline
+type TransactionCreator interface {
+	CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error)
+}
+
+// EventPublisher pushes events about an organization's resources to its subscribers
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
+
+// policy is a sweep configuration with its amounts parsed
+type policy struct {
+	interval      time.Duration
+	minAmount     *big.Int
+	maxFeePercent int64
+	feeRate       int64
+	maxInputs     int
+}
+
+// Service struct implements the SweepService interface
+type Service struct {
+	depositRepo  repository.DepositAddressRepository
+	vaultRepo    repository.VaultRepository
+	txRepo       repository.TransactionRepository
+	transactions TransactionCreator
+	evmChains    map[string]EVMChain
+	custodians   map[string]Custodian
+	policies     map[string]policy
+	events       []EventPublisher
+	log          *logger.Logger
+}
+
+// NewService creates a new SweepService instance with an EVM chain reader or UTXO custodian per blockchain type
+func NewService(depositRepo repository.DepositAddressRepository, vaultRepo repository.VaultRepository, txRepo repository.TransactionRepository, transactions TransactionCreator, evmChains map[string]EVMChain, custodians map[string]Custodian, sweeps []config.SweepConfig, log *logger.Logger) (*Service, error) {
+	policies := make(map[string]policy, len(sweeps))
+	for _, cfg := range sweeps {
+		blockchainType := strings.ToLower(cfg.BlockchainType)
+		_, isEVM := evmChains[blockchainType]
+		_, isUTXO := custodians[blockchainType]
+		if !isEVM && !isUTXO {
+			return nil, errors.NewBadRequestError("sweeping is not supported on " + cfg.BlockchainType)
+		}
+
+		p := policy{
+			interval:      cfg.Interval,
+			minAmount:     new(big.Int),
+			maxFeePercent: int64(cfg.MaxFeePercent),
+			feeRate:       cfg.FeeRate,
+			maxInputs:     cfg.MaxInputs,
+		}
+		if p.interval <= 0 {
+			p.interval = defaultInterval
+		}
+		if p.maxFeePercent <= 0 {
+			p.maxFeePercent = defaultMaxFeePercent
+		}
+		if cfg.MinAmount != "" {
+			if _, ok := p.minAmount.SetString(cfg.MinAmount, 10); !ok || p.minAmount.Sign() < 0 {
+				return nil, errors.NewBadRequestError("sweep MinAmount for " + cfg.BlockchainType + " must be a non-negative integer")
+			}
+		}
+		if isUTXO && p.feeRate <= 0 {
+			return nil, errors.NewBadRequestError("sweep FeeRate for " + cfg.BlockchainType + " must be positive")
+		}
+		policies[blockchainType] = p
+	}
+
+	return &Service{
+		depositRepo:  depositRepo,
+		vaultRepo:    vaultRepo,
+		txRepo:       txRepo,
+		transactions: transactions,
+		evmChains:    evmChains,
+		custodians:   custodians,
+		policies:     policies,
+		log:          log,
+	}, nil
+}
+
+// RegisterEventPublisher registers a publisher notified of deposits found while sweeping
+func (s *Service) RegisterEventPublisher(events EventPublisher) {
+	s.events = append(s.events, events)
+}
+
+// SweepVault consolidates the balances of a vault's deposit addresses into its main address
+func (s *Service) SweepVault(ctx context.Context, vaultID string) (*models.SweepResult, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, vaultID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", vaultID)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	if _, ok := s.policies[strings.ToLower(vault.BlockchainType)]; !ok {
+		return nil, errors.NewBadRequestError("sweeping is not configured on " + vault.BlockchainType)
+	}
+	return s.sweepVault(ctx, vault)
+}
+
+// SweepChain sweeps every vault on a blockchain, continuing past vaults that fail
+func (s *Service) SweepChain(ctx context.Context, blockchainType string) error {
+	vaults, err := s.vaultRepo.ListVaultsByBlockchainType(ctx, blockchainType)
+	if err != nil {
+		s.log.Error("Failed to list vaults", "error", err, "blockchainType", blockchainType)
+		return errors.Wrap(err, "failed to list vaults")
+	}
+
+	for _, vault := range vaults {
+		result, err := s.sweepVault(ctx, vault)
+		if err != nil {
+			s.log.Error("Failed to sweep vault", "error", err, "vaultID", vault.ID)
+			continue
+		}
+		if len(result.Transactions) > 0 {
+			s.log.Info("Swept vault deposit addresses", "vaultID", vault.ID, "transactions", len(result.Transactions), "skipped", len(result.Skipped))
+		}
+	}
+	return nil
+}
+
+// Run sweeps each configured blockchain on its own schedule until the context is cancelled
+func (s *Service) Run(ctx context.Context) {
+	// Background jobs work across every organization
+	ctx = tenant.WithSystem(ctx)
+	var wg sync.WaitGroup
+	for blockchainType, p := range s.policies {
+		wg.Add(1)
+		go func(blockchainType string, interval time.Duration) {
+			defer wg.Done()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := s.SweepChain(ctx, blockchainType); err != nil {
+						s.log.Error("Failed to sweep deposit addresses", "error", err, "blockchainType", blockchainType)
+					}
+				}
+			}
+		}(blockchainType, p.interval)
+	}
+	wg.Wait()
+}
+
+// sweepVault dispatches a vault's sweep to the account or UTXO strategy for its blockchain
+func (s *Service) sweepVault(ctx context.Context, vault *models.Vault) (*models.SweepResult, error) {
+	blockchainType := strings.ToLower(vault.BlockchainType)
+	p := s.policies[blockchainType]
+
+	deposits, err := s.depositRepo.ListDepositAddresses(ctx, vault.ID.String())
+	if err != nil {
+		s.log.Error("Failed to list deposit addresses", "error", err, "vaultID", vault.ID)
+		return nil, errors.Wrap(err, "failed to list deposit addresses")
+	}
+
+	// Leave addresses with a sweep still in flight alone so their funds are not double-spent
+	pending, err := s.txRepo.ListPendingSweeps(ctx, vault.ID.String())
+	if err != nil {
+		s.log.Error("Failed to list pending sweeps", "error", err, "vaultID", vault.ID)
+		return nil, errors.Wrap(err, "failed to list pending sweeps")
+	}
+
+	result := &models.SweepResult{VaultID: vault.ID, BlockchainType: vault.BlockchainType}
+	if chain, ok := s.evmChains[blockchainType]; ok {
+		s.sweepAccounts(ctx, chain, vault, deposits, pending, p, result)
+		return result, nil
+	}
+	if len(pending) > 0 {
+		result.Skipped = append(result.Skipped, models.SkippedSweep{Reason: "a sweep is already pending"})
+		return result, nil
+	}
+	if err := s.sweepUTXOs(ctx, s.custodians[blockchainType], vault, deposits, p, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sweepAccounts sends the balance of each deposit address, less its fee, to the vault in its own transaction
+func (s *Service) sweepAccounts(ctx context.Context, chain EVMChain, vault *models.Vault, deposits []*models.DepositAddress, pending []*models.Transaction, p policy, result *models.SweepResult) {
+	inFlight := make(map[string]bool, len(pending))
+	for _, transaction := range pending {
+		inFlight[strings.ToLower(transaction.FromAddress)] = true
+	}
+
+	for _, deposit := range deposits {
+		address := deposit.Address
+		if strings.EqualFold(address, vault.Address) {
+			continue
+		}
+		if inFlight[strings.ToLower(address)] {
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: address, Reason: "a sweep is already pending"})
+			continue
+		}
+
+		balance, err := chain.GetBalance(ctx, address)
+		if err != nil {
+			s.log.Error("Failed to get deposit address balance", "error", err, "address", address)
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: address, Reason: "balance unavailable"})
+			continue
+		}
+		if balance.Sign() == 0 || balance.Cmp(p.minAmount) < 0 {
+			continue
+		}
+
+		// The value of a plain transfer does not change its gas, so estimate without it
+		fee, err := chain.EstimateFee(ctx, address, vault.Address, new(big.Int), nil)
+		if err != nil {
+			s.log.Error("Failed to estimate sweep fee", "error", err, "address", address)
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: address, Reason: "fee estimate unavailable"})
+			continue
+		}
+		if reason := checkFee(fee, balance, p.maxFeePercent); reason != "" {
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: address, Reason: reason})
+			continue
+		}
+
+		transaction, err := s.transactions.CreateTransaction(ctx, &models.Transaction{
+			VaultID:        vault.ID,
+			BlockchainType: vault.BlockchainType,
+			FromAddress:    address,
+			ToAddress:      vault.Address,
+			Amount:         new(big.Int).Sub(balance, fee).String(),
+			Fee:            fee.String(),
+			Purpose:        models.TransactionPurposeSweep,
+		})
+		if err != nil {
+			s.log.Error("Failed to create sweep transaction", "error", err, "address", address)
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: address, Reason: "failed to create sweep transaction"})
+			continue
+		}
+		result.Transactions = append(result.Transactions, transaction)
+		s.publishDeposit(ctx, vault, address, balance.String(), transaction)
+	}
+}
+
+// sweepUTXOs consolidates the UTXOs of every deposit address into the vault in a single custodian transaction
+func (s *Service) sweepUTXOs(ctx context.Context, custodian Custodian, vault *models.Vault, deposits []*models.DepositAddress, p policy, result *models.SweepResult) error {
+	// Gather the UTXOs of every deposit address, remembering which address each belongs to
+	var utxos []utxo.UTXO
+	owners := make(map[string]string)
+	for _, deposit := range deposits {
+		if deposit.Address == vault.Address {
+			continue
+		}
+		found, err := custodian.GetUTXOs(ctx, deposit.Address)
+		if err != nil {
+			s.log.Error("Failed to get UTXOs", "error", err, "address", deposit.Address)
+			result.Skipped = append(result.Skipped, models.SkippedSweep{Address: deposit.Address, Reason: "UTXOs unavailable"})
+			continue
+		}
+		for _, u := range found {
+			owners[outpoint(u)] = deposit.Address
+		}
+		utxos = append(utxos, found...)
+	}
+	if len(utxos) == 0 {
+		return nil
+	}
+
+	// Plan a single changeless consolidation into the vault address
+	selection, err := utxo.PlanSweep(utxos, vault.Address, utxo.SweepParams{
+		FeeRate:   p.feeRate,
+		MaxInputs: p.maxInputs,
+	})
+	if err == utxo.ErrNothingToSweep {
+		result.Skipped = append(result.Skipped, models.SkippedSweep{Reason: err.Error()})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	amount := selection.Outputs[0].Amount
+	gross := big.NewInt(amount + selection.Fee)
+	if gross.Cmp(p.minAmount) < 0 {
+		return nil
+	}
+	if reason := checkFee(big.NewInt(selection.Fee), gross, p.maxFeePercent); reason != "" {
+		result.Skipped = append(result.Skipped, models.SkippedSweep{Reason: reason})
+		return nil
+	}
+
+	// Have the custodian build and broadcast the consolidation
+	req := &utxo.TransactionRequest{Inputs: selection.Inputs}
+	for _, output := range selection.Outputs {
+		req.Outputs = append(req.Outputs, output)
+	}
+	created, err := custodian.CreateTransaction(ctx, req)
+	if err != nil {
+		s.log.Error("Failed to create sweep transaction", "error", err, "vaultID", vault.ID)
+		return errors.Wrap(err, "failed to create sweep transaction")
+	}
+
+	// Record the consolidation as an internal transaction
+	transaction, err := s.txRepo.CreateTransaction(ctx, &models.Transaction{
+		OrganizationID: vault.OrganizationID,
+		VaultID:        vault.ID,
+		BlockchainType: vault.BlockchainType,
+		ToAddress:      vault.Address,
+		Amount:         strconv.FormatInt(amount, 10),
+		Fee:            strconv.FormatInt(selection.Fee, 10),
+		Status:         "Submitted",
+		TxHash:         created.TxID,
+		Purpose:        models.TransactionPurposeSweep,
+	})
+	if err != nil {
+		s.log.Error("Failed to record sweep transaction", "error", err, "vaultID", vault.ID, "txID", created.TxID)
+		return errors.Wrap(err, "failed to record sweep transaction")
+	}
+	result.Transactions = append(result.Transactions, transaction)
+
+	// Report the swept funds as one deposit per address, in the order the addresses are listed
+	deposited := make(map[string]int64)
+	for _, input := range selection.Inputs {
+		deposited[owners[outpoint(input)]] += input.Amount
+	}
+	for _, deposit := range deposits {
+		if amount, ok := deposited[deposit.Address]; ok {
+			s.publishDeposit(ctx, vault, deposit.Address, strconv.FormatInt(amount, 10), transaction)
+		}
+	}
+	return nil
+}
+
+// publishDeposit notifies subscribers of funds found on a deposit address. Delivery is best effort, so a
+// failure is logged rather than undoing the sweep
+func (s *Service) publishDeposit(ctx context.Context, vault *models.Vault, address, amount string, transaction *models.Transaction) {
+	event := models.NewEvent(vault.OrganizationID, models.EventDepositReceived, address, &models.DepositEvent{
+		VaultID:        vault.ID,
+		BlockchainType: vault.BlockchainType,
+		Address:        address,
+		Amount:         amount,
+		TransactionID:  transaction.ID,
+	})
+	for _, events := range s.events {
+		if err := events.Publish(ctx, event); err != nil {
+			s.log.Error("Failed to publish deposit", "error", err, "address", address, "vaultID", vault.ID)
+		}
+	}
+}
+
+// outpoint identifies a UTXO by its transaction and output index
+func outpoint(u utxo.UTXO) string {
+	return u.TxID + ":" + strconv.Itoa(u.Vout)
+}
+
+// checkFee returns why a sweep is uneconomic, or an empty string when its fee is within the allowed share of the balance
+func checkFee(fee, balance *big.Int, maxFeePercent int64) string {
+	if fee.Cmp(balance) >= 0 {
+		return "fee exceeds balance"
+	}
+	limit := new(big.Int).Mul(balance, big.NewInt(maxFeePercent))
+	if new(big.Int).Mul(fee, big.NewInt(100)).Cmp(limit) > 0 {
+		return "fee exceeds " + strconv.FormatInt(maxFeePercent, 10) + "% of balance"
+	}
+	return ""
+}
+
+// Human tasks:
+// TODO: Sweep ERC-20 balances, funding deposit addresses through the gas station
+// TODO: Re-check the gas price at submission so a price rise cannot leave a sweep unfunded
+// TODO: Add unit tests for each method in the service
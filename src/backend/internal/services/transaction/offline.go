@@ -0,0 +1,78 @@
+package transaction
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// PSBTBuilder builds unsigned PSBTs from the UTXOs of vault addresses
+type PSBTBuilder interface {
+	BuildPSBT(ctx context.Context, req *utxo.PSBTRequest, change utxo.ChangeAddressProvider) (*utxo.UnsignedTransaction, error)
+}
+
+// RawBroadcaster broadcasts fully signed raw UTXO transactions
+type RawBroadcaster interface {
+	BroadcastTransaction(ctx context.Context, rawTx string) (string, error)
+}
+
+// utxoOfflineSigner adapts local PSBT construction and custodian broadcast to the OfflineSigner interface
+type utxoOfflineSigner struct {
+	builder     PSBTBuilder
+	broadcaster RawBroadcaster
+	feeRate     int64
+}
+
+// NewUTXOOfflineSigner creates an OfflineSigner that exports PSBTs at a fixed fee rate in satoshis per virtual byte
+func NewUTXOOfflineSigner(builder PSBTBuilder, broadcaster RawBroadcaster, feeRate int64) OfflineSigner {
+	return &utxoOfflineSigner{builder: builder, broadcaster: broadcaster, feeRate: feeRate}
+}
+
+// ExportUnsigned builds a PSBT spending the vault's UTXOs, returning change to the vault address
+func (u *utxoOfflineSigner) ExportUnsigned(ctx context.Context, transaction *models.Transaction) (*offline.Payload, error) {
+	// UTXO amounts are whole satoshis
+	amount, err := strconv.ParseInt(transaction.Amount, 10, 64)
+	if err != nil || amount <= 0 {
+		return nil, errors.NewInvalidAmountError("invalid amount: must be a positive integer amount in satoshis")
+	}
+
+	unsigned, err := u.builder.BuildPSBT(ctx, &utxo.PSBTRequest{
+		FromAddresses: []string{transaction.FromAddress},
+		Outputs:       []utxo.Output{{Address: transaction.ToAddress, Amount: amount}},
+		FeeRate:       u.feeRate,
+	}, vaultChange(transaction.FromAddress))
+	if err != nil {
+		return nil, err
+	}
+	transaction.Fee = strconv.FormatInt(unsigned.Selection.Fee, 10)
+
+	return utxo.ExportPSBT(unsigned, transaction.ID.String(), transaction.BlockchainType)
+}
+
+// BroadcastSigned finalizes a signed PSBT that matches the exported digest and broadcasts it through the custodian
+func (u *utxoOfflineSigner) BroadcastSigned(ctx context.Context, transaction *models.Transaction, payload *offline.Payload) (string, error) {
+	if payload.Format != offline.FormatPSBT {
+		return "", errors.NewBadRequestError("payload is not a PSBT")
+	}
+	rawTx, err := utxo.FinalizePSBT(payload.Data, transaction.OfflineDigest)
+	if err != nil {
+		return "", errors.NewBadRequestError(err.Error())
+	}
+	return u.broadcaster.BroadcastTransaction(ctx, rawTx)
+}
+
+// vaultChange returns change to the vault's own address, as cold vaults cannot derive fresh addresses online
+type vaultChange string
+
+// NewChangeAddress returns the vault address
+func (v vaultChange) NewChangeAddress(ctx context.Context) (string, error) {
+	return string(v), nil
+}
+
+// Human tasks:
+// - Derive change addresses from the cold vault's xpub instead of reusing its address
+// - Take the fee rate from a custodian estimate at export time
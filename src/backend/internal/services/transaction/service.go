@@ -2,46 +2,163 @@ package transaction
 
 import (
 	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
 	"github.com/your-repo/blockchain-integration-service/pkg/blockchain"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
+// listRules are the sort fields and filters transaction listings accept
+var listRules = pagination.Rules{
+	SortFields: []string{pagination.SortCreatedAt, pagination.SortUpdatedAt, pagination.SortAmount},
+	Filters: []string{
+		pagination.FilterStatus, pagination.FilterBlockchainType, pagination.FilterVaultID,
+		pagination.FilterCreated, pagination.FilterAmount,
+	},
+}
+
+// Simulator previews a transaction on one blockchain without persisting or submitting it
+type Simulator interface {
+	Simulate(ctx context.Context, transaction *models.Transaction) (*models.SimulationResult, error)
+}
+
+// Validator checks a transaction against the ledger before it is accepted, e.g. the destination's requirements
+type Validator interface {
+	ValidateTransaction(ctx context.Context, transaction *models.Transaction) error
+}
+
+// GasStation funds a vault's gas before its transaction is submitted, returning the top-up to wait for if any
+type GasStation interface {
+	EnsureGas(ctx context.Context, transaction *models.Transaction) (*uuid.UUID, error)
+}
+
+// OfflineSigner exports unsigned transactions for an air-gapped signer and broadcasts the signed results
+type OfflineSigner interface {
+	ExportUnsigned(ctx context.Context, transaction *models.Transaction) (*offline.Payload, error)
+	BroadcastSigned(ctx context.Context, transaction *models.Transaction, payload *offline.Payload) (string, error)
+}
+
+// EventPublisher pushes events about an organization's resources to its subscribers
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
+
 // Service struct implements the TransactionService interface
 type Service struct {
 	repo             repository.TransactionRepository
+	vaultRepo        repository.VaultRepository
 	blockchainClient blockchain.Client
+	simulators       map[string]Simulator
+	validators       map[string]Validator
+	gasStation       GasStation
+	offlineSigners   map[string]OfflineSigner
+	events           []EventPublisher
 	log              *logger.Logger
 }
 
 // NewService creates a new TransactionService instance
-func NewService(repo repository.TransactionRepository, blockchainClient blockchain.Client, log *logger.Logger) *Service {
+func NewService(repo repository.TransactionRepository, vaultRepo repository.VaultRepository, blockchainClient blockchain.Client, log *logger.Logger) *Service {
 	return &Service{
 		repo:             repo,
+		vaultRepo:        vaultRepo,
 		blockchainClient: blockchainClient,
+		simulators:       make(map[string]Simulator),
+		validators:       make(map[string]Validator),
+		offlineSigners:   make(map[string]OfflineSigner),
 		log:              log,
 	}
 }
 
+// RegisterSimulator registers the simulator used to preview transactions on a blockchain type
+func (s *Service) RegisterSimulator(blockchainType string, simulator Simulator) {
+	s.simulators[strings.ToLower(blockchainType)] = simulator
+}
+
+// RegisterValidator registers the ledger checks run before a transaction on a blockchain type is accepted
+func (s *Service) RegisterValidator(blockchainType string, validator Validator) {
+	s.validators[strings.ToLower(blockchainType)] = validator
+}
+
+// RegisterGasStation registers the gas station that tops up vaults before their transactions are submitted
+func (s *Service) RegisterGasStation(gasStation GasStation) {
+	s.gasStation = gasStation
+}
+
+// RegisterOfflineSigner registers the exporter used for cold-storage vaults on a blockchain type
+func (s *Service) RegisterOfflineSigner(blockchainType string, signer OfflineSigner) {
+	s.offlineSigners[strings.ToLower(blockchainType)] = signer
+}
+
+// RegisterEventPublisher registers a publisher notified whenever a transaction's status changes
+func (s *Service) RegisterEventPublisher(events EventPublisher) {
+	s.events = append(s.events, events)
+}
+
 // CreateTransaction creates a new transaction
 func (s *Service) CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error) {
-	// TODO: Implement comprehensive input validation
+	// Validate the destination and chain-specific fields
+	if err := validateTransaction(transaction); err != nil {
+		return nil, err
+	}
 
 	// Set initial status to 'Pending'
 	transaction.Status = "Pending"
 
+	// The vault must belong to the caller's organization and be on the transaction's chain
+	vault, err := s.getVault(ctx, transaction.VaultID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(vault.BlockchainType, transaction.BlockchainType) {
+		return nil, errors.NewBadRequestError("vault is not on " + transaction.BlockchainType)
+	}
+	transaction.OrganizationID = vault.OrganizationID
+
+	// Transactions are sent from the vault's address, except sweeps, which spend one of its deposit addresses
+	if transaction.Purpose != models.TransactionPurposeSweep {
+		transaction.FromAddress = vault.Address
+	}
+
+	// Cold-storage vaults are signed offline, so their transactions wait for a signed payload
+	if vault.ColdStorage {
+		if _, ok := s.offlineSigners[strings.ToLower(transaction.BlockchainType)]; !ok {
+			return nil, errors.NewBadRequestError("offline signing is not supported on " + transaction.BlockchainType)
+		}
+		transaction.Status = models.TransactionStatusAwaitingOfflineSignature
+	}
+
+	// Refuse transactions the ledger would reject, such as payments missing a required destination tag
+	if validator, ok := s.validators[strings.ToLower(transaction.BlockchainType)]; ok {
+		if err := validator.ValidateTransaction(ctx, transaction); err != nil {
+			s.log.Error("Transaction failed ledger validation", "error", err, "vaultID", transaction.VaultID)
+			return nil, err
+		}
+	}
+
 	// Create transaction in the database
 	createdTransaction, err := s.repo.CreateTransaction(ctx, transaction)
 	if err != nil {
 		s.log.Error("Failed to create transaction", "error", err)
 		return nil, errors.Wrap(err, "failed to create transaction")
 	}
+	if vault.ColdStorage {
+		return createdTransaction, nil
+	}
 
-	// Initiate asynchronous transaction submission
+	// Initiate asynchronous transaction submission, still scoped to the transaction's organization
 	go func() {
-		if err := s.submitTransaction(context.Background(), createdTransaction); err != nil {
+		submitCtx := tenant.WithOrganization(context.Background(), createdTransaction.OrganizationID)
+		if err := s.dispatchTransaction(submitCtx, createdTransaction); err != nil {
 			s.log.Error("Failed to submit transaction", "error", err, "transactionID", createdTransaction.ID)
 		}
 	}()
@@ -49,6 +166,36 @@ func (s *Service) CreateTransaction(ctx context.Context, transaction *models.Tra
 	return createdTransaction, nil
 }
 
+// SimulateTransaction previews a transaction from its vault without persisting or submitting it
+func (s *Service) SimulateTransaction(ctx context.Context, transaction *models.Transaction) (*models.SimulationResult, error) {
+	// Validate the destination and chain-specific fields
+	if err := validateTransaction(transaction); err != nil {
+		return nil, err
+	}
+	simulator, ok := s.simulators[strings.ToLower(transaction.BlockchainType)]
+	if !ok {
+		return nil, errors.NewBadRequestError("simulation is not supported on " + transaction.BlockchainType)
+	}
+
+	// Simulate from the vault address, which must be on the same chain
+	vault, err := s.getVault(ctx, transaction.VaultID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(vault.BlockchainType, transaction.BlockchainType) {
+		return nil, errors.NewBadRequestError("vault is not on " + transaction.BlockchainType)
+	}
+	transaction.FromAddress = vault.Address
+
+	// Run the simulation; nothing is written to the database or the network
+	result, err := simulator.Simulate(ctx, transaction)
+	if err != nil {
+		s.log.Error("Failed to simulate transaction", "error", err, "vaultID", transaction.VaultID)
+		return nil, errors.Wrap(err, "failed to simulate transaction")
+	}
+	return result, nil
+}
+
 // GetTransaction retrieves a transaction by its ID
 func (s *Service) GetTransaction(ctx context.Context, id string) (*models.Transaction, error) {
 	transaction, err := s.repo.GetTransactionByID(ctx, id)
@@ -59,25 +206,62 @@ func (s *Service) GetTransaction(ctx context.Context, id string) (*models.Transa
 		s.log.Error("Failed to get transaction", "error", err, "transactionID", id)
 		return nil, errors.Wrap(err, "failed to get transaction")
 	}
+
+	// Treat other organizations' transactions as missing so their IDs cannot be probed
+	if !tenant.Owns(ctx, transaction.OrganizationID) {
+		return nil, errors.NewNotFoundError("transaction not found")
+	}
 	return transaction, nil
 }
 
-// ListTransactions lists transactions with pagination
-func (s *Service) ListTransactions(ctx context.Context, page, pageSize int) ([]*models.Transaction, int, error) {
-	// TODO: Validate pagination parameters
+// ListTransactions lists a page of transactions in the requested order, counting every match if asked to
+func (s *Service) ListTransactions(ctx context.Context, opts *pagination.Options) (*pagination.Page, error) {
+	if err := listRules.Validate(opts); err != nil {
+		return nil, err
+	}
+
+	// Refuse to list without an organization; the repository scopes the query to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
 
-	transactions, total, err := s.repo.ListTransactions(ctx, page, pageSize)
+	// The repository returns one extra transaction when another page follows
+	transactions, err := s.repo.ListTransactions(ctx, opts)
 	if err != nil {
 		s.log.Error("Failed to list transactions", "error", err)
-		return nil, 0, errors.Wrap(err, "failed to list transactions")
+		return nil, errors.Wrap(err, "failed to list transactions")
+	}
+	page := &pagination.Page{}
+	if len(transactions) > opts.Limit {
+		transactions = transactions[:opts.Limit]
+		last := transactions[len(transactions)-1]
+		page.NextCursor = opts.NextCursor(last.ID, sortValue(last, opts.Sort))
 	}
-	return transactions, total, nil
+	if transactions == nil {
+		transactions = []*models.Transaction{}
+	}
+	page.Data = transactions
+
+	if opts.IncludeTotal {
+		total, err := s.repo.CountTransactions(ctx, &opts.Filters)
+		if err != nil {
+			s.log.Error("Failed to count transactions", "error", err)
+			return nil, errors.Wrap(err, "failed to count transactions")
+		}
+		page.Total = &total
+	}
+	return page, nil
 }
 
 // UpdateTransactionStatus updates the status of a transaction
 func (s *Service) UpdateTransactionStatus(ctx context.Context, id, status string) (*models.Transaction, error) {
 	// TODO: Validate the new status
 
+	// Make sure the transaction belongs to the caller's organization
+	if _, err := s.GetTransaction(ctx, id); err != nil {
+		return nil, err
+	}
+
 	transaction, err := s.repo.UpdateTransactionStatus(ctx, id, status)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
@@ -86,9 +270,139 @@ func (s *Service) UpdateTransactionStatus(ctx context.Context, id, status string
 		s.log.Error("Failed to update transaction status", "error", err, "transactionID", id)
 		return nil, errors.Wrap(err, "failed to update transaction status")
 	}
+	s.publishStatus(ctx, transaction)
 	return transaction, nil
 }
 
+// ExportTransaction builds the unsigned payload of a cold-storage transaction for an offline signer.
+// Exporting again replaces the previous payload, whose signed form is then rejected on import
+func (s *Service) ExportTransaction(ctx context.Context, id string) (*offline.Payload, error) {
+	transaction, err := s.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != models.TransactionStatusAwaitingOfflineSignature {
+		return nil, errors.NewBadRequestError("transaction is not awaiting an offline signature")
+	}
+	signer, ok := s.offlineSigners[strings.ToLower(transaction.BlockchainType)]
+	if !ok {
+		return nil, errors.NewBadRequestError("offline signing is not supported on " + transaction.BlockchainType)
+	}
+
+	payload, err := signer.ExportUnsigned(ctx, transaction)
+	if err != nil {
+		s.log.Error("Failed to export transaction", "error", err, "transactionID", id)
+		return nil, errors.Wrap(err, "failed to export transaction")
+	}
+
+	// Remember the digest so only a signature over this exact payload is accepted
+	transaction.OfflineDigest = payload.Digest
+	if _, err := s.repo.UpdateTransaction(ctx, transaction); err != nil {
+		s.log.Error("Failed to record exported transaction", "error", err, "transactionID", id)
+		return nil, errors.Wrap(err, "failed to record exported transaction")
+	}
+	return payload, nil
+}
+
+// BroadcastTransaction imports the signed payload of a cold-storage transaction and broadcasts it
+func (s *Service) BroadcastTransaction(ctx context.Context, id string, payload *offline.Payload) (*models.Transaction, error) {
+	transaction, err := s.GetTransaction(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if transaction.Status != models.TransactionStatusAwaitingOfflineSignature || transaction.OfflineDigest == "" {
+		return nil, errors.NewBadRequestError("transaction has no exported payload awaiting a signature")
+	}
+
+	// The payload must be intact, signed, and bound to this transaction and its latest export
+	if err := payload.Verify(); err != nil {
+		return nil, errors.NewBadRequestError(err.Error())
+	}
+	if payload.TransactionID != transaction.ID.String() || payload.Digest != transaction.OfflineDigest {
+		return nil, errors.NewBadRequestError("payload was not exported for this transaction")
+	}
+	if !payload.Signed {
+		return nil, errors.NewBadRequestError("payload is not signed")
+	}
+	signer, ok := s.offlineSigners[strings.ToLower(transaction.BlockchainType)]
+	if !ok {
+		return nil, errors.NewBadRequestError("offline signing is not supported on " + transaction.BlockchainType)
+	}
+
+	txHash, err := signer.BroadcastSigned(ctx, transaction, payload)
+	if err != nil {
+		s.log.Error("Failed to broadcast offline-signed transaction", "error", err, "transactionID", id)
+		return nil, errors.Wrap(err, "failed to broadcast offline-signed transaction")
+	}
+
+	transaction.TxHash = txHash
+	transaction.Status = "Submitted"
+	updated, err := s.repo.UpdateTransaction(ctx, transaction)
+	if err != nil {
+		s.log.Error("Failed to update transaction after broadcast", "error", err, "transactionID", id)
+		return nil, errors.Wrap(err, "failed to update transaction after broadcast")
+	}
+	s.publishStatus(ctx, updated)
+	return updated, nil
+}
+
+// ReleaseTransaction submits a transaction that was held back until its vault had enough gas
+func (s *Service) ReleaseTransaction(ctx context.Context, id string) error {
+	transaction, err := s.GetTransaction(ctx, id)
+	if err != nil {
+		return err
+	}
+	if transaction.Status != models.TransactionStatusAwaitingGas {
+		return errors.NewBadRequestError("transaction is not awaiting gas")
+	}
+	return s.submitTransaction(ctx, transaction)
+}
+
+// dispatchTransaction submits a transaction, or holds it back while the gas station tops up its vault
+func (s *Service) dispatchTransaction(ctx context.Context, transaction *models.Transaction) error {
+	if s.gasStation != nil {
+		dependsOn, err := s.gasStation.EnsureGas(ctx, transaction)
+		if err != nil {
+			transaction.Status = "Failed"
+			if _, updateErr := s.repo.UpdateTransaction(ctx, transaction); updateErr != nil {
+				s.log.Error("Failed to update transaction after gas check", "error", updateErr, "transactionID", transaction.ID)
+			} else {
+				s.publishStatus(ctx, transaction)
+			}
+			return err
+		}
+
+		// Wait for the top-up to confirm; the gas station releases the transaction afterwards
+		if dependsOn != nil {
+			transaction.Status = models.TransactionStatusAwaitingGas
+			transaction.DependsOnID = dependsOn
+			if _, err := s.repo.UpdateTransaction(ctx, transaction); err != nil {
+				s.log.Error("Failed to hold transaction for gas", "error", err, "transactionID", transaction.ID)
+				return errors.Wrap(err, "failed to hold transaction for gas")
+			}
+			s.publishStatus(ctx, transaction)
+			return nil
+		}
+	}
+	return s.submitTransaction(ctx, transaction)
+}
+
+// getVault loads a vault of the caller's organization
+func (s *Service) getVault(ctx context.Context, id string) (*models.Vault, error) {
+	vault, err := s.vaultRepo.GetVault(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errors.NewNotFoundError("vault not found")
+		}
+		s.log.Error("Failed to get vault", "error", err, "vaultID", id)
+		return nil, errors.Wrap(err, "failed to get vault")
+	}
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+	return vault, nil
+}
+
 // submitTransaction submits a transaction to the blockchain
 func (s *Service) submitTransaction(ctx context.Context, transaction *models.Transaction) error {
 	// Submit transaction to blockchain
@@ -97,7 +411,7 @@ func (s *Service) submitTransaction(ctx context.Context, transaction *models.Tra
 		transaction.Status = "Failed"
 		s.log.Error("Failed to submit transaction to blockchain", "error", err, "transactionID", transaction.ID)
 	} else {
-		transaction.BlockchainTxHash = txHash
+		transaction.TxHash = txHash
 		transaction.Status = "Submitted"
 	}
 
@@ -107,10 +421,65 @@ func (s *Service) submitTransaction(ctx context.Context, transaction *models.Tra
 		s.log.Error("Failed to update transaction after submission", "error", updateErr, "transactionID", transaction.ID)
 		return errors.Wrap(updateErr, "failed to update transaction after submission")
 	}
+	s.publishStatus(ctx, transaction)
 
 	return err
 }
 
+// publishStatus notifies subscribers of a transaction's new status. Delivery is best effort, so a failure
+// is logged rather than undoing the change
+func (s *Service) publishStatus(ctx context.Context, transaction *models.Transaction) {
+	event := models.NewEvent(transaction.OrganizationID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)
+	for _, events := range s.events {
+		if err := events.Publish(ctx, event); err != nil {
+			s.log.Error("Failed to publish transaction status", "error", err, "transactionID", transaction.ID)
+		}
+	}
+}
+
+// validateTransaction checks the destination address and that chain-specific fields are only used on chains that support them
+func validateTransaction(transaction *models.Transaction) error {
+	if _, err := utils.ValidateBlockchainType(transaction.BlockchainType); err != nil {
+		return err
+	}
+	if _, err := utils.ValidateAddress(transaction.BlockchainType, transaction.ToAddress); err != nil {
+		return err
+	}
+
+	// Calldata is only meaningful on EVM networks
+	if transaction.Data != "" {
+		if !evm.IsNetwork(transaction.BlockchainType) {
+			return errors.NewBadRequestError("calldata is only supported for EVM transactions")
+		}
+		if _, err := hexutil.Decode(transaction.Data); err != nil {
+			return errors.NewBadRequestError("calldata must be a 0x-prefixed hex string")
+		}
+	}
+
+	if strings.ToLower(transaction.BlockchainType) != "xrp" {
+		if transaction.DestinationTag != nil || transaction.SourceTag != nil || len(transaction.Memos) > 0 {
+			return errors.NewBadRequestError("destination tags, source tags and memos are only supported for XRP transactions")
+		}
+		return nil
+	}
+
+	if _, err := utils.ValidateXRPMemos(transaction.Memos); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sortValue returns a transaction's value of a sort field, as stored in cursors
+func sortValue(transaction *models.Transaction, field string) string {
+	switch field {
+	case pagination.SortUpdatedAt:
+		return pagination.TimeValue(transaction.UpdatedAt)
+	case pagination.SortAmount:
+		return transaction.Amount
+	}
+	return pagination.TimeValue(transaction.CreatedAt)
+}
+
 // TODO: Implement the following human tasks:
 // - Implement comprehensive input validation for all methods
 // - Add unit tests for each method in the service
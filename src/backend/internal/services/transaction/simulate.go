@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// FeePreviewer estimates UTXO fees on the custodian without creating a transaction
+type FeePreviewer interface {
+	PreviewFee(ctx context.Context, req *utxo.FeePreviewRequest) (*utxo.FeePreview, error)
+}
+
+// utxoSimulator adapts the custodian fee preview to the Simulator interface
+type utxoSimulator struct {
+	custodian FeePreviewer
+}
+
+// NewUTXOSimulator creates a Simulator backed by the custodian's fee preview
+func NewUTXOSimulator(custodian FeePreviewer) Simulator {
+	return &utxoSimulator{custodian: custodian}
+}
+
+// Simulate asks the custodian for a fee preview and reports whether the vault can cover the payment
+func (u *utxoSimulator) Simulate(ctx context.Context, transaction *models.Transaction) (*models.SimulationResult, error) {
+	// UTXO amounts are whole satoshis
+	amount, err := strconv.ParseInt(transaction.Amount, 10, 64)
+	if err != nil || amount <= 0 {
+		return nil, errors.NewInvalidAmountError("invalid amount: must be a positive integer amount in satoshis")
+	}
+
+	preview, err := u.custodian.PreviewFee(ctx, &utxo.FeePreviewRequest{
+		FromAddress: transaction.FromAddress,
+		ToAddress:   transaction.ToAddress,
+		Amount:      amount,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.SimulationResult{
+		BlockchainType: transaction.BlockchainType,
+		Success:        true,
+		Fee:            strconv.FormatInt(preview.Fee, 10),
+		BalanceDeltas: []models.BalanceDelta{
+			{Address: transaction.FromAddress, Asset: "BTC", Amount: strconv.FormatInt(-(amount + preview.Fee), 10)},
+			{Address: transaction.ToAddress, Asset: "BTC", Amount: strconv.FormatInt(amount, 10)},
+		},
+	}
+	if !preview.Sufficient {
+		result.Fail("insufficient confirmed UTXOs to cover the amount and fee")
+	}
+	return result, nil
+}
+
+// Human tasks:
+// - Let callers choose a fee rate target for the preview
+// - Report the selected inputs so operators can spot dust consolidation
@@ -4,11 +4,20 @@ import (
 	"context"
 	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
 	"github.com/your-repo/blockchain-integration-service/pkg/blockchain"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
 )
 
+// listRules are the sort fields and filters vault listings accept
+var listRules = pagination.Rules{
+	SortFields: []string{pagination.SortCreatedAt, pagination.SortUpdatedAt, pagination.SortName},
+	Filters:    []string{pagination.FilterStatus, pagination.FilterBlockchainType, pagination.FilterCreated},
+}
+
 // Service struct implements the VaultService interface
 type Service struct {
 	repo             repository.VaultRepository
@@ -32,6 +41,13 @@ func (s *Service) CreateVault(ctx context.Context, vault *models.Vault) (*models
 		return nil, errors.Wrap(err, "invalid vault input")
 	}
 
+	// Create the vault in the caller's organization, whatever the request body says
+	organizationID, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	vault.OrganizationID = organizationID
+
 	// Generate a new blockchain address for the vault
 	address, err := s.blockchainClient.GenerateAddress(ctx)
 	if err != nil {
@@ -39,6 +55,12 @@ func (s *Service) CreateVault(ctx context.Context, vault *models.Vault) (*models
 		return nil, errors.Wrap(err, "failed to generate blockchain address")
 	}
 
+	// Make sure the generated address is valid for the vault's chain and network
+	if _, err := utils.ValidateAddress(vault.BlockchainType, address); err != nil {
+		s.log.Error("Generated blockchain address is invalid", "error", err, "blockchainType", vault.BlockchainType)
+		return nil, errors.Wrap(err, "generated blockchain address is invalid")
+	}
+
 	// Set the generated address in the vault model
 	vault.Address = address
 
@@ -64,24 +86,52 @@ func (s *Service) GetVault(ctx context.Context, id string) (*models.Vault, error
 		return nil, errors.Wrap(err, "failed to get vault")
 	}
 
+	// Treat other organizations' vaults as missing so their IDs cannot be probed
+	if !tenant.Owns(ctx, vault.OrganizationID) {
+		return nil, errors.NewNotFoundError("vault not found")
+	}
+
 	return vault, nil
 }
 
-// ListVaults lists vaults with pagination
-func (s *Service) ListVaults(ctx context.Context, page, pageSize int) ([]*models.Vault, int, error) {
-	// Validate pagination parameters
-	if page < 1 || pageSize < 1 {
-		return nil, 0, errors.New("invalid pagination parameters")
+// ListVaults lists a page of vaults in the requested order, counting every match if asked to
+func (s *Service) ListVaults(ctx context.Context, opts *pagination.Options) (*pagination.Page, error) {
+	// Validate the sort and filters against those vault listings support
+	if err := listRules.Validate(opts); err != nil {
+		return nil, err
 	}
 
-	// Call the repository to list vaults with pagination
-	vaults, total, err := s.repo.ListVaults(ctx, page, pageSize)
+	// Refuse to list without an organization; the repository scopes the query to it
+	if _, err := tenant.Require(ctx); err != nil {
+		return nil, err
+	}
+
+	// Call the repository for the page, which returns one extra vault when another page follows
+	vaults, err := s.repo.ListVaults(ctx, opts)
 	if err != nil {
 		s.log.Error("Failed to list vaults", "error", err)
-		return nil, 0, errors.Wrap(err, "failed to list vaults")
+		return nil, errors.Wrap(err, "failed to list vaults")
+	}
+	page := &pagination.Page{}
+	if len(vaults) > opts.Limit {
+		vaults = vaults[:opts.Limit]
+		last := vaults[len(vaults)-1]
+		page.NextCursor = opts.NextCursor(last.ID, sortValue(last, opts.Sort))
 	}
+	if vaults == nil {
+		vaults = []*models.Vault{}
+	}
+	page.Data = vaults
 
-	return vaults, total, nil
+	if opts.IncludeTotal {
+		total, err := s.repo.CountVaults(ctx, &opts.Filters)
+		if err != nil {
+			s.log.Error("Failed to count vaults", "error", err)
+			return nil, errors.Wrap(err, "failed to count vaults")
+		}
+		page.Total = &total
+	}
+	return page, nil
 }
 
 // UpdateVault updates an existing vault
@@ -91,6 +141,13 @@ func (s *Service) UpdateVault(ctx context.Context, vault *models.Vault) (*models
 		return nil, errors.Wrap(err, "invalid vault input")
 	}
 
+	// Make sure the vault belongs to the caller's organization and cannot be moved out of it
+	existing, err := s.GetVault(ctx, vault.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	vault.OrganizationID = existing.OrganizationID
+
 	// Call the repository to update the vault in the database
 	updatedVault, err := s.repo.UpdateVault(ctx, vault)
 	if err != nil {
@@ -106,6 +163,11 @@ func (s *Service) UpdateVault(ctx context.Context, vault *models.Vault) (*models
 
 // DeleteVault deletes a vault
 func (s *Service) DeleteVault(ctx context.Context, id string) error {
+	// Make sure the vault belongs to the caller's organization
+	if _, err := s.GetVault(ctx, id); err != nil {
+		return err
+	}
+
 	// Call the repository to delete the vault by ID
 	err := s.repo.DeleteVault(ctx, id)
 	if err != nil {
@@ -145,10 +207,24 @@ func validateVault(vault *models.Vault) error {
 	if vault.Name == "" {
 		return errors.New("vault name cannot be empty")
 	}
+	if _, err := utils.ValidateBlockchainType(vault.BlockchainType); err != nil {
+		return err
+	}
 	// Add more validation rules as needed
 	return nil
 }
 
+// sortValue returns a vault's value of a sort field, as stored in cursors
+func sortValue(vault *models.Vault, field string) string {
+	switch field {
+	case pagination.SortUpdatedAt:
+		return pagination.TimeValue(vault.UpdatedAt)
+	case pagination.SortName:
+		return vault.Name
+	}
+	return pagination.TimeValue(vault.CreatedAt)
+}
+
 // Human tasks:
 // - Implement comprehensive input validation for all methods
 // - Add unit tests for each method in the service
@@ -159,4 +235,3 @@ func validateVault(vault *models.Vault) error {
 // - Implement audit logging for all vault operations
 // - Add support for vault metadata and custom attributes
 // - Implement a mechanism to sync vault balances periodically
-// - Add support for vault access control and permissions
\ No newline at end of file
@@ -0,0 +1,63 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// contextKey is the type of the keys this package stores in a context
+type contextKey int
+
+const (
+	organizationKey contextKey = iota
+	systemKey
+)
+
+// ErrNoTenant is returned when tenant data is accessed without an organization in the context. Callers
+// fail closed rather than fall back to unscoped access
+var ErrNoTenant = errors.NewForbiddenError("no organization in request context")
+
+// WithOrganization scopes a context to an organization
+func WithOrganization(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationKey, organizationID)
+}
+
+// WithSystem marks a context as belonging to a background job that works across every organization
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey, true)
+}
+
+// OrganizationID returns the organization a context is scoped to
+func OrganizationID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(organizationKey).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
+
+// IsSystem reports whether a context belongs to a cross-organization background job
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemKey).(bool)
+	return system
+}
+
+// Require returns the organization a context is scoped to, or ErrNoTenant
+func Require(ctx context.Context) (uuid.UUID, error) {
+	if id, ok := OrganizationID(ctx); ok {
+		return id, nil
+	}
+	return uuid.Nil, ErrNoTenant
+}
+
+// Owns reports whether a context may access a record of an organization: background jobs may access any,
+// requests only their own organization's, and unscoped contexts none
+func Owns(ctx context.Context, organizationID uuid.UUID) bool {
+	if IsSystem(ctx) {
+		return true
+	}
+	id, ok := OrganizationID(ctx)
+	return ok && id == organizationID
+}
+
+// Human tasks:
+// - Support organization hierarchies where a parent may access its children's data
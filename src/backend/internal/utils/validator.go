@@ -4,13 +4,27 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
 )
 
+// maxXRPMemoBytes is the maximum combined size of all memos on an XRP transaction
+const maxXRPMemoBytes = 1024
+
 var (
 	ethereumAddressRegex = regexp.MustCompile("^0x[a-fA-F0-9]{40}$")
 	xrpAddressRegex      = regexp.MustCompile("^r[1-9A-HJ-NP-Za-km-z]{25,34}$")
+
+	// bitcoinNetworks maps the supported Bitcoin blockchain types to their network
+	bitcoinNetworks = map[string]string{
+		"bitcoin":         "mainnet",
+		"bitcoin-testnet": "testnet",
+		"bitcoin-regtest": "regtest",
+	}
 )
 
 // ValidateEthereumAddress checks if the given address is a valid Ethereum address
@@ -51,14 +65,85 @@ func ValidateAmount(amount string) (bool, error) {
 	return true, nil
 }
 
+// ValidateBitcoinAddress checks if the given address is a valid Bitcoin address for the network.
+// Base58Check P2PKH/P2SH, bech32 P2WPKH/P2WSH and bech32m Taproot addresses are accepted.
+// Testnet and regtest share Base58Check version bytes, so only bech32 addresses can tell them apart.
+func ValidateBitcoinAddress(address string, network string) (bool, error) {
+	// Resolve the chain parameters for the requested network
+	params, err := utxo.NetworkParams(network)
+	if err != nil {
+		return false, errors.NewInvalidBlockchainTypeError("Invalid Bitcoin network: " + network)
+	}
+
+	// Decode the address, which also verifies the Base58Check or bech32/bech32m checksum
+	addr, err := btcutil.DecodeAddress(address, params)
+	if err != nil || !addr.IsForNet(params) {
+		// Report a network mismatch when the address is valid on another network
+		for _, other := range []string{"mainnet", "testnet", "regtest"} {
+			otherParams, _ := utxo.NetworkParams(other)
+			if otherAddr, err := btcutil.DecodeAddress(address, otherParams); err == nil && otherAddr.IsForNet(otherParams) {
+				return false, errors.NewInvalidAddressError("Invalid Bitcoin address: address is for " + other + ", expected " + params.Name)
+			}
+		}
+		return false, errors.NewInvalidAddressError("Invalid Bitcoin address format")
+	}
+
+	// Only accept the standard address types
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressScriptHash,
+		*btcutil.AddressWitnessPubKeyHash, *btcutil.AddressWitnessScriptHash,
+		*btcutil.AddressTaproot:
+		return true, nil
+	default:
+		return false, errors.NewInvalidAddressError("Invalid Bitcoin address: unsupported address type")
+	}
+}
+
+// ValidateAddress checks if the given address is valid for the blockchain type
+func ValidateAddress(blockchainType string, address string) (bool, error) {
+	blockchainType = strings.ToLower(blockchainType)
+
+	// Dispatch to the chain-specific validator
+	switch {
+	case evm.IsNetwork(blockchainType):
+		return ValidateEthereumAddress(address)
+	case blockchainType == "xrp":
+		return ValidateXRPAddress(address)
+	case bitcoinNetworks[blockchainType] != "":
+		return ValidateBitcoinAddress(address, bitcoinNetworks[blockchainType])
+	default:
+		return false, errors.NewInvalidBlockchainTypeError("Invalid blockchain type: " + blockchainType)
+	}
+}
+
 // ValidateBlockchainType checks if the given blockchain type is supported
 func ValidateBlockchainType(blockchainType string) (bool, error) {
 	// Convert the blockchain type to lowercase
 	blockchainType = strings.ToLower(blockchainType)
 
-	// Check if the blockchain type is either 'ethereum' or 'xrp'
-	if blockchainType != "ethereum" && blockchainType != "xrp" {
-		return false, errors.NewInvalidBlockchainTypeError("Invalid blockchain type: must be 'ethereum' or 'xrp'")
+	// Check if the blockchain type is 'xrp', one of the Bitcoin networks or one of the EVM networks
+	if blockchainType != "xrp" && bitcoinNetworks[blockchainType] == "" && !evm.IsNetwork(blockchainType) {
+		return false, errors.NewInvalidBlockchainTypeError("Invalid blockchain type: must be 'xrp', 'bitcoin', 'bitcoin-testnet', 'bitcoin-regtest' or an EVM network (" + strings.Join(evm.Names(), ", ") + ")")
+	}
+
+	// If it's valid, return true and nil error
+	return true, nil
+}
+
+// ValidateXRPMemos checks that the memos fit within the XRP Ledger size limit
+func ValidateXRPMemos(memos []models.TransactionMemo) (bool, error) {
+	// Sum the size of every memo field
+	size := 0
+	for _, memo := range memos {
+		if memo.MemoType == "" && memo.MemoData == "" && memo.MemoFormat == "" {
+			return false, errors.NewBadRequestError("Invalid memo: at least one memo field must be set")
+		}
+		size += len(memo.MemoType) + len(memo.MemoData) + len(memo.MemoFormat)
+	}
+
+	// Reject memos that exceed the ledger limit
+	if size > maxXRPMemoBytes {
+		return false, errors.NewBadRequestError("Invalid memos: combined size must not exceed 1024 bytes")
 	}
 
 	// If it's valid, return true and nil error
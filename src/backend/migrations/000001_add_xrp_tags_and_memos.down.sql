@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS memos,
+    DROP COLUMN IF EXISTS source_tag,
+    DROP COLUMN IF EXISTS destination_tag;
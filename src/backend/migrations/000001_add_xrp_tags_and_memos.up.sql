@@ -0,0 +1,5 @@
+-- Destination tag, source tag and memos for XRP transactions
+ALTER TABLE transactions
+    ADD COLUMN destination_tag BIGINT CHECK (destination_tag BETWEEN 0 AND 4294967295),
+    ADD COLUMN source_tag BIGINT CHECK (source_tag BETWEEN 0 AND 4294967295),
+    ADD COLUMN memos JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_signature_requests_transaction_id;
+
+ALTER TABLE signature_requests
+    DROP COLUMN IF EXISTS signer_backend,
+    DROP COLUMN IF EXISTS transaction_id;
+
+ALTER TABLE vaults
+    DROP COLUMN IF EXISTS multisig;
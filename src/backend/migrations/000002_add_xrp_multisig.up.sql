@@ -0,0 +1,10 @@
+-- Native XRP Ledger signer lists for multi-signed vaults
+ALTER TABLE vaults
+    ADD COLUMN multisig JSONB;
+
+-- Partial signatures are linked to the transaction and signer backend that produced them
+ALTER TABLE signature_requests
+    ADD COLUMN transaction_id UUID REFERENCES transactions (id),
+    ADD COLUMN signer_backend VARCHAR(100);
+
+CREATE INDEX idx_signature_requests_transaction_id ON signature_requests (transaction_id);
@@ -0,0 +1,22 @@
+-- XRP Ledger escrows funded by vaults
+CREATE TABLE escrows (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vault_id UUID NOT NULL REFERENCES vaults (id),
+    owner VARCHAR(64) NOT NULL,
+    destination VARCHAR(64) NOT NULL,
+    amount VARCHAR(64) NOT NULL,
+    condition TEXT,
+    fulfillment TEXT,
+    finish_after TIMESTAMPTZ,
+    cancel_after TIMESTAMPTZ,
+    offer_sequence BIGINT NOT NULL DEFAULT 0,
+    create_tx_hash VARCHAR(64),
+    finish_tx_hash VARCHAR(64),
+    cancel_tx_hash VARCHAR(64),
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_escrows_vault_id ON escrows (vault_id);
+CREATE INDEX idx_escrows_open ON escrows (status) WHERE status = 'Created';
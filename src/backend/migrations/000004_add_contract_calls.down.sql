@@ -0,0 +1,4 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS revert_reason,
+    DROP COLUMN IF EXISTS contract_call,
+    DROP COLUMN IF EXISTS data;
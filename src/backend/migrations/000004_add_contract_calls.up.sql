@@ -0,0 +1,5 @@
+-- Calldata, contract call details and revert reasons for EVM transactions
+ALTER TABLE transactions
+    ADD COLUMN data TEXT,
+    ADD COLUMN contract_call JSONB,
+    ADD COLUMN revert_reason TEXT;
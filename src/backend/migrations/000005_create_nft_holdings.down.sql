@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS nft_sync_cursors;
+DROP TABLE IF EXISTS nft_transfers;
+DROP TABLE IF EXISTS nft_holdings;
@@ -0,0 +1,40 @@
+-- NFTs currently held by vaults
+CREATE TABLE nft_holdings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vault_id UUID NOT NULL REFERENCES vaults (id),
+    blockchain_type VARCHAR(32) NOT NULL,
+    contract_address VARCHAR(42) NOT NULL,
+    token_id NUMERIC(78, 0) NOT NULL,
+    standard VARCHAR(10) NOT NULL,
+    balance NUMERIC(78, 0) NOT NULL,
+    metadata_uri TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (vault_id, contract_address, token_id)
+);
+
+CREATE INDEX idx_nft_holdings_vault_id ON nft_holdings (vault_id);
+
+-- Transfer logs already applied to holdings, so rescans are idempotent
+CREATE TABLE nft_transfers (
+    blockchain_type VARCHAR(32) NOT NULL,
+    tx_hash VARCHAR(66) NOT NULL,
+    log_index INTEGER NOT NULL,
+    batch_index INTEGER NOT NULL DEFAULT 0,
+    contract_address VARCHAR(42) NOT NULL,
+    standard VARCHAR(10) NOT NULL,
+    from_address VARCHAR(42) NOT NULL,
+    to_address VARCHAR(42) NOT NULL,
+    token_id NUMERIC(78, 0) NOT NULL,
+    amount NUMERIC(78, 0) NOT NULL,
+    block_number BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blockchain_type, tx_hash, log_index, batch_index)
+);
+
+-- Last confirmed block scanned for NFT transfers on each network
+CREATE TABLE nft_sync_cursors (
+    blockchain_type VARCHAR(32) PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
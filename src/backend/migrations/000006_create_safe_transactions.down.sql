@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS safe_transactions;
+
+ALTER TABLE vaults
+    DROP COLUMN IF EXISTS safe;
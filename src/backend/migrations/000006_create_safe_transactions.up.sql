@@ -0,0 +1,23 @@
+-- Safe smart-contract wallet configuration for Safe vaults
+ALTER TABLE vaults
+    ADD COLUMN safe JSONB;
+
+-- Transactions proposed to Safes, awaiting owner signatures and execution through a relayer
+CREATE TABLE safe_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vault_id UUID NOT NULL REFERENCES vaults (id),
+    to_address VARCHAR(42) NOT NULL,
+    value NUMERIC(78, 0) NOT NULL DEFAULT 0,
+    data TEXT,
+    operation SMALLINT NOT NULL DEFAULT 0,
+    nonce BIGINT NOT NULL,
+    safe_tx_hash VARCHAR(66) NOT NULL,
+    signature_request_ids UUID[] NOT NULL DEFAULT '{}',
+    execution_tx_id UUID REFERENCES transactions (id),
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (vault_id, nonce)
+);
+
+CREATE INDEX idx_safe_transactions_vault_id ON safe_transactions (vault_id);
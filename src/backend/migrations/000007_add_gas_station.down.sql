@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_transactions_purpose;
+DROP INDEX IF EXISTS idx_transactions_awaiting_gas;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS depends_on_id,
+    DROP COLUMN IF EXISTS purpose;
+
+ALTER TABLE organizations
+    DROP COLUMN IF EXISTS gas_station_vault_id;
@@ -0,0 +1,10 @@
+-- Gas station vault per organization and top-up chaining for EVM transactions
+ALTER TABLE organizations
+    ADD COLUMN gas_station_vault_id UUID REFERENCES vaults(id) ON DELETE SET NULL;
+
+ALTER TABLE transactions
+    ADD COLUMN purpose VARCHAR(32),
+    ADD COLUMN depends_on_id UUID REFERENCES transactions(id);
+
+CREATE INDEX idx_transactions_awaiting_gas ON transactions (depends_on_id) WHERE status = 'AwaitingGas';
+CREATE INDEX idx_transactions_purpose ON transactions (purpose) WHERE purpose IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_pending_sweeps;
+DROP TABLE IF EXISTS deposit_addresses;
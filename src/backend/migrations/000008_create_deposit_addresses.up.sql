@@ -0,0 +1,15 @@
+-- HD-derived receive addresses swept into their vault's main address
+CREATE TABLE deposit_addresses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    vault_id UUID NOT NULL REFERENCES vaults (id),
+    address VARCHAR(128) NOT NULL,
+    derivation_path VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (vault_id, address)
+);
+
+CREATE INDEX idx_deposit_addresses_vault_id ON deposit_addresses (vault_id);
+
+-- Sweeps still in flight are looked up per vault before sweeping again
+CREATE INDEX idx_transactions_pending_sweeps ON transactions (vault_id)
+    WHERE purpose = 'sweep' AND status IN ('Pending', 'Submitted', 'AwaitingGas');
@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS offline_digest;
+
+ALTER TABLE vaults
+    DROP COLUMN IF EXISTS cold_storage;
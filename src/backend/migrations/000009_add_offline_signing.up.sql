@@ -0,0 +1,7 @@
+-- Cold-storage vaults whose transactions are signed on an air-gapped machine
+ALTER TABLE vaults
+    ADD COLUMN cold_storage BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Signing digest of the latest exported payload, which an imported signature must match
+ALTER TABLE transactions
+    ADD COLUMN offline_digest VARCHAR(66);
@@ -0,0 +1,5 @@
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS api_key VARCHAR(255);
+
+DROP INDEX IF EXISTS idx_api_keys_organization_id;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,19 @@
+-- Organization API keys; only a bcrypt hash of each secret is stored
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL UNIQUE,
+    secret_hash VARCHAR(255) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_keys_organization_id ON api_keys (organization_id);
+
+-- Plaintext organization keys are retired; organizations must be issued new keys
+ALTER TABLE organizations
+    DROP COLUMN IF EXISTS api_key;
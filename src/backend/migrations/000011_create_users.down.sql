@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_organization_id;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_audit_events_organization_id;
+DROP TABLE IF EXISTS audit_events;
+
+DROP INDEX IF EXISTS idx_role_bindings_user_id;
+DROP TABLE IF EXISTS role_bindings;
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,36 @@
+-- Custom roles defined by an organization alongside the built-in roles
+CREATE TABLE roles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    name VARCHAR(64) NOT NULL,
+    permissions TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, name)
+);
+
+-- Additional roles granted to a user across the organization, or on one vault when vault_id is set
+CREATE TABLE role_bindings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    user_id UUID NOT NULL REFERENCES users (id),
+    role VARCHAR(64) NOT NULL,
+    vault_id UUID REFERENCES vaults (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_role_bindings_user_id ON role_bindings (organization_id, user_id);
+
+-- Security-relevant events such as denied requests
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    actor_type VARCHAR(16) NOT NULL,
+    actor_id VARCHAR(64) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    resource VARCHAR(255) NOT NULL,
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_organization_id ON audit_events (organization_id, created_at DESC);
@@ -0,0 +1,49 @@
+DROP POLICY IF EXISTS tenant_isolation ON deposit_addresses;
+ALTER TABLE deposit_addresses NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE deposit_addresses DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON safe_transactions;
+ALTER TABLE safe_transactions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE safe_transactions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON nft_holdings;
+ALTER TABLE nft_holdings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE nft_holdings DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON escrows;
+ALTER TABLE escrows NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE escrows DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON audit_events;
+ALTER TABLE audit_events NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE audit_events DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON role_bindings;
+ALTER TABLE role_bindings NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE role_bindings DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON roles;
+ALTER TABLE roles NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE roles DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON signature_requests;
+ALTER TABLE signature_requests NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE signature_requests DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON transactions;
+ALTER TABLE transactions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE transactions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON vaults;
+ALTER TABLE vaults NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE vaults DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_visible_organization(UUID);
+
+DROP INDEX IF EXISTS idx_signature_requests_organization_id;
+ALTER TABLE signature_requests
+    DROP COLUMN IF EXISTS organization_id;
+
+DROP INDEX IF EXISTS idx_transactions_organization_id;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS organization_id;
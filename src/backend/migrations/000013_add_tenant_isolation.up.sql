@@ -0,0 +1,92 @@
+-- Transactions and signature requests carry their vault's organization so they can be filtered directly
+ALTER TABLE transactions
+    ADD COLUMN organization_id UUID REFERENCES organizations (id);
+
+UPDATE transactions t SET organization_id = v.organization_id FROM vaults v WHERE v.id = t.vault_id;
+
+ALTER TABLE transactions
+    ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX idx_transactions_organization_id ON transactions (organization_id, created_at DESC);
+
+ALTER TABLE signature_requests
+    ADD COLUMN organization_id UUID REFERENCES organizations (id);
+
+UPDATE signature_requests s SET organization_id = v.organization_id FROM vaults v WHERE v.id = s.vault_id;
+
+ALTER TABLE signature_requests
+    ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX idx_signature_requests_organization_id ON signature_requests (organization_id, created_at DESC);
+
+-- Rows are visible to the organization set for the current transaction, or to every organization for
+-- background jobs. With neither set, nothing is visible
+CREATE FUNCTION app_visible_organization(org UUID) RETURNS BOOLEAN AS $$
+    SELECT current_setting('app.all_organizations', true) = 'on'
+        OR org = NULLIF(current_setting('app.organization_id', true), '')::UUID
+$$ LANGUAGE SQL STABLE;
+
+-- Tables owned directly by an organization
+ALTER TABLE vaults ENABLE ROW LEVEL SECURITY;
+ALTER TABLE vaults FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON vaults
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE transactions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transactions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON transactions
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE signature_requests ENABLE ROW LEVEL SECURITY;
+ALTER TABLE signature_requests FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON signature_requests
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE roles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE roles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON roles
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE role_bindings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE role_bindings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON role_bindings
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_events
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+-- Tables owned through a vault; the vaults policy applies inside the subquery
+ALTER TABLE escrows ENABLE ROW LEVEL SECURITY;
+ALTER TABLE escrows FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON escrows
+    USING (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id));
+
+ALTER TABLE nft_holdings ENABLE ROW LEVEL SECURITY;
+ALTER TABLE nft_holdings FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON nft_holdings
+    USING (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id));
+
+ALTER TABLE safe_transactions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE safe_transactions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON safe_transactions
+    USING (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id));
+
+ALTER TABLE deposit_addresses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE deposit_addresses FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON deposit_addresses
+    USING (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM vaults v WHERE v.id = vault_id));
+
+-- organizations, users and api_keys are read while authenticating, before the organization is known,
+-- and stay outside row-level security
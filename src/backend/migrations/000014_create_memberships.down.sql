@@ -0,0 +1,23 @@
+DROP INDEX IF EXISTS idx_invitations_organization_id;
+DROP TABLE IF EXISTS invitations;
+
+-- Each user keeps only their oldest membership
+ALTER TABLE users ADD COLUMN organization_id UUID REFERENCES organizations (id), ADD COLUMN role VARCHAR(32);
+
+UPDATE users u
+SET organization_id = m.organization_id, role = m.role
+FROM (
+    SELECT DISTINCT ON (user_id) user_id, organization_id, role
+    FROM memberships
+    ORDER BY user_id, created_at
+) m
+WHERE m.user_id = u.id;
+
+DROP INDEX IF EXISTS idx_memberships_user_id;
+DROP TABLE IF EXISTS memberships;
+
+-- Users without a membership cannot be represented and are removed
+DELETE FROM role_bindings WHERE user_id IN (SELECT id FROM users WHERE organization_id IS NULL);
+DELETE FROM users WHERE organization_id IS NULL;
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL, ALTER COLUMN role SET NOT NULL;
+CREATE INDEX idx_users_organization_id ON users (organization_id);
@@ -0,0 +1,48 @@
+-- Memberships let a user belong to several organizations, each with its own role
+CREATE TABLE memberships (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    role VARCHAR(64) NOT NULL,
+    deactivated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (organization_id, user_id)
+);
+
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+-- Move each user's organization and role into a membership
+INSERT INTO memberships (user_id, organization_id, role, created_at)
+SELECT id, organization_id, role, created_at FROM users;
+
+DROP INDEX IF EXISTS idx_users_organization_id;
+ALTER TABLE users DROP COLUMN organization_id, DROP COLUMN role;
+
+-- Email invitations to join an organization; only a hash of the emailed token is kept
+CREATE TABLE invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations (id),
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(64) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    invited_by UUID REFERENCES users (id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invitations_organization_id ON invitations (organization_id);
+
+-- Both are scoped to their organization; sign-in and invitation acceptance read them as background jobs
+ALTER TABLE memberships ENABLE ROW LEVEL SECURITY;
+ALTER TABLE memberships FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON memberships
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
+
+ALTER TABLE invitations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE invitations FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON invitations
+    USING (app_visible_organization(organization_id))
+    WITH CHECK (app_visible_organization(organization_id));
@@ -0,0 +1,20 @@
+-- Second factors belong to users, who span organizations, so like users they are not tenant-scoped
+
+-- Authenticator app enrollments; the secret is encrypted by the service before it is stored
+CREATE TABLE totp_factors (
+    user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    encrypted_secret BYTEA NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Single-use recovery codes that stand in for a lost authenticator; only their hashes are kept
+CREATE TABLE recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
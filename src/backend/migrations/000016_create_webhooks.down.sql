@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_webhook_deliveries_endpoint;
+DROP INDEX IF EXISTS idx_webhook_deliveries_resource;
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP INDEX IF EXISTS idx_webhook_endpoints_organization_id;
+DROP TABLE IF EXISTS webhook_endpoints;
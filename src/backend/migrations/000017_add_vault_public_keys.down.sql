@@ -0,0 +1,2 @@
+ALTER TABLE vaults
+    DROP COLUMN IF EXISTS public_key;
@@ -0,0 +1,3 @@
+-- Public key of each vault's own signing key, which XRP transactions carry alongside the signature
+ALTER TABLE vaults
+    ADD COLUMN public_key VARCHAR(130);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_escrows_submitted;
+
+ALTER TABLE escrows
+    DROP COLUMN IF EXISTS last_ledger_sequence;
+
+ALTER TABLE escrows
+    DROP COLUMN IF EXISTS encrypted_fulfillment,
+    ADD COLUMN fulfillment TEXT;
@@ -0,0 +1,11 @@
+-- Escrow fulfillments are stored AES-GCM encrypted rather than in plain text. Plain-text fulfillments cannot be
+-- encrypted here, so conditional escrows created before this migration can only be cancelled
+ALTER TABLE escrows
+    DROP COLUMN fulfillment,
+    ADD COLUMN encrypted_fulfillment BYTEA;
+
+-- Ledger after which a submitted EscrowCreate that was never validated has expired
+ALTER TABLE escrows
+    ADD COLUMN last_ledger_sequence BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_escrows_submitted ON escrows (status) WHERE status = 'Submitted';
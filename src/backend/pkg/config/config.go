@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 	"github.com/your-repo/blockchain-integration-service/pkg/errors"
 )
@@ -11,6 +13,10 @@ type Config struct {
 	Database   DatabaseConfig
 	Redis      RedisConfig
 	Blockchain BlockchainConfig
+	Auth       AuthConfig
+	Mailer     MailerConfig
+	Webhooks   WebhookConfig
+	Realtime   RealtimeConfig
 	Logger     LoggerConfig
 }
 
@@ -40,8 +46,144 @@ type RedisConfig struct {
 
 // BlockchainConfig represents blockchain-specific configuration
 type BlockchainConfig struct {
-	EthereumRPC string
-	XRPRPC      string
+	EthereumRPC          string
+	EthereumRPCEndpoints []string
+	EVMNetworks          []EVMNetworkConfig
+	XRPRPC               string
+	XRPEndpoints         []string
+	RPCPool              RPCPoolConfig
+	UTXONetwork          string
+	UTXODustThreshold    int64
+	UTXOCustodian        CustodianConfig
+	Safe                 SafeContractsConfig
+	GasStation           GasStationConfig
+	Sweeps               []SweepConfig
+
+	// EscrowEncryptionKey is the base64-encoded AES-256 key escrow fulfillments are stored under
+	EscrowEncryptionKey string
+
+	// PollInterval is how often executing Safe transactions, NFT holdings and escrows are checked on chain
+	PollInterval time.Duration
+}
+
+// EVMNetworkConfig represents an EVM network; entries named after a built-in network override its defaults
+type EVMNetworkConfig struct {
+	Name          string
+	ChainID       int64
+	RPCEndpoints  []string
+	NativeSymbol  string
+	Confirmations uint64
+	Testnet       bool
+}
+
+// RPCPoolConfig represents health probing and failover configuration for RPC endpoints
+type RPCPoolConfig struct {
+	ProbeInterval   time.Duration
+	ProbeTimeout    time.Duration
+	MaxHeadLag      uint64
+	BroadcastFanout int
+}
+
+// CustodianConfig represents UTXO custodian client configuration
+type CustodianConfig struct {
+	BaseURL          string
+	APIKey           string
+	HMACSecret       string
+	Timeout          time.Duration
+	MaxRetries       int
+	RetryBaseDelay   time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// SafeContractsConfig represents the Safe deployment addresses shared by every EVM network
+type SafeContractsConfig struct {
+	SingletonAddress       string
+	ProxyFactoryAddress    string
+	FallbackHandlerAddress string
+
+	// DelegateCallTargets are the only contracts Safe transactions may delegatecall into, such as MultiSendCallOnly.
+	// A delegatecall runs the target's code with the Safe's storage and funds, so any other target is refused
+	DelegateCallTargets []string
+}
+
+// GasStationConfig represents how gas-station vaults top up the native balance of token-only vaults
+type GasStationConfig struct {
+	BufferPercent int
+
+	// MaxTopUpWei caps a single top-up and must be set; a vault needing more gas is refused rather than funded
+	MaxTopUpWei string
+
+	ReleaseInterval time.Duration
+}
+
+// SweepConfig represents the schedule and thresholds for consolidating deposit addresses on one blockchain
+type SweepConfig struct {
+	BlockchainType string
+	Interval       time.Duration
+	MinAmount      string
+	MaxFeePercent  int
+	FeeRate        int64
+	MaxInputs      int
+}
+
+// AuthConfig represents access token and session configuration
+type AuthConfig struct {
+	Issuer          string
+	Audience        string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	ClockSkew       time.Duration
+	SigningKeys     []SigningKeyConfig
+	InvitationTTL   time.Duration
+	InvitationURL   string
+
+	// StepUpMaxAge is how recently a second factor must have been presented for sensitive operations.
+	// TOTPEncryptionKey is the base64-encoded AES-256 key TOTP secrets are stored under, and TOTPIssuer the
+	// name authenticator apps show for them
+	StepUpMaxAge      time.Duration
+	TOTPEncryptionKey string
+	TOTPIssuer        string
+}
+
+// SigningKeyConfig represents an Ed25519 token signing key; the first configured key signs and the rest
+// only verify, so keys can be rotated without invalidating live tokens
+type SigningKeyConfig struct {
+	ID         string
+	PrivateKey string
+}
+
+// MailerConfig represents the SMTP server outgoing email is sent through. Username and Password may be left
+// empty for local relays that accept unauthenticated mail
+type MailerConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// WebhookConfig represents how events are delivered to organizations' webhook endpoints. Failed deliveries
+// are retried with exponential backoff for RetryWindow, and an endpoint failing every attempt for
+// DisableAfter is disabled. AllowPrivateNetworks lets endpoints resolve to loopback and private addresses,
+// which is only safe in development
+type WebhookConfig struct {
+	PollInterval         time.Duration
+	Timeout              time.Duration
+	RetryWindow          time.Duration
+	DisableAfter         time.Duration
+	BatchSize            int
+	AllowPrivateNetworks bool
+}
+
+// RealtimeConfig represents how events are streamed to connected clients. Events are kept in a log of the
+// last Retention events so reconnecting clients can resume, a client with more than BufferSize events
+// waiting is disconnected, and an organization may hold at most MaxConnections streams across all replicas
+type RealtimeConfig struct {
+	Retention         int64
+	BufferSize        int
+	HeartbeatInterval time.Duration
+	MaxConnections    int
 }
 
 // LoggerConfig represents logger-specific configuration
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -20,6 +21,11 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error so Is and As can inspect the cause
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // New creates a new AppError instance
 func New(message string, statusCode int, err error) *AppError {
 	return &AppError{
@@ -39,17 +45,61 @@ func NewBadRequestError(message string) *AppError {
 	return New(message, http.StatusBadRequest, nil)
 }
 
+// NewUnauthorizedError creates a new error for a request without valid credentials
+func NewUnauthorizedError(message string) *AppError {
+	return New(message, http.StatusUnauthorized, nil)
+}
+
+// NewForbiddenError creates a new error for a request whose credentials lack the required permission
+func NewForbiddenError(message string) *AppError {
+	return New(message, http.StatusForbidden, nil)
+}
+
 // NewInternalServerError creates a new InternalServerError
 func NewInternalServerError(message string, err error) *AppError {
 	return New(message, http.StatusInternalServerError, err)
 }
 
+// NewInvalidAddressError creates a new error for a malformed or unsupported blockchain address
+func NewInvalidAddressError(message string) *AppError {
+	return New(message, http.StatusBadRequest, nil)
+}
+
+// NewInvalidAmountError creates a new error for an invalid transaction amount
+func NewInvalidAmountError(message string) *AppError {
+	return New(message, http.StatusBadRequest, nil)
+}
+
+// NewInvalidBlockchainTypeError creates a new error for an unsupported blockchain type
+func NewInvalidBlockchainTypeError(message string) *AppError {
+	return New(message, http.StatusBadRequest, nil)
+}
+
+// Wrap adds context to an error. The result keeps the status code of the first AppError in err's chain,
+// or reports an internal server error if there is none
+func Wrap(err error, message string) *AppError {
+	statusCode := http.StatusInternalServerError
+	var appErr *AppError
+	if As(err, &appErr) && appErr.StatusCode != 0 {
+		statusCode = appErr.StatusCode
+	}
+	return New(message, statusCode, err)
+}
+
+// Is reports whether any error in err's chain matches target
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target and, if one is found, sets target to it
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
+
 // Human tasks:
 // TODO: Implement unit tests for each error creation function
-// TODO: Add more specific error types (e.g., UnauthorizedError, ForbiddenError)
 // TODO: Implement a method to convert AppError to a JSON response
 // TODO: Add support for error codes in addition to HTTP status codes
-// TODO: Implement a method to wrap errors with additional context
 // TODO: Add support for localization of error messages
 // TODO: Implement a central error handler for the application
 // TODO: Add logging integration for errors
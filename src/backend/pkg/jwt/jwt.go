@@ -0,0 +1,345 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// algorithm is the only signing algorithm issued and accepted: EdDSA over Ed25519
+const algorithm = "EdDSA"
+
+// Authentication method references of RFC 8176 recorded in the amr claim
+const (
+	MethodPassword    = "pwd"
+	MethodOTP         = "otp"
+	MethodMultiFactor = "mfa"
+)
+
+var (
+	// ErrMalformedToken is returned for tokens that are not three base64url segments of valid JSON
+	ErrMalformedToken = errors.New("malformed token")
+
+	// ErrUnsupportedAlgorithm is returned for tokens signed with anything but EdDSA, including "none"
+	ErrUnsupportedAlgorithm = errors.New("unsupported token algorithm")
+
+	// ErrUnknownKey is returned when a token's kid is not in the key set, e.g. after the key was retired
+	ErrUnknownKey = errors.New("unknown token signing key")
+
+	// ErrInvalidSignature is returned when a token's signature does not verify
+	ErrInvalidSignature = errors.New("invalid token signature")
+
+	// ErrExpired is returned for tokens past their expiry, allowing for clock skew
+	ErrExpired = errors.New("token has expired")
+
+	// ErrNotYetValid is returned for tokens used before their not-before or issued-at time
+	ErrNotYetValid = errors.New("token is not valid yet")
+
+	// ErrInvalidIssuer is returned when a token was issued by someone else
+	ErrInvalidIssuer = errors.New("invalid token issuer")
+
+	// ErrInvalidAudience is returned when a token was issued for another audience
+	ErrInvalidAudience = errors.New("invalid token audience")
+)
+
+// Claims are the registered and service-specific claims carried by an access token
+type Claims struct {
+	Issuer         string `json:"iss"`
+	Subject        string `json:"sub"`
+	Audience       string `json:"aud"`
+	ExpiresAt      int64  `json:"exp"`
+	NotBefore      int64  `json:"nbf,omitempty"`
+	IssuedAt       int64  `json:"iat"`
+	ID             string `json:"jti,omitempty"`
+	SessionID      string `json:"sid,omitempty"`
+	OrganizationID string `json:"org,omitempty"`
+	Role           string `json:"role,omitempty"`
+
+	// AuthMethods and AuthTime record how the user last authenticated and when, so sensitive operations
+	// can demand a recent second factor
+	AuthMethods []string `json:"amr,omitempty"`
+	AuthTime    int64    `json:"auth_time,omitempty"`
+}
+
+// HasMethod reports whether the user authenticated with a method
+func (c *Claims) HasMethod(method string) bool {
+	for _, m := range c.AuthMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// header is the JOSE header of a token
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+// Key is an Ed25519 signing key identified by its kid
+type Key struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+}
+
+// GenerateKey creates a new random signing key
+func GenerateKey(id string) (Key, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, PrivateKey: private}, nil
+}
+
+// ParseKey parses a signing key from a PKCS#8 PEM block or a base64-encoded 32-byte Ed25519 seed
+func ParseKey(id, encoded string) (Key, error) {
+	if id == "" {
+		return Key{}, errors.New("signing key requires an ID")
+	}
+	if block, _ := pem.Decode([]byte(encoded)); block != nil {
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid signing key %s: %w", id, err)
+		}
+		private, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return Key{}, fmt.Errorf("signing key %s is not an Ed25519 key", id)
+		}
+		return Key{ID: id, PrivateKey: private}, nil
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return Key{}, fmt.Errorf("signing key %s must be a PKCS#8 PEM block or a base64 Ed25519 seed", id)
+	}
+	return Key{ID: id, PrivateKey: ed25519.NewKeyFromSeed(seed)}, nil
+}
+
+// KeySet signs with its current key and verifies with every key it holds, so tokens signed before a
+// rotation stay valid until the retired key is removed
+type KeySet struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]ed25519.PublicKey
+	signers map[string]ed25519.PrivateKey
+	order   []string
+}
+
+// NewKeySet creates a key set that signs with the first key
+func NewKeySet(keys ...Key) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key set requires at least one key")
+	}
+	ks := &KeySet{
+		keys:    make(map[string]ed25519.PublicKey),
+		signers: make(map[string]ed25519.PrivateKey),
+	}
+	for _, key := range keys {
+		if err := ks.add(key); err != nil {
+			return nil, err
+		}
+	}
+	ks.current = keys[0].ID
+	return ks, nil
+}
+
+// Rotate adds a key and makes it the signing key; previous keys remain available for verification
+func (ks *KeySet) Rotate(key Key) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if err := ks.add(key); err != nil {
+		return err
+	}
+	ks.current = key.ID
+	return nil
+}
+
+// Retire removes a key that no longer signs, invalidating every token it signed
+func (ks *KeySet) Retire(id string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if id == ks.current {
+		return errors.New("cannot retire the current signing key")
+	}
+	delete(ks.keys, id)
+	delete(ks.signers, id)
+	for i, kid := range ks.order {
+		if kid == id {
+			ks.order = append(ks.order[:i], ks.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Sign encodes and signs claims with the current key
+func (ks *KeySet) Sign(claims *Claims) (string, error) {
+	ks.mu.RLock()
+	kid, private := ks.current, ks.signers[ks.current]
+	ks.mu.RUnlock()
+
+	encodedHeader, err := encodeSegment(header{Algorithm: algorithm, Type: "JWT", KeyID: kid})
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodedHeader + "." + encodedClaims
+	signature := ed25519.Sign(private, []byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify checks a token's signature against the key named by its kid and returns its claims, without
+// checking any claim
+func (ks *KeySet) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if h.Algorithm != algorithm {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	ks.mu.RLock()
+	public, ok := ks.keys[h.KeyID]
+	ks.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !ed25519.Verify(public, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	return &claims, nil
+}
+
+// JWK is a public Ed25519 key in JSON Web Key form
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys downstream services need to verify tokens, including keys kept only for
+// verification after a rotation
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	set := JWKS{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			X:         base64.RawURLEncoding.EncodeToString(ks.keys[kid]),
+			KeyID:     kid,
+			Use:       "sig",
+			Algorithm: algorithm,
+		})
+	}
+	return set
+}
+
+// add registers a key; callers hold the write lock or own the key set exclusively
+func (ks *KeySet) add(key Key) error {
+	if key.ID == "" || len(key.PrivateKey) != ed25519.PrivateKeySize {
+		return errors.New("signing key requires an ID and an Ed25519 private key")
+	}
+	if _, exists := ks.keys[key.ID]; exists {
+		return fmt.Errorf("duplicate signing key ID %s", key.ID)
+	}
+	ks.keys[key.ID] = key.PrivateKey.Public().(ed25519.PublicKey)
+	ks.signers[key.ID] = key.PrivateKey
+	ks.order = append(ks.order, key.ID)
+	return nil
+}
+
+// Validator verifies tokens and checks their issuer, audience and validity window
+type Validator struct {
+	Keys     *KeySet
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	Now      func() time.Time
+}
+
+// Validate verifies a token and returns its claims if it is currently valid for the issuer and audience
+func (v *Validator) Validate(token string) (*Claims, error) {
+	claims, err := v.Keys.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Issuer != v.Issuer {
+		return nil, ErrInvalidIssuer
+	}
+	if claims.Audience != v.Audience {
+		return nil, ErrInvalidAudience
+	}
+
+	// Allow for clock skew between this service and whoever issued or presents the token
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	if claims.ExpiresAt == 0 || !now.Before(time.Unix(claims.ExpiresAt, 0).Add(v.Leeway)) {
+		return nil, ErrExpired
+	}
+	if now.Add(v.Leeway).Before(time.Unix(claims.NotBefore, 0)) || now.Add(v.Leeway).Before(time.Unix(claims.IssuedAt, 0)) {
+		return nil, ErrNotYetValid
+	}
+	return claims, nil
+}
+
+// encodeSegment encodes a value as base64url JSON
+func encodeSegment(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeSegment decodes a base64url JSON segment
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Human tasks:
+// - Accept array-valued aud claims from tokens issued by other services
+// - Load signing keys from AWS KMS instead of configuration
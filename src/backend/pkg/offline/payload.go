@@ -0,0 +1,116 @@
+package offline
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Version is the payload format version written by this package
+	Version = 1
+
+	// qrPrefix marks the compact text form of a payload, suitable for QR codes
+	qrPrefix = "BISOFFLINE1:"
+)
+
+// Payload formats
+const (
+	FormatPSBT    = "psbt"
+	FormatRLP     = "rlp"
+	FormatXRPJSON = "xrp-json"
+)
+
+var (
+	// ErrChecksumMismatch is returned when a payload was altered or corrupted in transit
+	ErrChecksumMismatch = errors.New("offline payload checksum does not match its contents")
+
+	// ErrUnsupportedVersion is returned for payloads written by an unknown format version
+	ErrUnsupportedVersion = errors.New("unsupported offline payload version")
+)
+
+// Payload is an unsigned or signed transaction carried to and from an air-gapped signer. Data holds a
+// base64 PSBT, a hex RLP transaction, or an XRP transaction as JSON when unsigned and as a hex blob when signed
+type Payload struct {
+	Version        int    `json:"version"`
+	TransactionID  string `json:"transaction_id"`
+	BlockchainType string `json:"blockchain_type"`
+	Format         string `json:"format"`
+	Signed         bool   `json:"signed"`
+	Data           string `json:"data"`
+	Digest         string `json:"digest"`
+	Checksum       string `json:"checksum"`
+}
+
+// Seal sets the payload's checksum over its contents and originating transaction ID
+func (p *Payload) Seal() {
+	p.Version = Version
+	p.Checksum = p.checksum()
+}
+
+// Verify checks that the payload's checksum matches its contents
+func (p *Payload) Verify() error {
+	if p.Version != Version {
+		return ErrUnsupportedVersion
+	}
+	if p.Checksum != p.checksum() {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// QR encodes the sealed payload as a compact text string for QR codes
+func (p *Payload) QR() (string, error) {
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return qrPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Parse decodes a payload from its JSON file form or its QR text form and verifies its checksum
+func Parse(raw []byte) (*Payload, error) {
+	raw = bytes.TrimSpace(raw)
+	if text := string(raw); strings.HasPrefix(text, qrPrefix) {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(text, qrPrefix))
+		if err != nil {
+			return nil, errors.New("invalid offline payload QR encoding")
+		}
+		raw = decoded
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, errors.New("invalid offline payload: " + err.Error())
+	}
+	if err := payload.Verify(); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// checksum hashes every field except the checksum itself, length-prefixed so fields cannot run together
+func (p *Payload) checksum() string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.Itoa(p.Version),
+		p.TransactionID,
+		p.BlockchainType,
+		p.Format,
+		strconv.FormatBool(p.Signed),
+		p.Data,
+		p.Digest,
+	} {
+		h.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Human tasks:
+// - Split payloads larger than a single QR code into an animated multi-part sequence
+// - Sign exported payloads with a server key so the offline signer can authenticate their origin
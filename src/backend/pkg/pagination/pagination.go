@@ -0,0 +1,264 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// Page sizes
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+// Sort fields list endpoints can allow. A leading "-" in the sort parameter sorts descending
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortName      = "name"
+	SortAmount    = "amount"
+)
+
+// Filters list endpoints can allow. FilterCreated covers created_after and created_before, FilterAmount
+// covers min_amount and max_amount
+const (
+	FilterStatus         = "status"
+	FilterBlockchainType = "blockchain_type"
+	FilterVaultID        = "vault_id"
+	FilterCreated        = "created"
+	FilterAmount         = "amount"
+)
+
+// ErrInvalidCursor is returned for cursors that were not issued for the requested sort order
+var ErrInvalidCursor = errors.NewBadRequestError("invalid cursor")
+
+// Filters narrows a listing; unset fields do not filter. Created bounds are inclusive after and exclusive
+// before, amount bounds are inclusive decimal strings
+type Filters struct {
+	Status         string
+	BlockchainType string
+	VaultID        *uuid.UUID
+	CreatedAfter   *time.Time
+	CreatedBefore  *time.Time
+	MinAmount      string
+	MaxAmount      string
+}
+
+// Cursor is the keyset position after the last row of a page: that row's sort value and its ID, which
+// breaks ties. It is handed to clients as an opaque string
+type Cursor struct {
+	Sort       string    `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	Value      string    `json:"v"`
+	ID         uuid.UUID `json:"i"`
+}
+
+// Options is a list request. Repositories return the rows after Cursor in the sort order, fetching one
+// more than Limit so callers can tell whether another page follows
+type Options struct {
+	Limit        int
+	Sort         string
+	Descending   bool
+	Cursor       *Cursor
+	IncludeTotal bool
+	Filters      Filters
+}
+
+// Page is the envelope list endpoints respond with. NextCursor is empty on the last page and Total is only
+// counted when requested
+type Page struct {
+	Data       interface{} `json:"data"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Total      *int        `json:"total,omitempty"`
+}
+
+// Rules are the sort fields and filters a list endpoint accepts. The first sort field, descending, is the
+// default order
+type Rules struct {
+	SortFields []string
+	Filters    []string
+}
+
+// Parse reads list options from query parameters: limit, cursor, sort, include_total and the filters.
+// Which sorts and filters are allowed is left to the endpoint's Rules
+func Parse(query url.Values) (*Options, error) {
+	opts := &Options{}
+
+	// Page size and position
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.NewBadRequestError("limit must be a number")
+		}
+		opts.Limit = limit
+	}
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		if err != nil {
+			return nil, err
+		}
+		opts.Cursor = cursor
+	}
+	if sort := query.Get("sort"); sort != "" {
+		opts.Descending = strings.HasPrefix(sort, "-")
+		opts.Sort = strings.TrimPrefix(sort, "-")
+	}
+	if raw := query.Get("include_total"); raw != "" {
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.NewBadRequestError("include_total must be true or false")
+		}
+		opts.IncludeTotal = include
+	}
+
+	// Filters
+	opts.Filters.Status = query.Get("status")
+	opts.Filters.BlockchainType = query.Get("blockchain_type")
+	if raw := query.Get("vault_id"); raw != "" {
+		vaultID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, errors.NewBadRequestError("vault_id must be a UUID")
+		}
+		opts.Filters.VaultID = &vaultID
+	}
+	var err error
+	if opts.Filters.CreatedAfter, err = parseTime(query, "created_after"); err != nil {
+		return nil, err
+	}
+	if opts.Filters.CreatedBefore, err = parseTime(query, "created_before"); err != nil {
+		return nil, err
+	}
+	opts.Filters.MinAmount = query.Get("min_amount")
+	opts.Filters.MaxAmount = query.Get("max_amount")
+	return opts, nil
+}
+
+// Validate checks options against an endpoint's rules and fills in the default limit and order
+func (r Rules) Validate(opts *Options) error {
+	// Apply defaults and bound the page size
+	if opts.Limit == 0 {
+		opts.Limit = DefaultLimit
+	}
+	if opts.Limit < 1 || opts.Limit > MaxLimit {
+		return errors.NewBadRequestError("limit must be between 1 and " + strconv.Itoa(MaxLimit))
+	}
+	if opts.Sort == "" {
+		opts.Sort, opts.Descending = r.SortFields[0], true
+	}
+	if !contains(r.SortFields, opts.Sort) {
+		return errors.NewBadRequestError("cannot sort by " + opts.Sort + "; sort by one of " + strings.Join(r.SortFields, ", "))
+	}
+
+	// A cursor only continues the order it was issued for
+	if opts.Cursor != nil && (opts.Cursor.Sort != opts.Sort || opts.Cursor.Descending != opts.Descending) {
+		return ErrInvalidCursor
+	}
+
+	// Only the endpoint's filters may be set, with well-formed ranges
+	f := opts.Filters
+	used := []struct {
+		filter string
+		set    bool
+	}{
+		{FilterStatus, f.Status != ""},
+		{FilterBlockchainType, f.BlockchainType != ""},
+		{FilterVaultID, f.VaultID != nil},
+		{FilterCreated, f.CreatedAfter != nil || f.CreatedBefore != nil},
+		{FilterAmount, f.MinAmount != "" || f.MaxAmount != ""},
+	}
+	for _, u := range used {
+		if u.set && !contains(r.Filters, u.filter) {
+			return errors.NewBadRequestError("cannot filter by " + u.filter)
+		}
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return errors.NewBadRequestError("created_after must be before created_before")
+	}
+	low, ok := parseAmount(f.MinAmount)
+	if !ok {
+		return errors.NewBadRequestError("min_amount must be a non-negative decimal")
+	}
+	high, ok := parseAmount(f.MaxAmount)
+	if !ok {
+		return errors.NewBadRequestError("max_amount must be a non-negative decimal")
+	}
+	if low != nil && high != nil && low.Cmp(high) > 0 {
+		return errors.NewBadRequestError("min_amount must not exceed max_amount")
+	}
+	return nil
+}
+
+// NextCursor returns the cursor continuing after a row with the given ID and sort value
+func (o *Options) NextCursor(id uuid.UUID, value string) string {
+	return EncodeCursor(&Cursor{Sort: o.Sort, Descending: o.Descending, Value: value, ID: id})
+}
+
+// EncodeCursor encodes a cursor as an opaque URL-safe string
+func EncodeCursor(cursor *Cursor) string {
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor decodes a cursor returned by EncodeCursor
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort == "" || cursor.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// TimeValue formats a timestamp sort value so cursors keep its full precision
+func TimeValue(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// parseTime reads an optional RFC 3339 timestamp query parameter
+func parseTime(query url.Values, name string) (*time.Time, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.NewBadRequestError(name + " must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+// parseAmount parses an optional non-negative decimal amount
+func parseAmount(raw string) (*big.Rat, bool) {
+	if raw == "" {
+		return nil, true
+	}
+	amount, ok := new(big.Rat).SetString(raw)
+	if !ok || amount.Sign() < 0 || strings.ContainsAny(raw, "/eE") {
+		return nil, false
+	}
+	return amount, true
+}
+
+// contains reports whether a list holds a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Human tasks:
+// - Sign cursors so clients cannot craft positions, if sort values ever become sensitive
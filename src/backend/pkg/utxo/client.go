@@ -1,10 +1,20 @@
 package utxo
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/your-repo/blockchain-integration-service/pkg/config"
@@ -13,17 +23,44 @@ import (
 
 // UTXOClient represents a client for the UTXO custodian service
 type UTXOClient struct {
-	client  *http.Client
-	baseURL string
-	apiKey  string
-	log     *logger.Logger
+	client     *http.Client
+	baseURL    string
+	apiKey     string
+	hmacSecret []byte
+	maxRetries int
+	baseDelay  time.Duration
+	breaker    *circuitBreaker
+	log        *logger.Logger
 }
 
+const (
+	// defaultTimeout is the per-attempt HTTP timeout
+	defaultTimeout = 30 * time.Second
+
+	// defaultMaxRetries is the number of times an idempotent request is retried
+	defaultMaxRetries = 3
+
+	// defaultRetryBaseDelay is the base of the exponential backoff between retries
+	defaultRetryBaseDelay = 200 * time.Millisecond
+
+	// maxRetryDelay caps the backoff and any Retry-After requested by the custodian
+	maxRetryDelay = 30 * time.Second
+
+	// defaultBreakerThreshold is the number of consecutive failures that opens the circuit breaker
+	defaultBreakerThreshold = 5
+
+	// defaultBreakerCooldown is how long the circuit breaker stays open before probing again
+	defaultBreakerCooldown = 30 * time.Second
+)
+
 // UTXO represents an unspent transaction output
 type UTXO struct {
-	TxID   string `json:"txid"`
-	Vout   int    `json:"vout"`
-	Amount int64  `json:"amount"`
+	TxID         string `json:"txid"`
+	Vout         int    `json:"vout"`
+	Amount       int64  `json:"amount"`
+	ScriptPubKey string `json:"script_pubkey,omitempty"`
+	RedeemScript string `json:"redeem_script,omitempty"`
+	RawTx        string `json:"raw_tx,omitempty"`
 }
 
 // TransactionRequest represents a request to create a new transaction
@@ -47,145 +84,295 @@ type TransactionStatus struct {
 	Status string `json:"status"`
 }
 
+// FeePreviewRequest describes a payment whose fee the custodian should estimate
+type FeePreviewRequest struct {
+	FromAddress string `json:"from_address"`
+	ToAddress   string `json:"to_address"`
+	Amount      int64  `json:"amount"`
+}
+
+// FeePreview is the custodian's estimate of the fee and coin selection for a payment
+type FeePreview struct {
+	Fee        int64   `json:"fee"`
+	FeeRate    float64 `json:"fee_rate"`
+	Inputs     int     `json:"inputs"`
+	Change     int64   `json:"change"`
+	Sufficient bool    `json:"sufficient"`
+}
+
 // NewUTXOClient creates a new UTXOClient instance
 func NewUTXOClient(cfg *config.Config, log *logger.Logger) (*UTXOClient, error) {
-	// Create a new HTTP client with appropriate timeout
-	client := &http.Client{
-		Timeout: time.Second * 30,
+	custodian := cfg.Blockchain.UTXOCustodian
+	if custodian.BaseURL == "" {
+		return nil, fmt.Errorf("UTXO custodian base URL is not configured")
+	}
+
+	// Fall back to defaults for any unset tuning parameters
+	timeout := custodian.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	maxRetries := custodian.MaxRetries
+	if maxRetries < 0 {
+		maxRetries = 0
+	} else if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	baseDelay := custodian.RetryBaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultRetryBaseDelay
+	}
+	threshold := custodian.BreakerThreshold
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	cooldown := custodian.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
 	}
 
 	// Create and return a new UTXOClient instance with the HTTP client, base URL, API key, and logger
-	return &UTXOClient{
-		client:  client,
-		baseURL: cfg.UTXOCustodianBaseURL,
-		apiKey:  cfg.UTXOCustodianAPIKey,
-		log:     log,
-	}, nil
+	c := &UTXOClient{
+		client:     &http.Client{Timeout: timeout},
+		baseURL:    strings.TrimRight(custodian.BaseURL, "/"),
+		apiKey:     custodian.APIKey,
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		breaker:    newCircuitBreaker(threshold, cooldown),
+		log:        log,
+	}
+	if custodian.HMACSecret != "" {
+		c.hmacSecret = []byte(custodian.HMACSecret)
+	}
+	return c, nil
 }
 
 // GetUTXOs retrieves UTXOs for a given address
 func (c *UTXOClient) GetUTXOs(ctx context.Context, address string) ([]UTXO, error) {
-	// Construct the API endpoint URL
-	url := fmt.Sprintf("%s/utxos?address=%s", c.baseURL, address)
-
-	// Create a new HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var utxos []UTXO
+	path := "/utxos?address=" + url.QueryEscape(address)
+	if err := c.do(ctx, http.MethodGet, path, nil, http.StatusOK, &utxos); err != nil {
+		return nil, err
 	}
+	return utxos, nil
+}
 
-	// Set the API key in the request header
-	req.Header.Set("X-API-Key", c.apiKey)
+// CreateTransaction creates a new transaction using UTXOs
+func (c *UTXOClient) CreateTransaction(ctx context.Context, req *TransactionRequest) (*Transaction, error) {
+	var transaction Transaction
+	if err := c.do(ctx, http.MethodPost, "/transactions", req, http.StatusCreated, &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
 
-	// Send the HTTP request
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+// PreviewFee asks the custodian to estimate the fee for a payment without creating it
+func (c *UTXOClient) PreviewFee(ctx context.Context, req *FeePreviewRequest) (*FeePreview, error) {
+	var preview FeePreview
+	if err := c.do(ctx, http.MethodPost, "/transactions/preview", req, http.StatusOK, &preview); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &preview, nil
+}
 
-	// Check for HTTP errors
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// GetTransactionStatus checks the status of a transaction
+func (c *UTXOClient) GetTransactionStatus(ctx context.Context, txID string) (*TransactionStatus, error) {
+	var status TransactionStatus
+	path := fmt.Sprintf("/transactions/%s/status", url.PathEscape(txID))
+	if err := c.do(ctx, http.MethodGet, path, nil, http.StatusOK, &status); err != nil {
+		return nil, err
 	}
+	return &status, nil
+}
 
-	// Decode the JSON response into a slice of UTXOs
-	var utxos []UTXO
-	if err := json.NewDecoder(resp.Body).Decode(&utxos); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+// SignPSBT asks the custodian to sign a locally built PSBT and returns the signed PSBT
+func (c *UTXOClient) SignPSBT(ctx context.Context, psbt string) (string, error) {
+	var signed struct {
+		PSBT string `json:"psbt"`
 	}
+	if err := c.do(ctx, http.MethodPost, "/psbt/sign", map[string]string{"psbt": psbt}, http.StatusOK, &signed); err != nil {
+		return "", err
+	}
+	return signed.PSBT, nil
+}
 
-	// Return the UTXOs
-	return utxos, nil
+// BroadcastTransaction asks the custodian to broadcast a fully signed raw transaction and returns its txid
+func (c *UTXOClient) BroadcastTransaction(ctx context.Context, rawTx string) (string, error) {
+	var broadcast struct {
+		TxID string `json:"txid"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/transactions/broadcast", map[string]string{"hex": rawTx}, http.StatusOK, &broadcast); err != nil {
+		return "", err
+	}
+	return broadcast.TxID, nil
 }
 
-// CreateTransaction creates a new transaction using UTXOs
-func (c *UTXOClient) CreateTransaction(ctx context.Context, req *TransactionRequest) (*Transaction, error) {
-	// Construct the API endpoint URL
-	url := fmt.Sprintf("%s/transactions", c.baseURL)
+// do sends a request to the custodian and decodes the response into out. Only
+// idempotent (GET) requests are retried, so a transaction is never created twice
+func (c *UTXOClient) do(ctx context.Context, method, path string, body interface{}, expectedStatus int, out interface{}) error {
+	// Marshal the request body once so every attempt sends the same bytes
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
 
-	// Marshal the transaction request into JSON
-	payload, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	attempts := 1
+	if method == http.MethodGet {
+		attempts += c.maxRetries
 	}
 
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		// Wait before retrying, honouring any Retry-After from the previous response
+		if attempt > 0 {
+			delay := c.backoff(attempt)
+			var custodianErr *CustodianError
+			if errors.As(lastErr, &custodianErr) && custodianErr.retryAfter > 0 {
+				delay = custodianErr.retryAfter
+			}
+			if delay > maxRetryDelay {
+				delay = maxRetryDelay
+			}
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		// Short-circuit while the custodian is failing
+		if !c.breaker.allow() {
+			return ErrCircuitOpen
+		}
+
+		lastErr = c.send(ctx, method, path, payload, expectedStatus, out)
+		if lastErr == nil {
+			c.breaker.success()
+			return nil
+		}
+
+		// Client errors are final and say nothing about the custodian's health
+		if !isTemporary(lastErr) {
+			c.breaker.success()
+			return lastErr
+		}
+		c.breaker.failure()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.log.Error("UTXO custodian request failed", "error", lastErr, "method", method, "path", path, "attempt", attempt+1)
+	}
+
+	return lastErr
+}
+
+// send performs a single attempt of a custodian request
+func (c *UTXOClient) send(ctx context.Context, method, path string, payload []byte, expectedStatus int, out interface{}) error {
 	// Create a new HTTP request with the JSON payload
-	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Set the API key in the request header
+	// Set the API key and, when configured, the request signature
 	req.Header.Set("X-API-Key", c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.hmacSecret != nil {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Signature", SignRequest(c.hmacSecret, timestamp, method, path, payload))
+	}
 
 	// Send the HTTP request
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+		return &temporaryError{err: fmt.Errorf("failed to send request: %w", err)}
 	}
 	defer resp.Body.Close()
 
-	// Check for HTTP errors
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	// Parse error responses into typed errors
+	if resp.StatusCode != expectedStatus {
+		return parseCustodianError(resp)
 	}
 
-	// Decode the JSON response into a Transaction struct
-	var transaction Transaction
-	if err := json.NewDecoder(resp.Body).Decode(&transaction); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// Decode the JSON response
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
 	}
-
-	// Return the created transaction
-	return &transaction, nil
+	return nil
 }
 
-// GetTransactionStatus checks the status of a transaction
-func (c *UTXOClient) GetTransactionStatus(ctx context.Context, txID string) (*TransactionStatus, error) {
-	// Construct the API endpoint URL
-	url := fmt.Sprintf("%s/transactions/%s/status", c.baseURL, txID)
-
-	// Create a new HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// backoff returns the delay before a retry using exponential backoff with full jitter
+func (c *UTXOClient) backoff(attempt int) time.Duration {
+	ceiling := c.baseDelay << uint(attempt-1)
+	if ceiling <= 0 || ceiling > maxRetryDelay {
+		ceiling = maxRetryDelay
 	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
 
-	// Set the API key in the request header
-	req.Header.Set("X-API-Key", c.apiKey)
+// SignRequest computes the hex-encoded HMAC-SHA256 signature of a custodian request
+// over the timestamp, method, path and body
+func SignRequest(secret []byte, timestamp, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte(method))
+	mac.Write([]byte(path))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
-	// Send the HTTP request
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
+// parseCustodianError reads an error response body into a CustodianError
+func parseCustodianError(resp *http.Response) error {
+	custodianErr := &CustodianError{StatusCode: resp.StatusCode}
 
-	// Check for HTTP errors
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	// Bodies that are not JSON are kept as the message
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err := json.Unmarshal(raw, custodianErr); err != nil || (custodianErr.Code == "" && custodianErr.Message == "") {
+		custodianErr.Message = strings.TrimSpace(string(raw))
+	}
+	if custodianErr.Message == "" {
+		custodianErr.Message = http.StatusText(resp.StatusCode)
 	}
 
-	// Decode the JSON response into a TransactionStatus struct
-	var status TransactionStatus
-	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	// Record how long the custodian asked us to wait
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		custodianErr.retryAfter = time.Duration(seconds) * time.Second
 	}
+	return custodianErr
+}
 
-	// Return the transaction status
-	return &status, nil
+// temporaryError wraps transport failures that may succeed on retry
+type temporaryError struct {
+	err error
+}
+
+func (e *temporaryError) Error() string   { return e.err.Error() }
+func (e *temporaryError) Unwrap() error   { return e.err }
+func (e *temporaryError) Temporary() bool { return true }
+
+// isTemporary reports whether an error may succeed if the request is retried
+func isTemporary(err error) bool {
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
 }
 
 // Human tasks:
-// - Implement unit tests for the UTXOClient struct and its methods
 // - Add support for pagination in the GetUTXOs method
-// - Implement error handling and retries for HTTP requests
-// - Add support for cancellation and timeouts using context
-// - Implement rate limiting to comply with UTXO custodian service limits
+// - Implement client-side rate limiting to comply with UTXO custodian service limits
 // - Add support for batch operations (e.g., creating multiple transactions)
-// - Implement logging for all API calls and responses
+// - Send idempotency keys so transaction creation can be retried safely
 // - Add support for different UTXO types (e.g., Bitcoin, Litecoin)
 // - Implement a method to estimate transaction fees
-// - Add support for webhook notifications for transaction status changes
\ No newline at end of file
+// - Add support for webhook notifications for transaction status changes
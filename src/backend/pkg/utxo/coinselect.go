@@ -0,0 +1,334 @@
+package utxo
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+const (
+	// txOverheadVBytes covers version, locktime, input/output counts and the segwit marker
+	txOverheadVBytes = 11
+
+	// changeOutputVBytes is the size of the P2WPKH change output created by the selector
+	changeOutputVBytes = 31
+
+	// changeSpendVBytes is the size of the input that will later spend the change output
+	changeSpendVBytes = 68
+
+	// defaultDustThreshold is the smallest output value, in satoshis, relayed by default policy
+	defaultDustThreshold = 546
+
+	// maxBnBTries bounds the branch-and-bound search
+	maxBnBTries = 100000
+
+	// knapsackIterations is the number of random passes made by the knapsack fallback
+	knapsackIterations = 1000
+)
+
+var (
+	// ErrInsufficientFunds is returned when the available UTXOs cannot cover the outputs and fee
+	ErrInsufficientFunds = errors.New("insufficient funds for outputs and fee")
+
+	// ErrDustOutput is returned when a requested output is below the dust threshold
+	ErrDustOutput = errors.New("output amount is below the dust threshold")
+
+	// ErrNoOutputs is returned when a selection is requested without any outputs
+	ErrNoOutputs = errors.New("at least one output is required")
+)
+
+// Output represents a payment to an address
+type Output struct {
+	Address string `json:"address"`
+	Amount  int64  `json:"amount"`
+}
+
+// SelectionParams configures coin selection
+type SelectionParams struct {
+	// FeeRate is the target fee rate in satoshis per virtual byte
+	FeeRate int64
+	// DustThreshold is the smallest output value allowed; change below it is added to the fee
+	DustThreshold int64
+	// OutputVBytes is the combined size of the requested outputs
+	OutputVBytes int64
+}
+
+// Selection is the result of coin selection
+type Selection struct {
+	Inputs  []UTXO   `json:"inputs"`
+	Outputs []Output `json:"outputs"`
+	Change  int64    `json:"change"`
+	Fee     int64    `json:"fee"`
+	VBytes  int64    `json:"vbytes"`
+}
+
+// coin is a UTXO annotated with its spend cost at the target fee rate
+type coin struct {
+	utxo           UTXO
+	inputVBytes    int64
+	effectiveValue int64
+}
+
+// SelectCoins picks the UTXOs that fund the outputs at the target fee rate, using
+// branch-and-bound to find a changeless selection and falling back to knapsack with change
+func SelectCoins(utxos []UTXO, outputs []Output, params SelectionParams) (*Selection, error) {
+	if len(outputs) == 0 {
+		return nil, ErrNoOutputs
+	}
+	if params.DustThreshold <= 0 {
+		params.DustThreshold = defaultDustThreshold
+	}
+
+	// Sum the requested outputs, rejecting dust
+	var outputTotal int64
+	for _, output := range outputs {
+		if output.Amount < params.DustThreshold {
+			return nil, ErrDustOutput
+		}
+		outputTotal += output.Amount
+	}
+
+	// Compute the effective value of every UTXO, skipping those that cost more to spend than they are worth
+	pool := make([]coin, 0, len(utxos))
+	for _, u := range utxos {
+		size := inputVBytes(u)
+		effective := u.Amount - size*params.FeeRate
+		if effective <= 0 {
+			continue
+		}
+		pool = append(pool, coin{utxo: u, inputVBytes: size, effectiveValue: effective})
+	}
+
+	// The target excludes input fees, which are already deducted from the effective values
+	baseVBytes := txOverheadVBytes + params.OutputVBytes
+	target := outputTotal + baseVBytes*params.FeeRate
+	changeFee := changeOutputVBytes * params.FeeRate
+	costOfChange := changeFee + changeSpendVBytes*params.FeeRate
+
+	// Prefer a changeless selection within the cost of creating and later spending change
+	if selected := branchAndBound(pool, target, costOfChange); selected != nil {
+		return buildSelection(selected, outputs, outputTotal, baseVBytes, 0), nil
+	}
+
+	// Otherwise select with a change output that must itself clear the dust threshold
+	selected := knapsack(pool, target+changeFee+params.DustThreshold)
+	if selected == nil {
+		// Try again without change; any surplus below the dust threshold goes to the fee
+		selected = knapsack(pool, target)
+		if selected == nil {
+			return nil, ErrInsufficientFunds
+		}
+		return buildSelection(selected, outputs, outputTotal, baseVBytes, 0), nil
+	}
+
+	change := sumEffective(selected) - target - changeFee
+	return buildSelection(selected, outputs, outputTotal, baseVBytes+changeOutputVBytes, change), nil
+}
+
+// branchAndBound searches depth-first for the selection whose value lands in
+// [target, target+costOfChange] with the least excess
+func branchAndBound(pool []coin, target, costOfChange int64) []coin {
+	// Explore the largest coins first so the upper bound prunes early
+	sorted := append([]coin(nil), pool...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].effectiveValue > sorted[j].effectiveValue })
+
+	// remaining[i] is the total value available from index i onward
+	remaining := make([]int64, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + sorted[i].effectiveValue
+	}
+	if remaining[0] < target {
+		return nil
+	}
+
+	var best []bool
+	bestExcess := costOfChange + 1
+	current := make([]bool, len(sorted))
+	tries := 0
+
+	var search func(i int, sum int64)
+	search = func(i int, sum int64) {
+		tries++
+		if tries > maxBnBTries || sum > target+costOfChange {
+			return
+		}
+		if sum >= target {
+			if excess := sum - target; excess < bestExcess {
+				bestExcess = excess
+				best = append([]bool(nil), current...)
+			}
+			return
+		}
+		if i == len(sorted) || sum+remaining[i] < target {
+			return
+		}
+
+		// Branch on including the coin, unless an equal coin was just left out, since
+		// including this one instead would only repeat selections already explored
+		if i == 0 || current[i-1] || sorted[i].effectiveValue != sorted[i-1].effectiveValue {
+			current[i] = true
+			search(i+1, sum+sorted[i].effectiveValue)
+			current[i] = false
+		}
+
+		// Branch on leaving the coin out
+		search(i+1, sum)
+	}
+	search(0, 0)
+
+	if best == nil {
+		return nil
+	}
+	selected := make([]coin, 0, len(sorted))
+	for i, include := range best {
+		if include {
+			selected = append(selected, sorted[i])
+		}
+	}
+	return selected
+}
+
+// knapsack approximates the smallest selection covering the target, as in the
+// classic stochastic approximation, and compares it against the smallest single larger coin
+func knapsack(pool []coin, target int64) []coin {
+	var smaller []coin
+	var lowestLarger *coin
+	var smallerTotal int64
+
+	for i := range pool {
+		c := pool[i]
+		switch {
+		case c.effectiveValue == target:
+			return []coin{c}
+		case c.effectiveValue < target:
+			smaller = append(smaller, c)
+			smallerTotal += c.effectiveValue
+		case lowestLarger == nil || c.effectiveValue < lowestLarger.effectiveValue:
+			lowestLarger = &pool[i]
+		}
+	}
+
+	// Every smaller coin together exactly matches the target
+	if smallerTotal == target {
+		return smaller
+	}
+
+	// The smaller coins cannot cover the target, so only a larger coin will do
+	if smallerTotal < target {
+		if lowestLarger == nil {
+			return nil
+		}
+		return []coin{*lowestLarger}
+	}
+
+	// Randomly approximate the best subset of the smaller coins
+	sort.Slice(smaller, func(i, j int) bool { return smaller[i].effectiveValue > smaller[j].effectiveValue })
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	best := make([]bool, len(smaller))
+	for i := range best {
+		best[i] = true
+	}
+	bestTotal := smallerTotal
+
+	included := make([]bool, len(smaller))
+	for iteration := 0; iteration < knapsackIterations && bestTotal != target; iteration++ {
+		for i := range included {
+			included[i] = false
+		}
+		var total int64
+		reached := false
+		for pass := 0; pass < 2 && !reached; pass++ {
+			for i := range smaller {
+				// The first pass includes coins at random, the second fills in the rest
+				if (pass == 0 && rng.Intn(2) == 0) || (pass == 1 && !included[i]) {
+					total += smaller[i].effectiveValue
+					included[i] = true
+					if total >= target {
+						reached = true
+						if total < bestTotal {
+							bestTotal = total
+							copy(best, included)
+						}
+						total -= smaller[i].effectiveValue
+						included[i] = false
+					}
+				}
+			}
+		}
+	}
+
+	// A single larger coin is preferred when it is closer to the target
+	if lowestLarger != nil && lowestLarger.effectiveValue-target <= bestTotal-target {
+		return []coin{*lowestLarger}
+	}
+
+	selected := make([]coin, 0, len(smaller))
+	for i, include := range best {
+		if include {
+			selected = append(selected, smaller[i])
+		}
+	}
+	return selected
+}
+
+// buildSelection assembles the selection result and its fee
+func buildSelection(selected []coin, outputs []Output, outputTotal, baseVBytes, change int64) *Selection {
+	selection := &Selection{
+		Outputs: outputs,
+		Change:  change,
+		VBytes:  baseVBytes,
+	}
+
+	var inputTotal int64
+	for _, c := range selected {
+		selection.Inputs = append(selection.Inputs, c.utxo)
+		selection.VBytes += c.inputVBytes
+		inputTotal += c.utxo.Amount
+	}
+
+	// Whatever is not paid out or returned as change is the fee
+	selection.Fee = inputTotal - outputTotal - change
+	return selection
+}
+
+// sumEffective totals the effective values of the selected coins
+func sumEffective(selected []coin) int64 {
+	var total int64
+	for _, c := range selected {
+		total += c.effectiveValue
+	}
+	return total
+}
+
+// inputVBytes estimates the virtual size of the input spending a UTXO from its script type
+func inputVBytes(u UTXO) int64 {
+	script, err := hex.DecodeString(u.ScriptPubKey)
+	if err != nil || len(script) == 0 {
+		return 68
+	}
+
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy:
+		return 148
+	case txscript.ScriptHashTy:
+		// Assume P2SH-wrapped P2WPKH, the only P2SH form our vaults create
+		return 91
+	case txscript.WitnessV0ScriptHashTy:
+		// Assume a 2-of-3 multisig witness script
+		return 105
+	case txscript.WitnessV1TaprootTy:
+		return 58
+	default:
+		return 68
+	}
+}
+
+// outputVBytes estimates the virtual size of an output paying to a script
+func outputVBytes(script []byte) int64 {
+	// Value (8 bytes) plus the script length prefix and the script itself
+	return int64(8 + 1 + len(script))
+}
@@ -0,0 +1,61 @@
+package utxo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrInvalidAddress is returned when the custodian rejects an address
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrRateLimited is returned when the custodian throttles the client
+	ErrRateLimited = errors.New("rate limited by custodian")
+
+	// ErrCircuitOpen is returned when requests are short-circuited after repeated custodian failures
+	ErrCircuitOpen = errors.New("custodian circuit breaker is open")
+)
+
+// CustodianError represents an error response returned by the UTXO custodian service
+type CustodianError struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+
+	retryAfter time.Duration
+}
+
+// Error implements the error interface for CustodianError
+func (e *CustodianError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("custodian error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("custodian error %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the custodian error code to the matching sentinel error so callers can use errors.Is
+func (e *CustodianError) Unwrap() error {
+	switch e.Code {
+	case "insufficient_funds":
+		return ErrInsufficientFunds
+	case "invalid_address":
+		return ErrInvalidAddress
+	case "rate_limited":
+		return ErrRateLimited
+	}
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *CustodianError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Human tasks:
+// - Map further custodian error codes (e.g. unknown_transaction) once documented
+// - Surface CustodianError codes in API error responses
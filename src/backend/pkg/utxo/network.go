@@ -0,0 +1,22 @@
+package utxo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// NetworkParams returns the chain parameters for a configured network name
+func NetworkParams(network string) (*chaincfg.Params, error) {
+	switch strings.ToLower(network) {
+	case "", "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet", "testnet3":
+		return &chaincfg.TestNet3Params, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("unsupported UTXO network: %s", network)
+	}
+}
@@ -0,0 +1,148 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+)
+
+var (
+	// ErrDigestMismatch is returned when a signed PSBT spends or pays differently than the exported one
+	ErrDigestMismatch = errors.New("signed PSBT does not match the exported transaction")
+
+	// ErrNoSignableInputs is returned when a key controls none of a PSBT's inputs
+	ErrNoSignableInputs = errors.New("key does not control any input of the PSBT")
+)
+
+// ExportPSBT wraps an unsigned PSBT in an offline payload bound to the originating transaction. The digest is
+// the txid of the unsigned transaction, which signing does not change
+func ExportPSBT(unsigned *UnsignedTransaction, transactionID, blockchainType string) (*offline.Payload, error) {
+	packet, err := decodePSBT(unsigned.PSBT)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &offline.Payload{
+		TransactionID:  transactionID,
+		BlockchainType: blockchainType,
+		Format:         offline.FormatPSBT,
+		Data:           unsigned.PSBT,
+		Digest:         packet.UnsignedTx.TxHash().String(),
+	}
+	payload.Seal()
+	return payload, nil
+}
+
+// SignPSBTWithKey adds the key's signature to every P2WPKH and P2PKH input it controls and returns the updated PSBT
+func SignPSBTWithKey(encoded string, key *btcec.PrivateKey) (string, error) {
+	packet, err := decodePSBT(encoded)
+	if err != nil {
+		return "", err
+	}
+	pubKey := key.PubKey().SerializeCompressed()
+	pubKeyHash := btcutil.Hash160(pubKey)
+
+	// Collect the spent outputs so segwit sighashes commit to every input amount
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	prevOuts := make([]*wire.TxOut, len(packet.Inputs))
+	for i := range packet.Inputs {
+		prevOut, err := spentOutput(packet, i)
+		if err != nil {
+			return "", err
+		}
+		prevOuts[i] = prevOut
+		fetcher.AddPrevOut(packet.UnsignedTx.TxIn[i].PreviousOutPoint, prevOut)
+	}
+	sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx, fetcher)
+
+	updater, err := psbt.NewUpdater(packet)
+	if err != nil {
+		return "", err
+	}
+	signed := 0
+	for i, prevOut := range prevOuts {
+		script := prevOut.PkScript
+		var sig []byte
+		switch {
+		case txscript.IsPayToWitnessPubKeyHash(script) && bytes.Equal(script[2:], pubKeyHash):
+			sig, err = txscript.RawTxInWitnessSignature(packet.UnsignedTx, sigHashes, i, prevOut.Value, script, txscript.SigHashAll, key)
+		case txscript.IsPayToPubKeyHash(script) && bytes.Equal(script[3:23], pubKeyHash):
+			sig, err = txscript.RawTxInSignature(packet.UnsignedTx, i, script, txscript.SigHashAll, key)
+		default:
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to sign input %d: %w", i, err)
+		}
+		if _, err := updater.Sign(i, sig, pubKey, nil, nil); err != nil {
+			return "", fmt.Errorf("failed to add signature to input %d: %w", i, err)
+		}
+		signed++
+	}
+	if signed == 0 {
+		return "", ErrNoSignableInputs
+	}
+
+	return packet.B64Encode()
+}
+
+// FinalizePSBT checks that a signed PSBT is the exported transaction, finalizes it and returns the raw transaction hex
+func FinalizePSBT(encoded, digest string) (string, error) {
+	packet, err := decodePSBT(encoded)
+	if err != nil {
+		return "", err
+	}
+	if packet.UnsignedTx.TxHash().String() != digest {
+		return "", ErrDigestMismatch
+	}
+
+	if err := psbt.MaybeFinalizeAll(packet); err != nil {
+		return "", fmt.Errorf("PSBT is not fully signed: %w", err)
+	}
+	tx, err := psbt.Extract(packet)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract transaction: %w", err)
+	}
+
+	var raw bytes.Buffer
+	if err := tx.Serialize(&raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw.Bytes()), nil
+}
+
+// spentOutput returns the output spent by a PSBT input from its witness or non-witness UTXO
+func spentOutput(packet *psbt.Packet, i int) (*wire.TxOut, error) {
+	input := packet.Inputs[i]
+	if input.WitnessUtxo != nil {
+		return input.WitnessUtxo, nil
+	}
+	if input.NonWitnessUtxo != nil {
+		index := packet.UnsignedTx.TxIn[i].PreviousOutPoint.Index
+		if int(index) < len(input.NonWitnessUtxo.TxOut) {
+			return input.NonWitnessUtxo.TxOut[index], nil
+		}
+	}
+	return nil, fmt.Errorf("PSBT input %d has no previous output", i)
+}
+
+// decodePSBT parses a base64 PSBT
+func decodePSBT(encoded string) (*psbt.Packet, error) {
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader([]byte(encoded)), true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PSBT: %w", err)
+	}
+	return packet, nil
+}
+
+// Human tasks:
+// - Sign P2SH-wrapped and multisig witness inputs from BIP32 derivation paths
+// - Check that change outputs pay to vault addresses before signing
@@ -0,0 +1,206 @@
+package utxo
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// ChangeAddressProvider derives fresh vault addresses to receive change
+type ChangeAddressProvider interface {
+	NewChangeAddress(ctx context.Context) (string, error)
+}
+
+// PSBTRequest represents a request to build an unsigned transaction locally
+type PSBTRequest struct {
+	FromAddresses []string `json:"from_addresses"`
+	Outputs       []Output `json:"outputs"`
+	FeeRate       int64    `json:"fee_rate"`
+}
+
+// UnsignedTransaction is a BIP-174 PSBT together with the coin selection that produced it
+type UnsignedTransaction struct {
+	PSBT          string     `json:"psbt"`
+	Selection     *Selection `json:"selection"`
+	ChangeAddress string     `json:"change_address,omitempty"`
+}
+
+// PSBTBuilder selects coins and constructs PSBTs without relying on the custodian
+type PSBTBuilder struct {
+	client        *UTXOClient
+	params        *chaincfg.Params
+	dustThreshold int64
+	log           *logger.Logger
+}
+
+// NewPSBTBuilder creates a new PSBTBuilder instance
+func NewPSBTBuilder(client *UTXOClient, params *chaincfg.Params, dustThreshold int64, log *logger.Logger) *PSBTBuilder {
+	return &PSBTBuilder{
+		client:        client,
+		params:        params,
+		dustThreshold: dustThreshold,
+		log:           log,
+	}
+}
+
+// BuildPSBT gathers the UTXOs of the source addresses, selects coins and builds an unsigned PSBT
+func (b *PSBTBuilder) BuildPSBT(ctx context.Context, req *PSBTRequest, change ChangeAddressProvider) (*UnsignedTransaction, error) {
+	if req.FeeRate <= 0 {
+		return nil, fmt.Errorf("fee rate must be positive")
+	}
+
+	// Resolve the output scripts and their combined size
+	outputScripts := make([][]byte, 0, len(req.Outputs))
+	var outputSize int64
+	for _, output := range req.Outputs {
+		script, err := b.addressScript(output.Address)
+		if err != nil {
+			return nil, err
+		}
+		outputScripts = append(outputScripts, script)
+		outputSize += outputVBytes(script)
+	}
+
+	// Gather the spendable UTXOs of every source address
+	var utxos []UTXO
+	for _, address := range req.FromAddresses {
+		found, err := b.client.GetUTXOs(ctx, address)
+		if err != nil {
+			b.log.Error("Failed to get UTXOs", "address", address, "error", err)
+			return nil, err
+		}
+		utxos = append(utxos, found...)
+	}
+
+	// Select the coins for the outputs at the requested fee rate
+	selection, err := SelectCoins(utxos, req.Outputs, SelectionParams{
+		FeeRate:       req.FeeRate,
+		DustThreshold: b.dustThreshold,
+		OutputVBytes:  outputSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the unsigned transaction outputs, adding change to a fresh vault address
+	txOuts := make([]*wire.TxOut, 0, len(outputScripts)+1)
+	for i, script := range outputScripts {
+		txOuts = append(txOuts, wire.NewTxOut(req.Outputs[i].Amount, script))
+	}
+	result := &UnsignedTransaction{Selection: selection}
+	if selection.Change > 0 {
+		if change == nil {
+			return nil, fmt.Errorf("selection leaves %d in change but no change address provider was given", selection.Change)
+		}
+		result.ChangeAddress, err = change.NewChangeAddress(ctx)
+		if err != nil {
+			b.log.Error("Failed to derive change address", "error", err)
+			return nil, err
+		}
+		script, err := b.addressScript(result.ChangeAddress)
+		if err != nil {
+			return nil, err
+		}
+		txOuts = append(txOuts, wire.NewTxOut(selection.Change, script))
+	}
+
+	// Build the unsigned transaction inputs
+	outPoints := make([]*wire.OutPoint, 0, len(selection.Inputs))
+	sequences := make([]uint32, 0, len(selection.Inputs))
+	for _, input := range selection.Inputs {
+		hash, err := chainhash.NewHashFromStr(input.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UTXO txid %s: %w", input.TxID, err)
+		}
+		outPoints = append(outPoints, wire.NewOutPoint(hash, uint32(input.Vout)))
+		// Signal opt-in replace-by-fee so stuck transactions can be bumped
+		sequences = append(sequences, wire.MaxTxInSequenceNum-2)
+	}
+
+	packet, err := psbt.New(outPoints, txOuts, 2, 0, sequences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PSBT: %w", err)
+	}
+
+	// Attach the previous outputs so signers can verify amounts and scripts
+	for i, input := range selection.Inputs {
+		if err := attachPrevOut(&packet.Inputs[i], input); err != nil {
+			return nil, err
+		}
+	}
+
+	result.PSBT, err = packet.B64Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PSBT: %w", err)
+	}
+
+	return result, nil
+}
+
+// addressScript decodes an address for the configured network into its output script
+func (b *PSBTBuilder) addressScript(address string) ([]byte, error) {
+	addr, err := btcutil.DecodeAddress(address, b.params)
+	if err != nil || !addr.IsForNet(b.params) {
+		return nil, fmt.Errorf("invalid address for %s: %s", b.params.Name, address)
+	}
+	return txscript.PayToAddrScript(addr)
+}
+
+// attachPrevOut sets the witness or non-witness UTXO of a PSBT input. P2SH outputs are nested segwit only when
+// their redeem script is a witness program; without a redeem script they are treated as legacy
+func attachPrevOut(input *psbt.PInput, u UTXO) error {
+	script, err := hex.DecodeString(u.ScriptPubKey)
+	if err != nil || len(script) == 0 {
+		return fmt.Errorf("UTXO %s:%d has no script", u.TxID, u.Vout)
+	}
+
+	// Native segwit inputs only need the spent output
+	if txscript.IsWitnessProgram(script) {
+		input.WitnessUtxo = wire.NewTxOut(u.Amount, script)
+		return nil
+	}
+
+	// P2SH inputs carry their redeem script, which must hash to the output script
+	if txscript.GetScriptClass(script) == txscript.ScriptHashTy && u.RedeemScript != "" {
+		redeemScript, err := hex.DecodeString(u.RedeemScript)
+		if err != nil || len(redeemScript) == 0 {
+			return fmt.Errorf("UTXO %s:%d has an invalid redeem script", u.TxID, u.Vout)
+		}
+		if !bytes.Equal(script[2:22], btcutil.Hash160(redeemScript)) {
+			return fmt.Errorf("redeem script of UTXO %s:%d does not match its output script", u.TxID, u.Vout)
+		}
+		input.RedeemScript = redeemScript
+
+		// Nested segwit inputs only need the spent output
+		if txscript.IsWitnessProgram(redeemScript) {
+			input.WitnessUtxo = wire.NewTxOut(u.Amount, script)
+			return nil
+		}
+	}
+
+	// Legacy inputs, including legacy P2SH, need the full previous transaction
+	raw, err := hex.DecodeString(u.RawTx)
+	if err != nil || len(raw) == 0 {
+		return fmt.Errorf("UTXO %s:%d requires the previous transaction", u.TxID, u.Vout)
+	}
+	prevTx := wire.NewMsgTx(wire.TxVersion)
+	if err := prevTx.Deserialize(bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("invalid previous transaction for %s: %w", u.TxID, err)
+	}
+	input.NonWitnessUtxo = prevTx
+	return nil
+}
+
+// Human tasks:
+// - Add BIP32 derivation paths to inputs and change outputs for hardware signers
+// - Support excluding unconfirmed UTXOs from selection
+// - Add support for a long-term fee rate when computing selection waste
@@ -0,0 +1,68 @@
+package utxo
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNothingToSweep is returned when no UTXO is worth more than the fee to spend it
+var ErrNothingToSweep = errors.New("no UTXOs worth sweeping at the target fee rate")
+
+// SweepParams configures the consolidation of UTXOs into a single output
+type SweepParams struct {
+	// FeeRate is the target fee rate in satoshis per virtual byte
+	FeeRate int64
+	// DustThreshold is the smallest consolidated output allowed
+	DustThreshold int64
+	// OutputVBytes is the size of the destination output; zero assumes P2WPKH
+	OutputVBytes int64
+	// MaxInputs caps the number of inputs in one sweep; zero means no limit
+	MaxInputs int
+}
+
+// PlanSweep consolidates UTXOs into a single changeless output to the destination. UTXOs that cost more
+// to spend than they are worth are left behind, and when inputs are capped the most valuable are swept first
+func PlanSweep(utxos []UTXO, destination string, params SweepParams) (*Selection, error) {
+	if params.DustThreshold <= 0 {
+		params.DustThreshold = defaultDustThreshold
+	}
+	if params.OutputVBytes <= 0 {
+		params.OutputVBytes = changeOutputVBytes
+	}
+
+	// Keep only the UTXOs with a positive effective value
+	pool := make([]coin, 0, len(utxos))
+	for _, u := range utxos {
+		size := inputVBytes(u)
+		effective := u.Amount - size*params.FeeRate
+		if effective <= 0 {
+			continue
+		}
+		pool = append(pool, coin{utxo: u, inputVBytes: size, effectiveValue: effective})
+	}
+	if len(pool) == 0 {
+		return nil, ErrNothingToSweep
+	}
+
+	// Sweep the most valuable coins first when the input count is capped
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].effectiveValue > pool[j].effectiveValue
+	})
+	if params.MaxInputs > 0 && len(pool) > params.MaxInputs {
+		pool = pool[:params.MaxInputs]
+	}
+
+	// Everything except the fee for the overhead and single output goes to the destination
+	baseVBytes := txOverheadVBytes + params.OutputVBytes
+	amount := sumEffective(pool) - baseVBytes*params.FeeRate
+	if amount < params.DustThreshold {
+		return nil, ErrNothingToSweep
+	}
+
+	outputs := []Output{{Address: destination, Amount: amount}}
+	return buildSelection(pool, outputs, amount, baseVBytes, 0), nil
+}
+
+// Human tasks:
+// - Prefer draining whole addresses when the input count is capped, to limit address linkage
+// - Exclude unconfirmed UTXOs from sweeps
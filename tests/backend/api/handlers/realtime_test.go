@@ -0,0 +1,361 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/api/handlers"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/realtime"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// streamLog is an in-memory event log with sequential IDs
+type streamLog struct {
+	mu       sync.Mutex
+	messages []*realtime.Message
+	appended chan struct{}
+	waiting  chan struct{}
+}
+
+func (s *streamLog) Append(ctx context.Context, message *realtime.Message) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := *message
+	stored.ID = strconv.Itoa(len(s.messages)+1) + "-0"
+	s.messages = append(s.messages, &stored)
+	close(s.appended)
+	s.appended = make(chan struct{})
+	return stored.ID, nil
+}
+
+func (s *streamLog) Read(ctx context.Context, after string, count int, block time.Duration) ([]*realtime.Message, error) {
+	seq, _ := strconv.Atoi(strings.TrimSuffix(after, "-0"))
+	for {
+		s.mu.Lock()
+		var messages []*realtime.Message
+		if seq < len(s.messages) {
+			messages = s.messages[seq:]
+		}
+		if len(messages) > count {
+			messages = messages[:count]
+		}
+		appended := s.appended
+		s.mu.Unlock()
+		if len(messages) > 0 || block < 0 {
+			return messages, nil
+		}
+
+		select {
+		case s.waiting <- struct{}{}:
+		default:
+		}
+		select {
+		case <-appended:
+		case <-time.After(block):
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *streamLog) Bounds(ctx context.Context) (string, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.messages) == 0 {
+		return "", "", nil
+	}
+	return s.messages[0].ID, s.messages[len(s.messages)-1].ID, nil
+}
+
+// connectionCounter counts open streams per organization; its leases never expire
+type connectionCounter struct {
+	mu      sync.Mutex
+	streams map[uuid.UUID]map[string]bool
+}
+
+func (cc *connectionCounter) Acquire(ctx context.Context, organizationID uuid.UUID, streamID string, limit int, ttl time.Duration) (bool, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if len(cc.streams[organizationID]) >= limit {
+		return false, nil
+	}
+	if cc.streams[organizationID] == nil {
+		cc.streams[organizationID] = make(map[string]bool)
+	}
+	cc.streams[organizationID][streamID] = true
+	return true, nil
+}
+
+func (cc *connectionCounter) Refresh(ctx context.Context, streams map[uuid.UUID][]string, ttl time.Duration) error {
+	return nil
+}
+
+func (cc *connectionCounter) Release(ctx context.Context, organizationID uuid.UUID, streamID string) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.streams[organizationID], streamID)
+	return nil
+}
+
+// testAPIKey is the plaintext API key test clients connect with
+const testAPIKey = "test-key"
+
+// keyring authenticates API keys that can be rescoped or revoked while a stream is open
+type keyring struct {
+	mu   sync.Mutex
+	org  *models.Organization
+	keys map[string]*models.APIKey
+}
+
+func (k *keyring) Authenticate(ctx context.Context, rawKey string) (*models.APIKey, *models.Organization, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[rawKey]
+	if !ok {
+		return nil, nil, errors.NewUnauthorizedError("Invalid API key")
+	}
+	resolved := *key
+	return &resolved, k.org, nil
+}
+
+// scope replaces the test key's scopes, creating the key if it was revoked
+func (k *keyring) scope(scopes ...string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[testAPIKey] = &models.APIKey{ID: uuid.New(), OrganizationID: k.org.ID, Scopes: scopes}
+}
+
+// revoke removes the test key
+func (k *keyring) revoke() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, testAPIKey)
+}
+
+// apiKeyHeader carries the test key on requests
+func apiKeyHeader() http.Header {
+	return http.Header{"X-API-Key": []string{testAPIKey}}
+}
+
+// setupRealtimeServer serves the WebSocket and SSE endpoints to an API key scoped to read transactions
+func setupRealtimeServer(t *testing.T, orgID uuid.UUID, cfg config.RealtimeConfig) (*realtime.Service, *httptest.Server, *keyring) {
+	log := &streamLog{appended: make(chan struct{}), waiting: make(chan struct{}, 1)}
+	service := realtime.NewService(cfg, log, &connectionCounter{streams: make(map[uuid.UUID]map[string]bool)}, logger.NewLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+	<-log.waiting
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	keys := &keyring{org: &models.Organization{ID: orgID}, keys: make(map[string]*models.APIKey)}
+	keys.scope(models.PermissionTxRead)
+	authenticate := middleware.Authenticate(keys, nil)
+	realtimeHandler := handlers.NewRealtimeHandler(service, nil, keys, nil)
+	router.GET("/ws", authenticate, realtimeHandler.WebSocket)
+	router.GET("/api/v1/events/stream", authenticate, realtimeHandler.EventStream)
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		server.Close()
+		cancel()
+		<-done
+	})
+	return service, server, keys
+}
+
+func dialRealtime(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?"+query, apiKeyHeader())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// openEventStream requests the SSE endpoint, returning a reader of its events
+func openEventStream(t *testing.T, server *httptest.Server, query, lastEventID string) (*http.Response, *bufio.Reader) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/v1/events/stream?"+query, nil)
+	require.NoError(t, err)
+	req.Header = apiKeyHeader()
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+// readServerSentEvent reads the next SSE event, returning its ID, name and frame
+func readServerSentEvent(t *testing.T, reader *bufio.Reader) (string, string, models.RealtimeFrame) {
+	var id, name string
+	var frame models.RealtimeFrame
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return id, name, frame
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &frame))
+		}
+	}
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) models.RealtimeFrame {
+	var frame models.RealtimeFrame
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	require.NoError(t, conn.ReadJSON(&frame))
+	return frame
+}
+
+func TestWebSocketStreamsAuthorizedChannels(t *testing.T) {
+	orgID := uuid.New()
+	service, server, _ := setupRealtimeServer(t, orgID, config.RealtimeConfig{})
+
+	conn := dialRealtime(t, server, "channels=transactions")
+	frame := readFrame(t, conn)
+	assert.Equal(t, models.RealtimeFrameSubscribed, frame.Type)
+	assert.Equal(t, []string{realtime.ChannelTransactions}, frame.Channels)
+
+	// Channels the key is not scoped for are refused
+	require.NoError(t, conn.WriteJSON(models.RealtimeRequest{Action: models.RealtimeActionSubscribe, Channels: []string{realtime.ChannelSignatures}}))
+	frame = readFrame(t, conn)
+	assert.Equal(t, models.RealtimeFrameError, frame.Type)
+
+	// Published events arrive as event frames carrying their log ID
+	transaction := &models.Transaction{ID: uuid.New(), OrganizationID: orgID, VaultID: uuid.New(), Status: "Submitted"}
+	first := models.NewEvent(orgID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)
+	require.NoError(t, service.Publish(context.Background(), first))
+	frame = readFrame(t, conn)
+	assert.Equal(t, models.RealtimeFrameEvent, frame.Type)
+	require.NotEmpty(t, frame.ID)
+	var event models.Event
+	require.NoError(t, json.Unmarshal(frame.Event, &event))
+	assert.Equal(t, first.ID, event.ID)
+
+	// A client reconnecting with the last ID it saw receives the events it missed
+	conn.Close()
+	transaction.Status = "Confirmed"
+	missed := models.NewEvent(orgID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)
+	require.NoError(t, service.Publish(context.Background(), missed))
+	conn = dialRealtime(t, server, "channels=transactions&last_event_id="+frame.ID)
+	assert.Equal(t, models.RealtimeFrameSubscribed, readFrame(t, conn).Type)
+	frame = readFrame(t, conn)
+	require.NoError(t, json.Unmarshal(frame.Event, &event))
+	assert.Equal(t, missed.ID, event.ID)
+}
+
+func TestWebSocketRejectsUnauthorizedChannels(t *testing.T) {
+	_, server, _ := setupRealtimeServer(t, uuid.New(), config.RealtimeConfig{})
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?channels="
+
+	// Failures before the upgrade are answered over HTTP
+	_, resp, err := websocket.DefaultDialer.Dial(url+"signatures", apiKeyHeader())
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"everything", apiKeyHeader())
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	orgID := uuid.New()
+	service, server, _ := setupRealtimeServer(t, orgID, config.RealtimeConfig{})
+
+	resp, reader := openEventStream(t, server, "channels=transactions", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	_, name, frame := readServerSentEvent(t, reader)
+	assert.Equal(t, models.RealtimeFrameSubscribed, name)
+	assert.Equal(t, []string{realtime.ChannelTransactions}, frame.Channels)
+
+	// Events carry their log ID as the SSE event ID
+	transaction := &models.Transaction{ID: uuid.New(), OrganizationID: orgID, VaultID: uuid.New(), Status: "Submitted"}
+	require.NoError(t, service.Publish(context.Background(), models.NewEvent(orgID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)))
+	id, name, frame := readServerSentEvent(t, reader)
+	assert.Equal(t, models.RealtimeFrameEvent, name)
+	assert.Equal(t, frame.ID, id)
+	resp.Body.Close()
+
+	// Reconnecting with Last-Event-ID replays what was missed
+	transaction.Status = "Confirmed"
+	missed := models.NewEvent(orgID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)
+	require.NoError(t, service.Publish(context.Background(), missed))
+	_, reader = openEventStream(t, server, "channels=transactions", id)
+	_, name, _ = readServerSentEvent(t, reader)
+	assert.Equal(t, models.RealtimeFrameSubscribed, name)
+	_, _, frame = readServerSentEvent(t, reader)
+	var event models.Event
+	require.NoError(t, json.Unmarshal(frame.Event, &event))
+	assert.Equal(t, missed.ID, event.ID)
+}
+
+func TestEventStreamLimitsConnections(t *testing.T) {
+	_, server, _ := setupRealtimeServer(t, uuid.New(), config.RealtimeConfig{MaxConnections: 1})
+
+	resp, reader := openEventStream(t, server, "channels=transactions", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	readServerSentEvent(t, reader)
+
+	// The organization's second stream is refused, on either transport
+	resp, _ = openEventStream(t, server, "channels=transactions", "")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	_, wsResp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws?channels=transactions", apiKeyHeader())
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, wsResp.StatusCode)
+
+	resp, _ = openEventStream(t, server, "channels=signatures", "")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestStreamsEndWhenAuthorizationIsRevoked(t *testing.T) {
+	_, server, keys := setupRealtimeServer(t, uuid.New(), config.RealtimeConfig{HeartbeatInterval: 50 * time.Millisecond})
+
+	// A WebSocket is closed on the first heartbeat after its key loses a channel's scope
+	conn := dialRealtime(t, server, "channels=transactions")
+	assert.Equal(t, models.RealtimeFrameSubscribed, readFrame(t, conn).Type)
+	keys.scope(models.PermissionVaultRead)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "expected a policy violation close, got %v", err)
+
+	// An event stream ends with an error frame once its key is revoked
+	keys.scope(models.PermissionTxRead)
+	resp, reader := openEventStream(t, server, "channels=transactions", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	_, name, _ := readServerSentEvent(t, reader)
+	assert.Equal(t, models.RealtimeFrameSubscribed, name)
+	keys.revoke()
+	for name == models.RealtimeFrameSubscribed || name == "" {
+		_, name, _ = readServerSentEvent(t, reader)
+	}
+	assert.Equal(t, models.RealtimeFrameError, name)
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+)
+
+// fakeAuthorizer grants users the permissions of their role name and remembers denials
+type fakeAuthorizer struct {
+	denied []*models.AuditEvent
+}
+
+func (f *fakeAuthorizer) Authorize(ctx context.Context, user *models.User, permission, vaultID string) (bool, error) {
+	return user.Role == permission || user.Role == permission+"@"+vaultID, nil
+}
+
+func (f *fakeAuthorizer) RecordDenied(ctx context.Context, event *models.AuditEvent) {
+	f.denied = append(f.denied, event)
+}
+
+// serve runs a request through a route guarded by a permission, with the given principal already authenticated
+func serve(authorizer *fakeAuthorizer, guard gin.HandlerFunc, principal interface{}) int {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/vault/:id", func(c *gin.Context) {
+		switch p := principal.(type) {
+		case *models.User:
+			c.Set(middleware.ContextUser, p)
+			c.Set(middleware.ContextOrganizationID, p.OrganizationID)
+		case *models.APIKey:
+			c.Set(middleware.ContextAPIKey, p)
+			c.Set(middleware.ContextOrganizationID, p.OrganizationID)
+		}
+	}, guard, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/vault/v1", nil))
+	return recorder.Code
+}
+
+func TestRequirePermission(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	guard := middleware.RequirePermission(authorizer, models.PermissionVaultRead)
+
+	assert.Equal(t, http.StatusOK, serve(authorizer, guard, &models.User{Role: models.PermissionVaultRead}))
+	assert.Equal(t, http.StatusOK, serve(authorizer, guard, &models.APIKey{Scopes: []string{models.PermissionVaultRead}}))
+	assert.Equal(t, http.StatusUnauthorized, serve(authorizer, guard, nil))
+	assert.Empty(t, authorizer.denied)
+
+	// Denials are forbidden and audited with the actor and permission
+	user := &models.User{ID: uuid.New(), OrganizationID: uuid.New(), Role: models.PermissionTxRead}
+	assert.Equal(t, http.StatusForbidden, serve(authorizer, guard, user))
+	assert.Equal(t, http.StatusForbidden, serve(authorizer, guard, &models.APIKey{Scopes: []string{models.PermissionTxRead}}))
+	if assert.Len(t, authorizer.denied, 2) {
+		assert.Equal(t, models.AuditActorUser, authorizer.denied[0].ActorType)
+		assert.Equal(t, user.ID.String(), authorizer.denied[0].ActorID)
+		assert.Equal(t, user.OrganizationID, authorizer.denied[0].OrganizationID)
+		assert.Equal(t, "GET /vault/:id", authorizer.denied[0].Resource)
+		assert.Equal(t, models.AuditActorAPIKey, authorizer.denied[1].ActorType)
+	}
+}
+
+func TestRequireVaultPermission(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+	guard := middleware.RequireVaultPermission(authorizer, models.PermissionVaultRead, "id")
+
+	// The vault ID from the route is passed to the authorizer
+	assert.Equal(t, http.StatusOK, serve(authorizer, guard, &models.User{Role: models.PermissionVaultRead + "@v1"}))
+	assert.Equal(t, http.StatusForbidden, serve(authorizer, guard, &models.User{Role: models.PermissionVaultRead + "@v2"}))
+	assert.Equal(t, "v1", authorizer.denied[0].Details["vault_id"])
+}
+
+func TestRequireResolvedVaultPermission(t *testing.T) {
+	authorizer := &fakeAuthorizer{}
+
+	// The permission is checked on the vault of the record the route names
+	guard := middleware.RequireResolvedVaultPermission(authorizer, models.PermissionTxApprove, func(c *gin.Context) (string, error) {
+		return "vault-of-" + c.Param("id"), nil
+	})
+	assert.Equal(t, http.StatusOK, serve(authorizer, guard, &models.User{Role: models.PermissionTxApprove + "@vault-of-v1"}))
+	assert.Equal(t, http.StatusForbidden, serve(authorizer, guard, &models.User{Role: models.PermissionTxApprove + "@v1"}))
+
+	// Records that cannot be resolved fail with the resolver's status instead of being authorized
+	missing := middleware.RequireResolvedVaultPermission(authorizer, models.PermissionTxApprove, func(c *gin.Context) (string, error) {
+		return "", errors.NewNotFoundError("transaction not found")
+	})
+	assert.Equal(t, http.StatusNotFound, serve(authorizer, missing, &models.User{Role: models.PermissionTxApprove}))
+}
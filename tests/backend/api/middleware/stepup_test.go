@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/internal/api/middleware"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/pkg/jwt"
+)
+
+// fixedPolicy requires a second factor within a fixed age
+type fixedPolicy time.Duration
+
+func (p fixedPolicy) StepUpMaxAge() time.Duration {
+	return time.Duration(p)
+}
+
+// serveStepUp runs a request through a step-up guarded route with the given principal already authenticated
+func serveStepUp(principal interface{}) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/transactions/:id/broadcast", func(c *gin.Context) {
+		switch p := principal.(type) {
+		case *jwt.Claims:
+			c.Set(middleware.ContextClaims, p)
+		case *models.APIKey:
+			c.Set(middleware.ContextAPIKey, p)
+		}
+	}, middleware.RequireStepUp(fixedPolicy(5*time.Minute)), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/transactions/t1/broadcast", nil))
+	return recorder
+}
+
+func TestRequireStepUp(t *testing.T) {
+	now := time.Now()
+	stepped := []string{jwt.MethodPassword, jwt.MethodOTP, jwt.MethodMultiFactor}
+
+	// A recent second factor passes
+	assert.Equal(t, http.StatusOK, serveStepUp(&jwt.Claims{AuthMethods: stepped, AuthTime: now.Add(-time.Minute).Unix()}).Code)
+
+	// Password-only and stale step-ups are challenged to step up
+	for _, claims := range []*jwt.Claims{
+		{AuthMethods: []string{jwt.MethodPassword}, AuthTime: now.Unix()},
+		{AuthMethods: stepped, AuthTime: now.Add(-10 * time.Minute).Unix()},
+	} {
+		recorder := serveStepUp(claims)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), `error="insufficient_user_authentication"`)
+		assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "max_age=300")
+	}
+
+	// API keys cannot present a second factor, whatever their scopes; unauthenticated requests are refused
+	assert.Equal(t, http.StatusForbidden, serveStepUp(&models.APIKey{Scopes: []string{models.PermissionTxApprove}}).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveStepUp(nil).Code)
+}
@@ -0,0 +1,123 @@
+package ethereum_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ethcontract "github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+)
+
+const erc20ABI = `[
+	{"type":"function","name":"transfer","stateMutability":"nonpayable","inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`
+
+// rawArgs marshals each argument into its JSON form
+func rawArgs(t *testing.T, args ...interface{}) []json.RawMessage {
+	raw := make([]json.RawMessage, len(args))
+	for i, arg := range args {
+		encoded, err := json.Marshal(arg)
+		require.NoError(t, err)
+		raw[i] = encoded
+	}
+	return raw
+}
+
+func TestParseABIAcceptsSingleFragment(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(`{"type":"function","name":"stake","stateMutability":"payable","inputs":[],"outputs":[]}`))
+
+	require.NoError(t, err)
+	assert.True(t, parsed.Methods["stake"].IsPayable())
+}
+
+func TestEncodeCallMatchesSelectorAndArguments(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(erc20ABI))
+	require.NoError(t, err)
+
+	to := "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"
+	data, err := ethcontract.EncodeCall(parsed, "transfer", rawArgs(t, to, "1000000000000000000"))
+	require.NoError(t, err)
+
+	expected, err := parsed.Pack("transfer", common.HexToAddress(to), big.NewInt(1000000000000000000))
+	require.NoError(t, err)
+	assert.Equal(t, expected, data)
+	assert.Equal(t, "a9059cbb", hex.EncodeToString(data[:4]))
+}
+
+func TestEncodeCallConvertsNestedTypes(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(`{"type":"function","name":"settle","stateMutability":"nonpayable","inputs":[
+		{"name":"ids","type":"uint64[]"},
+		{"name":"ref","type":"bytes32"},
+		{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"price","type":"int128"}]}
+	],"outputs":[]}`))
+	require.NoError(t, err)
+
+	ref := "0x" + hex.EncodeToString(make([]byte, 32))
+	order := map[string]interface{}{"maker": "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", "price": -5}
+	_, err = ethcontract.EncodeCall(parsed, "settle", rawArgs(t, []uint64{1, 2}, ref, order))
+
+	assert.NoError(t, err)
+}
+
+func TestEncodeCallRejectsInvalidArguments(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(erc20ABI))
+	require.NoError(t, err)
+
+	_, err = ethcontract.EncodeCall(parsed, "transfer", rawArgs(t, "not-an-address", "1"))
+	assert.Error(t, err)
+
+	_, err = ethcontract.EncodeCall(parsed, "transfer", rawArgs(t, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e", "-1"))
+	assert.Error(t, err)
+
+	_, err = ethcontract.EncodeCall(parsed, "transfer", rawArgs(t, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e"))
+	assert.Error(t, err)
+
+	_, err = ethcontract.EncodeCall(parsed, "approve", nil)
+	assert.Error(t, err)
+}
+
+func TestDecodeOutputs(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(erc20ABI))
+	require.NoError(t, err)
+
+	data, err := parsed.Methods["balanceOf"].Outputs.Pack(big.NewInt(42))
+	require.NoError(t, err)
+
+	outputs, err := ethcontract.DecodeOutputs(parsed, "balanceOf", data)
+
+	require.NoError(t, err)
+	require.Len(t, outputs, 1)
+	assert.Equal(t, "balance", outputs[0].Name)
+	assert.Equal(t, "uint256", outputs[0].Type)
+	assert.Equal(t, "42", outputs[0].Value)
+}
+
+func TestDecodeRevertReason(t *testing.T) {
+	parsed, err := ethcontract.ParseABI(json.RawMessage(erc20ABI))
+	require.NoError(t, err)
+
+	// Error(string)
+	stringType, _ := abi.NewType("string", "", nil)
+	reason, err := abi.Arguments{{Type: stringType}}.Pack("insufficient allowance")
+	require.NoError(t, err)
+	errorData := append([]byte{0x08, 0xc3, 0x79, 0xa0}, reason...)
+	assert.Equal(t, "insufficient allowance", ethcontract.DecodeRevertReason(nil, errorData))
+
+	// Panic(uint256)
+	panicData := append([]byte{0x4e, 0x48, 0x7b, 0x71}, common.LeftPadBytes([]byte{0x11}, 32)...)
+	assert.Equal(t, "panic: arithmetic overflow or underflow (0x11)", ethcontract.DecodeRevertReason(nil, panicData))
+
+	// Custom error declared in the ABI
+	customErr := parsed.Errors["InsufficientBalance"]
+	args, err := customErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	customData := append(customErr.ID[:4:4], args...)
+	assert.Equal(t, `InsufficientBalance("1", "2")`, ethcontract.DecodeRevertReason(&parsed, customData))
+}
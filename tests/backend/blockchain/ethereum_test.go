@@ -18,7 +18,9 @@ import (
 func TestNewEthereumClient(t *testing.T) {
 	// Create a mock configuration with Ethereum RPC URL
 	mockConfig := &config.Config{
-		EthereumRPCURL: "https://mainnet.infura.io/v3/YOUR-PROJECT-ID",
+		Blockchain: config.BlockchainConfig{
+			EthereumRPCEndpoints: []string{"https://mainnet.infura.io/v3/YOUR-PROJECT-ID"},
+		},
 	}
 
 	// Create a mock logger
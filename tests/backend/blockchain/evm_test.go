@@ -0,0 +1,123 @@
+package evm_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+)
+
+// signWith returns a SignFunc backed by a local private key
+func signWith(key *ecdsa.PrivateKey) ethereum.SignFunc {
+	return func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	}
+}
+
+func TestBuiltInNetworks(t *testing.T) {
+	tests := map[string]int64{
+		"ethereum":         1,
+		"ethereum-sepolia": 11155111,
+		"polygon":          137,
+		"arbitrum":         42161,
+		"base":             8453,
+		"evm-devnet":       31337,
+	}
+
+	for name, chainID := range tests {
+		network, ok := evm.Lookup(name)
+		require.True(t, ok, name)
+		assert.Equal(t, big.NewInt(chainID), network.ChainID, name)
+		assert.NotEmpty(t, network.NativeSymbol, name)
+		assert.NotZero(t, network.Confirmations, name)
+	}
+
+	assert.False(t, evm.IsNetwork("xrp"))
+}
+
+func TestLoadNetworksMergesConfiguration(t *testing.T) {
+	networks, err := evm.LoadNetworks([]config.EVMNetworkConfig{
+		{Name: "Polygon", RPCEndpoints: []string{"https://polygon.example.com"}, Confirmations: 64},
+		{Name: "evm-test", ChainID: 990001, NativeSymbol: "TST", Testnet: true},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, networks)
+
+	polygon, _ := evm.Lookup("polygon")
+	assert.Equal(t, uint64(64), polygon.Confirmations)
+	assert.Equal(t, "POL", polygon.NativeSymbol)
+	assert.Equal(t, []string{"https://polygon.example.com"}, polygon.RPCEndpoints)
+
+	custom, ok := evm.Lookup("evm-test")
+	require.True(t, ok)
+	assert.Equal(t, big.NewInt(990001), custom.ChainID)
+	assert.True(t, custom.Testnet)
+}
+
+func TestLoadNetworksRejectsInvalidConfiguration(t *testing.T) {
+	_, err := evm.LoadNetworks([]config.EVMNetworkConfig{{Name: "evm-no-chain-id"}})
+	assert.Error(t, err)
+
+	_, err = evm.LoadNetworks([]config.EVMNetworkConfig{{Name: "evm-duplicate", ChainID: 1}})
+	assert.Error(t, err)
+	assert.False(t, evm.IsNetwork("evm-duplicate"))
+}
+
+func TestSignTransactionAppliesEIP155(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	network, _ := evm.Lookup("ethereum-sepolia")
+
+	tx := types.NewTransaction(0, common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	signed, err := ethereum.SignTransaction(tx, network, signWith(key))
+
+	require.NoError(t, err)
+	assert.True(t, signed.Protected())
+	assert.Equal(t, network.ChainID, signed.ChainId())
+
+	sender, err := types.Sender(types.LatestSignerForChainID(network.ChainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+}
+
+func TestSignTransactionRejectsChainIDMismatch(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	network, _ := evm.Lookup("base")
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1),
+		GasTipCap: big.NewInt(1),
+		Value:     big.NewInt(1),
+	})
+	_, err = ethereum.SignTransaction(tx, network, signWith(key))
+
+	assert.Equal(t, ethereum.ErrChainIDMismatch, err)
+}
+
+func TestCheckReplayProtectionRejectsUnprotectedTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	network, _ := evm.Lookup("ethereum")
+
+	tx := types.NewTransaction(0, common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e"), big.NewInt(1), 21000, big.NewInt(1), nil)
+	unprotected, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	require.NoError(t, err)
+
+	assert.Equal(t, ethereum.ErrReplayUnprotected, ethereum.CheckReplayProtection(unprotected, network))
+
+	polygon, _ := evm.Lookup("polygon")
+	signed, err := ethereum.SignTransaction(tx, polygon, signWith(key))
+	require.NoError(t, err)
+	assert.Equal(t, ethereum.ErrChainIDMismatch, ethereum.CheckReplayProtection(signed, network))
+}
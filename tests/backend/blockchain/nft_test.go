@@ -0,0 +1,118 @@
+package ethereum_test
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ethcontract "github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+)
+
+var (
+	nftContract = common.HexToAddress("0x06012c8cf97BEaD5deAe237070F9587f8E7A266d")
+	nftFrom     = common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+	nftTo       = common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+)
+
+// addressTopic left-pads an address into an indexed topic
+func addressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+func TestParseNFTTransfersERC721(t *testing.T) {
+	log := types.Log{
+		Address: nftContract,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			addressTopic(nftFrom),
+			addressTopic(nftTo),
+			common.BigToHash(big.NewInt(42)),
+		},
+		BlockNumber: 100,
+		Index:       3,
+	}
+
+	transfers, err := ethcontract.ParseNFTTransfers(log)
+
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	assert.Equal(t, models.NFTStandardERC721, transfers[0].Standard)
+	assert.Equal(t, nftFrom.Hex(), transfers[0].From)
+	assert.Equal(t, nftTo.Hex(), transfers[0].To)
+	assert.Equal(t, "42", transfers[0].TokenID)
+	assert.Equal(t, "1", transfers[0].Amount)
+	assert.Equal(t, uint64(100), transfers[0].BlockNumber)
+}
+
+func TestParseNFTTransfersIgnoresERC20(t *testing.T) {
+	// ERC-20 Transfer shares the signature but carries the amount in data
+	log := types.Log{
+		Address: nftContract,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")),
+			addressTopic(nftFrom),
+			addressTopic(nftTo),
+		},
+		Data: common.BigToHash(big.NewInt(1000)).Bytes(),
+	}
+
+	transfers, err := ethcontract.ParseNFTTransfers(log)
+
+	require.NoError(t, err)
+	assert.Empty(t, transfers)
+}
+
+func TestParseNFTTransfersERC1155Batch(t *testing.T) {
+	uintArray, _ := abi.NewType("uint256[]", "", nil)
+	data, err := abi.Arguments{{Type: uintArray}, {Type: uintArray}}.Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]*big.Int{big.NewInt(10), big.NewInt(20)},
+	)
+	require.NoError(t, err)
+
+	log := types.Log{
+		Address: nftContract,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])")),
+			addressTopic(nftFrom),
+			addressTopic(nftFrom),
+			addressTopic(nftTo),
+		},
+		Data: data,
+	}
+
+	transfers, err := ethcontract.ParseNFTTransfers(log)
+
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	for i, transfer := range transfers {
+		assert.Equal(t, models.NFTStandardERC1155, transfer.Standard)
+		assert.Equal(t, nftTo.Hex(), transfer.To)
+		assert.Equal(t, i, transfer.BatchIndex)
+	}
+	assert.Equal(t, "2", transfers[1].TokenID)
+	assert.Equal(t, "20", transfers[1].Amount)
+}
+
+func TestEncodeSafeTransferFrom(t *testing.T) {
+	data, err := ethcontract.EncodeSafeTransferFrom(models.NFTStandardERC721, nftFrom.Hex(), nftTo.Hex(), big.NewInt(42), big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "42842e0e", hex.EncodeToString(data[:4]))
+
+	data, err = ethcontract.EncodeSafeTransferFrom(models.NFTStandardERC1155, nftFrom.Hex(), nftTo.Hex(), big.NewInt(42), big.NewInt(5))
+	require.NoError(t, err)
+	assert.Equal(t, "f242432a", hex.EncodeToString(data[:4]))
+
+	_, err = ethcontract.EncodeSafeTransferFrom(models.NFTStandardERC721, nftFrom.Hex(), nftTo.Hex(), big.NewInt(42), big.NewInt(2))
+	assert.Error(t, err)
+
+	_, err = ethcontract.EncodeSafeTransferFrom(models.NFTStandardERC1155, nftFrom.Hex(), nftTo.Hex(), big.NewInt(42), big.NewInt(0))
+	assert.Error(t, err)
+}
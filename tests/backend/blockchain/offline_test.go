@@ -0,0 +1,71 @@
+package ethereum_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ethcontract "github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+)
+
+// exportSample encodes an unsigned transfer on mainnet for an offline signer
+func exportSample(t *testing.T) (*models.Transaction, *types.Transaction, evm.Network) {
+	network, ok := evm.Lookup("ethereum")
+	require.True(t, ok)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx := types.NewTx(&types.LegacyTx{Nonce: 7, GasPrice: big.NewInt(1e9), Gas: 21000, To: &to, Value: big.NewInt(1e15)})
+	return &models.Transaction{ID: uuid.New(), BlockchainType: "ethereum"}, tx, network
+}
+
+func TestOfflineRLPRoundTrip(t *testing.T) {
+	transaction, tx, network := exportSample(t)
+	payload, err := ethcontract.EncodeUnsigned(tx, network, transaction)
+	require.NoError(t, err)
+	assert.Equal(t, transaction.ID.String(), payload.TransactionID)
+	assert.NoError(t, payload.Verify())
+
+	// Sign the exported transaction as the offline signer would
+	key, _ := crypto.GenerateKey()
+	signed, err := ethcontract.SignTransaction(tx, network, func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	})
+	require.NoError(t, err)
+	raw, _ := signed.MarshalBinary()
+	payload.Signed = true
+	payload.Data = hexutil.Encode(raw)
+	payload.Seal()
+
+	// The signed transaction matches the exported digest
+	decoded, err := ethcontract.DecodeSigned(payload, network, payload.Digest)
+	require.NoError(t, err)
+	assert.Equal(t, signed.Hash(), decoded.Hash())
+}
+
+func TestOfflineRLPRejectsAlteredTransaction(t *testing.T) {
+	transaction, tx, network := exportSample(t)
+	payload, err := ethcontract.EncodeUnsigned(tx, network, transaction)
+	require.NoError(t, err)
+
+	// Sign a transaction with a different nonce than the one exported
+	altered := types.NewTx(&types.LegacyTx{Nonce: 8, GasPrice: tx.GasPrice(), Gas: tx.Gas(), To: tx.To(), Value: tx.Value()})
+	key, _ := crypto.GenerateKey()
+	signed, err := ethcontract.SignTransaction(altered, network, func(hash []byte) ([]byte, error) {
+		return crypto.Sign(hash, key)
+	})
+	require.NoError(t, err)
+	raw, _ := signed.MarshalBinary()
+	payload.Signed = true
+	payload.Data = hexutil.Encode(raw)
+	payload.Seal()
+
+	_, err = ethcontract.DecodeSigned(payload, network, payload.Digest)
+	assert.Equal(t, ethcontract.ErrDigestMismatch, err)
+}
@@ -0,0 +1,124 @@
+package rpcpool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/rpcpool"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+var errUnavailable = errors.New("endpoint unavailable")
+
+// newTestPool creates a pool whose probe returns the given heads and errors by endpoint index
+func newTestPool(t *testing.T, heads []uint64, probeErrs []error, opts rpcpool.Options) *rpcpool.Pool {
+	urls := make([]string, len(heads))
+	for i := range urls {
+		urls[i] = "https://node" + string(rune('a'+i)) + ".example.com/v3/secret-key"
+	}
+	pool, err := rpcpool.NewPool("test", urls, func(ctx context.Context, i int) (uint64, error) {
+		return heads[i], probeErrs[i]
+	}, opts, logger.NewLogger())
+	require.NoError(t, err)
+	return pool
+}
+
+func TestNewPoolRequiresEndpoints(t *testing.T) {
+	_, err := rpcpool.NewPool("test", nil, nil, rpcpool.Options{}, logger.NewLogger())
+	assert.Equal(t, rpcpool.ErrNoEndpoints, err)
+}
+
+func TestEndpointNamesHideCredentials(t *testing.T) {
+	pool := newTestPool(t, []uint64{1}, []error{nil}, rpcpool.Options{})
+
+	stats := pool.Stats()
+
+	assert.Equal(t, "https://nodea.example.com", stats[0].Endpoint)
+}
+
+func TestDoFailsOverToNextEndpoint(t *testing.T) {
+	pool := newTestPool(t, []uint64{100, 100}, []error{nil, nil}, rpcpool.Options{})
+
+	var tried []int
+	err := pool.Do(context.Background(), func(i int) error {
+		tried = append(tried, i)
+		if len(tried) == 1 {
+			return errUnavailable
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, tried, 2)
+	assert.NotEqual(t, tried[0], tried[1])
+}
+
+func TestDoDoesNotFailOverOnNonRetryableErrors(t *testing.T) {
+	notFound := errors.New("not found")
+	pool := newTestPool(t, []uint64{100, 100}, []error{nil, nil}, rpcpool.Options{
+		Retryable: func(err error) bool { return err != notFound },
+	})
+
+	calls := 0
+	err := pool.Do(context.Background(), func(i int) error {
+		calls++
+		return notFound
+	})
+
+	assert.Equal(t, notFound, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestProbeMarksLaggingAndFailingEndpointsUnhealthy(t *testing.T) {
+	pool := newTestPool(t, []uint64{100, 90, 0}, []error{nil, nil, errUnavailable}, rpcpool.Options{MaxHeadLag: 3})
+
+	pool.Probe(context.Background())
+	stats := pool.Stats()
+
+	assert.True(t, stats[0].Healthy)
+	assert.False(t, stats[1].Healthy)
+	assert.Equal(t, uint64(10), stats[1].HeadLag)
+	assert.False(t, stats[2].Healthy)
+	assert.Equal(t, errUnavailable.Error(), stats[2].LastError)
+
+	// Requests go to the healthy endpoint first
+	var first int
+	pool.Do(context.Background(), func(i int) error {
+		first = i
+		return nil
+	})
+	assert.Equal(t, 0, first)
+}
+
+func TestBroadcastSucceedsIfAnyEndpointAccepts(t *testing.T) {
+	pool := newTestPool(t, []uint64{100, 100, 100}, []error{nil, nil, nil}, rpcpool.Options{BroadcastFanout: 2})
+
+	var mu sync.Mutex
+	sent := map[int]bool{}
+	err := pool.Broadcast(context.Background(), func(i int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent[i] = true
+		if len(sent) == 1 {
+			return errUnavailable
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, sent, 2)
+}
+
+func TestBroadcastFailsWhenEveryEndpointRejects(t *testing.T) {
+	pool := newTestPool(t, []uint64{100, 100}, []error{nil, nil}, rpcpool.Options{})
+
+	err := pool.Broadcast(context.Background(), func(i int) error {
+		return errUnavailable
+	})
+
+	assert.True(t, errors.Is(err, errUnavailable))
+}
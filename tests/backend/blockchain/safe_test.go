@@ -0,0 +1,121 @@
+package ethereum_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ethcontract "github.com/your-repo/blockchain-integration-service/internal/blockchain/ethereum"
+)
+
+var safeAddress = common.HexToAddress("0x3E5c63644E683549055b9Be8653de26E0B4CD36E")
+
+func TestSafeTxHashMatchesEIP712(t *testing.T) {
+	tx := ethcontract.SafeTx{
+		To:    common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e"),
+		Value: big.NewInt(1000000000000000000),
+		Data:  []byte{0xa9, 0x05, 0x9c, 0xbb},
+		Nonce: big.NewInt(7),
+	}
+
+	// Hash the same transaction with the generic EIP-712 implementation
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {{Name: "chainId", Type: "uint256"}, {Name: "verifyingContract", Type: "address"}},
+			"SafeTx": {
+				{Name: "to", Type: "address"}, {Name: "value", Type: "uint256"}, {Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"}, {Name: "safeTxGas", Type: "uint256"}, {Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"}, {Name: "gasToken", Type: "address"}, {Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           math.NewHexOrDecimal256(11155111),
+			VerifyingContract: safeAddress.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to": tx.To.Hex(), "value": "1000000000000000000", "data": hexutil.Encode(tx.Data),
+			"operation": "0", "safeTxGas": "0", "baseGas": "0", "gasPrice": "0",
+			"gasToken": common.Address{}.Hex(), "refundReceiver": common.Address{}.Hex(), "nonce": "7",
+		},
+	}
+	expected, _, err := apitypes.TypedDataAndHash(typedData)
+	require.NoError(t, err)
+
+	hash := ethcontract.SafeTxHash(big.NewInt(11155111), safeAddress, tx)
+
+	assert.Equal(t, common.BytesToHash(expected), hash)
+}
+
+func TestSafeSignaturesAreRecoveredAndSorted(t *testing.T) {
+	hash := crypto.Keccak256Hash([]byte("safe transaction"))
+
+	signatures := make(map[common.Address][]byte)
+	for i := 0; i < 3; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		signature, err := crypto.Sign(hash.Bytes(), key)
+		require.NoError(t, err)
+
+		owner, err := ethcontract.RecoverSafeSigner(hash, signature)
+		require.NoError(t, err)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), owner)
+		signatures[owner] = signature
+	}
+
+	packed := ethcontract.EncodeSafeSignatures(signatures)
+
+	require.Len(t, packed, 3*crypto.SignatureLength)
+	var previous common.Address
+	for i := 0; i < 3; i++ {
+		sig := packed[i*crypto.SignatureLength : (i+1)*crypto.SignatureLength]
+		assert.True(t, sig[64] == 27 || sig[64] == 28)
+
+		// Owners must appear in strictly ascending order and V must still recover them
+		owner, err := ethcontract.RecoverSafeSigner(hash, sig)
+		require.NoError(t, err)
+		assert.True(t, bytes.Compare(previous.Bytes(), owner.Bytes()) < 0)
+		previous = owner
+	}
+}
+
+func TestSafeAddressFromReceipt(t *testing.T) {
+	factory := common.HexToAddress("0xa6B71E26C5e0845f74c812102Ca7114b6a896AB2")
+	proxy := common.HexToAddress("0x3E5c63644E683549055b9Be8653de26E0B4CD36E")
+	topic := crypto.Keccak256Hash([]byte("ProxyCreation(address,address)"))
+
+	// Safe v1.3 emits the proxy and singleton addresses in the log data
+	data := append(common.LeftPadBytes(proxy.Bytes(), 32), common.LeftPadBytes(factory.Bytes(), 32)...)
+	legacy := &types.Receipt{Logs: []*types.Log{{Address: factory, Topics: []common.Hash{topic}, Data: data}}}
+	address, err := ethcontract.SafeAddressFromReceipt(legacy, factory)
+	require.NoError(t, err)
+	assert.Equal(t, proxy, address)
+
+	// Safe v1.4 indexes it
+	indexed := &types.Receipt{Logs: []*types.Log{{Address: factory, Topics: []common.Hash{topic, common.BytesToHash(proxy.Bytes())}}}}
+	address, err = ethcontract.SafeAddressFromReceipt(indexed, factory)
+	require.NoError(t, err)
+	assert.Equal(t, proxy, address)
+
+	_, err = ethcontract.SafeAddressFromReceipt(&types.Receipt{}, factory)
+	assert.Error(t, err)
+}
+
+func TestEncodeSafeSetupRejectsUnreachableThreshold(t *testing.T) {
+	owners := []common.Address{common.HexToAddress("0x742d35Cc6634C0532925a3b844Bc454e4438f44e")}
+
+	_, err := ethcontract.EncodeSafeSetup(owners, 2, common.Address{})
+	assert.Error(t, err)
+
+	_, err = ethcontract.EncodeSafeSetup(owners, 1, common.Address{})
+	assert.NoError(t, err)
+}
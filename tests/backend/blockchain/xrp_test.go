@@ -2,12 +2,18 @@ package xrp_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/rubblelabs/ripple/data"
 	"github.com/rubblelabs/ripple/websockets"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
 	"github.com/your-repo/blockchain-integration-service/pkg/blockchain/xrp"
 	"github.com/your-repo/blockchain-integration-service/pkg/config"
 	"github.com/your-repo/blockchain-integration-service/pkg/logger"
@@ -17,7 +23,9 @@ import (
 func TestNewXRPClient(t *testing.T) {
 	// Create a mock configuration with XRP WebSocket URL
 	mockConfig := &config.Config{
-		XRPWebSocketURL: "wss://s.altnet.rippletest.net:51233",
+		Blockchain: config.BlockchainConfig{
+			XRPEndpoints: []string{"wss://s.altnet.rippletest.net:51233"},
+		},
 	}
 
 	// Create a mock logger
@@ -144,6 +152,199 @@ func TestGetTransaction(t *testing.T) {
 	mockWSClient.AssertExpectations(t)
 }
 
+// TestBuildPaymentWithTagsAndMemos tests that tags and memos are carried onto the payment
+func TestBuildPaymentWithTagsAndMemos(t *testing.T) {
+	// Create a sample transaction with a destination tag, source tag and memo
+	destinationTag := uint32(12345)
+	sourceTag := uint32(7)
+	sampleTx := &models.Transaction{
+		FromAddress:    "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		ToAddress:      "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		Amount:         "1000000",
+		DestinationTag: &destinationTag,
+		SourceTag:      &sourceTag,
+		Memos: []models.TransactionMemo{
+			{MemoType: "invoice", MemoData: "INV-001", MemoFormat: "text/plain"},
+		},
+	}
+
+	// Call BuildPayment with the sample transaction
+	payment, err := xrpClient.BuildPayment(sampleTx)
+
+	// Assert that no error was returned
+	assert.NoError(t, err)
+
+	// Assert that the tags and memos were carried onto the payment
+	assert.Equal(t, destinationTag, *payment.DestinationTag)
+	assert.Equal(t, sourceTag, *payment.SourceTag)
+	assert.Len(t, payment.Memos, 1)
+	assert.Equal(t, "INV-001", string(payment.Memos[0].Memo.MemoData))
+}
+
+// TestBuildPaymentWithoutTags tests that tags stay unset when the transaction has none
+func TestBuildPaymentWithoutTags(t *testing.T) {
+	// Create a sample transaction without tags or memos
+	sampleTx := &models.Transaction{
+		FromAddress: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		ToAddress:   "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		Amount:      "1000000",
+	}
+
+	// Call BuildPayment with the sample transaction
+	payment, err := xrpClient.BuildPayment(sampleTx)
+
+	// Assert that the payment has no tags or memos
+	assert.NoError(t, err)
+	assert.Nil(t, payment.DestinationTag)
+	assert.Nil(t, payment.SourceTag)
+	assert.Empty(t, payment.Memos)
+}
+
+// TestValidateMultiSigConfig tests validation of vault signer lists
+func TestValidateMultiSigConfig(t *testing.T) {
+	vaultAccount := "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+	// A 2-of-3 signer list is accepted
+	validConfig := &models.MultiSigConfig{
+		Quorum: 2,
+		Signers: []models.MultiSigSigner{
+			{Account: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", Weight: 1, Backend: "hsm-a"},
+			{Account: "rN7n7otQDd6FczFgLdSqtcsAUxDkw6fzRH", Weight: 1, Backend: "hsm-b"},
+			{Account: "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh", Weight: 1, Backend: "kms"},
+		},
+	}
+	assert.NoError(t, xrpClient.ValidateMultiSigConfig(vaultAccount, validConfig))
+
+	// A quorum that the signer weights cannot reach is rejected
+	unreachable := &models.MultiSigConfig{
+		Quorum:  3,
+		Signers: validConfig.Signers[:2],
+	}
+	assert.Error(t, xrpClient.ValidateMultiSigConfig(vaultAccount, unreachable))
+
+	// The vault account cannot sign for itself
+	selfSigner := &models.MultiSigConfig{
+		Quorum:  1,
+		Signers: []models.MultiSigSigner{{Account: vaultAccount, Weight: 1}},
+	}
+	assert.Error(t, xrpClient.ValidateMultiSigConfig(vaultAccount, selfSigner))
+
+	// Two signers on one backend are rejected, as that backend could meet the quorum alone
+	sharedBackend := &models.MultiSigConfig{
+		Quorum: 2,
+		Signers: []models.MultiSigSigner{
+			validConfig.Signers[0],
+			{Account: "rN7n7otQDd6FczFgLdSqtcsAUxDkw6fzRH", Weight: 1, Backend: "hsm-a"},
+		},
+	}
+	assert.Error(t, xrpClient.ValidateMultiSigConfig(vaultAccount, sharedBackend))
+}
+
+// TestCombineSignaturesRequiresQuorum tests that partial signatures are only combined once the quorum is met
+func TestCombineSignaturesRequiresQuorum(t *testing.T) {
+	config := &models.MultiSigConfig{
+		Quorum: 2,
+		Signers: []models.MultiSigSigner{
+			{Account: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe", PublicKey: "02" + strings.Repeat("ab", 32), Weight: 1, Backend: "hsm-a"},
+			{Account: "rN7n7otQDd6FczFgLdSqtcsAUxDkw6fzRH", PublicKey: "03" + strings.Repeat("cd", 32), Weight: 1, Backend: "hsm-b"},
+		},
+	}
+	payment := &data.Payment{
+		Destination: *mustAccount(t, "rEb8TK3gBgk5auZkwc6sHnwrGVJH8DuaLh"),
+		Amount:      *data.NewAmount(1000000),
+	}
+	transactionID := uuid.New()
+
+	// partial builds a completed signature request over the payment for one signer
+	partial := func(signer string) *models.SignatureRequest {
+		payload, err := xrpClient.MultiSigningData(payment, signer)
+		assert.NoError(t, err)
+		return &models.SignatureRequest{
+			ID:            uuid.New(),
+			Status:        models.SignatureStatusCompleted,
+			DataToSign:    hex.EncodeToString(payload),
+			Signature:     "3045",
+			TransactionID: &transactionID,
+		}
+	}
+
+	// Only one of the two required signatures has completed
+	second := partial(config.Signers[1].Account)
+	second.Status = models.SignatureStatusPending
+	requests := []*models.SignatureRequest{partial(config.Signers[0].Account), second}
+	assert.Error(t, xrpClient.CombineSignatures(payment, transactionID, config, requests))
+
+	// A signer's duplicate signature does not count twice towards the quorum
+	requests[1] = partial(config.Signers[0].Account)
+	assert.Error(t, xrpClient.CombineSignatures(payment, transactionID, config, requests))
+
+	// Signatures collected for another transaction are refused
+	requests[1] = partial(config.Signers[1].Account)
+	otherID := uuid.New()
+	requests[1].TransactionID = &otherID
+	assert.Error(t, xrpClient.CombineSignatures(payment, transactionID, config, requests))
+
+	// Once both signers have signed this transaction the signatures are attached
+	requests[1] = partial(config.Signers[1].Account)
+	assert.NoError(t, xrpClient.CombineSignatures(payment, transactionID, config, requests))
+	assert.Len(t, payment.Signers, 2)
+}
+
+// TestPreimageCondition tests the DER encoding of PREIMAGE-SHA-256 conditions and fulfillments
+func TestPreimageCondition(t *testing.T) {
+	// Use a fixed 32-byte preimage
+	preimage := []byte(strings.Repeat("a", 32))
+
+	condition, fulfillment := xrpClient.PreimageCondition(preimage)
+
+	// The fulfillment wraps the preimage in the [0] choice
+	assert.Equal(t, "A0228020"+strings.ToUpper(hex.EncodeToString(preimage)), strings.ToUpper(hex.EncodeToString(fulfillment)))
+
+	// The condition carries the SHA-256 fingerprint and a cost equal to the preimage length
+	fingerprint := sha256.Sum256(preimage)
+	assert.Equal(t, "A0258020"+strings.ToUpper(hex.EncodeToString(fingerprint[:]))+"810120", strings.ToUpper(hex.EncodeToString(condition)))
+}
+
+// TestBuildEscrowCreateRequiresReleaseCondition tests that an escrow without a release condition is rejected
+func TestBuildEscrowCreateRequiresReleaseCondition(t *testing.T) {
+	escrow := &models.Escrow{
+		Owner:       "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		Destination: "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe",
+		Amount:      "1000000",
+	}
+
+	// Without a finish time or condition the escrow is rejected
+	_, err := xrpClient.BuildEscrowCreate(escrow, nil)
+	assert.Error(t, err)
+
+	// A time-based escrow is accepted and converted to ripple time
+	finishAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	escrow.FinishAfter = &finishAfter
+	tx, err := xrpClient.BuildEscrowCreate(escrow, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(finishAfter.Unix()-946684800), *tx.FinishAfter)
+}
+
+// TestRequiresDestinationTag tests that payments to RequireDest accounts are refused without a tag
+func TestRequiresDestinationTag(t *testing.T) {
+	// Accounts with the RequireDest flag need a tag; others and unflagged accounts do not
+	requireDest := data.LedgerEntryFlag(0x00020000)
+	other := data.LedgerEntryFlag(0x00100000)
+	assert.True(t, xrpClient.RequiresDestinationTag(&data.AccountInfo{Flags: &requireDest}))
+	assert.False(t, xrpClient.RequiresDestinationTag(&data.AccountInfo{Flags: &other}))
+	assert.False(t, xrpClient.RequiresDestinationTag(&data.AccountInfo{}))
+
+	// The refusal is reported to clients as a bad request
+	assert.Equal(t, 400, xrpClient.ErrDestinationTagRequired.StatusCode)
+}
+
+// mustAccount parses an XRP address or fails the test
+func mustAccount(t *testing.T, address string) *data.Account {
+	account, err := data.NewAccountFromAddress(address)
+	assert.NoError(t, err)
+	return account
+}
+
 // Human tasks:
 // - Implement test cases for error scenarios (e.g., network errors, invalid addresses)
 // - Add tests for XRP-specific features like escrows and payment channels
@@ -0,0 +1,35 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/internal/database"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+)
+
+func TestTenantSettings(t *testing.T) {
+	organizationID := uuid.New()
+
+	// Requests see their own organization's rows
+	org, all := database.TenantSettings(tenant.WithOrganization(context.Background(), organizationID))
+	assert.Equal(t, organizationID.String(), org)
+	assert.Equal(t, "off", all)
+
+	// Background jobs see every organization's rows
+	org, all = database.TenantSettings(tenant.WithSystem(context.Background()))
+	assert.Empty(t, org)
+	assert.Equal(t, "on", all)
+
+	// Unscoped contexts and the nil organization see nothing, and never keep a previous request's organization
+	for _, ctx := range []context.Context{
+		context.Background(),
+		tenant.WithOrganization(context.Background(), uuid.Nil),
+	} {
+		org, all = database.TenantSettings(ctx)
+		assert.Empty(t, org)
+		assert.Equal(t, "off", all)
+	}
+}
@@ -0,0 +1,107 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/mailer"
+)
+
+// smtpServer accepts a single SMTP conversation and returns the received DATA section
+func smtpServer(t *testing.T) (int, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, received
+}
+
+func TestSMTPMailerSendsMessage(t *testing.T) {
+	port, received := smtpServer(t)
+	m, err := mailer.NewSMTPMailer(config.MailerConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = m.Send(ctx, &mailer.Message{To: "alice@example.com", Subject: "Hello", Body: "line one\nline two"})
+	require.NoError(t, err)
+
+	select {
+	case data := <-received:
+		assert.Contains(t, data, "From: noreply@example.com\r\n")
+		assert.Contains(t, data, "To: alice@example.com\r\n")
+		assert.Contains(t, data, "Subject: Hello\r\n")
+		assert.True(t, strings.HasSuffix(data, "\r\n\r\nline one\r\nline two\r\n"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not received")
+	}
+}
+
+func TestSMTPMailerRejectsHeaderInjection(t *testing.T) {
+	m, err := mailer.NewSMTPMailer(config.MailerConfig{Host: "127.0.0.1", Port: 1, From: "noreply@example.com"})
+	require.NoError(t, err)
+
+	err = m.Send(context.Background(), &mailer.Message{To: "alice@example.com\r\nBcc: mallory@example.com", Subject: "Hello"})
+	assert.Equal(t, mailer.ErrInvalidHeader, err)
+	err = m.Send(context.Background(), &mailer.Message{To: "alice@example.com", Subject: "Hello\nBcc: mallory@example.com"})
+	assert.Equal(t, mailer.ErrInvalidHeader, err)
+}
+
+func TestNewSMTPMailerRequiresHostAndSender(t *testing.T) {
+	_, err := mailer.NewSMTPMailer(config.MailerConfig{From: "noreply@example.com"})
+	assert.Error(t, err)
+	_, err = mailer.NewSMTPMailer(config.MailerConfig{Host: "localhost", Port: 1025})
+	assert.Error(t, err)
+	_, err = mailer.NewSMTPMailer(config.MailerConfig{Host: "localhost", From: "noreply@example.com"})
+	assert.NoError(t, err)
+}
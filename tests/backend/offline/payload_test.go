@@ -0,0 +1,63 @@
+package offline_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+)
+
+func samplePayload() *offline.Payload {
+	payload := &offline.Payload{
+		TransactionID:  "5b6f7a8e-3c1d-4c5e-9f1a-2b3c4d5e6f70",
+		BlockchainType: "ethereum",
+		Format:         offline.FormatRLP,
+		Data:           "0xe980843b9aca0082520894000000000000000000000000000000000000dead8080018080",
+		Digest:         "0x2f1d5b3c6a7e8f9011223344556677889900aabbccddeeff0011223344556677",
+	}
+	payload.Seal()
+	return payload
+}
+
+func TestParseJSONAndQR(t *testing.T) {
+	payload := samplePayload()
+
+	// The JSON file form round-trips
+	raw, err := json.Marshal(payload)
+	assert.NoError(t, err)
+	parsed, err := offline.Parse(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, parsed)
+
+	// The QR text form round-trips, ignoring surrounding whitespace from scanners
+	text, err := payload.QR()
+	assert.NoError(t, err)
+	parsed, err = offline.Parse([]byte(text + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, payload, parsed)
+}
+
+func TestChecksumBindsTransactionID(t *testing.T) {
+	// Moving a payload to another transaction invalidates its checksum
+	payload := samplePayload()
+	payload.TransactionID = "00000000-0000-0000-0000-000000000000"
+	assert.Equal(t, offline.ErrChecksumMismatch, payload.Verify())
+
+	// So does altering the data or claiming it is signed
+	payload = samplePayload()
+	payload.Signed = true
+	assert.Equal(t, offline.ErrChecksumMismatch, payload.Verify())
+
+	payload = samplePayload()
+	payload.Data += "00"
+	raw, _ := json.Marshal(payload)
+	_, err := offline.Parse(raw)
+	assert.Equal(t, offline.ErrChecksumMismatch, err)
+}
+
+func TestParseRejectsUnknownVersion(t *testing.T) {
+	payload := samplePayload()
+	payload.Version = offline.Version + 1
+	assert.Equal(t, offline.ErrUnsupportedVersion, payload.Verify())
+}
@@ -0,0 +1,108 @@
+package pagination_test
+
+import (
+	stderrors "errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
+)
+
+var rules = pagination.Rules{
+	SortFields: []string{pagination.SortCreatedAt, pagination.SortAmount},
+	Filters:    []string{pagination.FilterStatus, pagination.FilterVaultID, pagination.FilterCreated, pagination.FilterAmount},
+}
+
+// assertBadRequest checks an error is reported to clients as a 400
+func assertBadRequest(t *testing.T, err error) {
+	t.Helper()
+	var appErr *errors.AppError
+	require.True(t, stderrors.As(err, &appErr), "expected an AppError, got %v", err)
+	assert.Equal(t, 400, appErr.StatusCode)
+}
+
+func TestParseAndValidate(t *testing.T) {
+	vaultID := uuid.New()
+	query := url.Values{
+		"limit":          {"25"},
+		"sort":           {"-amount"},
+		"include_total":  {"true"},
+		"status":         {"Confirmed"},
+		"vault_id":       {vaultID.String()},
+		"created_after":  {"2024-01-01T00:00:00Z"},
+		"created_before": {"2024-02-01T00:00:00Z"},
+		"min_amount":     {"0.5"},
+		"max_amount":     {"10"},
+	}
+	opts, err := pagination.Parse(query)
+	require.NoError(t, err)
+	require.NoError(t, rules.Validate(opts))
+	assert.Equal(t, 25, opts.Limit)
+	assert.Equal(t, pagination.SortAmount, opts.Sort)
+	assert.True(t, opts.Descending)
+	assert.True(t, opts.IncludeTotal)
+	assert.Equal(t, "Confirmed", opts.Filters.Status)
+	assert.Equal(t, vaultID, *opts.Filters.VaultID)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), opts.Filters.CreatedAfter.UTC())
+	assert.Equal(t, "0.5", opts.Filters.MinAmount)
+
+	// Without parameters the endpoint's first sort field is used, newest first, with the default page size
+	opts, err = pagination.Parse(url.Values{})
+	require.NoError(t, err)
+	require.NoError(t, rules.Validate(opts))
+	assert.Equal(t, pagination.DefaultLimit, opts.Limit)
+	assert.Equal(t, pagination.SortCreatedAt, opts.Sort)
+	assert.True(t, opts.Descending)
+}
+
+func TestRejectsInvalidOptions(t *testing.T) {
+	// Malformed parameters fail to parse
+	for _, query := range []string{"limit=ten", "vault_id=treasury", "created_after=yesterday", "include_total=maybe", "cursor=not-a-cursor"} {
+		values, _ := url.ParseQuery(query)
+		_, err := pagination.Parse(values)
+		assertBadRequest(t, err)
+	}
+
+	// Sorts, filters and ranges the endpoint does not allow fail validation
+	for _, query := range []string{
+		"limit=-1",
+		"limit=1000",
+		"sort=name",
+		"blockchain_type=ethereum",
+		"min_amount=-1",
+		"max_amount=1e9",
+		"min_amount=5&max_amount=1",
+		"created_after=2024-02-01T00:00:00Z&created_before=2024-01-01T00:00:00Z",
+	} {
+		values, _ := url.ParseQuery(query)
+		opts, err := pagination.Parse(values)
+		require.NoError(t, err, query)
+		assertBadRequest(t, rules.Validate(opts))
+	}
+}
+
+func TestCursors(t *testing.T) {
+	opts := &pagination.Options{Sort: pagination.SortAmount, Descending: true}
+	require.NoError(t, rules.Validate(opts))
+	id := uuid.New()
+
+	// A cursor round-trips through the cursor parameter
+	next := opts.NextCursor(id, "1.5")
+	resumed, err := pagination.Parse(url.Values{"cursor": {next}, "sort": {"-amount"}})
+	require.NoError(t, err)
+	require.NoError(t, rules.Validate(resumed))
+	assert.Equal(t, &pagination.Cursor{Sort: pagination.SortAmount, Descending: true, Value: "1.5", ID: id}, resumed.Cursor)
+
+	// It only continues the order it was issued for
+	reordered, err := pagination.Parse(url.Values{"cursor": {next}, "sort": {"amount"}})
+	require.NoError(t, err)
+	assert.Equal(t, pagination.ErrInvalidCursor, rules.Validate(reordered))
+
+	_, err = pagination.DecodeCursor(pagination.EncodeCursor(&pagination.Cursor{Sort: pagination.SortAmount}))
+	assert.Equal(t, pagination.ErrInvalidCursor, err)
+}
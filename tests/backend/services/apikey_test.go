@@ -0,0 +1,154 @@
+package apikey_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/apikey"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// memoryKeys is an in-memory API key repository
+type memoryKeys struct {
+	keys map[string]*models.APIKey
+}
+
+func (m *memoryKeys) CreateAPIKey(ctx context.Context, k *models.APIKey) (*models.APIKey, error) {
+	k.ID = uuid.New()
+	k.CreatedAt = time.Now()
+	m.keys[k.ID.String()] = k
+	return k, nil
+}
+
+func (m *memoryKeys) GetAPIKey(ctx context.Context, id string) (*models.APIKey, error) {
+	if k, ok := m.keys[id]; ok {
+		return k, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryKeys) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	for _, k := range m.keys {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryKeys) ListAPIKeys(ctx context.Context, organizationID string) ([]*models.APIKey, error) {
+	var keys []*models.APIKey
+	for _, k := range m.keys {
+		if k.OrganizationID.String() == organizationID {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (m *memoryKeys) UpdateAPIKey(ctx context.Context, k *models.APIKey) (*models.APIKey, error) {
+	m.keys[k.ID.String()] = k
+	return k, nil
+}
+
+func (m *memoryKeys) TouchAPIKey(ctx context.Context, id string, t time.Time) error {
+	m.keys[id].LastUsedAt = &t
+	return nil
+}
+
+// memoryOrgs is an in-memory organization repository
+type memoryOrgs struct {
+	orgs map[string]*models.Organization
+}
+
+func (m *memoryOrgs) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	if o, ok := m.orgs[id]; ok {
+		return o, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryOrgs) UpdateOrganization(ctx context.Context, o *models.Organization) (*models.Organization, error) {
+	m.orgs[o.ID.String()] = o
+	return o, nil
+}
+
+func newService() (*apikey.Service, *memoryKeys, *models.Organization) {
+	org := &models.Organization{ID: uuid.New(), Name: "Acme"}
+	keys := &memoryKeys{keys: map[string]*models.APIKey{}}
+	orgs := &memoryOrgs{orgs: map[string]*models.Organization{org.ID.String(): org}}
+	return apikey.NewService(keys, orgs, logger.NewLogger()), keys, org
+}
+
+func TestIssueAndAuthenticateAPIKey(t *testing.T) {
+	service, keys, org := newService()
+	ctx := context.Background()
+
+	// Issue a key and check that only its hash is stored
+	issued, err := service.IssueAPIKey(ctx, org.ID, &models.APIKeyRequest{Name: "ci", Scopes: []string{models.PermissionTxRead}})
+	require.NoError(t, err)
+	stored := keys.keys[issued.APIKey.ID.String()]
+	assert.NotContains(t, stored.SecretHash, issued.Key[len(issued.Key)-16:])
+
+	// The plaintext key resolves to its organization and scopes, and its use is recorded
+	key, resolved, err := service.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, resolved.ID)
+	assert.True(t, key.HasScope(models.PermissionTxRead))
+	assert.False(t, key.HasScope(models.PermissionTxWrite))
+	assert.NotNil(t, stored.LastUsedAt)
+
+	// A wrong secret with a valid prefix is rejected
+	_, _, err = service.Authenticate(ctx, issued.Key+"x")
+	assert.Equal(t, apikey.ErrInvalidAPIKey, err)
+}
+
+func TestAuthenticateRejectsRevokedAPIKey(t *testing.T) {
+	service, _, org := newService()
+	ctx := context.Background()
+
+	issued, err := service.IssueAPIKey(ctx, org.ID, &models.APIKeyRequest{Name: "ci", Scopes: []string{models.PermissionVaultRead}})
+	require.NoError(t, err)
+	_, _, err = service.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+
+	// Revocation takes effect immediately, even for a key that was just verified
+	_, err = service.RevokeAPIKey(ctx, org.ID, issued.APIKey.ID.String())
+	require.NoError(t, err)
+	_, _, err = service.Authenticate(ctx, issued.Key)
+	assert.Equal(t, apikey.ErrInvalidAPIKey, err)
+
+	// Keys cannot be revoked from another organization
+	_, err = service.RevokeAPIKey(ctx, uuid.New(), issued.APIKey.ID.String())
+	assert.Equal(t, apikey.ErrAPIKeyNotFound, err)
+}
+
+func TestIssueAPIKeyValidation(t *testing.T) {
+	service, _, org := newService()
+	ctx := context.Background()
+
+	_, err := service.IssueAPIKey(ctx, org.ID, &models.APIKeyRequest{Name: "ci"})
+	assert.Error(t, err)
+
+	_, err = service.IssueAPIKey(ctx, org.ID, &models.APIKeyRequest{Name: "ci", Scopes: []string{"vault:everything"}})
+	assert.Error(t, err)
+
+	past := time.Now().Add(-time.Hour)
+	_, err = service.IssueAPIKey(ctx, org.ID, &models.APIKeyRequest{Name: "ci", Scopes: []string{models.PermissionTxRead}, ExpiresAt: &past})
+	assert.Error(t, err)
+}
+
+func TestAuthenticateRejectsMalformedAPIKey(t *testing.T) {
+	service, _, _ := newService()
+
+	for _, raw := range []string{"", "bis_", "bis_abc_secret", "sk_0123456789ab_secret"} {
+		_, _, err := service.Authenticate(context.Background(), raw)
+		assert.Equal(t, apikey.ErrInvalidAPIKey, err, raw)
+	}
+}
@@ -0,0 +1,445 @@
+package auth_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/auth"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/jwt"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryUsers is an in-memory user repository
+type memoryUsers struct {
+	users []*models.User
+}
+
+func (m *memoryUsers) GetUser(ctx context.Context, id string) (*models.User, error) {
+	for _, u := range m.users {
+		if u.ID.String() == id {
+			return u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range m.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) CreateUser(ctx context.Context, u *models.User) (*models.User, error) {
+	u.ID = uuid.New()
+	m.users = append(m.users, u)
+	return u, nil
+}
+
+// memoryMemberships is an in-memory membership repository
+type memoryMemberships struct {
+	memberships []*models.Membership
+}
+
+func (m *memoryMemberships) CreateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	membership.ID = uuid.New()
+	m.memberships = append(m.memberships, membership)
+	return membership, nil
+}
+
+func (m *memoryMemberships) GetMembership(ctx context.Context, organizationID, userID string) (*models.Membership, error) {
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID && membership.UserID.String() == userID {
+			return membership, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryMemberships) ListMemberships(ctx context.Context, userID string) ([]*models.Membership, error) {
+	var memberships []*models.Membership
+	for _, membership := range m.memberships {
+		if membership.UserID.String() == userID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (m *memoryMemberships) ListMembers(ctx context.Context, organizationID string) ([]*models.Membership, error) {
+	var memberships []*models.Membership
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (m *memoryMemberships) UpdateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	return membership, nil
+}
+
+func (m *memoryMemberships) CountActiveMembers(ctx context.Context, organizationID, role string) (int, error) {
+	count := 0
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID && membership.Role == role && membership.Active() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// memoryFactors is an in-memory second factor repository
+type memoryFactors struct {
+	factors       map[string]models.TOTPFactor
+	recoveryCodes map[string]map[string]bool
+}
+
+func (m *memoryFactors) GetTOTPFactor(ctx context.Context, userID string) (*models.TOTPFactor, error) {
+	factor, ok := m.factors[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &factor, nil
+}
+
+func (m *memoryFactors) SaveTOTPFactor(ctx context.Context, factor *models.TOTPFactor) error {
+	m.factors[factor.UserID.String()] = *factor
+	return nil
+}
+
+func (m *memoryFactors) AdvanceTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	factor, ok := m.factors[userID]
+	if !ok || factor.LastUsedStep >= step {
+		return false, nil
+	}
+	factor.LastUsedStep = step
+	m.factors[userID] = factor
+	return true, nil
+}
+
+func (m *memoryFactors) DeleteTOTPFactor(ctx context.Context, userID string) error {
+	if _, ok := m.factors[userID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(m.factors, userID)
+	return nil
+}
+
+func (m *memoryFactors) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	m.recoveryCodes[userID] = map[string]bool{}
+	for _, hash := range hashes {
+		m.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (m *memoryFactors) UseRecoveryCode(ctx context.Context, userID, hash string) (bool, error) {
+	used, ok := m.recoveryCodes[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	m.recoveryCodes[userID][hash] = true
+	return true, nil
+}
+
+// memorySessions is an in-memory session store
+type memorySessions struct {
+	sessions map[string]models.Session
+}
+
+func (m *memorySessions) CreateSession(ctx context.Context, session *models.Session) error {
+	m.sessions[session.ID] = *session
+	return nil
+}
+
+func (m *memorySessions) GetSession(ctx context.Context, id string) (*models.Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, auth.ErrSessionNotFound
+	}
+	return &session, nil
+}
+
+func (m *memorySessions) RotateSession(ctx context.Context, id, oldHash, newHash string, expiresAt time.Time) error {
+	session, ok := m.sessions[id]
+	if !ok {
+		return auth.ErrSessionNotFound
+	}
+	if session.RefreshHash != oldHash {
+		delete(m.sessions, id)
+		return auth.ErrRefreshTokenReused
+	}
+	session.RefreshHash = newHash
+	session.ExpiresAt = expiresAt
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memorySessions) MarkStepUp(ctx context.Context, id string, at time.Time) error {
+	session, ok := m.sessions[id]
+	if !ok {
+		return auth.ErrSessionNotFound
+	}
+	session.StepUpAt = &at
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memorySessions) DeleteSession(ctx context.Context, id string) error {
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *memorySessions) DeleteUserSessions(ctx context.Context, userID string) error {
+	for id, session := range m.sessions {
+		if session.UserID == userID {
+			delete(m.sessions, id)
+		}
+	}
+	return nil
+}
+
+func newService(t *testing.T) (*auth.Service, *memorySessions, *models.User, *memoryMemberships) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &models.User{ID: uuid.New(), Email: "ops@example.com", PasswordHash: string(hash)}
+	memberships := &memoryMemberships{memberships: []*models.Membership{
+		{ID: uuid.New(), UserID: user.ID, OrganizationID: uuid.New(), Role: models.RoleAdmin},
+	}}
+	sessions := &memorySessions{sessions: map[string]models.Session{}}
+
+	service, err := auth.NewService(config.AuthConfig{
+		Issuer:            "https://auth.example.com",
+		Audience:          "blockchain-integration-service",
+		SigningKeys:       []config.SigningKeyConfig{{ID: "k1", PrivateKey: "nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A="}},
+		TOTPEncryptionKey: "3q2+7w0K8kM0eZC3lX1r5bq1w2Y4Vt9pS6hJxN7uA0c=",
+	}, &memoryUsers{users: []*models.User{user}}, memberships,
+		&memoryFactors{factors: map[string]models.TOTPFactor{}, recoveryCodes: map[string]map[string]bool{}}, sessions, logger.NewLogger())
+	require.NoError(t, err)
+	return service, sessions, user, memberships
+}
+
+func TestLoginIssuesValidAccessToken(t *testing.T) {
+	service, _, user, memberships := newService(t)
+	ctx := context.Background()
+
+	tokens, err := service.Login(ctx, &models.LoginRequest{Email: "Ops@Example.com", Password: "correct horse"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", tokens.TokenType)
+
+	// The token and the authenticated user carry the membership the user signed in with
+	claims, authenticated, err := service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, authenticated.ID)
+	assert.Equal(t, memberships.memberships[0].OrganizationID.String(), claims.OrganizationID)
+	assert.Equal(t, memberships.memberships[0].OrganizationID, authenticated.OrganizationID)
+	assert.Equal(t, models.RoleAdmin, authenticated.Role)
+	assert.Equal(t, strings.SplitN(tokens.RefreshToken, ".", 2)[0], claims.SessionID)
+
+	// Wrong passwords and unknown emails fail the same way
+	_, err = service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "wrong"})
+	assert.Equal(t, auth.ErrInvalidCredentials, err)
+	_, err = service.Login(ctx, &models.LoginRequest{Email: "nobody@example.com", Password: "correct horse"})
+	assert.Equal(t, auth.ErrInvalidCredentials, err)
+}
+
+func TestRefreshRotatesAndDetectsReuse(t *testing.T) {
+	service, _, _, _ := newService(t)
+	ctx := context.Background()
+
+	first, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	require.NoError(t, err)
+	second, err := service.Refresh(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+
+	// Replaying the rotated-out token revokes the session, including the newest tokens
+	_, err = service.Refresh(ctx, first.RefreshToken)
+	assert.Equal(t, auth.ErrRefreshTokenReused, err)
+	_, err = service.Refresh(ctx, second.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+	_, _, err = service.AuthenticateAccessToken(ctx, second.AccessToken)
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func TestLogoutAndRevokeAll(t *testing.T) {
+	service, sessions, user, _ := newService(t)
+	ctx := context.Background()
+	login := func() *models.TokenPair {
+		tokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+		require.NoError(t, err)
+		return tokens
+	}
+
+	// Logging out ends one session and its access token immediately
+	laptop, phone := login(), login()
+	require.NoError(t, service.Logout(ctx, laptop.RefreshToken))
+	_, _, err := service.AuthenticateAccessToken(ctx, laptop.AccessToken)
+	assert.Equal(t, auth.ErrInvalidToken, err)
+	_, _, err = service.AuthenticateAccessToken(ctx, phone.AccessToken)
+	assert.NoError(t, err)
+
+	// Revoking all sessions ends the rest
+	login()
+	require.NoError(t, service.RevokeAllSessions(ctx, user.ID.String()))
+	assert.Empty(t, sessions.sessions)
+	_, err = service.Refresh(ctx, phone.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestDeactivatedUserCannotAuthenticate(t *testing.T) {
+	service, _, user, _ := newService(t)
+	ctx := context.Background()
+
+	tokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	require.NoError(t, err)
+
+	deactivated := time.Now()
+	user.DeactivatedAt = &deactivated
+	_, _, err = service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	assert.Equal(t, auth.ErrInvalidToken, err)
+	_, err = service.Refresh(ctx, tokens.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+}
+
+func TestLoginChoosesOrganization(t *testing.T) {
+	service, _, user, memberships := newService(t)
+	ctx := context.Background()
+	first := memberships.memberships[0].OrganizationID
+	second := uuid.New()
+	memberships.memberships = append(memberships.memberships, &models.Membership{ID: uuid.New(), UserID: user.ID, OrganizationID: second, Role: models.RoleViewer})
+
+	// Members of several organizations must choose one
+	_, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	assert.Equal(t, auth.ErrOrganizationRequired, err)
+
+	tokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse", OrganizationID: &second})
+	require.NoError(t, err)
+	claims, authenticated, err := service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, second.String(), claims.OrganizationID)
+	assert.Equal(t, models.RoleViewer, authenticated.Role)
+
+	// Organizations the user does not belong to are refused
+	other := uuid.New()
+	_, err = service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse", OrganizationID: &other})
+	assert.Equal(t, auth.ErrNotMember, err)
+
+	// Deactivating one membership ends its tokens but leaves the other organization usable
+	firstTokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse", OrganizationID: &first})
+	require.NoError(t, err)
+	deactivated := time.Now()
+	memberships.memberships[1].DeactivatedAt = &deactivated
+	_, _, err = service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	assert.Equal(t, auth.ErrInvalidToken, err)
+	_, err = service.Refresh(ctx, tokens.RefreshToken)
+	assert.Equal(t, auth.ErrInvalidRefreshToken, err)
+	_, _, err = service.AuthenticateAccessToken(ctx, firstTokens.AccessToken)
+	assert.NoError(t, err)
+
+	// With one active membership left, no choice is needed
+	_, err = service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	assert.NoError(t, err)
+}
+
+func TestStepUpWithTOTP(t *testing.T) {
+	service, _, user, _ := newService(t)
+	ctx := context.Background()
+
+	tokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	require.NoError(t, err)
+	claims, authenticated, err := service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+
+	// A password sign-in is recorded as such
+	assert.Equal(t, []string{jwt.MethodPassword}, claims.AuthMethods)
+	assert.NotZero(t, claims.AuthTime)
+
+	// Step-up needs a confirmed authenticator
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{Code: "123456"})
+	assert.Equal(t, auth.ErrTOTPNotEnrolled, err)
+	enrollment, err := service.EnrollTOTP(ctx, authenticated)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{Code: "123456"})
+	assert.Equal(t, auth.ErrTOTPNotEnrolled, err)
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	recovery, err := service.ConfirmTOTP(ctx, authenticated, code)
+	require.NoError(t, err)
+	assert.Len(t, recovery.Codes, 10)
+	_, err = service.EnrollTOTP(ctx, authenticated)
+	assert.Equal(t, auth.ErrTOTPAlreadyEnrolled, err)
+
+	// The confirming code cannot be replayed, but the next one steps up
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{Code: code})
+	assert.Equal(t, auth.ErrInvalidSecondFactor, err)
+	next, err := totp.Code(enrollment.Secret, time.Now().Add(totp.Period))
+	require.NoError(t, err)
+	stepped, err := service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{Code: next})
+	require.NoError(t, err)
+
+	steppedClaims, _, err := service.AuthenticateAccessToken(ctx, stepped.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, steppedClaims.HasMethod(jwt.MethodOTP))
+	assert.True(t, steppedClaims.HasMethod(jwt.MethodMultiFactor))
+	assert.Equal(t, claims.SessionID, steppedClaims.SessionID)
+	assert.GreaterOrEqual(t, steppedClaims.AuthTime, claims.AuthTime)
+
+	// Refreshed tokens keep the step-up of their session
+	refreshed, err := service.Refresh(ctx, tokens.RefreshToken)
+	require.NoError(t, err)
+	refreshedClaims, _, err := service.AuthenticateAccessToken(ctx, refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, refreshedClaims.HasMethod(jwt.MethodOTP))
+	assert.Equal(t, steppedClaims.AuthTime, refreshedClaims.AuthTime)
+	assert.Equal(t, user.ID.String(), refreshedClaims.Subject)
+}
+
+func TestStepUpWithRecoveryCode(t *testing.T) {
+	service, _, _, _ := newService(t)
+	ctx := context.Background()
+
+	tokens, err := service.Login(ctx, &models.LoginRequest{Email: "ops@example.com", Password: "correct horse"})
+	require.NoError(t, err)
+	claims, authenticated, err := service.AuthenticateAccessToken(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	enrollment, err := service.EnrollTOTP(ctx, authenticated)
+	require.NoError(t, err)
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	require.NoError(t, err)
+	recovery, err := service.ConfirmTOTP(ctx, authenticated, code)
+	require.NoError(t, err)
+
+	// Recovery codes work once, however they are typed
+	typed := strings.ToUpper(strings.Replace(recovery.Codes[0], "-", " ", 1))
+	stepped, err := service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{RecoveryCode: typed})
+	require.NoError(t, err)
+	assert.NotEmpty(t, stepped.AccessToken)
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{RecoveryCode: recovery.Codes[0]})
+	assert.Equal(t, auth.ErrInvalidSecondFactor, err)
+
+	// Regenerating invalidates the unused codes, and disabling removes the authenticator
+	regenerated, err := service.RegenerateRecoveryCodes(ctx, authenticated)
+	require.NoError(t, err)
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{RecoveryCode: recovery.Codes[1]})
+	assert.Equal(t, auth.ErrInvalidSecondFactor, err)
+	require.NoError(t, service.DisableTOTP(ctx, authenticated))
+	_, err = service.StepUp(ctx, claims, authenticated, &models.StepUpRequest{RecoveryCode: regenerated.Codes[0]})
+	assert.Equal(t, auth.ErrTOTPNotEnrolled, err)
+}
@@ -0,0 +1,363 @@
+package gasstation_test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/gasstation"
+	"github.com/your-repo/blockchain-integration-service/internal/services/transaction"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
+)
+
+const (
+	stationAddress = "0x52908400098527886E0F7030069857D2E4169EE7"
+	vaultAddress   = "0x8617E340B3D01FA5F11F306F4090FD50E238070D"
+
+	// transferCalldata is an ERC-20 transfer, which moves tokens but no native value
+	transferCalldata = "0xa9059cbb0000000000000000000000008617e340b3d01fa5f11f306f4090fd50e238070d0000000000000000000000000000000000000000000000000000000000000001"
+)
+
+func TestTopUpAmount(t *testing.T) {
+	// A shortfall of 1000 wei with a 20% buffer tops up 1200 wei
+	amount, err := gasstation.TopUpAmount(big.NewInt(1500), big.NewInt(500), 20, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1200", amount.String())
+
+	// Fractional buffers round up so the vault is never left short
+	amount, err = gasstation.TopUpAmount(big.NewInt(11), big.NewInt(0), 10, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "13", amount.String())
+
+	// Pending top-ups can leave available funds negative when other transfers are waiting
+	amount, err = gasstation.TopUpAmount(big.NewInt(100), big.NewInt(-50), 0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "150", amount.String())
+
+	// No top-up is needed when the vault can already pay
+	amount, err = gasstation.TopUpAmount(big.NewInt(100), big.NewInt(100), 20, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, amount.Sign())
+}
+
+func TestTopUpAmountExceedsMaximum(t *testing.T) {
+	// Top-ups above the configured maximum are refused rather than capped
+	_, err := gasstation.TopUpAmount(big.NewInt(1000), big.NewInt(0), 20, big.NewInt(1199))
+	assert.Equal(t, gasstation.ErrTopUpTooLarge, err)
+
+	amount, err := gasstation.TopUpAmount(big.NewInt(1000), big.NewInt(0), 20, big.NewInt(1200))
+	assert.NoError(t, err)
+	assert.Equal(t, "1200", amount.String())
+}
+
+// memoryOrganizations is an in-memory organization repository
+type memoryOrganizations struct {
+	organizations map[uuid.UUID]*models.Organization
+}
+
+func (m *memoryOrganizations) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	if org, ok := m.organizations[uuid.MustParse(id)]; ok {
+		return org, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryOrganizations) UpdateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	m.organizations[org.ID] = org
+	return org, nil
+}
+
+// memoryVaults is an in-memory vault repository
+type memoryVaults struct {
+	vaults map[string]*models.Vault
+}
+
+func (m *memoryVaults) CreateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	m.vaults[v.ID.String()] = v
+	return v, nil
+}
+
+func (m *memoryVaults) GetVault(ctx context.Context, id string) (*models.Vault, error) {
+	if v, ok := m.vaults[id]; ok {
+		return v, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryVaults) UpdateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	return m.CreateVault(ctx, v)
+}
+
+func (m *memoryVaults) DeleteVault(ctx context.Context, id string) error {
+	delete(m.vaults, id)
+	return nil
+}
+
+func (m *memoryVaults) ListVaults(ctx context.Context, opts *pagination.Options) ([]*models.Vault, error) {
+	var vaults []*models.Vault
+	for _, v := range m.vaults {
+		vaults = append(vaults, v)
+	}
+	return vaults, nil
+}
+
+func (m *memoryVaults) CountVaults(ctx context.Context, filters *pagination.Filters) (int, error) {
+	return len(m.vaults), nil
+}
+
+func (m *memoryVaults) ListVaultsByBlockchainType(ctx context.Context, blockchainType string) ([]*models.Vault, error) {
+	return m.ListVaults(ctx, nil)
+}
+
+// memoryTransactions is an in-memory transaction repository
+type memoryTransactions struct {
+	mu           sync.Mutex
+	transactions map[string]*models.Transaction
+}
+
+func (m *memoryTransactions) CreateTransaction(ctx context.Context, t *models.Transaction) (*models.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	copied := *t
+	m.transactions[t.ID.String()] = &copied
+	return t, nil
+}
+
+func (m *memoryTransactions) GetTransactionByID(ctx context.Context, id string) (*models.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.transactions[id]; ok {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryTransactions) ListTransactions(ctx context.Context, opts *pagination.Options) ([]*models.Transaction, error) {
+	return m.list(func(t *models.Transaction) bool { return true }), nil
+}
+
+func (m *memoryTransactions) CountTransactions(ctx context.Context, filters *pagination.Filters) (int, error) {
+	return len(m.list(func(t *models.Transaction) bool { return true })), nil
+}
+
+func (m *memoryTransactions) UpdateTransactionStatus(ctx context.Context, id, status string) (*models.Transaction, error) {
+	t, err := m.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Status = status
+	return m.CreateTransaction(ctx, t)
+}
+
+func (m *memoryTransactions) UpdateTransaction(ctx context.Context, t *models.Transaction) (*models.Transaction, error) {
+	return m.CreateTransaction(ctx, t)
+}
+
+// ListPendingTopUps returns every unconfirmed top-up, as the tests fund a single vault
+func (m *memoryTransactions) ListPendingTopUps(ctx context.Context, vaultID string) ([]*models.Transaction, error) {
+	return m.list(func(t *models.Transaction) bool {
+		return t.Purpose == models.TransactionPurposeGasTopUp && t.Status != "Confirmed" && t.Status != "Failed"
+	}), nil
+}
+
+func (m *memoryTransactions) ListAwaitingGas(ctx context.Context) ([]*models.Transaction, error) {
+	return m.list(func(t *models.Transaction) bool { return t.Status == models.TransactionStatusAwaitingGas }), nil
+}
+
+func (m *memoryTransactions) ListPendingSweeps(ctx context.Context, vaultID string) ([]*models.Transaction, error) {
+	return nil, nil
+}
+
+func (m *memoryTransactions) list(match func(t *models.Transaction) bool) []*models.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var transactions []*models.Transaction
+	for _, t := range m.transactions {
+		if match(t) {
+			copied := *t
+			transactions = append(transactions, &copied)
+		}
+	}
+	return transactions
+}
+
+// recordingTransactions stores top-ups without submitting them
+type recordingTransactions struct {
+	repo *memoryTransactions
+}
+
+func (r *recordingTransactions) CreateTransaction(ctx context.Context, t *models.Transaction) (*models.Transaction, error) {
+	t.Status = "Pending"
+	return r.repo.CreateTransaction(ctx, t)
+}
+
+func (r *recordingTransactions) ReleaseTransaction(ctx context.Context, id string) error {
+	_, err := r.repo.UpdateTransactionStatus(ctx, id, "Submitted")
+	return err
+}
+
+func (r *recordingTransactions) UpdateTransactionStatus(ctx context.Context, id, status string) (*models.Transaction, error) {
+	return r.repo.UpdateTransactionStatus(ctx, id, status)
+}
+
+// fakeChain reports a fixed balance and fee, and whether broadcast transactions have confirmed
+type fakeChain struct {
+	balance   *big.Int
+	fee       *big.Int
+	confirmed bool
+}
+
+func (c *fakeChain) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return c.balance, nil
+}
+
+func (c *fakeChain) EstimateFee(ctx context.Context, from, to string, value *big.Int, data []byte) (*big.Int, error) {
+	return c.fee, nil
+}
+
+func (c *fakeChain) GetConfirmations(ctx context.Context, txHash string) (uint64, bool, error) {
+	if c.confirmed {
+		return 12, true, nil
+	}
+	return 0, false, nil
+}
+
+// broadcastingClient submits every transaction with a hash derived from its ID
+type broadcastingClient struct{}
+
+func (broadcastingClient) GenerateAddress(ctx context.Context) (string, error) {
+	return vaultAddress, nil
+}
+func (broadcastingClient) GetBalance(ctx context.Context, address string) (string, error) {
+	return "0", nil
+}
+func (broadcastingClient) SubmitTransaction(ctx context.Context, t *models.Transaction) (string, error) {
+	return "0x" + t.ID.String(), nil
+}
+
+// station is an organization whose gas station funds a token-only vault
+type station struct {
+	ctx          context.Context
+	orgs         *memoryOrganizations
+	vaults       *memoryVaults
+	transactions *memoryTransactions
+	chain        *fakeChain
+	station      *models.Vault
+	vault        *models.Vault
+}
+
+func newStation(t *testing.T) *station {
+	orgID := uuid.New()
+	s := &station{
+		ctx:          tenant.WithOrganization(context.Background(), orgID),
+		vaults:       &memoryVaults{vaults: make(map[string]*models.Vault)},
+		transactions: &memoryTransactions{transactions: make(map[string]*models.Transaction)},
+		chain:        &fakeChain{balance: big.NewInt(0), fee: big.NewInt(1000)},
+	}
+	var err error
+	s.station, err = s.vaults.CreateVault(s.ctx, &models.Vault{OrganizationID: orgID, Name: "station", BlockchainType: "ethereum", Address: stationAddress})
+	require.NoError(t, err)
+	s.vault, err = s.vaults.CreateVault(s.ctx, &models.Vault{OrganizationID: orgID, Name: "tokens", BlockchainType: "ethereum", Address: vaultAddress})
+	require.NoError(t, err)
+	s.orgs = &memoryOrganizations{organizations: map[uuid.UUID]*models.Organization{
+		orgID: {ID: orgID, GasStationVaultID: &s.station.ID},
+	}}
+	return s
+}
+
+func (s *station) service(t *testing.T, transactions gasstation.Transactions) *gasstation.Service {
+	service, err := gasstation.NewService(s.orgs, s.vaults, s.transactions, transactions, map[string]gasstation.Chain{"ethereum": s.chain},
+		config.GasStationConfig{BufferPercent: 20, MaxTopUpWei: "1000000"}, logger.NewLogger())
+	require.NoError(t, err)
+	return service
+}
+
+func TestMaxTopUpIsRequired(t *testing.T) {
+	// Without a maximum a single misestimate could empty the station, so the service refuses to start
+	for _, maxTopUp := range []string{"", "0", "-1", "1e18"} {
+		_, err := gasstation.NewService(nil, nil, nil, nil, nil, config.GasStationConfig{MaxTopUpWei: maxTopUp}, logger.NewLogger())
+		assert.Error(t, err, "MaxTopUpWei %q", maxTopUp)
+	}
+}
+
+func TestTopUpCoversOnlyGas(t *testing.T) {
+	s := newStation(t)
+	service := s.service(t, &recordingTransactions{repo: s.transactions})
+
+	// A token transfer from an empty vault is topped up with its fee plus the buffer
+	transfer := &models.Transaction{ID: uuid.New(), VaultID: s.vault.ID, BlockchainType: "ethereum", ToAddress: stationAddress, Amount: "0", Data: transferCalldata}
+	dependsOn, err := service.EnsureGas(s.ctx, transfer)
+	require.NoError(t, err)
+	require.NotNil(t, dependsOn)
+
+	topUp, err := s.transactions.GetTransactionByID(s.ctx, dependsOn.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionPurposeGasTopUp, topUp.Purpose)
+	assert.Equal(t, s.station.ID, topUp.VaultID)
+	assert.Equal(t, vaultAddress, topUp.ToAddress)
+	assert.Equal(t, "1200", topUp.Amount)
+}
+
+func TestNativeTransfersAreNotToppedUp(t *testing.T) {
+	s := newStation(t)
+	service := s.service(t, &recordingTransactions{repo: s.transactions})
+
+	// The station never funds the value of a transfer, so native transfers pay their own gas
+	transfer := &models.Transaction{ID: uuid.New(), VaultID: s.vault.ID, BlockchainType: "ethereum", ToAddress: stationAddress, Amount: "5000000000000000000"}
+	dependsOn, err := service.EnsureGas(s.ctx, transfer)
+	require.NoError(t, err)
+	assert.Nil(t, dependsOn)
+	assert.Empty(t, s.transactions.transactions)
+}
+
+func TestSubmittedTopUpReleasesTransaction(t *testing.T) {
+	s := newStation(t)
+	transactions := transaction.NewService(s.transactions, s.vaults, broadcastingClient{}, logger.NewLogger())
+	service := s.service(t, transactions)
+	transactions.RegisterGasStation(service)
+
+	// A token transfer from an empty vault waits for a top-up, which is broadcast straight away
+	transfer, err := transactions.CreateTransaction(s.ctx, &models.Transaction{VaultID: s.vault.ID, BlockchainType: "ethereum", ToAddress: stationAddress, Amount: "0", Data: transferCalldata})
+	require.NoError(t, err)
+	var topUp *models.Transaction
+	require.Eventually(t, func() bool {
+		waiting, err := s.transactions.GetTransactionByID(s.ctx, transfer.ID.String())
+		if err != nil || waiting.DependsOnID == nil {
+			return false
+		}
+		topUp, err = s.transactions.GetTransactionByID(s.ctx, waiting.DependsOnID.String())
+		return err == nil && topUp.Status == "Submitted"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "0x"+topUp.ID.String(), topUp.TxHash)
+
+	// The transfer stays held until the top-up confirms
+	require.NoError(t, service.ReleaseReady(s.ctx))
+	waiting, err := s.transactions.GetTransactionByID(s.ctx, transfer.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TransactionStatusAwaitingGas, waiting.Status)
+
+	// Once it confirms the transfer is submitted with its own hash
+	s.chain.confirmed = true
+	require.NoError(t, service.ReleaseReady(s.ctx))
+	released, err := s.transactions.GetTransactionByID(s.ctx, transfer.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Submitted", released.Status)
+	assert.Equal(t, "0x"+transfer.ID.String(), released.TxHash)
+}
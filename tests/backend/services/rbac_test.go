@@ -0,0 +1,351 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/rbac"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// memoryRoles is an in-memory role and role binding repository
+type memoryRoles struct {
+	roles    []*models.Role
+	bindings []*models.RoleBinding
+}
+
+func (m *memoryRoles) CreateRole(ctx context.Context, r *models.Role) (*models.Role, error) {
+	r.ID = uuid.New()
+	m.roles = append(m.roles, r)
+	return r, nil
+}
+
+func (m *memoryRoles) GetRole(ctx context.Context, id string) (*models.Role, error) {
+	for _, r := range m.roles {
+		if r.ID.String() == id {
+			return r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryRoles) GetRoleByName(ctx context.Context, organizationID, name string) (*models.Role, error) {
+	for _, r := range m.roles {
+		if r.OrganizationID.String() == organizationID && r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryRoles) ListRoles(ctx context.Context, organizationID string) ([]*models.Role, error) {
+	var roles []*models.Role
+	for _, r := range m.roles {
+		if r.OrganizationID.String() == organizationID {
+			roles = append(roles, r)
+		}
+	}
+	return roles, nil
+}
+
+func (m *memoryRoles) DeleteRole(ctx context.Context, id string) error {
+	for i, r := range m.roles {
+		if r.ID.String() == id {
+			m.roles = append(m.roles[:i], m.roles[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (m *memoryRoles) CountRoleAssignments(ctx context.Context, organizationID, role string) (int, error) {
+	count := 0
+	for _, b := range m.bindings {
+		if b.OrganizationID.String() == organizationID && b.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *memoryRoles) CreateRoleBinding(ctx context.Context, b *models.RoleBinding) (*models.RoleBinding, error) {
+	b.ID = uuid.New()
+	m.bindings = append(m.bindings, b)
+	return b, nil
+}
+
+func (m *memoryRoles) GetRoleBinding(ctx context.Context, id string) (*models.RoleBinding, error) {
+	for _, b := range m.bindings {
+		if b.ID.String() == id {
+			return b, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryRoles) ListRoleBindings(ctx context.Context, organizationID, userID string) ([]*models.RoleBinding, error) {
+	var bindings []*models.RoleBinding
+	for _, b := range m.bindings {
+		if b.OrganizationID.String() == organizationID && (userID == "" || b.UserID.String() == userID) {
+			bindings = append(bindings, b)
+		}
+	}
+	return bindings, nil
+}
+
+func (m *memoryRoles) DeleteRoleBinding(ctx context.Context, id string) error {
+	for i, b := range m.bindings {
+		if b.ID.String() == id {
+			m.bindings = append(m.bindings[:i], m.bindings[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// memoryAudit is an in-memory audit event repository
+type memoryAudit struct {
+	events []*models.AuditEvent
+}
+
+func (m *memoryAudit) CreateAuditEvent(ctx context.Context, e *models.AuditEvent) (*models.AuditEvent, error) {
+	m.events = append(m.events, e)
+	return e, nil
+}
+
+func (m *memoryAudit) ListAuditEvents(ctx context.Context, organizationID string, limit int) ([]*models.AuditEvent, error) {
+	return m.events, nil
+}
+
+// memberDirectory holds the organization's memberships and vaults
+type memberDirectory struct {
+	memberships []*models.Membership
+	vaults      []*models.Vault
+}
+
+func (m *memberDirectory) GetMembership(ctx context.Context, organizationID, userID string) (*models.Membership, error) {
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID && membership.UserID.String() == userID {
+			return membership, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memberDirectory) GetVault(ctx context.Context, id string) (*models.Vault, error) {
+	for _, vault := range m.vaults {
+		if vault.ID.String() == id {
+			return vault, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// join makes a user an active member of an organization with a vault
+func (m *memberDirectory) join(user *models.User) uuid.UUID {
+	m.memberships = append(m.memberships, &models.Membership{UserID: user.ID, OrganizationID: user.OrganizationID, Role: user.Role})
+	vault := &models.Vault{ID: uuid.New(), OrganizationID: user.OrganizationID}
+	m.vaults = append(m.vaults, vault)
+	return vault.ID
+}
+
+func newService() (*rbac.Service, *memoryAudit, *memberDirectory) {
+	audit, directory := &memoryAudit{}, &memberDirectory{}
+	return rbac.NewService(&memoryRoles{}, audit, directory, directory, logger.NewLogger()), audit, directory
+}
+
+func TestBuiltinRolesAreAdditive(t *testing.T) {
+	service, _, _ := newService()
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	// Every built-in role can do what a viewer can, and an admin can do everything
+	for role := range models.BuiltinRoles {
+		user := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: role}
+		for _, permission := range models.BuiltinRoles[models.RoleViewer] {
+			allowed, err := service.Authorize(ctx, user, permission, "")
+			require.NoError(t, err)
+			assert.True(t, allowed, "%s should have %s", role, permission)
+		}
+	}
+	admin := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleAdmin}
+	for _, permission := range models.Permissions {
+		allowed, err := service.Authorize(ctx, admin, permission, "")
+		require.NoError(t, err)
+		assert.True(t, allowed, permission)
+	}
+
+	// Viewers cannot write, and only approvers and admins can approve
+	viewer := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleViewer}
+	allowed, err := service.Authorize(ctx, viewer, models.PermissionTxWrite, "")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	operator := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleOperator}
+	allowed, err = service.Authorize(ctx, operator, models.PermissionTxApprove, "")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestCustomRolesAndVaultBindings(t *testing.T) {
+	service, _, directory := newService()
+	ctx := context.Background()
+	orgID := uuid.New()
+	user := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleViewer}
+	vaultID := directory.join(user)
+
+	// Built-in role names cannot be redefined, and permissions must exist
+	_, err := service.CreateRole(ctx, orgID, &models.RoleRequest{Name: "admin", Permissions: []string{models.PermissionTxRead}})
+	assert.Error(t, err)
+	_, err = service.CreateRole(ctx, orgID, &models.RoleRequest{Name: "treasurer", Permissions: []string{"vault:everything"}})
+	assert.Error(t, err)
+
+	role, err := service.CreateRole(ctx, orgID, &models.RoleRequest{Name: "Treasurer", Permissions: []string{models.PermissionTxWrite, models.PermissionTxApprove}})
+	require.NoError(t, err)
+	assert.Equal(t, "treasurer", role.Name)
+
+	// A vault binding grants the custom role's permissions on that vault only
+	binding, err := service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: user.ID, Role: "treasurer", VaultID: &vaultID})
+	require.NoError(t, err)
+	allowed, err := service.Authorize(ctx, user, models.PermissionTxApprove, vaultID.String())
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = service.Authorize(ctx, user, models.PermissionTxApprove, uuid.New().String())
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	allowed, err = service.Authorize(ctx, user, models.PermissionTxApprove, "")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	// Bound roles cannot be deleted until they are unbound
+	assert.Equal(t, rbac.ErrRoleInUse, service.DeleteRole(ctx, orgID, role.ID.String()))
+	require.NoError(t, service.UnbindRole(ctx, orgID, binding.ID.String()))
+	require.NoError(t, service.DeleteRole(ctx, orgID, role.ID.String()))
+
+	// Binding unknown roles and touching another organization's bindings fail
+	_, err = service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: user.ID, Role: "treasurer"})
+	assert.Equal(t, rbac.ErrRoleNotFound, err)
+	other, err := service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: user.ID, Role: models.RoleAuditor})
+	require.NoError(t, err)
+	assert.Equal(t, rbac.ErrRoleBindingNotFound, service.UnbindRole(ctx, uuid.New(), other.ID.String()))
+}
+
+func TestRoleNamesIgnoreCase(t *testing.T) {
+	roles, directory := &memoryRoles{}, &memberDirectory{}
+	service := rbac.NewService(roles, &memoryAudit{}, directory, directory, logger.NewLogger())
+	ctx := context.Background()
+	orgID := uuid.New()
+	user := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleViewer}
+	directory.join(user)
+
+	// Roles are bound and validated under the name they were created with, whatever the caller's casing
+	_, err := service.CreateRole(ctx, orgID, &models.RoleRequest{Name: "Treasury", Permissions: []string{models.PermissionTxApprove}})
+	require.NoError(t, err)
+	binding, err := service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: user.ID, Role: " Treasury "})
+	require.NoError(t, err)
+	assert.Equal(t, "treasury", binding.Role)
+	name, err := service.ValidateRole(ctx, orgID, "TREASURY")
+	require.NoError(t, err)
+	assert.Equal(t, "treasury", name)
+	name, err = service.ValidateRole(ctx, orgID, "Admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, name)
+
+	// Bindings stored with other casing still grant their role
+	roles.bindings = []*models.RoleBinding{{ID: uuid.New(), OrganizationID: orgID, UserID: user.ID, Role: "Treasury"}}
+	allowed, err := service.Authorize(ctx, user, models.PermissionTxApprove, "")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestBindRoleRequiresMemberAndVault(t *testing.T) {
+	service, _, directory := newService()
+	ctx := context.Background()
+	orgID := uuid.New()
+	member := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleViewer}
+	vaultID := directory.join(member)
+
+	// Users outside the organization cannot be bound
+	_, err := service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: uuid.New(), Role: models.RoleApprover})
+	assert.Equal(t, rbac.ErrNotMember, err)
+
+	// Nor can members of another organization, or deactivated members
+	outsider := &models.User{ID: uuid.New(), OrganizationID: uuid.New(), Role: models.RoleViewer}
+	otherVaultID := directory.join(outsider)
+	_, err = service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: outsider.ID, Role: models.RoleApprover})
+	assert.Equal(t, rbac.ErrNotMember, err)
+	deactivated := &models.User{ID: uuid.New(), OrganizationID: orgID, Role: models.RoleViewer}
+	directory.join(deactivated)
+	now := time.Now()
+	directory.memberships[len(directory.memberships)-1].DeactivatedAt = &now
+	_, err = service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: deactivated.ID, Role: models.RoleApprover})
+	assert.Equal(t, rbac.ErrNotMember, err)
+
+	// Vault bindings must name one of the organization's own vaults
+	unknown := uuid.New()
+	_, err = service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: member.ID, Role: models.RoleApprover, VaultID: &unknown})
+	assert.Equal(t, rbac.ErrVaultNotFound, err)
+	_, err = service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: member.ID, Role: models.RoleApprover, VaultID: &otherVaultID})
+	assert.Equal(t, rbac.ErrVaultNotFound, err)
+	binding, err := service.BindRole(ctx, orgID, &models.RoleBindingRequest{UserID: member.ID, Role: models.RoleApprover, VaultID: &vaultID})
+	require.NoError(t, err)
+	assert.Equal(t, vaultID, *binding.VaultID)
+}
+
+func TestRolesAreIsolatedPerOrganization(t *testing.T) {
+	service, _, directory := newService()
+	ctx := context.Background()
+	orgA, orgB := uuid.New(), uuid.New()
+	userA := &models.User{ID: uuid.New(), OrganizationID: orgA, Role: models.RoleViewer}
+	userB := &models.User{ID: uuid.New(), OrganizationID: orgB, Role: models.RoleViewer}
+	directory.join(userA)
+	directory.join(userB)
+
+	role, err := service.CreateRole(ctx, orgA, &models.RoleRequest{Name: "treasurer", Permissions: []string{models.PermissionTxApprove}})
+	require.NoError(t, err)
+	binding, err := service.BindRole(ctx, orgA, &models.RoleBindingRequest{UserID: userA.ID, Role: "treasurer"})
+	require.NoError(t, err)
+
+	// Another organization neither sees the custom role nor can bind or delete it
+	roles, err := service.ListRoles(ctx, orgB)
+	require.NoError(t, err)
+	for _, r := range roles {
+		assert.NotEqual(t, role.ID, r.ID)
+	}
+	_, err = service.BindRole(ctx, orgB, &models.RoleBindingRequest{UserID: userB.ID, Role: "treasurer"})
+	assert.Equal(t, rbac.ErrRoleNotFound, err)
+	assert.Equal(t, rbac.ErrRoleNotFound, service.DeleteRole(ctx, orgB, role.ID.String()))
+
+	// Nor can it list or remove the first organization's bindings
+	bindings, err := service.ListRoleBindings(ctx, orgB, userA.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, bindings)
+	assert.Equal(t, rbac.ErrRoleBindingNotFound, service.UnbindRole(ctx, orgB, binding.ID.String()))
+
+	// The binding grants nothing to members of the other organization
+	allowed, err := service.Authorize(ctx, userA, models.PermissionTxApprove, "")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	allowed, err = service.Authorize(ctx, userB, models.PermissionTxApprove, "")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestRecordDenied(t *testing.T) {
+	service, audit, _ := newService()
+
+	service.RecordDenied(context.Background(), &models.AuditEvent{
+		OrganizationID: uuid.New(),
+		ActorType:      models.AuditActorUser,
+		ActorID:        "user-1",
+		Resource:       "PUT /api/v1/transactions/:id/sign",
+		Details:        map[string]string{"permission": models.PermissionTxApprove},
+	})
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, models.AuditActionPermissionDenied, audit.events[0].Action)
+}
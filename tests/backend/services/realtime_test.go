@@ -0,0 +1,365 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/services/realtime"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+)
+
+// memoryLog is an in-memory event log with sequential IDs
+type memoryLog struct {
+	mu       sync.Mutex
+	messages []*realtime.Message
+	seq      int
+	appended chan struct{}
+	waiting  chan struct{}
+}
+
+func newMemoryLog() *memoryLog {
+	return &memoryLog{appended: make(chan struct{}), waiting: make(chan struct{}, 1)}
+}
+
+func (m *memoryLog) Append(ctx context.Context, message *realtime.Message) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	stored := *message
+	stored.ID = strconv.Itoa(m.seq) + "-0"
+	m.messages = append(m.messages, &stored)
+	close(m.appended)
+	m.appended = make(chan struct{})
+	return stored.ID, nil
+}
+
+func (m *memoryLog) Read(ctx context.Context, after string, count int, block time.Duration) ([]*realtime.Message, error) {
+	deadline := time.After(block)
+	for {
+		m.mu.Lock()
+		var messages []*realtime.Message
+		for _, message := range m.messages {
+			if seqOf(message.ID) > seqOf(after) && len(messages) < count {
+				messages = append(messages, message)
+			}
+		}
+		appended := m.appended
+		m.mu.Unlock()
+		if len(messages) > 0 || block < 0 {
+			return messages, nil
+		}
+
+		// Let tests know a reader is waiting for new events
+		select {
+		case m.waiting <- struct{}{}:
+		default:
+		}
+		select {
+		case <-appended:
+		case <-deadline:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (m *memoryLog) Bounds(ctx context.Context) (string, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.messages) == 0 {
+		return "", "", nil
+	}
+	return m.messages[0].ID, m.messages[len(m.messages)-1].ID, nil
+}
+
+// trim drops the oldest messages, as retention would
+func (m *memoryLog) trim(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = m.messages[n:]
+}
+
+// id returns the ID of the nth message appended, counting from 1
+func (m *memoryLog) id(n int) string {
+	return strconv.Itoa(n) + "-0"
+}
+
+func seqOf(id string) int {
+	seq, _ := strconv.Atoi(strings.TrimSuffix(id, "-0"))
+	return seq
+}
+
+// memoryConnections is an in-memory connection counter, shared by services standing in for replicas
+type memoryConnections struct {
+	mu     sync.Mutex
+	leases map[uuid.UUID]map[string]time.Time
+}
+
+func newMemoryConnections() *memoryConnections {
+	return &memoryConnections{leases: make(map[uuid.UUID]map[string]time.Time)}
+}
+
+func (m *memoryConnections) Acquire(ctx context.Context, organizationID uuid.UUID, streamID string, limit int, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	leases, ok := m.leases[organizationID]
+	if !ok {
+		leases = make(map[string]time.Time)
+		m.leases[organizationID] = leases
+	}
+	for id, expiresAt := range leases {
+		if !expiresAt.After(now) {
+			delete(leases, id)
+		}
+	}
+	if len(leases) >= limit {
+		return false, nil
+	}
+	leases[streamID] = now.Add(ttl)
+	return true, nil
+}
+
+func (m *memoryConnections) Refresh(ctx context.Context, streams map[uuid.UUID][]string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for organizationID, streamIDs := range streams {
+		for _, streamID := range streamIDs {
+			if _, ok := m.leases[organizationID][streamID]; ok {
+				m.leases[organizationID][streamID] = time.Now().Add(ttl)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryConnections) Release(ctx context.Context, organizationID uuid.UUID, streamID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.leases[organizationID], streamID)
+	return nil
+}
+
+// start runs the fan-out until the test ends, returning once it waits for new events
+func start(t *testing.T, service *realtime.Service, log *memoryLog) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		service.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	<-log.waiting
+}
+
+// next returns the subscriber's next event, or nil if none arrives shortly
+func next(t *testing.T, subscriber *realtime.Subscriber) *models.Event {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	message, err := subscriber.Next(ctx)
+	if err == context.DeadlineExceeded {
+		return nil
+	}
+	require.NoError(t, err)
+	var event models.Event
+	require.NoError(t, json.Unmarshal(message.Event, &event))
+	return &event
+}
+
+func transactionEvent(orgID, vaultID uuid.UUID, status string) *models.Event {
+	transaction := &models.Transaction{ID: uuid.New(), OrganizationID: orgID, VaultID: vaultID, Status: status}
+	return models.NewEvent(orgID, models.EventTransactionStatusChanged, transaction.ID.String(), transaction)
+}
+
+func TestChannelsAndPermissions(t *testing.T) {
+	vaultID := uuid.New()
+	event := transactionEvent(uuid.New(), vaultID, "Submitted")
+	assert.Equal(t, []string{realtime.ChannelTransactions, realtime.VaultChannel(vaultID)}, realtime.Channels(event))
+
+	deposit := models.NewEvent(uuid.New(), models.EventDepositReceived, "0xdeposit", &models.DepositEvent{VaultID: vaultID})
+	assert.Equal(t, []string{realtime.ChannelDeposits, realtime.VaultChannel(vaultID)}, realtime.Channels(deposit))
+
+	permission, vault, err := realtime.ChannelPermission(realtime.VaultChannel(vaultID))
+	require.NoError(t, err)
+	assert.Equal(t, models.PermissionVaultRead, permission)
+	assert.Equal(t, vaultID.String(), vault)
+	permission, _, _ = realtime.ChannelPermission(realtime.ChannelSignatures)
+	assert.Equal(t, models.PermissionSignRead, permission)
+
+	for _, channel := range []string{"vault:not-a-uuid", "everything", ""} {
+		_, _, err := realtime.ChannelPermission(channel)
+		assert.Error(t, err, channel)
+	}
+}
+
+func TestEventsFanOutByOrganizationAndChannel(t *testing.T) {
+	log := newMemoryLog()
+	service := realtime.NewService(config.RealtimeConfig{}, log, newMemoryConnections(), logger.NewLogger())
+	ctx := context.Background()
+	orgID, otherOrgID, vaultID := uuid.New(), uuid.New(), uuid.New()
+
+	transactions, err := service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	vault, err := service.Subscribe(ctx, orgID, []string{realtime.VaultChannel(vaultID)}, "")
+	require.NoError(t, err)
+	other, err := service.Subscribe(ctx, otherOrgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	start(t, service, log)
+
+	// Subscribers of the organization on a matching channel receive the event; other organizations do not
+	event := transactionEvent(orgID, vaultID, "Submitted")
+	require.NoError(t, service.Publish(ctx, event))
+	assert.Equal(t, event.ID, next(t, transactions).ID)
+	assert.Equal(t, event.ID, next(t, vault).ID)
+	assert.Nil(t, next(t, other))
+
+	// Events about other vaults only reach the organization-wide channel
+	elsewhere := transactionEvent(orgID, uuid.New(), "Confirmed")
+	require.NoError(t, service.Publish(ctx, elsewhere))
+	assert.Equal(t, elsewhere.ID, next(t, transactions).ID)
+	assert.Nil(t, next(t, vault))
+
+	// Subscriptions can change while connected
+	transactions.Unsubscribe([]string{realtime.ChannelTransactions})
+	require.NoError(t, vault.Subscribe([]string{realtime.ChannelTransactions}))
+	assert.Error(t, vault.Subscribe([]string{"everything"}))
+	assert.Equal(t, []string{realtime.ChannelTransactions, realtime.VaultChannel(vaultID)}, vault.Channels())
+	later := transactionEvent(orgID, uuid.New(), "Failed")
+	require.NoError(t, service.Publish(ctx, later))
+	assert.Nil(t, next(t, transactions))
+	assert.Equal(t, later.ID, next(t, vault).ID)
+
+	// Disconnected subscribers receive nothing more
+	service.Disconnect(vault)
+	require.NoError(t, service.Publish(ctx, transactionEvent(orgID, vaultID, "Confirmed")))
+	assert.Nil(t, next(t, vault))
+}
+
+func TestResumeReplaysMissedEvents(t *testing.T) {
+	log := newMemoryLog()
+	service := realtime.NewService(config.RealtimeConfig{}, log, newMemoryConnections(), logger.NewLogger())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	var published []*models.Event
+	for _, status := range []string{"Pending", "Submitted", "Confirmed"} {
+		event := transactionEvent(orgID, uuid.New(), status)
+		require.NoError(t, service.Publish(ctx, event))
+		published = append(published, event)
+	}
+	require.NoError(t, service.Publish(ctx, transactionEvent(uuid.New(), uuid.New(), "Pending")))
+	start(t, service, log)
+
+	// Events after the client's last one are replayed in order, followed by live ones
+	subscriber, err := service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, log.id(1))
+	require.NoError(t, err)
+	assert.False(t, subscriber.Resync())
+	assert.Equal(t, published[1].ID, next(t, subscriber).ID)
+	assert.Equal(t, published[2].ID, next(t, subscriber).ID)
+	live := transactionEvent(orgID, uuid.New(), "Submitted")
+	require.NoError(t, service.Publish(ctx, live))
+	assert.Equal(t, live.ID, next(t, subscriber).ID)
+	assert.Nil(t, next(t, subscriber))
+
+	// Resuming from events no longer retained asks the client to resync instead
+	log.trim(2)
+	subscriber, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, log.id(1))
+	require.NoError(t, err)
+	assert.True(t, subscriber.Resync())
+	assert.Nil(t, next(t, subscriber))
+
+	_, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "yesterday")
+	assert.True(t, errors.Is(err, realtime.ErrInvalidEventID))
+	_, err = service.Subscribe(ctx, orgID, []string{"everything"}, "")
+	assert.Error(t, err)
+}
+
+func TestSlowConsumerIsDropped(t *testing.T) {
+	log := newMemoryLog()
+	service := realtime.NewService(config.RealtimeConfig{BufferSize: 2}, log, newMemoryConnections(), logger.NewLogger())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	slow, err := service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	fast, err := service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	start(t, service, log)
+
+	// The slow subscriber lets three events pile up; the fast one keeps up
+	for i := 0; i < 3; i++ {
+		event := transactionEvent(orgID, uuid.New(), "Submitted")
+		require.NoError(t, service.Publish(ctx, event))
+		assert.Equal(t, event.ID, next(t, fast).ID)
+	}
+
+	// It still gets the events it had queued, then learns it was dropped
+	assert.NotNil(t, next(t, slow))
+	assert.NotNil(t, next(t, slow))
+	_, err = slow.Next(ctx)
+	assert.True(t, errors.Is(err, realtime.ErrSlowConsumer))
+}
+
+func TestConnectionsAreLimitedPerOrganization(t *testing.T) {
+	service := realtime.NewService(config.RealtimeConfig{MaxConnections: 2}, newMemoryLog(), newMemoryConnections(), logger.NewLogger())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	first, err := service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	assert.True(t, errors.Is(err, realtime.ErrTooManyConnections))
+
+	// Other organizations have their own limit, and disconnecting frees a slot once
+	_, err = service.Subscribe(ctx, uuid.New(), []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	service.Disconnect(first)
+	service.Disconnect(first)
+	_, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = service.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	assert.True(t, errors.Is(err, realtime.ErrTooManyConnections))
+}
+
+func TestConnectionsAreLimitedAcrossReplicas(t *testing.T) {
+	connections := newMemoryConnections()
+	cfg := config.RealtimeConfig{MaxConnections: 2, HeartbeatInterval: 20 * time.Millisecond}
+	log := newMemoryLog()
+	live := realtime.NewService(cfg, log, connections, logger.NewLogger())
+	stopped := realtime.NewService(cfg, newMemoryLog(), connections, logger.NewLogger())
+	start(t, live, log)
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	// Streams on every replica count against the organization's one limit
+	_, err := live.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = stopped.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = live.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	assert.True(t, errors.Is(err, realtime.ErrTooManyConnections))
+
+	// A replica that stops refreshing its leases loses its slots once they expire; running replicas keep theirs
+	time.Sleep(100 * time.Millisecond)
+	_, err = live.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	require.NoError(t, err)
+	_, err = live.Subscribe(ctx, orgID, []string{realtime.ChannelTransactions}, "")
+	assert.True(t, errors.Is(err, realtime.ErrTooManyConnections))
+}
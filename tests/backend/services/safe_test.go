@@ -0,0 +1,421 @@
+package safe_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/blockchain/evm"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/safe"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
+)
+
+const (
+	safeAddress      = "0x52908400098527886E0F7030069857D2E4169EE7"
+	multiSendAddress = "0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"
+	recipient        = "0x8617E340B3D01FA5F11F306F4090FD50E238070D"
+)
+
+// memoryVaults is an in-memory vault repository
+type memoryVaults struct {
+	mu     sync.Mutex
+	vaults map[string]*models.Vault
+}
+
+func (m *memoryVaults) CreateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	m.vaults[v.ID.String()] = v
+	return v, nil
+}
+
+func (m *memoryVaults) GetVault(ctx context.Context, id string) (*models.Vault, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.vaults[id]; ok {
+		return v, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryVaults) UpdateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	return m.CreateVault(ctx, v)
+}
+
+func (m *memoryVaults) DeleteVault(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.vaults, id)
+	return nil
+}
+
+func (m *memoryVaults) ListVaults(ctx context.Context, opts *pagination.Options) ([]*models.Vault, error) {
+	return nil, nil
+}
+
+func (m *memoryVaults) CountVaults(ctx context.Context, filters *pagination.Filters) (int, error) {
+	return 0, nil
+}
+
+func (m *memoryVaults) ListVaultsByBlockchainType(ctx context.Context, blockchainType string) ([]*models.Vault, error) {
+	return nil, nil
+}
+
+// memorySafeTransactions is an in-memory Safe transaction repository
+type memorySafeTransactions struct {
+	mu  sync.Mutex
+	txs map[string]*models.SafeTransaction
+}
+
+func (m *memorySafeTransactions) CreateSafeTransaction(ctx context.Context, t *models.SafeTransaction) (*models.SafeTransaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.txs[t.ID.String()] = t
+	return t, nil
+}
+
+func (m *memorySafeTransactions) GetSafeTransaction(ctx context.Context, id string) (*models.SafeTransaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.txs[id]; ok {
+		return t, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memorySafeTransactions) UpdateSafeTransaction(ctx context.Context, t *models.SafeTransaction) (*models.SafeTransaction, error) {
+	return m.CreateSafeTransaction(ctx, t)
+}
+
+func (m *memorySafeTransactions) ListExecutingSafeTransactions(ctx context.Context) ([]*models.SafeTransaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var executing []*models.SafeTransaction
+	for _, t := range m.txs {
+		if t.Status == models.SafeTransactionStatusExecuting {
+			executing = append(executing, t)
+		}
+	}
+	return executing, nil
+}
+
+// fakeChain serves a fixed Safe nonce and receipt
+type fakeChain struct {
+	nonce   uint64
+	receipt *types.Receipt
+}
+
+func (f *fakeChain) Network() evm.Network {
+	return evm.Network{Name: "ethereum", ChainID: big.NewInt(1)}
+}
+
+func (f *fakeChain) SafeNonce(ctx context.Context, safe string) (uint64, error) {
+	return f.nonce, nil
+}
+
+func (f *fakeChain) SafeOwners(ctx context.Context, safe string) ([]string, uint64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeChain) GetTransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	return f.receipt, nil
+}
+
+// fakeTransactions keeps the relayer transactions the service creates
+type fakeTransactions struct {
+	txs map[string]*models.Transaction
+}
+
+func (f *fakeTransactions) CreateTransaction(ctx context.Context, transaction *models.Transaction) (*models.Transaction, error) {
+	transaction.ID = uuid.New()
+	transaction.Status = "Pending"
+	f.txs[transaction.ID.String()] = transaction
+	return transaction, nil
+}
+
+func (f *fakeTransactions) GetTransaction(ctx context.Context, id string) (*models.Transaction, error) {
+	if transaction, ok := f.txs[id]; ok {
+		return transaction, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// keySigner signs with the owner key held by each backend at once; backends without a key fail
+type keySigner struct {
+	keys     map[string]*ecdsa.PrivateKey
+	requests map[string]*models.SignatureRequest
+}
+
+func (k *keySigner) RequestSignature(ctx context.Context, request *models.SignatureRequest) (*models.SignatureRequest, error) {
+	request.ID = uuid.New()
+	request.Status = models.SignatureStatusFailed
+	if key, ok := k.keys[request.SignerBackend]; ok {
+		hash, _ := hex.DecodeString(request.DataToSign)
+		signature, err := crypto.Sign(hash, key)
+		if err != nil {
+			return nil, err
+		}
+		request.Signature = hex.EncodeToString(signature)
+		request.Status = models.SignatureStatusCompleted
+	}
+	k.requests[request.ID.String()] = request
+	return request, nil
+}
+
+func (k *keySigner) GetSignatureStatus(ctx context.Context, id string) (*models.SignatureRequest, error) {
+	if request, ok := k.requests[id]; ok {
+		return request, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+// safeFixture is a 2-of-3 Safe vault with a relayer, where the "offline" owner never signs
+type safeFixture struct {
+	service      *safe.Service
+	ctx          context.Context
+	vault        *models.Vault
+	vaults       *memoryVaults
+	safeTxs      *memorySafeTransactions
+	chain        *fakeChain
+	transactions *fakeTransactions
+}
+
+func newSafeFixture(t *testing.T) *safeFixture {
+	organizationID := uuid.New()
+	f := &safeFixture{
+		ctx:          tenant.WithOrganization(context.Background(), organizationID),
+		vaults:       &memoryVaults{vaults: map[string]*models.Vault{}},
+		safeTxs:      &memorySafeTransactions{txs: map[string]*models.SafeTransaction{}},
+		chain:        &fakeChain{nonce: 4},
+		transactions: &fakeTransactions{txs: map[string]*models.Transaction{}},
+	}
+	signer := &keySigner{keys: map[string]*ecdsa.PrivateKey{}, requests: map[string]*models.SignatureRequest{}}
+
+	// Two owners sign through their backends and the third is offline
+	var owners []models.SafeOwner
+	for _, backend := range []string{"hsm", "mpc", "offline"} {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		if backend != "offline" {
+			signer.keys[backend] = key
+		}
+		owners = append(owners, models.SafeOwner{Address: crypto.PubkeyToAddress(key.PublicKey).Hex(), Backend: backend})
+	}
+
+	relayer, _ := f.vaults.CreateVault(f.ctx, &models.Vault{OrganizationID: organizationID, BlockchainType: "ethereum", Address: recipient})
+	f.vault, _ = f.vaults.CreateVault(f.ctx, &models.Vault{
+		OrganizationID: organizationID,
+		BlockchainType: "ethereum",
+		Address:        safeAddress,
+		Safe:           &models.SafeConfig{Owners: owners, Threshold: 2, Nonce: 4, RelayerVaultID: relayer.ID},
+	})
+
+	f.service = safe.NewService(f.safeTxs, f.vaults, f.transactions, signer, map[string]safe.Chain{"ethereum": f.chain},
+		config.SafeContractsConfig{DelegateCallTargets: []string{multiSendAddress}}, logger.NewLogger())
+	return f
+}
+
+// execute proposes and executes a transfer, returning the executing Safe transaction and its relayer transaction
+func (f *safeFixture) execute(t *testing.T) (*models.SafeTransaction, *models.Transaction) {
+	proposed, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient, Value: "1000"})
+	require.NoError(t, err)
+	executing, err := f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), proposed.ID.String())
+	require.NoError(t, err)
+	return executing, f.transactions.txs[executing.ExecutionTxID.String()]
+}
+
+// executionReceipt is a mined receipt carrying a Safe execution event for a transaction
+func executionReceipt(event string, safeTx *models.SafeTransaction) *types.Receipt {
+	return &types.Receipt{
+		Status: types.ReceiptStatusSuccessful,
+		Logs: []*types.Log{{
+			Address: common.HexToAddress(safeAddress),
+			Topics:  []common.Hash{crypto.Keccak256Hash([]byte(event + "(bytes32,uint256)"))},
+			Data:    append(common.HexToHash(safeTx.SafeTxHash).Bytes(), make([]byte, 32)...),
+		}},
+	}
+}
+
+func TestProposeTransaction(t *testing.T) {
+	f := newSafeFixture(t)
+
+	// The proposal takes the tracked nonce and asks every owner to sign
+	proposed, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient, Value: "1000"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), proposed.Nonce)
+	assert.Equal(t, models.SafeTransactionStatusPending, proposed.Status)
+	assert.Len(t, proposed.SignatureRequestIDs, 3)
+	assert.NotEmpty(t, proposed.SafeTxHash)
+	assert.Equal(t, uint64(5), f.vault.Safe.Nonce)
+
+	// A Safe that moved past the tracked nonce is followed
+	f.chain.nonce = 9
+	proposed, err = f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), proposed.Nonce)
+	assert.Equal(t, "0", proposed.Value)
+}
+
+func TestProposeTransactionRestrictsDelegateCall(t *testing.T) {
+	f := newSafeFixture(t)
+
+	// Delegatecalls run the target's code against the Safe, so only allowlisted targets are accepted
+	_, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient, Operation: models.SafeOperationDelegateCall})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "delegatecall")
+	assert.Empty(t, f.safeTxs.txs)
+
+	proposed, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: multiSendAddress, Operation: models.SafeOperationDelegateCall})
+	require.NoError(t, err)
+	assert.Equal(t, models.SafeOperationDelegateCall, proposed.Operation)
+}
+
+func TestSafeTransactionsCheckTenant(t *testing.T) {
+	f := newSafeFixture(t)
+	vaultID := f.vault.ID.String()
+
+	// Another organization cannot propose against the Safe
+	other := tenant.WithOrganization(context.Background(), uuid.New())
+	_, err := f.service.ProposeTransaction(other, vaultID, &models.SafeTransactionRequest{To: recipient})
+	assert.Error(t, err)
+	assert.Empty(t, f.safeTxs.txs)
+
+	// Nor execute, confirm or cancel the Safe's transactions
+	proposed, err := f.service.ProposeTransaction(f.ctx, vaultID, &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+	_, err = f.service.ExecuteTransaction(other, vaultID, proposed.ID.String())
+	assert.Error(t, err)
+	_, err = f.service.CancelTransaction(other, vaultID, proposed.ID.String())
+	assert.Error(t, err)
+	assert.Equal(t, models.SafeTransactionStatusPending, proposed.Status)
+	executing, err := f.service.ExecuteTransaction(f.ctx, vaultID, proposed.ID.String())
+	require.NoError(t, err)
+	_, err = f.service.ConfirmExecution(other, vaultID, executing.ID.String())
+	assert.Error(t, err)
+	assert.Equal(t, models.SafeTransactionStatusExecuting, executing.Status)
+}
+
+func TestExecuteTransaction(t *testing.T) {
+	f := newSafeFixture(t)
+
+	// Two of the three owners signed, which meets the threshold
+	executing, relayed := f.execute(t)
+	assert.Equal(t, models.SafeTransactionStatusExecuting, executing.Status)
+	assert.Equal(t, safeAddress, relayed.ToAddress)
+	assert.Equal(t, f.vault.Safe.RelayerVaultID, relayed.VaultID)
+	assert.NotEmpty(t, relayed.Data)
+
+	// An executing transaction cannot be executed twice
+	_, err := f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), executing.ID.String())
+	assert.Error(t, err)
+}
+
+func TestExecuteTransactionRequiresThreshold(t *testing.T) {
+	f := newSafeFixture(t)
+	f.vault.Safe.Threshold = 3
+
+	// The offline owner never signs, so a 3-of-3 threshold cannot be met
+	proposed, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+	_, err = f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), proposed.ID.String())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "threshold")
+	assert.Empty(t, f.transactions.txs)
+
+	// Transactions must execute in nonce order
+	f.vault.Safe.Threshold = 2
+	f.chain.nonce = 3
+	_, err = f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), proposed.ID.String())
+	assert.Error(t, err)
+}
+
+func TestConfirmExecution(t *testing.T) {
+	f := newSafeFixture(t)
+
+	// A mined execution with ExecutionSuccess completes the Safe transaction
+	executing, relayed := f.execute(t)
+	relayed.TxHash = "0xabc"
+	f.chain.receipt = executionReceipt("ExecutionSuccess", executing)
+	confirmed, err := f.service.ConfirmExecution(f.ctx, f.vault.ID.String(), executing.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.SafeTransactionStatusExecuted, confirmed.Status)
+
+	// ExecutionFailure uses up the nonce, so the Safe transaction fails for good
+	f.chain.nonce = 5
+	executing, relayed = f.execute(t)
+	relayed.TxHash = "0xdef"
+	f.chain.receipt = executionReceipt("ExecutionFailure", executing)
+	require.NoError(t, f.service.ConfirmExecutions(tenant.WithSystem(context.Background())))
+	assert.Equal(t, models.SafeTransactionStatusFailed, f.safeTxs.txs[executing.ID.String()].Status)
+}
+
+func TestConfirmExecutionAllowsRetry(t *testing.T) {
+	f := newSafeFixture(t)
+
+	// A relayer transaction that was never sent leaves the nonce free
+	executing, relayed := f.execute(t)
+	relayed.Status = "Failed"
+	confirmed, err := f.service.ConfirmExecution(f.ctx, f.vault.ID.String(), executing.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.SafeTransactionStatusPending, confirmed.Status)
+	assert.Nil(t, confirmed.ExecutionTxID)
+
+	// So does one that reverted before reaching the Safe; either way it can be executed again
+	executing, err = f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), executing.ID.String())
+	require.NoError(t, err)
+	f.transactions.txs[executing.ExecutionTxID.String()].TxHash = "0xabc"
+	f.chain.receipt = &types.Receipt{Status: types.ReceiptStatusFailed}
+	confirmed, err = f.service.ConfirmExecution(f.ctx, f.vault.ID.String(), executing.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.SafeTransactionStatusPending, confirmed.Status)
+	_, err = f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), executing.ID.String())
+	assert.NoError(t, err)
+}
+
+func TestCancelTransaction(t *testing.T) {
+	f := newSafeFixture(t)
+	first, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+	second, err := f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+
+	// Cancelling the newest proposal gives its nonce back
+	cancelled, err := f.service.CancelTransaction(f.ctx, f.vault.ID.String(), second.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.SafeTransactionStatusCancelled, cancelled.Status)
+	assert.Equal(t, uint64(5), f.vault.Safe.Nonce)
+
+	// An earlier proposal is replaced by a rejection at the same nonce so later proposals are not blocked
+	_, err = f.service.ProposeTransaction(f.ctx, f.vault.ID.String(), &models.SafeTransactionRequest{To: recipient})
+	require.NoError(t, err)
+	rejection, err := f.service.CancelTransaction(f.ctx, f.vault.ID.String(), first.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, first.Nonce, rejection.Nonce)
+	assert.Equal(t, safeAddress, rejection.To)
+	assert.Equal(t, "0", rejection.Value)
+	assert.Equal(t, models.SafeTransactionStatusCancelled, f.safeTxs.txs[first.ID.String()].Status)
+	assert.Equal(t, uint64(6), f.vault.Safe.Nonce)
+
+	// Cancelled transactions cannot be executed or cancelled again
+	_, err = f.service.ExecuteTransaction(f.ctx, f.vault.ID.String(), first.ID.String())
+	assert.Error(t, err)
+	_, err = f.service.CancelTransaction(f.ctx, f.vault.ID.String(), first.ID.String())
+	assert.Error(t, err)
+}
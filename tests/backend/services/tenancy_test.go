@@ -0,0 +1,612 @@
+package tenancy_test
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/escrow"
+	"github.com/your-repo/blockchain-integration-service/internal/services/gasstation"
+	"github.com/your-repo/blockchain-integration-service/internal/services/nft"
+	"github.com/your-repo/blockchain-integration-service/internal/services/signature"
+	"github.com/your-repo/blockchain-integration-service/internal/services/sweep"
+	"github.com/your-repo/blockchain-integration-service/internal/services/transaction"
+	"github.com/your-repo/blockchain-integration-service/internal/services/vault"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/offline"
+	"github.com/your-repo/blockchain-integration-service/pkg/pagination"
+)
+
+const evmAddress = "0x52908400098527886E0F7030069857D2E4169EE7"
+
+// visible mirrors the row-level security policy: requests see their organization's rows and background jobs see all
+func visible(ctx context.Context, organizationID uuid.UUID) bool {
+	return tenant.Owns(ctx, organizationID)
+}
+
+// memoryVaults is an in-memory vault repository. Lookups by ID ignore the tenant, as a misconfigured database
+// role would, so the tests prove that the services check ownership themselves
+type memoryVaults struct {
+	mu     sync.Mutex
+	vaults map[string]*models.Vault
+}
+
+func (m *memoryVaults) CreateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	m.vaults[v.ID.String()] = v
+	return v, nil
+}
+
+func (m *memoryVaults) GetVault(ctx context.Context, id string) (*models.Vault, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.vaults[id]; ok {
+		return v, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryVaults) UpdateVault(ctx context.Context, v *models.Vault) (*models.Vault, error) {
+	return m.CreateVault(ctx, v)
+}
+
+func (m *memoryVaults) DeleteVault(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.vaults, id)
+	return nil
+}
+
+func (m *memoryVaults) ListVaults(ctx context.Context, opts *pagination.Options) ([]*models.Vault, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var vaults []*models.Vault
+	for _, v := range m.vaults {
+		if visible(ctx, v.OrganizationID) {
+			vaults = append(vaults, v)
+		}
+	}
+	return vaults, nil
+}
+
+func (m *memoryVaults) CountVaults(ctx context.Context, filters *pagination.Filters) (int, error) {
+	vaults, err := m.ListVaults(ctx, nil)
+	return len(vaults), err
+}
+
+func (m *memoryVaults) ListVaultsByBlockchainType(ctx context.Context, blockchainType string) ([]*models.Vault, error) {
+	return m.ListVaults(ctx, nil)
+}
+
+// memoryTransactions is an in-memory transaction repository
+type memoryTransactions struct {
+	mu           sync.Mutex
+	transactions map[string]*models.Transaction
+}
+
+func (m *memoryTransactions) CreateTransaction(ctx context.Context, t *models.Transaction) (*models.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	m.transactions[t.ID.String()] = t
+	return t, nil
+}
+
+func (m *memoryTransactions) GetTransactionByID(ctx context.Context, id string) (*models.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok := m.transactions[id]; ok {
+		return t, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryTransactions) ListTransactions(ctx context.Context, opts *pagination.Options) ([]*models.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var transactions []*models.Transaction
+	for _, t := range m.transactions {
+		if visible(ctx, t.OrganizationID) {
+			transactions = append(transactions, t)
+		}
+	}
+	return transactions, nil
+}
+
+func (m *memoryTransactions) CountTransactions(ctx context.Context, filters *pagination.Filters) (int, error) {
+	transactions, err := m.ListTransactions(ctx, nil)
+	return len(transactions), err
+}
+
+func (m *memoryTransactions) UpdateTransactionStatus(ctx context.Context, id, status string) (*models.Transaction, error) {
+	t, err := m.GetTransactionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	t.Status = status
+	return t, nil
+}
+
+func (m *memoryTransactions) UpdateTransaction(ctx context.Context, t *models.Transaction) (*models.Transaction, error) {
+	return m.CreateTransaction(ctx, t)
+}
+
+func (m *memoryTransactions) ListPendingTopUps(ctx context.Context, vaultID string) ([]*models.Transaction, error) {
+	return nil, nil
+}
+
+func (m *memoryTransactions) ListAwaitingGas(ctx context.Context) ([]*models.Transaction, error) {
+	return nil, nil
+}
+
+func (m *memoryTransactions) ListPendingSweeps(ctx context.Context, vaultID string) ([]*models.Transaction, error) {
+	return nil, nil
+}
+
+// memorySignatures is an in-memory signature request repository
+type memorySignatures struct {
+	mu       sync.Mutex
+	requests map[string]*models.SignatureRequest
+}
+
+func (m *memorySignatures) CreateSignatureRequest(ctx context.Context, r *models.SignatureRequest) (*models.SignatureRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	m.requests[r.ID.String()] = r
+	return r, nil
+}
+
+func (m *memorySignatures) GetSignatureRequestByID(ctx context.Context, id string) (*models.SignatureRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if r, ok := m.requests[id]; ok {
+		return r, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memorySignatures) ListSignatureRequests(ctx context.Context, opts *pagination.Options) ([]*models.SignatureRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var requests []*models.SignatureRequest
+	for _, r := range m.requests {
+		if visible(ctx, r.OrganizationID) {
+			requests = append(requests, r)
+		}
+	}
+	return requests, nil
+}
+
+func (m *memorySignatures) CountSignatureRequests(ctx context.Context, filters *pagination.Filters) (int, error) {
+	requests, err := m.ListSignatureRequests(ctx, nil)
+	return len(requests), err
+}
+
+func (m *memorySignatures) UpdateSignatureRequest(ctx context.Context, r *models.SignatureRequest) error {
+	_, err := m.CreateSignatureRequest(ctx, r)
+	return err
+}
+
+// fakeChain generates addresses and submits nothing
+type fakeChain struct{}
+
+func (fakeChain) GenerateAddress(ctx context.Context) (string, error) { return evmAddress, nil }
+func (fakeChain) GetBalance(ctx context.Context, address string) (string, error) {
+	return "0", nil
+}
+func (fakeChain) SubmitTransaction(ctx context.Context, t *models.Transaction) (string, error) {
+	return "0xhash", nil
+}
+
+// fakeOfflineSigner lets transactions on cold-storage vaults be created without being submitted
+type fakeOfflineSigner struct{}
+
+func (fakeOfflineSigner) ExportUnsigned(ctx context.Context, t *models.Transaction) (*offline.Payload, error) {
+	return &offline.Payload{}, nil
+}
+func (fakeOfflineSigner) BroadcastSigned(ctx context.Context, t *models.Transaction, p *offline.Payload) (string, error) {
+	return "", nil
+}
+
+// refusingValidator rejects every transaction, as the XRP client does payments missing a required destination tag
+type refusingValidator struct{}
+
+func (refusingValidator) ValidateTransaction(ctx context.Context, t *models.Transaction) error {
+	return errors.NewBadRequestError("destination account requires a destination tag")
+}
+
+// fakeSigner signs everything with a fixed signature
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(data []byte) (string, error) { return "signature", nil }
+
+// memoryOrganizations is an in-memory organization repository
+type memoryOrganizations struct {
+	organizations map[uuid.UUID]*models.Organization
+}
+
+func (m *memoryOrganizations) GetOrganization(ctx context.Context, id string) (*models.Organization, error) {
+	if org, ok := m.organizations[uuid.MustParse(id)]; ok {
+		return org, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryOrganizations) UpdateOrganization(ctx context.Context, org *models.Organization) (*models.Organization, error) {
+	m.organizations[org.ID] = org
+	return org, nil
+}
+
+// memoryHoldings is an in-memory NFT repository. Holdings are listed by vault alone, so only the service's
+// ownership check keeps them from other organizations
+type memoryHoldings struct {
+	holdings []*models.NFTHolding
+}
+
+func (m *memoryHoldings) ListHoldings(ctx context.Context, vaultID string) ([]*models.NFTHolding, error) {
+	var holdings []*models.NFTHolding
+	for _, h := range m.holdings {
+		if h.VaultID.String() == vaultID {
+			holdings = append(holdings, h)
+		}
+	}
+	return holdings, nil
+}
+
+func (m *memoryHoldings) GetHolding(ctx context.Context, vaultID, contract, tokenID string) (*models.NFTHolding, error) {
+	for _, h := range m.holdings {
+		if h.VaultID.String() == vaultID && h.ContractAddress == contract && h.TokenID == tokenID {
+			return h, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryHoldings) ApplyTransfer(ctx context.Context, t *models.NFTTransfer, holdings []*models.NFTHolding) (bool, error) {
+	return false, nil
+}
+
+func (m *memoryHoldings) GetSyncCursor(ctx context.Context, blockchainType string) (uint64, error) {
+	return 0, nil
+}
+
+func (m *memoryHoldings) SetSyncCursor(ctx context.Context, blockchainType string, block uint64) error {
+	return nil
+}
+
+// memoryEscrows is an in-memory escrow repository
+type memoryEscrows struct {
+	escrows map[string]*models.Escrow
+}
+
+func (m *memoryEscrows) CreateEscrow(ctx context.Context, e *models.Escrow) (*models.Escrow, error) {
+	e.ID = uuid.New()
+	m.escrows[e.ID.String()] = e
+	return e, nil
+}
+
+func (m *memoryEscrows) GetEscrowByID(ctx context.Context, id string) (*models.Escrow, error) {
+	if e, ok := m.escrows[id]; ok {
+		return e, nil
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryEscrows) ListEscrowsByVault(ctx context.Context, vaultID string) ([]*models.Escrow, error) {
+	var escrows []*models.Escrow
+	for _, e := range m.escrows {
+		if e.VaultID.String() == vaultID {
+			escrows = append(escrows, e)
+		}
+	}
+	return escrows, nil
+}
+
+func (m *memoryEscrows) ListOpenEscrows(ctx context.Context) ([]*models.Escrow, error) {
+	return nil, nil
+}
+
+func (m *memoryEscrows) ListSubmittedEscrows(ctx context.Context) ([]*models.Escrow, error) {
+	return nil, nil
+}
+
+func (m *memoryEscrows) UpdateEscrow(ctx context.Context, e *models.Escrow) (*models.Escrow, error) {
+	m.escrows[e.ID.String()] = e
+	return e, nil
+}
+
+// tenants holds two organizations' request contexts and a vault owned by the first
+type tenants struct {
+	orgA, orgB uuid.UUID
+	ctxA, ctxB context.Context
+	vaults     *memoryVaults
+	vaultA     *models.Vault
+}
+
+func newTenants(t *testing.T) *tenants {
+	ts := &tenants{orgA: uuid.New(), orgB: uuid.New(), vaults: &memoryVaults{vaults: make(map[string]*models.Vault)}}
+	ts.ctxA = tenant.WithOrganization(context.Background(), ts.orgA)
+	ts.ctxB = tenant.WithOrganization(context.Background(), ts.orgB)
+
+	created, err := vault.NewService(ts.vaults, fakeChain{}, logger.NewLogger()).CreateVault(ts.ctxA, &models.Vault{
+		Name:           "treasury",
+		BlockchainType: "ethereum",
+		ColdStorage:    true,
+	})
+	require.NoError(t, err)
+	ts.vaultA = created
+	return ts
+}
+
+// assertNotFound checks that an error hides the record rather than revealing that it exists
+func assertNotFound(t *testing.T, err error) {
+	t.Helper()
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr), "expected an AppError, got %v", err)
+	assert.Equal(t, 404, appErr.StatusCode)
+}
+
+func TestVaultIsolation(t *testing.T) {
+	ts := newTenants(t)
+	service := vault.NewService(ts.vaults, fakeChain{}, logger.NewLogger())
+	id := ts.vaultA.ID.String()
+
+	// The vault is created in the caller's organization
+	assert.Equal(t, ts.orgA, ts.vaultA.OrganizationID)
+	_, err := service.GetVault(ts.ctxA, id)
+	require.NoError(t, err)
+
+	// Another organization can neither read, update, delete nor query the balance of it
+	_, err = service.GetVault(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.GetVaultBalance(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.UpdateVault(ts.ctxB, &models.Vault{ID: ts.vaultA.ID, Name: "stolen", BlockchainType: "ethereum"})
+	assertNotFound(t, err)
+	assertNotFound(t, service.DeleteVault(ts.ctxB, id))
+	assert.Equal(t, "treasury", ts.vaultA.Name)
+
+	// Nor does it appear in the other organization's listing
+	vaults, err := service.ListVaults(ts.ctxB, &pagination.Options{IncludeTotal: true})
+	require.NoError(t, err)
+	assert.Empty(t, vaults.Data)
+	assert.Zero(t, *vaults.Total)
+
+	// An update cannot move a vault to another organization
+	updated, err := service.UpdateVault(ts.ctxA, &models.Vault{ID: ts.vaultA.ID, OrganizationID: ts.orgB, Name: "renamed", BlockchainType: "ethereum"})
+	require.NoError(t, err)
+	assert.Equal(t, ts.orgA, updated.OrganizationID)
+}
+
+func TestTransactionIsolation(t *testing.T) {
+	ts := newTenants(t)
+	repo := &memoryTransactions{transactions: make(map[string]*models.Transaction)}
+	service := transaction.NewService(repo, ts.vaults, fakeChain{}, logger.NewLogger())
+	service.RegisterOfflineSigner("ethereum", fakeOfflineSigner{})
+	request := func() *models.Transaction {
+		return &models.Transaction{VaultID: ts.vaultA.ID, BlockchainType: "ethereum", ToAddress: evmAddress, Amount: "1"}
+	}
+
+	// Transactions can only be created on the caller's vaults and inherit their organization
+	_, err := service.CreateTransaction(ts.ctxB, request())
+	assertNotFound(t, err)
+	created, err := service.CreateTransaction(ts.ctxA, request())
+	require.NoError(t, err)
+	assert.Equal(t, ts.orgA, created.OrganizationID)
+
+	// Another organization can neither read nor update it
+	id := created.ID.String()
+	_, err = service.GetTransaction(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.UpdateTransactionStatus(ts.ctxB, id, "Failed")
+	assertNotFound(t, err)
+	_, err = service.ExportTransaction(ts.ctxB, id)
+	assertNotFound(t, err)
+	assert.Equal(t, models.TransactionStatusAwaitingOfflineSignature, created.Status)
+
+	transactions, err := service.ListTransactions(ts.ctxB, &pagination.Options{})
+	require.NoError(t, err)
+	assert.Empty(t, transactions.Data)
+	transactions, err = service.ListTransactions(ts.ctxA, &pagination.Options{})
+	require.NoError(t, err)
+	assert.Len(t, transactions.Data, 1)
+}
+
+func TestValidatorRefusesTransaction(t *testing.T) {
+	ts := newTenants(t)
+	repo := &memoryTransactions{transactions: make(map[string]*models.Transaction)}
+	service := transaction.NewService(repo, ts.vaults, fakeChain{}, logger.NewLogger())
+	service.RegisterOfflineSigner("ethereum", fakeOfflineSigner{})
+	service.RegisterValidator("Ethereum", refusingValidator{})
+
+	// A transaction the ledger checks refuse is reported as a bad request and never stored
+	_, err := service.CreateTransaction(ts.ctxA, &models.Transaction{VaultID: ts.vaultA.ID, BlockchainType: "ethereum", ToAddress: evmAddress, Amount: "1"})
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr), "expected an AppError, got %v", err)
+	assert.Equal(t, 400, appErr.StatusCode)
+	assert.Empty(t, repo.transactions)
+}
+
+func TestTransactionsAreSentFromTheirVault(t *testing.T) {
+	ts := newTenants(t)
+	repo := &memoryTransactions{transactions: make(map[string]*models.Transaction)}
+	service := transaction.NewService(repo, ts.vaults, fakeChain{}, logger.NewLogger())
+	hot, err := ts.vaults.CreateVault(ts.ctxA, &models.Vault{OrganizationID: ts.orgA, Name: "hot", BlockchainType: "ethereum", Address: evmAddress})
+	require.NoError(t, err)
+
+	// The source is always the vault's address, whatever the caller supplied
+	created, err := service.CreateTransaction(ts.ctxA, &models.Transaction{VaultID: hot.ID, BlockchainType: "Ethereum", FromAddress: "0x0000000000000000000000000000000000000001", ToAddress: evmAddress, Amount: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, hot.Address, created.FromAddress)
+
+	// A transaction on another chain than its vault is refused and never stored
+	before, err := repo.CountTransactions(ts.ctxA, nil)
+	require.NoError(t, err)
+	_, err = service.CreateTransaction(ts.ctxA, &models.Transaction{VaultID: hot.ID, BlockchainType: "xrp", ToAddress: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", Amount: "1"})
+	var appErr *errors.AppError
+	require.True(t, errors.As(err, &appErr), "expected an AppError, got %v", err)
+	assert.Equal(t, 400, appErr.StatusCode)
+	after, err := repo.CountTransactions(ts.ctxA, nil)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestSignatureIsolation(t *testing.T) {
+	ts := newTenants(t)
+	repo := &memorySignatures{requests: make(map[string]*models.SignatureRequest)}
+	service := signature.NewService(repo, ts.vaults, fakeSigner{}, logger.NewLogger())
+
+	// Requests can only name the caller's vaults and are created in the caller's organization
+	_, err := service.RequestSignature(ts.ctxB, &models.SignatureRequest{VaultID: ts.vaultA.ID, Data: []byte("payload")})
+	assertNotFound(t, err)
+	created, err := service.RequestSignature(ts.ctxA, &models.SignatureRequest{VaultID: ts.vaultA.ID, OrganizationID: ts.orgB, Data: []byte("payload")})
+	require.NoError(t, err)
+	assert.Equal(t, ts.orgA, created.OrganizationID)
+
+	// Another organization can neither read nor list it
+	_, err = service.GetSignatureStatus(ts.ctxB, created.ID.String())
+	assertNotFound(t, err)
+	requests, err := service.ListSignatureRequests(ts.ctxB, &pagination.Options{})
+	require.NoError(t, err)
+	assert.Empty(t, requests.Data)
+}
+
+func TestNFTIsolation(t *testing.T) {
+	ts := newTenants(t)
+	id := ts.vaultA.ID.String()
+	repo := &memoryHoldings{holdings: []*models.NFTHolding{{
+		ID:              uuid.New(),
+		VaultID:         ts.vaultA.ID,
+		BlockchainType:  "ethereum",
+		ContractAddress: evmAddress,
+		TokenID:         "1",
+		Standard:        "erc721",
+		Balance:         "1",
+	}}}
+	service := nft.NewService(repo, ts.vaults, nil, map[string]nft.Tracker{"ethereum": nil}, logger.NewLogger())
+
+	holdings, err := service.ListHoldings(ts.ctxA, id)
+	require.NoError(t, err)
+	assert.Len(t, holdings, 1)
+
+	// Another organization can neither list nor move the vault's NFTs
+	_, err = service.ListHoldings(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.TransferNFT(ts.ctxB, id, &models.NFTTransferRequest{ContractAddress: evmAddress, TokenID: "1", ToAddress: evmAddress})
+	assertNotFound(t, err)
+}
+
+func TestGasStationIsolation(t *testing.T) {
+	ts := newTenants(t)
+	station, err := ts.vaults.CreateVault(ts.ctxA, &models.Vault{OrganizationID: ts.orgA, Name: "station", BlockchainType: "ethereum", Address: evmAddress})
+	require.NoError(t, err)
+	orgs := &memoryOrganizations{organizations: map[uuid.UUID]*models.Organization{
+		ts.orgA: {ID: ts.orgA, GasStationVaultID: &station.ID},
+		ts.orgB: {ID: ts.orgB},
+	}}
+	transactions := &memoryTransactions{transactions: make(map[string]*models.Transaction)}
+	service, err := gasstation.NewService(orgs, ts.vaults, transactions, nil, map[string]gasstation.Chain{"ethereum": nil}, config.GasStationConfig{MaxTopUpWei: "1000000000000000000"}, logger.NewLogger())
+	require.NoError(t, err)
+
+	_, err = service.GetGasStationVault(ts.ctxA, ts.orgA.String())
+	require.NoError(t, err)
+
+	// Another organization can neither read the station nor reconfigure it
+	_, err = service.GetGasStationVault(ts.ctxB, ts.orgA.String())
+	assertNotFound(t, err)
+	_, err = service.SetGasStationVault(ts.ctxB, ts.orgA.String(), station.ID.String())
+	assertNotFound(t, err)
+
+	// Nor make the first organization's vault pay its own gas
+	_, err = service.SetGasStationVault(ts.ctxB, ts.orgB.String(), station.ID.String())
+	assertNotFound(t, err)
+	assert.Nil(t, orgs.organizations[ts.orgB].GasStationVaultID)
+}
+
+func TestSweepIsolation(t *testing.T) {
+	ts := newTenants(t)
+	transactions := &memoryTransactions{transactions: make(map[string]*models.Transaction)}
+	service, err := sweep.NewService(nil, ts.vaults, transactions, nil, nil, nil, nil, logger.NewLogger())
+	require.NoError(t, err)
+
+	// Another organization cannot sweep the vault
+	_, err = service.SweepVault(ts.ctxB, ts.vaultA.ID.String())
+	assertNotFound(t, err)
+	assert.Empty(t, transactions.transactions)
+}
+
+func TestEscrowIsolation(t *testing.T) {
+	ts := newTenants(t)
+	ledger, err := ts.vaults.CreateVault(ts.ctxA, &models.Vault{OrganizationID: ts.orgA, Name: "ledger", BlockchainType: "xrp", Address: "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"})
+	require.NoError(t, err)
+	repo := &memoryEscrows{escrows: make(map[string]*models.Escrow)}
+	escrowed, err := repo.CreateEscrow(ts.ctxA, &models.Escrow{VaultID: ledger.ID, Owner: ledger.Address, Amount: "1000000", Status: models.EscrowStatusCreated})
+	require.NoError(t, err)
+	key := base64.StdEncoding.EncodeToString(make([]byte, 32))
+	service, err := escrow.NewService(config.BlockchainConfig{EscrowEncryptionKey: key}, repo, ts.vaults, nil, nil, logger.NewLogger())
+	require.NoError(t, err)
+	id := escrowed.ID.String()
+
+	_, err = service.GetEscrow(ts.ctxA, id)
+	require.NoError(t, err)
+
+	// Another organization can neither read, list, finish nor cancel the vault's escrows
+	_, err = service.GetEscrow(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.ListEscrows(ts.ctxB, ledger.ID.String())
+	assertNotFound(t, err)
+	_, err = service.FinishEscrow(ts.ctxB, id)
+	assertNotFound(t, err)
+	_, err = service.CancelEscrow(ts.ctxB, id)
+	assertNotFound(t, err)
+
+	// Nor lock the vault's funds in a new one
+	_, err = service.CreateEscrow(ts.ctxB, ledger.ID.String(), &models.EscrowRequest{Destination: "rPEPPER7kfTD9w2To4CQk6UCfuHM9c6GDY", Amount: "1"})
+	assertNotFound(t, err)
+	assert.Len(t, repo.escrows, 1)
+	assert.Equal(t, models.EscrowStatusCreated, escrowed.Status)
+}
+
+func TestUnscopedContextFailsClosed(t *testing.T) {
+	ts := newTenants(t)
+	ctx := context.Background()
+	vaults := vault.NewService(ts.vaults, fakeChain{}, logger.NewLogger())
+	transactions := transaction.NewService(&memoryTransactions{transactions: make(map[string]*models.Transaction)}, ts.vaults, fakeChain{}, logger.NewLogger())
+	signatures := signature.NewService(&memorySignatures{requests: make(map[string]*models.SignatureRequest)}, ts.vaults, fakeSigner{}, logger.NewLogger())
+
+	// Without an organization nothing is created, listed or read
+	_, err := vaults.CreateVault(ctx, &models.Vault{Name: "orphan", BlockchainType: "ethereum"})
+	assert.Equal(t, tenant.ErrNoTenant, err)
+	_, err = vaults.ListVaults(ctx, &pagination.Options{})
+	assert.Equal(t, tenant.ErrNoTenant, err)
+	_, err = vaults.GetVault(ctx, ts.vaultA.ID.String())
+	assertNotFound(t, err)
+	_, err = transactions.ListTransactions(ctx, &pagination.Options{})
+	assert.Equal(t, tenant.ErrNoTenant, err)
+	_, err = signatures.ListSignatureRequests(ctx, &pagination.Options{})
+	assert.Equal(t, tenant.ErrNoTenant, err)
+	_, err = signatures.RequestSignature(ctx, &models.SignatureRequest{VaultID: ts.vaultA.ID, Data: []byte("payload")})
+	assert.Equal(t, tenant.ErrNoTenant, err)
+
+	// Background jobs see every organization's records
+	_, err = vaults.GetVault(tenant.WithSystem(ctx), ts.vaultA.ID.String())
+	assert.NoError(t, err)
+}
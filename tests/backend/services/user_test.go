@@ -0,0 +1,352 @@
+package user_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/user"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/mailer"
+)
+
+// memoryUsers is an in-memory user repository
+type memoryUsers struct {
+	users []*models.User
+}
+
+func (m *memoryUsers) GetUser(ctx context.Context, id string) (*models.User, error) {
+	for _, u := range m.users {
+		if u.ID.String() == id {
+			return u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range m.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryUsers) CreateUser(ctx context.Context, u *models.User) (*models.User, error) {
+	u.ID = uuid.New()
+	m.users = append(m.users, u)
+	return u, nil
+}
+
+// memoryMemberships is an in-memory membership repository
+type memoryMemberships struct {
+	memberships []*models.Membership
+}
+
+func (m *memoryMemberships) CreateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	membership.ID = uuid.New()
+	m.memberships = append(m.memberships, membership)
+	return membership, nil
+}
+
+func (m *memoryMemberships) GetMembership(ctx context.Context, organizationID, userID string) (*models.Membership, error) {
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID && membership.UserID.String() == userID {
+			return membership, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryMemberships) ListMemberships(ctx context.Context, userID string) ([]*models.Membership, error) {
+	var memberships []*models.Membership
+	for _, membership := range m.memberships {
+		if membership.UserID.String() == userID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (m *memoryMemberships) ListMembers(ctx context.Context, organizationID string) ([]*models.Membership, error) {
+	var memberships []*models.Membership
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID {
+			memberships = append(memberships, membership)
+		}
+	}
+	return memberships, nil
+}
+
+func (m *memoryMemberships) UpdateMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	return membership, nil
+}
+
+func (m *memoryMemberships) CountActiveMembers(ctx context.Context, organizationID, role string) (int, error) {
+	count := 0
+	for _, membership := range m.memberships {
+		if membership.OrganizationID.String() == organizationID && membership.Role == role && membership.Active() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// memoryInvitations is an in-memory invitation repository
+type memoryInvitations struct {
+	invitations []*models.Invitation
+}
+
+func (m *memoryInvitations) CreateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	invitation.ID = uuid.New()
+	m.invitations = append(m.invitations, invitation)
+	return invitation, nil
+}
+
+func (m *memoryInvitations) GetInvitation(ctx context.Context, id string) (*models.Invitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.ID.String() == id {
+			return invitation, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryInvitations) GetInvitationByTokenHash(ctx context.Context, hash string) (*models.Invitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.TokenHash == hash {
+			return invitation, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (m *memoryInvitations) ListInvitations(ctx context.Context, organizationID string) ([]*models.Invitation, error) {
+	var invitations []*models.Invitation
+	for _, invitation := range m.invitations {
+		if invitation.OrganizationID.String() == organizationID {
+			invitations = append(invitations, invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (m *memoryInvitations) UpdateInvitation(ctx context.Context, invitation *models.Invitation) (*models.Invitation, error) {
+	return invitation, nil
+}
+
+func (m *memoryInvitations) DeleteInvitation(ctx context.Context, id string) error {
+	for i, invitation := range m.invitations {
+		if invitation.ID.String() == id {
+			m.invitations = append(m.invitations[:i], m.invitations[i+1:]...)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// builtinRoles resolves the built-in roles only
+type builtinRoles struct{}
+
+func (builtinRoles) ValidateRole(ctx context.Context, organizationID uuid.UUID, role string) (string, error) {
+	if _, ok := models.BuiltinRoles[role]; !ok {
+		return "", errors.NewNotFoundError("role not found")
+	}
+	return role, nil
+}
+
+func (builtinRoles) EffectivePermissions(ctx context.Context, u *models.User, vaultID string) (map[string]bool, error) {
+	granted := make(map[string]bool)
+	for _, permission := range models.BuiltinRoles[u.Role] {
+		granted[permission] = true
+	}
+	return granted, nil
+}
+
+// outbox records sent messages, failing when err is set
+type outbox struct {
+	sent []*mailer.Message
+	err  error
+}
+
+func (o *outbox) Send(ctx context.Context, msg *mailer.Message) error {
+	if o.err != nil {
+		return o.err
+	}
+	o.sent = append(o.sent, msg)
+	return nil
+}
+
+// tokenFrom extracts the invitation token from an invitation email's accept link
+func tokenFrom(t *testing.T, msg *mailer.Message) string {
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if i := strings.Index(line, "https://"); i >= 0 {
+			link, err := url.Parse(line[i:])
+			require.NoError(t, err)
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatal("no accept link in invitation email")
+	return ""
+}
+
+type fixture struct {
+	service     *user.Service
+	users       *memoryUsers
+	memberships *memoryMemberships
+	invitations *memoryInvitations
+	outbox      *outbox
+	org         uuid.UUID
+	admin       *models.User
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		users:       &memoryUsers{},
+		memberships: &memoryMemberships{},
+		invitations: &memoryInvitations{},
+		outbox:      &outbox{},
+		org:         uuid.New(),
+	}
+	f.admin, _ = f.users.CreateUser(context.Background(), &models.User{Email: "admin@example.com"})
+	f.memberships.CreateMembership(context.Background(), &models.Membership{UserID: f.admin.ID, OrganizationID: f.org, Role: models.RoleAdmin})
+	f.service = user.NewService(config.AuthConfig{InvitationURL: "https://app.example.com/invitations/accept"},
+		f.users, f.memberships, f.invitations, builtinRoles{}, f.outbox, logger.NewLogger())
+	return f
+}
+
+func TestInviteAndAcceptCreatesAccountAndMembership(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	// Unknown roles are rejected before anything is sent
+	_, err := f.service.InviteUser(ctx, f.org, &f.admin.ID, &models.InvitationRequest{Email: "new@example.com", Role: "owner"})
+	assert.Error(t, err)
+	assert.Empty(t, f.outbox.sent)
+
+	invitation, err := f.service.InviteUser(ctx, f.org, &f.admin.ID, &models.InvitationRequest{Email: " New@Example.com ", Role: models.RoleOperator})
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", invitation.Email)
+	require.Len(t, f.outbox.sent, 1)
+	assert.Equal(t, "new@example.com", f.outbox.sent[0].To)
+
+	// Only a hash of the emailed token is stored
+	token := tokenFrom(t, f.outbox.sent[0])
+	require.NotEmpty(t, token)
+	assert.NotContains(t, invitation.TokenHash, token)
+
+	// New accounts need a strong enough password
+	_, err = f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: token, Password: "short"})
+	assert.Equal(t, user.ErrWeakPassword, err)
+
+	membership, err := f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: token, Password: "a long enough password"})
+	require.NoError(t, err)
+	assert.Equal(t, f.org, membership.OrganizationID)
+	assert.Equal(t, models.RoleOperator, membership.Role)
+	created, err := f.users.GetUserByEmail(ctx, "new@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, membership.UserID)
+	assert.NotEmpty(t, created.PasswordHash)
+
+	// The token cannot be used twice, and the new member cannot be invited again
+	_, err = f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: token, Password: "a long enough password"})
+	assert.Equal(t, user.ErrInvalidInvitation, err)
+	_, err = f.service.InviteUser(ctx, f.org, &f.admin.ID, &models.InvitationRequest{Email: "new@example.com", Role: models.RoleViewer})
+	assert.Equal(t, user.ErrAlreadyMember, err)
+}
+
+func TestExistingUserJoinsSecondOrganization(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	other := uuid.New()
+
+	_, err := f.service.InviteUser(ctx, other, nil, &models.InvitationRequest{Email: "admin@example.com", Role: models.RoleViewer})
+	require.NoError(t, err)
+
+	// Existing accounts join without a password
+	membership, err := f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: tokenFrom(t, f.outbox.sent[0])})
+	require.NoError(t, err)
+	assert.Equal(t, f.admin.ID, membership.UserID)
+
+	// The profile lists both memberships and the permissions of the current one
+	f.admin.OrganizationID, f.admin.Role = f.org, models.RoleAdmin
+	profile, err := f.service.Profile(ctx, f.admin)
+	require.NoError(t, err)
+	assert.Len(t, profile.Memberships, 2)
+	assert.Equal(t, f.org, profile.OrganizationID)
+	assert.Contains(t, profile.Permissions, models.PermissionOrgAdmin)
+}
+
+func TestInvitationExpiryRevocationAndMailFailure(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+
+	// Expired invitations cannot be accepted
+	_, err := f.service.InviteUser(ctx, f.org, nil, &models.InvitationRequest{Email: "late@example.com", Role: models.RoleViewer})
+	require.NoError(t, err)
+	f.invitations.invitations[0].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: tokenFrom(t, f.outbox.sent[0]), Password: "a long enough password"})
+	assert.Equal(t, user.ErrInvalidInvitation, err)
+
+	// Revoked invitations are gone, and other organizations cannot revoke them
+	invitation, err := f.service.InviteUser(ctx, f.org, nil, &models.InvitationRequest{Email: "maybe@example.com", Role: models.RoleViewer})
+	require.NoError(t, err)
+	assert.Equal(t, user.ErrInvitationNotFound, f.service.RevokeInvitation(ctx, uuid.New(), invitation.ID.String()))
+	require.NoError(t, f.service.RevokeInvitation(ctx, f.org, invitation.ID.String()))
+	_, err = f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: tokenFrom(t, f.outbox.sent[1]), Password: "a long enough password"})
+	assert.Equal(t, user.ErrInvalidInvitation, err)
+
+	// Invitations that cannot be emailed are not kept
+	f.outbox.err = errors.New("smtp unavailable", 500, nil)
+	_, err = f.service.InviteUser(ctx, f.org, nil, &models.InvitationRequest{Email: "lost@example.com", Role: models.RoleViewer})
+	assert.Error(t, err)
+	invitations, err := f.service.ListInvitations(ctx, f.org)
+	require.NoError(t, err)
+	assert.Len(t, invitations, 1)
+}
+
+func TestMemberRolesAndDeactivation(t *testing.T) {
+	f := newFixture()
+	ctx := context.Background()
+	adminID := f.admin.ID.String()
+
+	// The last admin can neither be demoted nor deactivated
+	_, err := f.service.SetMemberRole(ctx, f.org, adminID, &models.MembershipRoleRequest{Role: models.RoleViewer})
+	assert.Equal(t, user.ErrLastAdmin, err)
+	_, err = f.service.DeactivateMember(ctx, f.org, adminID)
+	assert.Equal(t, user.ErrLastAdmin, err)
+
+	// With a second admin they can
+	second, _ := f.users.CreateUser(ctx, &models.User{Email: "second@example.com"})
+	f.memberships.CreateMembership(ctx, &models.Membership{UserID: second.ID, OrganizationID: f.org, Role: models.RoleAdmin})
+	membership, err := f.service.SetMemberRole(ctx, f.org, adminID, &models.MembershipRoleRequest{Role: models.RoleApprover})
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleApprover, membership.Role)
+	membership, err = f.service.DeactivateMember(ctx, f.org, adminID)
+	require.NoError(t, err)
+	assert.False(t, membership.Active())
+
+	// Members of other organizations are not found
+	_, err = f.service.DeactivateMember(ctx, uuid.New(), second.ID.String())
+	assert.Equal(t, user.ErrMemberNotFound, err)
+	_, err = f.service.SetMemberRole(ctx, f.org, "not-a-uuid", &models.MembershipRoleRequest{Role: models.RoleViewer})
+	assert.Equal(t, user.ErrMemberNotFound, err)
+
+	// A deactivated member can be invited back, which reactivates their membership with the new role
+	_, err = f.service.InviteUser(ctx, f.org, nil, &models.InvitationRequest{Email: "admin@example.com", Role: models.RoleViewer})
+	require.NoError(t, err)
+	membership, err = f.service.AcceptInvitation(ctx, &models.AcceptInvitationRequest{Token: tokenFrom(t, f.outbox.sent[0])})
+	require.NoError(t, err)
+	assert.True(t, membership.Active())
+	assert.Equal(t, models.RoleViewer, membership.Role)
+}
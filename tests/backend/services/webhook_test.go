@@ -0,0 +1,477 @@
+package webhook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/internal/models"
+	"github.com/your-repo/blockchain-integration-service/internal/repository"
+	"github.com/your-repo/blockchain-integration-service/internal/services/webhook"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/errors"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/webhooksig"
+)
+
+// memoryWebhooks is an in-memory webhook repository that stores copies, as a database would
+type memoryWebhooks struct {
+	mu         sync.Mutex
+	endpoints  map[uuid.UUID]models.WebhookEndpoint
+	deliveries map[uuid.UUID]models.WebhookDelivery
+	created    int
+}
+
+func newMemoryWebhooks() *memoryWebhooks {
+	return &memoryWebhooks{
+		endpoints:  make(map[uuid.UUID]models.WebhookEndpoint),
+		deliveries: make(map[uuid.UUID]models.WebhookDelivery),
+	}
+}
+
+func (m *memoryWebhooks) CreateWebhookEndpoint(ctx context.Context, e *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e.ID = uuid.New()
+	e.CreatedAt = time.Now()
+	m.endpoints[e.ID] = *e
+	return e, nil
+}
+
+func (m *memoryWebhooks) GetWebhookEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.endpoints[uuid.MustParse(id)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &e, nil
+}
+
+func (m *memoryWebhooks) ListWebhookEndpoints(ctx context.Context, organizationID string) ([]*models.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var endpoints []*models.WebhookEndpoint
+	for _, e := range m.endpoints {
+		if e.OrganizationID.String() == organizationID {
+			e := e
+			endpoints = append(endpoints, &e)
+		}
+	}
+	return endpoints, nil
+}
+
+func (m *memoryWebhooks) UpdateWebhookEndpoint(ctx context.Context, e *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.endpoints[e.ID] = *e
+	return e, nil
+}
+
+func (m *memoryWebhooks) DeleteWebhookEndpoint(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.endpoints, uuid.MustParse(id))
+	return nil
+}
+
+func (m *memoryWebhooks) CreateWebhookDelivery(ctx context.Context, d *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// Keep creation times distinct so deliveries have a stable order
+	m.created++
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now().Add(time.Duration(m.created) * time.Microsecond)
+	m.deliveries[d.ID] = *d
+	return d, nil
+}
+
+func (m *memoryWebhooks) GetWebhookDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[uuid.MustParse(id)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &d, nil
+}
+
+func (m *memoryWebhooks) ListWebhookDeliveries(ctx context.Context, endpointID, status string) ([]*models.WebhookDelivery, error) {
+	return m.filter(func(d models.WebhookDelivery) bool {
+		return d.EndpointID.String() == endpointID && (status == "" || d.Status == status)
+	}), nil
+}
+
+func (m *memoryWebhooks) ListDueWebhookDeliveries(ctx context.Context, before time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	due := m.filter(func(d models.WebhookDelivery) bool {
+		return d.Status == models.WebhookDeliveryPending && !d.NextAttemptAt.After(before)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (m *memoryWebhooks) GetOldestPendingWebhookDelivery(ctx context.Context, endpointID, resourceID string) (*models.WebhookDelivery, error) {
+	pending := m.filter(func(d models.WebhookDelivery) bool {
+		return d.EndpointID.String() == endpointID && d.ResourceID == resourceID && d.Status == models.WebhookDeliveryPending
+	})
+	if len(pending) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return pending[0], nil
+}
+
+func (m *memoryWebhooks) UpdateWebhookDelivery(ctx context.Context, d *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.ID] = *d
+	return d, nil
+}
+
+// filter returns copies of the matching deliveries, oldest first
+func (m *memoryWebhooks) filter(match func(models.WebhookDelivery) bool) []*models.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []*models.WebhookDelivery
+	for _, d := range m.deliveries {
+		if match(d) {
+			d := d
+			deliveries = append(deliveries, &d)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt) })
+	return deliveries
+}
+
+// rewind makes an endpoint's pending deliveries due now, as if their backoff had passed
+func (m *memoryWebhooks) rewind(endpointID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, d := range m.deliveries {
+		if d.EndpointID == endpointID {
+			d.NextAttemptAt = time.Now().Add(-time.Second)
+			m.deliveries[id] = d
+		}
+	}
+}
+
+// receiver is a webhook receiver that records what it was sent and answers with a configurable status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) respond(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+// events returns the IDs of the events received so far, in order
+func (r *receiver) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, req := range r.requests {
+		ids = append(ids, req.Header.Get(webhooksig.HeaderID))
+	}
+	return ids
+}
+
+type fixture struct {
+	repo     *memoryWebhooks
+	receiver *receiver
+	server   *httptest.Server
+	service  *webhook.Service
+	orgID    uuid.UUID
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{repo: newMemoryWebhooks(), receiver: &receiver{status: http.StatusOK}, orgID: uuid.New()}
+	f.server = httptest.NewServer(f.receiver)
+	t.Cleanup(f.server.Close)
+
+	// The test receiver listens on loopback
+	f.service = webhook.NewService(config.WebhookConfig{AllowPrivateNetworks: true}, f.repo, logger.NewLogger())
+	return f
+}
+
+// register creates an endpoint on the test receiver subscribed to the given event types
+func (f *fixture) register(t *testing.T, eventTypes ...string) *models.CreatedWebhookEndpoint {
+	created, err := f.service.CreateEndpoint(context.Background(), f.orgID, &models.WebhookEndpointRequest{
+		URL:        f.server.URL,
+		EventTypes: eventTypes,
+	})
+	require.NoError(t, err)
+	return created
+}
+
+// publish publishes a transaction status change for a resource
+func (f *fixture) publish(t *testing.T, resourceID, status string) *models.Event {
+	event := models.NewEvent(f.orgID, models.EventTransactionStatusChanged, resourceID, map[string]string{"status": status})
+	require.NoError(t, f.service.Publish(context.Background(), event))
+	return event
+}
+
+func TestDeliveriesAreFilteredAndSigned(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	statuses := f.register(t, models.EventTransactionStatusChanged)
+	deposits := f.register(t, models.EventDepositReceived)
+	assert.NotEmpty(t, statuses.Secret)
+	assert.NotEqual(t, statuses.Secret, deposits.Secret)
+
+	event := f.publish(t, "tx-1", "Submitted")
+	require.NoError(t, f.service.DeliverDue(ctx))
+
+	// Only the subscribed endpoint receives the event
+	require.Len(t, f.receiver.requests, 1)
+	req, body := f.receiver.requests[0], f.receiver.bodies[0]
+	assert.Equal(t, event.ID.String(), req.Header.Get(webhooksig.HeaderID))
+	assert.Equal(t, models.EventTransactionStatusChanged, req.Header.Get(webhooksig.HeaderEvent))
+	assert.Contains(t, string(body), `"resource_id":"tx-1"`)
+
+	// The receiver can verify the delivery with the endpoint's secret, and only with it
+	signature, timestamp := req.Header.Get(webhooksig.HeaderSignature), req.Header.Get(webhooksig.HeaderTimestamp)
+	assert.NoError(t, webhooksig.Verify(statuses.Secret, signature, timestamp, body, 5*time.Minute, time.Now()))
+	assert.Equal(t, webhooksig.ErrInvalidSignature, webhooksig.Verify(deposits.Secret, signature, timestamp, body, 5*time.Minute, time.Now()))
+	assert.Equal(t, webhooksig.ErrInvalidSignature, webhooksig.Verify(statuses.Secret, signature, timestamp, append(body, ' '), 5*time.Minute, time.Now()))
+	assert.Equal(t, webhooksig.ErrStaleTimestamp, webhooksig.Verify(statuses.Secret, signature, timestamp, body, 5*time.Minute, time.Now().Add(time.Hour)))
+
+	delivered, err := f.service.ListDeliveries(ctx, f.orgID, statuses.Endpoint.ID.String(), models.WebhookDeliverySucceeded)
+	require.NoError(t, err)
+	require.Len(t, delivered, 1)
+	assert.Equal(t, 1, delivered[0].Attempts)
+	assert.Equal(t, http.StatusOK, delivered[0].LastStatusCode)
+
+	// Delivered events are not sent again
+	require.NoError(t, f.service.DeliverDue(ctx))
+	assert.Len(t, f.receiver.requests, 1)
+}
+
+func TestFailedDeliveriesBackOffThenDeadLetterAndReplay(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	endpoint := f.register(t, models.EventTransactionStatusChanged).Endpoint
+	f.receiver.respond(http.StatusInternalServerError)
+	f.publish(t, "tx-1", "Failed")
+
+	// A failure schedules a retry after the first backoff
+	require.NoError(t, f.service.DeliverDue(ctx))
+	pending, err := f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), models.WebhookDeliveryPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, pending[0].LastStatusCode)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), pending[0].NextAttemptAt, 5*time.Second)
+	assert.WithinDuration(t, pending[0].CreatedAt.Add(72*time.Hour), pending[0].RetryUntil, 5*time.Second)
+
+	// Nothing is sent before the retry is due
+	require.NoError(t, f.service.DeliverDue(ctx))
+	assert.Len(t, f.receiver.requests, 1)
+
+	// The backoff doubles with each failure
+	f.repo.rewind(endpoint.ID)
+	require.NoError(t, f.service.DeliverDue(ctx))
+	pending, _ = f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), models.WebhookDeliveryPending)
+	require.Len(t, pending, 1)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), pending[0].NextAttemptAt, 5*time.Second)
+
+	// Once the retry window closes the delivery is dead-lettered
+	closing := *pending[0]
+	closing.RetryUntil = time.Now()
+	_, err = f.repo.UpdateWebhookDelivery(ctx, &closing)
+	require.NoError(t, err)
+	f.repo.rewind(endpoint.ID)
+	require.NoError(t, f.service.DeliverDue(ctx))
+	dead, err := f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), models.WebhookDeliveryDead)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempts)
+
+	// Only dead deliveries of the organization's own endpoint can be replayed
+	_, err = f.service.ReplayDelivery(ctx, uuid.New(), endpoint.ID.String(), dead[0].ID.String())
+	assert.True(t, errors.Is(err, webhook.ErrDeliveryNotFound))
+
+	// A replay gets a fresh retry window and is delivered once the receiver recovers
+	f.receiver.respond(http.StatusNoContent)
+	replayed, err := f.service.ReplayDelivery(ctx, f.orgID, endpoint.ID.String(), dead[0].ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryPending, replayed.Status)
+	assert.Equal(t, 0, replayed.Attempts)
+	_, err = f.service.ReplayDelivery(ctx, f.orgID, endpoint.ID.String(), dead[0].ID.String())
+	assert.Error(t, err)
+
+	require.NoError(t, f.service.DeliverDue(ctx))
+	delivered, _ := f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), models.WebhookDeliverySucceeded)
+	assert.Len(t, delivered, 1)
+	assert.Len(t, f.receiver.requests, 4)
+}
+
+func TestDeliveriesAreOrderedPerResource(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	endpoint := f.register(t, models.EventTransactionStatusChanged).Endpoint
+	f.receiver.respond(http.StatusServiceUnavailable)
+
+	submitted := f.publish(t, "tx-1", "Submitted")
+	confirmed := f.publish(t, "tx-1", "Confirmed")
+	other := f.publish(t, "tx-2", "Submitted")
+
+	// A failing delivery holds back later events about its resource, but not about others
+	require.NoError(t, f.service.DeliverDue(ctx))
+	assert.ElementsMatch(t, []string{submitted.ID.String(), other.ID.String()}, f.receiver.events())
+
+	// Once it succeeds the next event about the resource follows
+	f.receiver.respond(http.StatusOK)
+	f.repo.rewind(endpoint.ID)
+	require.NoError(t, f.service.DeliverDue(ctx))
+	require.NoError(t, f.service.DeliverDue(ctx))
+
+	var resource []string
+	for _, id := range f.receiver.events() {
+		if id != other.ID.String() {
+			resource = append(resource, id)
+		}
+	}
+	assert.Equal(t, []string{submitted.ID.String(), submitted.ID.String(), confirmed.ID.String()}, resource)
+}
+
+func TestFailingEndpointIsDisabled(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	endpoint := f.register(t, models.EventTransactionStatusChanged).Endpoint
+	f.receiver.respond(http.StatusBadGateway)
+	f.publish(t, "tx-1", "Submitted")
+
+	// A failure starts tracking the endpoint's health
+	require.NoError(t, f.service.DeliverDue(ctx))
+	stored, err := f.service.GetEndpoint(ctx, f.orgID, endpoint.ID.String())
+	require.NoError(t, err)
+	assert.True(t, stored.Enabled())
+	assert.Equal(t, 1, stored.ConsecutiveFailures)
+	require.NotNil(t, stored.FailingSince)
+
+	// An endpoint that has failed for the whole disable period is disabled
+	failingSince := time.Now().Add(-25 * time.Hour)
+	stored.FailingSince = &failingSince
+	_, err = f.repo.UpdateWebhookEndpoint(ctx, stored)
+	require.NoError(t, err)
+	f.repo.rewind(endpoint.ID)
+	require.NoError(t, f.service.DeliverDue(ctx))
+
+	stored, _ = f.service.GetEndpoint(ctx, f.orgID, endpoint.ID.String())
+	assert.False(t, stored.Enabled())
+	assert.NotEmpty(t, stored.DisabledReason)
+	assert.Equal(t, 2, stored.ConsecutiveFailures)
+
+	// Its queued deliveries are dead-lettered and new events are not queued for it
+	f.repo.rewind(endpoint.ID)
+	require.NoError(t, f.service.DeliverDue(ctx))
+	dead, _ := f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), models.WebhookDeliveryDead)
+	assert.Len(t, dead, 1)
+	f.publish(t, "tx-2", "Submitted")
+	all, _ := f.service.ListDeliveries(ctx, f.orgID, endpoint.ID.String(), "")
+	assert.Len(t, all, 1)
+	_, err = f.service.ReplayDelivery(ctx, f.orgID, endpoint.ID.String(), dead[0].ID.String())
+	assert.Error(t, err)
+
+	// Re-enabling the endpoint resets its health
+	enabled := true
+	stored, err = f.service.UpdateEndpoint(ctx, f.orgID, endpoint.ID.String(), &models.WebhookEndpointUpdate{Enabled: &enabled})
+	require.NoError(t, err)
+	assert.True(t, stored.Enabled())
+	assert.Equal(t, 0, stored.ConsecutiveFailures)
+	assert.Nil(t, stored.FailingSince)
+}
+
+func TestEndpointsAreIsolatedPerOrganization(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	other := uuid.New()
+	endpoint := f.register(t, models.EventTransactionStatusChanged).Endpoint
+	id := endpoint.ID.String()
+	f.publish(t, "tx-1", "Confirmed")
+	deliveries, err := f.service.ListDeliveries(ctx, f.orgID, id, "")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	// Another organization can neither list, read, change nor delete the endpoint
+	endpoints, err := f.service.ListEndpoints(ctx, other)
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+	_, err = f.service.GetEndpoint(ctx, other, id)
+	assert.Equal(t, webhook.ErrEndpointNotFound, err)
+	disabled := false
+	_, err = f.service.UpdateEndpoint(ctx, other, id, &models.WebhookEndpointUpdate{Enabled: &disabled})
+	assert.Equal(t, webhook.ErrEndpointNotFound, err)
+	assert.Equal(t, webhook.ErrEndpointNotFound, f.service.DeleteEndpoint(ctx, other, id))
+
+	// Nor list or replay its deliveries
+	_, err = f.service.ListDeliveries(ctx, other, id, "")
+	assert.Equal(t, webhook.ErrEndpointNotFound, err)
+	_, err = f.service.ReplayDelivery(ctx, other, id, deliveries[0].ID.String())
+	assert.Equal(t, webhook.ErrDeliveryNotFound, err)
+
+	// Its events are never delivered to the first organization's endpoint
+	event := models.NewEvent(other, models.EventTransactionStatusChanged, "tx-2", map[string]string{"status": "Confirmed"})
+	require.NoError(t, f.service.Publish(ctx, event))
+	deliveries, err = f.service.ListDeliveries(ctx, f.orgID, id, "")
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	current, err := f.service.GetEndpoint(ctx, f.orgID, id)
+	require.NoError(t, err)
+	assert.True(t, current.Enabled())
+}
+
+func TestEndpointValidationAndPrivateNetworks(t *testing.T) {
+	ctx := context.Background()
+	orgID := uuid.New()
+	repo := newMemoryWebhooks()
+	service := webhook.NewService(config.WebhookConfig{}, repo, logger.NewLogger())
+
+	// Endpoints must use HTTPS and subscribe to known event types
+	_, err := service.CreateEndpoint(ctx, orgID, &models.WebhookEndpointRequest{URL: "http://hooks.example.com", EventTypes: []string{models.EventDepositReceived}})
+	assert.Error(t, err)
+	_, err = service.CreateEndpoint(ctx, orgID, &models.WebhookEndpointRequest{URL: "https://hooks.example.com", EventTypes: []string{"vault.deleted"}})
+	assert.Error(t, err)
+	created, err := service.CreateEndpoint(ctx, orgID, &models.WebhookEndpointRequest{
+		URL:        "https://127.0.0.1:9/hooks",
+		EventTypes: []string{models.EventDepositReceived, models.EventDepositReceived},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.EventDepositReceived}, created.Endpoint.EventTypes)
+
+	// Other organizations cannot see the endpoint
+	_, err = service.GetEndpoint(ctx, uuid.New(), created.Endpoint.ID.String())
+	assert.True(t, errors.Is(err, webhook.ErrEndpointNotFound))
+
+	// Deliveries are never sent into private networks
+	require.NoError(t, service.Publish(ctx, models.NewEvent(orgID, models.EventDepositReceived, "0xdeposit", nil)))
+	require.NoError(t, service.DeliverDue(ctx))
+	pending, err := service.ListDeliveries(ctx, orgID, created.Endpoint.ID.String(), models.WebhookDeliveryPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Contains(t, pending[0].LastError, "private network")
+}
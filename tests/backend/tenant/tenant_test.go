@@ -0,0 +1,38 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/internal/tenant"
+)
+
+func TestRequire(t *testing.T) {
+	organizationID := uuid.New()
+
+	id, err := tenant.Require(tenant.WithOrganization(context.Background(), organizationID))
+	assert.NoError(t, err)
+	assert.Equal(t, organizationID, id)
+
+	// Unscoped contexts, the nil organization and background jobs have no organization to require
+	for _, ctx := range []context.Context{
+		context.Background(),
+		tenant.WithOrganization(context.Background(), uuid.Nil),
+		tenant.WithSystem(context.Background()),
+	} {
+		_, err := tenant.Require(ctx)
+		assert.Equal(t, tenant.ErrNoTenant, err)
+	}
+}
+
+func TestOwns(t *testing.T) {
+	organizationID, other := uuid.New(), uuid.New()
+	ctx := tenant.WithOrganization(context.Background(), organizationID)
+
+	assert.True(t, tenant.Owns(ctx, organizationID))
+	assert.False(t, tenant.Owns(ctx, other))
+	assert.False(t, tenant.Owns(context.Background(), organizationID))
+	assert.True(t, tenant.Owns(tenant.WithSystem(context.Background()), other))
+}
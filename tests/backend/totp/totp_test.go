@@ -0,0 +1,75 @@
+package totp_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/pkg/totp"
+)
+
+// rfcSecret is the SHA-1 seed of the RFC 6238 test vectors, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeMatchesRFC6238Vectors(t *testing.T) {
+	// The RFC lists eight digits; six-digit codes are their last six
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range vectors {
+		got, err := totp.Code(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		assert.Equal(t, want, got, "time %d", unix)
+	}
+
+	// Secrets are accepted as users may type them
+	got, err := totp.Code(strings.ToLower(rfcSecret[:16])+" "+rfcSecret[16:], time.Unix(59, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", got)
+	_, err = totp.Code("not base32!", time.Now())
+	assert.Equal(t, totp.ErrInvalidSecret, err)
+}
+
+func TestVerifyAllowsSkewAndReturnsStep(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+
+	previous, err := totp.Code(secret, now.Add(-totp.Period))
+	require.NoError(t, err)
+	step, ok := totp.Verify(secret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	// Codes outside the skew, of the wrong length or for another secret are rejected
+	stale, err := totp.Code(secret, now.Add(-2*totp.Period))
+	require.NoError(t, err)
+	_, ok = totp.Verify(secret, stale, now, 1)
+	assert.False(t, ok)
+	_, ok = totp.Verify(secret, "12345", now, 1)
+	assert.False(t, ok)
+	current, err := totp.Code(rfcSecret, now)
+	require.NoError(t, err)
+	_, ok = totp.Verify(secret, current, now, 0)
+	assert.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(totp.URI("Acme Custody", "ops@example.com", rfcSecret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Acme Custody:ops@example.com", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Acme Custody", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
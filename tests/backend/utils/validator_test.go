@@ -0,0 +1,94 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/internal/utils"
+)
+
+func TestValidateBitcoinAddressAcceptsStandardTypes(t *testing.T) {
+	// Sample mainnet addresses of every supported type
+	addresses := map[string]string{
+		"P2PKH":  "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		"P2SH":   "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+		"P2WPKH": "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		"P2WSH":  "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3",
+		"P2TR":   "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+	}
+
+	for addressType, address := range addresses {
+		valid, err := utils.ValidateBitcoinAddress(address, "mainnet")
+		assert.NoError(t, err, addressType)
+		assert.True(t, valid, addressType)
+	}
+}
+
+func TestValidateBitcoinAddressRejectsWrongChecksumVariant(t *testing.T) {
+	// A segwit v0 program encoded with bech32m is invalid
+	_, err := utils.ValidateBitcoinAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", "mainnet")
+	assert.Error(t, err)
+
+	// A taproot program encoded with bech32 is invalid
+	_, err = utils.ValidateBitcoinAddress("bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", "mainnet")
+	assert.Error(t, err)
+
+	// A corrupted checksum is invalid
+	_, err = utils.ValidateBitcoinAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", "mainnet")
+	assert.Error(t, err)
+}
+
+func TestValidateBitcoinAddressChecksNetwork(t *testing.T) {
+	// Testnet and regtest addresses are valid on their own networks
+	valid, err := utils.ValidateBitcoinAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", "testnet")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = utils.ValidateBitcoinAddress("bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", "regtest")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// A mainnet address is rejected on testnet and vice versa
+	_, err = utils.ValidateBitcoinAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "testnet")
+	assert.Error(t, err)
+	_, err = utils.ValidateBitcoinAddress("mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", "mainnet")
+	assert.Error(t, err)
+
+	// A testnet bech32 address is rejected on regtest
+	_, err = utils.ValidateBitcoinAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", "regtest")
+	assert.Error(t, err)
+}
+
+func TestValidateAddressDispatchesByBlockchainType(t *testing.T) {
+	valid, err := utils.ValidateAddress("bitcoin-testnet", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = utils.ValidateAddress("bitcoin", "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx")
+	assert.Error(t, err)
+
+	_, err = utils.ValidateAddress("dogecoin", "DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L")
+	assert.Error(t, err)
+}
+
+func TestValidateAddressAcceptsEVMNetworks(t *testing.T) {
+	for _, blockchainType := range []string{"ethereum", "ethereum-sepolia", "polygon", "arbitrum", "base", "evm-devnet"} {
+		valid, err := utils.ValidateAddress(blockchainType, "0x742d35Cc6634C0532925a3b844Bc454e4438f44e")
+		assert.NoError(t, err, blockchainType)
+		assert.True(t, valid, blockchainType)
+	}
+}
+
+func TestValidateBlockchainTypeAcceptsBitcoin(t *testing.T) {
+	for _, blockchainType := range []string{"ethereum", "polygon", "xrp", "bitcoin", "bitcoin-testnet", "bitcoin-regtest"} {
+		valid, err := utils.ValidateBlockchainType(blockchainType)
+		assert.NoError(t, err, blockchainType)
+		assert.True(t, valid, blockchainType)
+	}
+
+	_, err := utils.ValidateBlockchainType("litecoin")
+	assert.Error(t, err)
+}
+
+// Human tasks:
+// - Add tests for Ethereum checksum validation
+// - Add tests for XRP memo size limits
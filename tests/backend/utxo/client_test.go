@@ -0,0 +1,188 @@
+package utxo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// newTestClient creates a UTXOClient pointed at a test custodian with fast retries
+func newTestClient(t *testing.T, server *httptest.Server, custodian config.CustodianConfig) *utxo.UTXOClient {
+	custodian.BaseURL = server.URL
+	if custodian.RetryBaseDelay == 0 {
+		custodian.RetryBaseDelay = time.Millisecond
+	}
+	cfg := &config.Config{Blockchain: config.BlockchainConfig{UTXOCustodian: custodian}}
+
+	client, err := utxo.NewUTXOClient(cfg, logger.NewLogger())
+	require.NoError(t, err)
+	return client
+}
+
+func TestCreateTransactionSendsBody(t *testing.T) {
+	var received utxo.TransactionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/transactions", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(utxo.Transaction{TxID: "abc", Hex: "00"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{})
+	request := &utxo.TransactionRequest{Inputs: []utxo.UTXO{{TxID: "prev", Vout: 1, Amount: 5000}}}
+
+	tx, err := client.CreateTransaction(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", tx.TxID)
+	assert.Equal(t, request.Inputs, received.Inputs)
+}
+
+func TestPreviewFee(t *testing.T) {
+	var received utxo.FeePreviewRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/transactions/preview", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+		json.NewEncoder(w).Encode(utxo.FeePreview{Fee: 1410, FeeRate: 10, Inputs: 1, Change: 3590, Sufficient: true})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{})
+	request := &utxo.FeePreviewRequest{FromAddress: "bc1qfrom", ToAddress: "bc1qto", Amount: 5000}
+
+	preview, err := client.PreviewFee(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, *request, received)
+	assert.Equal(t, int64(1410), preview.Fee)
+	assert.True(t, preview.Sufficient)
+}
+
+func TestGetUTXOsRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]utxo.UTXO{{TxID: "abc", Vout: 0, Amount: 1000}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{MaxRetries: 3})
+
+	utxos, err := client.GetUTXOs(context.Background(), "bc1qaddress")
+
+	require.NoError(t, err)
+	assert.Len(t, utxos, 1)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestCreateTransactionIsNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{MaxRetries: 3})
+
+	_, err := client.CreateTransaction(context.Background(), &utxo.TransactionRequest{})
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCustodianErrorsAreTyped(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected error
+	}{
+		{"insufficient funds", http.StatusUnprocessableEntity, `{"code":"insufficient_funds","message":"balance too low"}`, utxo.ErrInsufficientFunds},
+		{"invalid address", http.StatusBadRequest, `{"code":"invalid_address","message":"bad checksum"}`, utxo.ErrInvalidAddress},
+		{"rate limited", http.StatusTooManyRequests, `slow down`, utxo.ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server, config.CustodianConfig{MaxRetries: -1})
+
+			_, err := client.CreateTransaction(context.Background(), &utxo.TransactionRequest{})
+
+			assert.True(t, errors.Is(err, tt.expected), "got %v", err)
+			var custodianErr *utxo.CustodianError
+			require.True(t, errors.As(err, &custodianErr))
+			assert.Equal(t, tt.status, custodianErr.StatusCode)
+		})
+	}
+}
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{
+		MaxRetries:       -1,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := client.GetTransactionStatus(context.Background(), "abc")
+		assert.Error(t, err)
+	}
+	_, err := client.GetTransactionStatus(context.Background(), "abc")
+
+	assert.True(t, errors.Is(err, utxo.ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestRequestsAreSignedWithHMAC(t *testing.T) {
+	secret := "custodian-secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		expected := utxo.SignRequest([]byte(secret), r.Header.Get("X-Timestamp"), r.Method, r.URL.RequestURI(), body)
+		assert.Equal(t, expected, r.Header.Get("X-Signature"))
+
+		json.NewEncoder(w).Encode(map[string]string{"psbt": "signed"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server, config.CustodianConfig{HMACSecret: secret})
+
+	signed, err := client.SignPSBT(context.Background(), "unsigned")
+
+	require.NoError(t, err)
+	assert.Equal(t, "signed", signed)
+}
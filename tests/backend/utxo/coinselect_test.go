@@ -0,0 +1,89 @@
+package utxo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// p2wpkhScript is a sample P2WPKH script used for every test UTXO
+const p2wpkhScript = "0014751e76e8199196d454941c45d1b3a323f1433bd6"
+
+func sampleUTXOs(amounts ...int64) []utxo.UTXO {
+	utxos := make([]utxo.UTXO, 0, len(amounts))
+	for i, amount := range amounts {
+		utxos = append(utxos, utxo.UTXO{
+			TxID:         "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+			Vout:         i,
+			Amount:       amount,
+			ScriptPubKey: p2wpkhScript,
+		})
+	}
+	return utxos
+}
+
+func TestSelectCoinsFindsChangelessSelection(t *testing.T) {
+	// Fee for one P2WPKH input and one P2WPKH output at 1 sat/vB is 11 + 31 + 68 = 110
+	utxos := sampleUTXOs(50000, 100110, 300000)
+	outputs := []utxo.Output{{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 100000}}
+
+	selection, err := utxo.SelectCoins(utxos, outputs, utxo.SelectionParams{FeeRate: 1, OutputVBytes: 31})
+
+	// Assert that the exact-match UTXO was chosen without change
+	assert.NoError(t, err)
+	assert.Len(t, selection.Inputs, 1)
+	assert.Equal(t, int64(100110), selection.Inputs[0].Amount)
+	assert.Equal(t, int64(0), selection.Change)
+	assert.Equal(t, int64(110), selection.Fee)
+}
+
+func TestSelectCoinsCreatesChange(t *testing.T) {
+	utxos := sampleUTXOs(500000, 700000)
+	outputs := []utxo.Output{{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 100000}}
+
+	selection, err := utxo.SelectCoins(utxos, outputs, utxo.SelectionParams{FeeRate: 5, OutputVBytes: 31})
+
+	// Assert that the amounts balance and the fee matches the fee rate
+	assert.NoError(t, err)
+	var inputTotal int64
+	for _, input := range selection.Inputs {
+		inputTotal += input.Amount
+	}
+	assert.Greater(t, selection.Change, int64(546))
+	assert.Equal(t, inputTotal, int64(100000)+selection.Change+selection.Fee)
+	assert.Equal(t, selection.VBytes*5, selection.Fee)
+}
+
+func TestSelectCoinsDropsDustChange(t *testing.T) {
+	// The surplus after fees is too small to be worth a change output
+	utxos := sampleUTXOs(100500)
+	outputs := []utxo.Output{{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 100000}}
+
+	selection, err := utxo.SelectCoins(utxos, outputs, utxo.SelectionParams{FeeRate: 1, OutputVBytes: 31})
+
+	// Assert that the surplus was added to the fee
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), selection.Change)
+	assert.Equal(t, int64(500), selection.Fee)
+}
+
+func TestSelectCoinsRejectsDustOutputs(t *testing.T) {
+	outputs := []utxo.Output{{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 100}}
+
+	_, err := utxo.SelectCoins(sampleUTXOs(100000), outputs, utxo.SelectionParams{FeeRate: 1, OutputVBytes: 31})
+
+	assert.ErrorIs(t, err, utxo.ErrDustOutput)
+}
+
+func TestSelectCoinsInsufficientFunds(t *testing.T) {
+	outputs := []utxo.Output{{Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Amount: 1000000}}
+
+	_, err := utxo.SelectCoins(sampleUTXOs(100000, 200000), outputs, utxo.SelectionParams{FeeRate: 1, OutputVBytes: 31})
+
+	assert.ErrorIs(t, err, utxo.ErrInsufficientFunds)
+}
+
+// Human tasks:
+// - Add tests for mixed input script types
+// - Add tests for PSBT construction with change outputs
@@ -0,0 +1,74 @@
+package utxo_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// unsignedPSBT builds a PSBT spending one P2WPKH output controlled by key
+func unsignedPSBT(t *testing.T, key *btcec.PrivateKey) *utxo.UnsignedTransaction {
+	address, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	assert.NoError(t, err)
+	script, err := txscript.PayToAddrScript(address)
+	assert.NoError(t, err)
+
+	hash, _ := chainhash.NewHashFromStr("4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+	packet, err := psbt.New([]*wire.OutPoint{wire.NewOutPoint(hash, 0)}, []*wire.TxOut{wire.NewTxOut(90000, script)}, 2, 0, []uint32{wire.MaxTxInSequenceNum - 2})
+	assert.NoError(t, err)
+	packet.Inputs[0].WitnessUtxo = wire.NewTxOut(100000, script)
+
+	encoded, err := packet.B64Encode()
+	assert.NoError(t, err)
+	return &utxo.UnsignedTransaction{PSBT: encoded}
+}
+
+func TestOfflinePSBTRoundTrip(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	assert.NoError(t, err)
+	unsigned := unsignedPSBT(t, key)
+
+	// Export the PSBT bound to its transaction
+	payload, err := utxo.ExportPSBT(unsigned, "tx-1", "bitcoin-regtest")
+	assert.NoError(t, err)
+	assert.NoError(t, payload.Verify())
+
+	// Sign offline and finalize against the exported digest
+	signed, err := utxo.SignPSBTWithKey(payload.Data, key)
+	assert.NoError(t, err)
+	rawTx, err := utxo.FinalizePSBT(signed, payload.Digest)
+	assert.NoError(t, err)
+
+	// The extracted transaction carries a witness and keeps the exported txid
+	raw, _ := hex.DecodeString(rawTx)
+	tx := wire.NewMsgTx(2)
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(raw)))
+	assert.Len(t, tx.TxIn[0].Witness, 2)
+	assert.Equal(t, payload.Digest, tx.TxHash().String())
+}
+
+func TestOfflinePSBTRejectsOtherTransaction(t *testing.T) {
+	key, _ := btcec.NewPrivateKey()
+	other, _ := btcec.NewPrivateKey()
+	unsigned := unsignedPSBT(t, key)
+
+	// A key that controls no input cannot sign
+	_, err := utxo.SignPSBTWithKey(unsigned.PSBT, other)
+	assert.Equal(t, utxo.ErrNoSignableInputs, err)
+
+	// A signed PSBT for a different transaction is rejected
+	signed, err := utxo.SignPSBTWithKey(unsigned.PSBT, key)
+	assert.NoError(t, err)
+	_, err = utxo.FinalizePSBT(signed, "0000000000000000000000000000000000000000000000000000000000000000")
+	assert.Equal(t, utxo.ErrDigestMismatch, err)
+}
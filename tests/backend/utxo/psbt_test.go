@@ -0,0 +1,125 @@
+package utxo_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/your-repo/blockchain-integration-service/pkg/config"
+	"github.com/your-repo/blockchain-integration-service/pkg/logger"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+// fixedChange hands out one change address
+type fixedChange string
+
+func (f fixedChange) NewChangeAddress(ctx context.Context) (string, error) { return string(f), nil }
+
+// newTestBuilder creates a PSBTBuilder whose custodian returns the given UTXOs for every address
+func newTestBuilder(t *testing.T, utxos ...utxo.UTXO) *utxo.PSBTBuilder {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(utxos)
+	}))
+	t.Cleanup(server.Close)
+	return utxo.NewPSBTBuilder(newTestClient(t, server, config.CustodianConfig{}), &chaincfg.RegressionNetParams, 546, logger.NewLogger())
+}
+
+// p2shUTXO returns a P2SH output of the given amount paying to a redeem script, with its previous transaction
+func p2shUTXO(t *testing.T, redeemScript []byte, amount int64) utxo.UTXO {
+	address, err := btcutil.NewAddressScriptHash(redeemScript, &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	script, err := txscript.PayToAddrScript(address)
+	require.NoError(t, err)
+
+	prevTx := wire.NewMsgTx(wire.TxVersion)
+	prevTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(amount, script))
+	var raw bytes.Buffer
+	require.NoError(t, prevTx.Serialize(&raw))
+
+	return utxo.UTXO{
+		TxID:         prevTx.TxHash().String(),
+		Amount:       amount,
+		ScriptPubKey: hex.EncodeToString(script),
+		RedeemScript: hex.EncodeToString(redeemScript),
+		RawTx:        hex.EncodeToString(raw.Bytes()),
+	}
+}
+
+// buildInput builds a PSBT paying away part of a UTXO and returns its decoded input
+func buildInput(t *testing.T, u utxo.UTXO, destination string) psbt.PInput {
+	unsigned, err := newTestBuilder(t, u).BuildPSBT(context.Background(), &utxo.PSBTRequest{
+		FromAddresses: []string{"source"},
+		Outputs:       []utxo.Output{{Address: destination, Amount: 50000}},
+		FeeRate:       2,
+	}, fixedChange(destination))
+	require.NoError(t, err)
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader([]byte(unsigned.PSBT)), true)
+	require.NoError(t, err)
+	return packet.Inputs[0]
+}
+
+func TestPSBTAttachesP2SHPrevOutByRedeemScript(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := key.PubKey().SerializeCompressed()
+	destination, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+
+	// A P2SH-wrapped P2WPKH input is nested segwit and only needs the spent output
+	witnessProgram, err := txscript.PayToAddrScript(destination)
+	require.NoError(t, err)
+	input := buildInput(t, p2shUTXO(t, witnessProgram, 100000), destination.EncodeAddress())
+	assert.NotNil(t, input.WitnessUtxo)
+	assert.Nil(t, input.NonWitnessUtxo)
+	assert.Equal(t, witnessProgram, input.RedeemScript)
+
+	// A legacy P2SH multisig input needs the full previous transaction
+	multisig, err := txscript.NewScriptBuilder().AddOp(txscript.OP_1).AddData(pubKey).AddOp(txscript.OP_1).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	require.NoError(t, err)
+	input = buildInput(t, p2shUTXO(t, multisig, 100000), destination.EncodeAddress())
+	assert.Nil(t, input.WitnessUtxo)
+	assert.NotNil(t, input.NonWitnessUtxo)
+	assert.Equal(t, multisig, input.RedeemScript)
+
+	// A redeem script that does not hash to the output script is refused
+	mismatched := p2shUTXO(t, multisig, 100000)
+	mismatched.RedeemScript = hex.EncodeToString(witnessProgram)
+	_, err = newTestBuilder(t, mismatched).BuildPSBT(context.Background(), &utxo.PSBTRequest{
+		FromAddresses: []string{"source"},
+		Outputs:       []utxo.Output{{Address: destination.EncodeAddress(), Amount: 50000}},
+		FeeRate:       2,
+	}, fixedChange(destination.EncodeAddress()))
+	assert.Error(t, err)
+}
+
+func TestPSBTChangeRequiresProvider(t *testing.T) {
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	destination, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	require.NoError(t, err)
+	script, err := txscript.PayToAddrScript(destination)
+	require.NoError(t, err)
+
+	// Spending part of the UTXO leaves change, which cannot be sent anywhere without a provider
+	builder := newTestBuilder(t, utxo.UTXO{TxID: "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b", Amount: 100000, ScriptPubKey: hex.EncodeToString(script)})
+	_, err = builder.BuildPSBT(context.Background(), &utxo.PSBTRequest{
+		FromAddresses: []string{"source"},
+		Outputs:       []utxo.Output{{Address: destination.EncodeAddress(), Amount: 50000}},
+		FeeRate:       2,
+	}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no change address provider")
+}
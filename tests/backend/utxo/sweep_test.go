@@ -0,0 +1,47 @@
+package utxo_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/pkg/utxo"
+)
+
+const sweepDestination = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+
+func TestPlanSweepConsolidatesWithoutChange(t *testing.T) {
+	// The 60 sat UTXO costs 68 sats to spend at 1 sat/vB and is left behind
+	utxos := sampleUTXOs(50000, 30000, 60)
+
+	selection, err := utxo.PlanSweep(utxos, sweepDestination, utxo.SweepParams{FeeRate: 1})
+
+	// Fee for two P2WPKH inputs and one output is 11 + 31 + 2*68 = 178
+	assert.NoError(t, err)
+	assert.Len(t, selection.Inputs, 2)
+	assert.Equal(t, int64(0), selection.Change)
+	assert.Equal(t, int64(178), selection.Fee)
+	assert.Len(t, selection.Outputs, 1)
+	assert.Equal(t, sweepDestination, selection.Outputs[0].Address)
+	assert.Equal(t, int64(80000-178), selection.Outputs[0].Amount)
+}
+
+func TestPlanSweepPrefersLargestInputsWhenCapped(t *testing.T) {
+	utxos := sampleUTXOs(10000, 50000, 30000)
+
+	selection, err := utxo.PlanSweep(utxos, sweepDestination, utxo.SweepParams{FeeRate: 2, MaxInputs: 2})
+
+	// Assert that the two most valuable UTXOs were swept
+	assert.NoError(t, err)
+	assert.Len(t, selection.Inputs, 2)
+	assert.Equal(t, int64(50000), selection.Inputs[0].Amount)
+	assert.Equal(t, int64(30000), selection.Inputs[1].Amount)
+	assert.Equal(t, selection.VBytes*2, selection.Fee)
+}
+
+func TestPlanSweepNothingWorthSweeping(t *testing.T) {
+	// Every UTXO costs more in fees than it holds
+	utxos := sampleUTXOs(300, 400)
+
+	_, err := utxo.PlanSweep(utxos, sweepDestination, utxo.SweepParams{FeeRate: 10})
+	assert.Equal(t, utxo.ErrNothingToSweep, err)
+}
@@ -0,0 +1,31 @@
+package webhooksig_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/your-repo/blockchain-integration-service/pkg/webhooksig"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"type":"deposit.received"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := webhooksig.Sign("whsec_current", now, body)
+
+	// The signature is versioned and bound to the secret, timestamp and body
+	assert.Regexp(t, `^v1=[0-9a-f]{64}$`, signature)
+	assert.NoError(t, webhooksig.Verify("whsec_current", signature, timestamp, body, time.Minute, now.Add(30*time.Second)))
+	assert.Equal(t, webhooksig.ErrInvalidSignature, webhooksig.Verify("whsec_other", signature, timestamp, body, time.Minute, now))
+	assert.Equal(t, webhooksig.ErrInvalidSignature, webhooksig.Verify("whsec_current", signature, strconv.FormatInt(now.Unix()+1, 10), body, time.Minute, now))
+
+	// Any of several signatures may match, so secrets can be rotated
+	rotating := webhooksig.Sign("whsec_previous", now, body) + ", " + signature
+	assert.NoError(t, webhooksig.Verify("whsec_current", rotating, timestamp, body, time.Minute, now))
+
+	// Old or malformed timestamps are rejected before the signature is checked
+	assert.Equal(t, webhooksig.ErrStaleTimestamp, webhooksig.Verify("whsec_current", signature, timestamp, body, time.Minute, now.Add(2*time.Minute)))
+	assert.Equal(t, webhooksig.ErrStaleTimestamp, webhooksig.Verify("whsec_current", signature, "yesterday", body, time.Minute, now))
+}